@@ -20,7 +20,6 @@ go get -u github.com/aws/aws-sdk-go/aws/session
 go get -u github.com/aws/aws-sdk-go/aws/credentials
 go get -u github.com/aws/aws-sdk-go/service/s3
 
-# for setting up secondary indexes on aerospike
-#aql
-#CREATE INDEX phoneindex ON users.username (Phone) STRING
-#CREATE INDEX emailindex ON users.username (Email) STRING
+# postgres tables and aerospike secondary indexes are created by the migrations,
+# once postgres and aerospike are running:
+#pingedchat migrate
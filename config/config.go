@@ -0,0 +1,421 @@
+// Package config holds every setting that differs between dev, staging and
+// prod: listen addresses, TLS files, database endpoints, S3 and the API keys
+// of the services we push through.
+//
+// Settings are read from, in order, the defaults below, a JSON file
+// (see config.example.json), PINGEDCHAT_* environment variables and finally
+// command line flags, each overriding the one before.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const ENV_PREFIX = "PINGEDCHAT_"
+
+type Config struct {
+	Server    ServerConfig
+	Postgres  PostgresConfig
+	Aerospike AerospikeConfig
+	Nats      NatsConfig
+	Session   SessionConfig
+	AWS       AWSConfig
+	Push      PushConfig
+	Mail      MailConfig
+	Telapi    TelapiConfig
+	Forecast  ForecastConfig
+	Previews  PreviewsConfig
+}
+
+type ServerConfig struct {
+	Addr             string // https and sockjs
+	RedirectAddr     string // plain http, redirects to PublicURL
+	TextWebhookAddr  string
+	EmailWebhookAddr string
+	TLSCertFile      string
+	TLSKeyFile       string
+	PublicURL        string // eg. https://pingedchat.com
+	SockJSURL        string // defaults to the copy served from PublicURL
+	WebDir           string
+}
+
+type PostgresConfig struct {
+	ConnectString string
+	// shared by every session, keep below postgres' max_connections
+	MaxOpenConns int
+	MaxIdleConns int
+}
+
+type AerospikeConfig struct {
+	Host string
+	Port int
+}
+
+type NatsConfig struct {
+	URL string
+}
+
+type SessionConfig struct {
+	Secret   string // HMAC key the session tokens are signed with
+	TTLHours int    // how long a token lasts without RefreshSession
+}
+
+type AWSConfig struct {
+	// for signing browser upload policies
+	AccessKeyID     string
+	SecretAccessKey string
+	// for uploads from the server
+	CredentialsFile string
+	Region          string
+	Bucket          string
+}
+
+type PushConfig struct {
+	AndroidAPIKey   string
+	APNSCertFile    string
+	APNSKeyFile     string
+	APNSSandbox     bool
+	ADMClientID     string
+	ADMClientSecret string
+}
+
+type MailConfig struct {
+	MandrillAPIKey string
+	Domain         string // users' email addresses are <username>@Domain
+	TextDomain     string // sms gateway replies come from <CID>@TextDomain
+}
+
+type TelapiConfig struct {
+	SID       string
+	AuthToken string
+}
+
+type ForecastConfig struct {
+	APIKey string
+}
+
+// link previews, see pcDatabase/link_previews.go
+type PreviewsConfig struct {
+	Enabled bool
+	// comma separated hosts, a host matches its subdomains too.  With Allow
+	// set only those hosts are fetched, Deny is never fetched
+	Allow          string
+	Deny           string
+	TimeoutSeconds int // per page, redirects included
+	MaxKB          int // of a page read, the rest is ignored
+}
+
+// Default is the config for running everything on localhost
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Addr:             ":443",
+			RedirectAddr:     ":80",
+			TextWebhookAddr:  ":8118",
+			EmailWebhookAddr: ":8119",
+			TLSCertFile:      "./PC_public.pem",
+			TLSKeyFile:       "./PC_private_unencrypted.key",
+			PublicURL:        "https://localhost",
+			WebDir:           "web/",
+		},
+		Postgres: PostgresConfig{
+			ConnectString: "user=postgres password=postgres dbname=pingedchatdb host=127.0.0.1",
+			MaxOpenConns:  50,
+			MaxIdleConns:  10,
+		},
+		Aerospike: AerospikeConfig{
+			Host: "127.0.0.1",
+			Port: 3000,
+		},
+		Nats: NatsConfig{
+			URL: "nats://127.0.0.1:4222",
+		},
+		Session: SessionConfig{
+			TTLHours: 30 * 24,
+		},
+		AWS: AWSConfig{
+			CredentialsFile: "./aws_credentials",
+			Region:          "us-east-1",
+			Bucket:          "pingedchat-us1",
+		},
+		Push: PushConfig{
+			APNSCertFile: "pcDatabase/iosDevelopmentPushCert.pem",
+			APNSKeyFile:  "pcDatabase/iosDevelopmentPushKey-noenc.pem",
+			APNSSandbox:  true,
+		},
+		Mail: MailConfig{
+			Domain:     "pinged.email",
+			TextDomain: "txt.pingedchat.com",
+		},
+		Previews: PreviewsConfig{
+			Enabled:        true,
+			TimeoutSeconds: 5,
+			MaxKB:          512,
+		},
+	}
+}
+
+// kinds of setting
+const (
+	PLAIN  = iota
+	SECRET // left out of String()
+	KEY    // an API key, secret and allowed to be missing
+)
+
+// one entry per setting, the flag name doubles as the environment variable
+// name, eg. postgres-connect-string is PINGEDCHAT_POSTGRES_CONNECT_STRING
+type setting struct {
+	name  string
+	usage string
+	value interface{} // *string, *int or *bool
+	kind  int
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{"addr", "https and sockjs listen address", &c.Server.Addr, PLAIN},
+		{"redirect-addr", "http listen address, redirects to public-url", &c.Server.RedirectAddr, PLAIN},
+		{"text-webhook-addr", "mandrill text webhook listen address", &c.Server.TextWebhookAddr, PLAIN},
+		{"email-webhook-addr", "mandrill email webhook listen address", &c.Server.EmailWebhookAddr, PLAIN},
+		{"tls-cert-file", "TLS certificate", &c.Server.TLSCertFile, PLAIN},
+		{"tls-key-file", "TLS private key, unencrypted", &c.Server.TLSKeyFile, PLAIN},
+		{"public-url", "where users reach the server, eg. https://pingedchat.com", &c.Server.PublicURL, PLAIN},
+		{"sockjs-url", "sockjs client script, defaults to the one under public-url", &c.Server.SockJSURL, PLAIN},
+		{"web-dir", "static files", &c.Server.WebDir, PLAIN},
+		{"postgres-connect-string", "postgres connection string", &c.Postgres.ConnectString, SECRET},
+		{"postgres-max-open-conns", "postgres connections shared by every session", &c.Postgres.MaxOpenConns, PLAIN},
+		{"postgres-max-idle-conns", "idle postgres connections kept open", &c.Postgres.MaxIdleConns, PLAIN},
+		{"aerospike-host", "aerospike host", &c.Aerospike.Host, PLAIN},
+		{"aerospike-port", "aerospike port", &c.Aerospike.Port, PLAIN},
+		{"nats-url", "gnatsd url", &c.Nats.URL, PLAIN},
+		{"session-secret", "key session tokens are signed with, at least 32 characters", &c.Session.Secret, SECRET},
+		{"session-ttl-hours", "hours a session token lasts", &c.Session.TTLHours, PLAIN},
+		{"aws-access-key-id", "AWS key for signing browser uploads", &c.AWS.AccessKeyID, KEY},
+		{"aws-secret-access-key", "AWS secret for signing browser uploads", &c.AWS.SecretAccessKey, KEY},
+		{"aws-credentials-file", "AWS shared credentials file for server uploads", &c.AWS.CredentialsFile, PLAIN},
+		{"aws-region", "S3 region", &c.AWS.Region, PLAIN},
+		{"aws-bucket", "S3 bucket for uploads", &c.AWS.Bucket, PLAIN},
+		{"android-api-key", "GCM API key", &c.Push.AndroidAPIKey, KEY},
+		{"apns-cert-file", "APNS certificate", &c.Push.APNSCertFile, PLAIN},
+		{"apns-key-file", "APNS private key, unencrypted", &c.Push.APNSKeyFile, PLAIN},
+		{"apns-sandbox", "use the APNS sandbox gateways", &c.Push.APNSSandbox, PLAIN},
+		{"adm-client-id", "Amazon device messaging client ID", &c.Push.ADMClientID, KEY},
+		{"adm-client-secret", "Amazon device messaging client secret", &c.Push.ADMClientSecret, KEY},
+		{"mandrill-api-key", "mandrill API key", &c.Mail.MandrillAPIKey, KEY},
+		{"mail-domain", "users' email domain", &c.Mail.Domain, PLAIN},
+		{"text-domain", "domain sms replies are sent from", &c.Mail.TextDomain, PLAIN},
+		{"telapi-sid", "telapi account SID", &c.Telapi.SID, KEY},
+		{"telapi-auth-token", "telapi auth token", &c.Telapi.AuthToken, KEY},
+		{"forecast-api-key", "forecast.io API key", &c.Forecast.APIKey, KEY},
+		{"previews-enabled", "fetch link previews for messages", &c.Previews.Enabled, PLAIN},
+		{"previews-allow", "hosts link previews are fetched from, comma separated, every public host when empty", &c.Previews.Allow, PLAIN},
+		{"previews-deny", "hosts link previews are never fetched from, comma separated", &c.Previews.Deny, PLAIN},
+		{"previews-timeout-seconds", "seconds a link preview fetch can take", &c.Previews.TimeoutSeconds, PLAIN},
+		{"previews-max-kb", "KB of a page read for its link preview", &c.Previews.MaxKB, PLAIN},
+	}
+}
+
+func envName(name string) string {
+	return ENV_PREFIX + strings.ToUpper(strings.Replace(name, "-", "_", -1))
+}
+
+func (s setting) set(value string) error {
+	switch v := s.value.(type) {
+	case *string:
+		*v = value
+	case *int:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s: %v", s.name, err)
+		}
+		*v = i
+	case *bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: %v", s.name, err)
+		}
+		*v = b
+	}
+	return nil
+}
+
+func (s setting) isBool() bool {
+	_, ok := s.value.(*bool)
+	return ok
+}
+
+// Flags are the command line flags for every setting plus -config
+type Flags struct {
+	path *string
+	set  map[string]string
+}
+
+// holds a flag's value until Load, so flags only override what was given
+type flagValue struct {
+	name   string
+	isBool bool
+	set    map[string]string
+}
+
+func (f *flagValue) String() string   { return "" }
+func (f *flagValue) IsBoolFlag() bool { return f.isBool }
+func (f *flagValue) Set(value string) error {
+	f.set[f.name] = value
+	return nil
+}
+
+// RegisterFlags adds -config and a flag for every setting to fs
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{
+		path: fs.String("config", os.Getenv(ENV_PREFIX+"CONFIG"), "JSON config file, see config.example.json"),
+		set:  make(map[string]string),
+	}
+	defaults := Default()
+	for _, s := range defaults.settings() {
+		usage := s.usage + " (" + envName(s.name) + ")"
+		fs.Var(&flagValue{name: s.name, isBool: s.isBool(), set: f.set}, s.name, usage)
+	}
+	return f
+}
+
+// Load builds the config from the defaults, the -config file, the
+// environment and the flags that were set.  Call Validate before serving.
+func (f *Flags) Load() (*Config, error) {
+	c := Default()
+	if *f.path != "" {
+		if err := c.loadFile(*f.path); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range c.settings() {
+		if value, ok := os.LookupEnv(envName(s.name)); ok {
+			if err := s.set(value); err != nil {
+				return nil, errors.New(envName(s.name) + ": " + err.Error())
+			}
+		}
+		if value, ok := f.set[s.name]; ok {
+			if err := s.set(value); err != nil {
+				return nil, errors.New("-" + err.Error())
+			}
+		}
+	}
+	if c.Server.SockJSURL == "" {
+		c.Server.SockJSURL = strings.TrimRight(c.Server.PublicURL, "/") + "/vendor/plugins/sockjs.min.js"
+	}
+	return c, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields() // catch typos instead of silently using the default
+	if err := decoder.Decode(c); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
+
+// Validate checks everything the server can't start without.  Missing API
+// keys only disable their feature, see MissingKeys.
+func (c *Config) Validate() error {
+	problems := make([]string, 0)
+	for name, addr := range map[string]string{
+		"addr":               c.Server.Addr,
+		"redirect-addr":      c.Server.RedirectAddr,
+		"text-webhook-addr":  c.Server.TextWebhookAddr,
+		"email-webhook-addr": c.Server.EmailWebhookAddr,
+	} {
+		if addr == "" {
+			problems = append(problems, name+" is empty")
+		}
+	}
+	for name, path := range map[string]string{
+		"tls-cert-file": c.Server.TLSCertFile,
+		"tls-key-file":  c.Server.TLSKeyFile,
+	} {
+		if _, err := os.Stat(path); err != nil {
+			problems = append(problems, name+": "+err.Error())
+		}
+	}
+	for name, rawurl := range map[string]string{
+		"public-url": c.Server.PublicURL,
+		"sockjs-url": c.Server.SockJSURL,
+		"nats-url":   c.Nats.URL,
+	} {
+		if u, err := url.Parse(rawurl); err != nil || u.Scheme == "" || u.Host == "" {
+			problems = append(problems, name+" is not an absolute url: "+rawurl)
+		}
+	}
+	if c.Postgres.ConnectString == "" {
+		problems = append(problems, "postgres-connect-string is empty")
+	}
+	if c.Postgres.MaxOpenConns < 1 || c.Postgres.MaxIdleConns < 0 {
+		problems = append(problems, "postgres-max-open-conns must be at least 1 and postgres-max-idle-conns at least 0")
+	}
+	if c.Aerospike.Host == "" || c.Aerospike.Port < 1 || c.Aerospike.Port > 65535 {
+		problems = append(problems, "aerospike-host and aerospike-port must be a valid address")
+	}
+	if len(c.Session.Secret) < 32 {
+		problems = append(problems, "session-secret must be at least 32 characters")
+	}
+	if c.Session.TTLHours < 1 {
+		problems = append(problems, "session-ttl-hours must be at least 1")
+	}
+	if c.AWS.Bucket == "" || c.AWS.Region == "" {
+		problems = append(problems, "aws-bucket and aws-region are required")
+	}
+	if c.Mail.Domain == "" || c.Mail.TextDomain == "" {
+		problems = append(problems, "mail-domain and text-domain are required")
+	}
+	if c.Previews.Enabled && (c.Previews.TimeoutSeconds < 1 || c.Previews.MaxKB < 1) {
+		problems = append(problems, "previews-timeout-seconds and previews-max-kb must be at least 1")
+	}
+	if len(problems) > 0 {
+		return errors.New("invalid config: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// MissingKeys lists the API keys that aren't set, the features using them
+// won't work
+func (c *Config) MissingKeys() []string {
+	missing := make([]string, 0)
+	for _, s := range c.settings() {
+		if v, ok := s.value.(*string); ok && s.kind == KEY && *v == "" {
+			missing = append(missing, s.name)
+		}
+	}
+	return missing
+}
+
+// String is the config with secrets blanked out, for logging
+func (c *Config) String() string {
+	lines := make([]string, 0)
+	for _, s := range c.settings() {
+		value := ""
+		switch v := s.value.(type) {
+		case *string:
+			value = *v
+		case *int:
+			value = strconv.Itoa(*v)
+		case *bool:
+			value = strconv.FormatBool(*v)
+		}
+		if s.kind != PLAIN && value != "" {
+			value = "<set>"
+		}
+		lines = append(lines, s.name+"="+value)
+	}
+	return strings.Join(lines, " ")
+}
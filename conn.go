@@ -1,66 +1,50 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"gopkg.in/igm/sockjs-go.v2/sockjs"
 	"pingedchat/pcDatabase"
 )
 
-func sockHandler(session sockjs.Session) {
+func sockHandler(backend *pcDatabase.Backend, session sockjs.Session) {
 	TRACE.Println("new sockjs session established")
 
+	// cancelled when the socket closes, stops Run and the writer with it
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	db := pcDatabase.Database{}
-	db.Connect(&session)
+	db.Connect(ctx, backend, &session)
 	defer db.Close()
 
-	var validatedUser bool = false
-	var token string
-	ValidUser := pcDatabase.UserStruct{}
-	cmd := pcDatabase.CreateUserCmdStruct{}
-	for !validatedUser {
+	// only the public commands (CreateUser, ValidateUser, ResumeSession and
+	// the password reset) are accepted until the user logs in, see
+	// pcDatabase/commands.go
+	for db.Username() == "" {
 		// TRACE.Println("waiting for valid user")
 		if msg, err := session.Recv(); err == nil {
 			TRACE.Println("message received: " + msg)
-			err := json.Unmarshal([]byte(msg), &cmd)
-			if err != nil {
-				ERROR.Println(err)
+			if retstr := db.Dispatch(msg); retstr != "" {
+				session.Send(retstr)
 			}
-			TRACE.Println("cmd.Cmd = " + cmd.Cmd)
-			if cmd.Cmd == "CreateUser" {
-				ValidUser = db.CreateUser(msg)
-				if ValidUser.UsernameUpper == "" {
-					// invalid user
-					session.Send(`{"cmd":"CreateUser", "error":"Username already taken."}`)
-				} else {
-					validatedUser = true
-					token = cmd.Token
-					session.Send(ValidUser.ToJSONStringWithCmd("CreateUser"))
-				}
-			} else if cmd.Cmd == "ValidateUser" {
-				ValidUser = db.ValidateUser(msg)
-				if ValidUser.UsernameUpper != "" {
-					validatedUser = true
-					token = cmd.Token
-				}
-				session.Send(ValidUser.ToJSONStringWithCmd("ValidateUser")) // send back no matter what so we can show bad password on UI
-			} else if cmd.Cmd == "GetPasswordResetUser" {
-				session.Send(db.GetPasswordResetUser(msg))
-			} else if cmd.Cmd == "ResetUserPassword" {
-				session.Send(db.ResetUserPassword(msg))
-			}
-
 		} else {
 			return // exit this place
 		}
 	}
-	// TRACE.Println("found valid user")
-	// TRACE.Println(ValidUser.ToJSONString())
 	// we have a valid user at this point
 	// web token is already linked
 	// start our db handler
 	// go pcDatabase.Protect(db.Run)
-	go db.Run()
-	defer db.RemoveUserWebToken(ValidUser.UsernameUpper, token)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		db.Run()
+	}()
+	defer db.RemoveUserWebToken(db.Username(), db.Token())
+	// Run has to be done with db before db.Close()
+	defer func() {
+		cancel()
+		<-stopped
+	}()
 
 	for {
 		msg, err := session.Recv()
@@ -70,7 +54,11 @@ func sockHandler(session sockjs.Session) {
 			break
 		}
 		TRACE.Println("message received: " + msg)
-		db.Receive <- msg
+		select {
+		case db.Receive <- msg:
+		case <-db.Context().Done():
+			return // disconnected, eg. too slow or the session was revoked
+		}
 	}
 
 }
@@ -1,8 +1,8 @@
 package mailWebhooks
 
 import (
+	"context"
 	"encoding/base64"
-	aerospike "github.com/aerospike/aerospike-client-go"
 	_ "github.com/lib/pq"
 	"github.com/pquerna/ffjson/ffjson"
 	"html"
@@ -11,8 +11,8 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"pingedchat/config"
 	"pingedchat/pcDatabase"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -23,10 +23,18 @@ var (
 	ERROR = log.New(os.Stderr, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
 )
 
-var db pcDatabase.Database
+// Hooks handles the mandrill webhooks on top of the shared backend
+type Hooks struct {
+	backend *pcDatabase.Backend
+	config  *config.Config
+}
+
+func NewHooks(backend *pcDatabase.Backend, c *config.Config) *Hooks {
+	return &Hooks{backend: backend, config: c}
+}
 
 // handle post event
-func MandrillEmailPost(w http.ResponseWriter, req *http.Request) {
+func (h *Hooks) MandrillEmailPost(w http.ResponseWriter, req *http.Request) {
 	TRACE.Println("in MandrillEmailPost")
 	w.Write([]byte("OK")) // send 200 response
 
@@ -55,15 +63,14 @@ func MandrillEmailPost(w http.ResponseWriter, req *http.Request) {
 	// TRACE.Println(messages)
 
 	// get db
-	if !db.IsAerospikePostgresConnected() {
-		db.Connect(nil)
-	}
+	db := pcDatabase.Database{}
+	db.Connect(context.Background(), h.backend, nil)
 
 	// loop through each message
 	for _, msg := range messages {
 		// get user info
-		username := msg.Msg.Email[:strings.Index(msg.Msg.Email, "@pinged.email")]
-		user := db.GetAerospikeUser(username)
+		username := msg.Msg.Email[:strings.Index(msg.Msg.Email, "@"+h.config.Mail.Domain)]
+		user := db.Users.GetUser(username)
 		TRACE.Println("username:" + username)
 		TRACE.Println("username found: " + user.Username)
 		if user.Username == "" {
@@ -135,23 +142,21 @@ func MandrillEmailPost(w http.ResponseWriter, req *http.Request) {
 		// call helper function
 		db.AddEmailToDb(user.Username, msg.Msg.FromEmail, ToEmailStr, msg.Msg.Email, msg.Msg.Subject, msg_html, att_str, false, true, isSpam, t_s, t_s)
 
-		// send instant update to user
-		ret_str := `{"cmd":"EmailReceived",` +
-			`"FromEmail":"` + msg.Msg.FromEmail + `",` +
-			`"ToEmails":` + ToEmailStr + `,` +
-			`"RecvEmail":"` + msg.Msg.Email + `",` +
-			`"Subject":"` + strings.TrimSpace(msg.Msg.Subject) + `",` +
-			`"Content":"` + strings.TrimSpace(html.EscapeString(msg_html)) + `",` +
-			`"Attachments":` + att_str + `,` +
-			`"Spam":"` + strconv.FormatBool(isSpam) + `",` +
-			`"RecvTime":"` + t_s + `"` +
-			`}`
-		db.SendStringToWebDevices(user.Web, ret_str)
-		// update m_time for user
-		emailMtimeBin := aerospike.NewBin("EmailMtime", t_s)
-		key, err := aerospike.NewKey(pcDatabase.AEROSPIKE_USERS_NAMESPACE, pcDatabase.AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(user.UsernameUpper))
-		if err == nil {
-			db.UpdateAerospikeSingleBin(key, emailMtimeBin)
+		// send instant update to user, it's in their change log too
+		if mail_attachments == nil {
+			mail_attachments = make([]string, 0)
 		}
+		db.SendEmailReceived(user.Username, pcDatabase.EmailReceivedEvent{
+			FromEmail:   msg.Msg.FromEmail,
+			ToEmails:    ToEmails,
+			RecvEmail:   msg.Msg.Email,
+			Subject:     strings.TrimSpace(msg.Msg.Subject),
+			Content:     strings.TrimSpace(html.EscapeString(msg_html)),
+			Attachments: mail_attachments,
+			Spam:        isSpam,
+			RecvTime:    t_s,
+		})
+		// update m_time for user
+		db.Users.UpdateUserFields(user.UsernameUpper, pcDatabase.UserFields{"EmailMtime": t_s})
 	} // end for _, msg := range messages
 }
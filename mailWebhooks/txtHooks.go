@@ -1,6 +1,7 @@
 package mailWebhooks
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -10,7 +11,7 @@ import (
 )
 
 // handle post event
-func MandrillTextingPost(w http.ResponseWriter, req *http.Request) {
+func (h *Hooks) MandrillTextingPost(w http.ResponseWriter, req *http.Request) {
 	TRACE.Println("in MandrillTextingPost")
 	w.Write([]byte("OK")) // send 200 response
 
@@ -40,30 +41,33 @@ func MandrillTextingPost(w http.ResponseWriter, req *http.Request) {
 
 	// get db
 	db := pcDatabase.Database{}
-	db.Connect(nil)
+	db.Connect(context.Background(), h.backend, nil)
 	// loop through each message
 	for _, msg := range messages {
 		// get user info
 		phonenum := msg.Msg.FromEmail[:strings.Index(msg.Msg.FromEmail, "@")]
 		TRACE.Println("phonenum in main.go: " + phonenum)
 		TRACE.Println("msg.Msg.FromEmail: " + string(msg.Msg.FromEmail))
-		user := db.GetAerospikeUserByPhone(string(msg.Msg.FromEmail))
+		user := db.Users.GetUserByPhone(string(msg.Msg.FromEmail))
 		username := user.Username
 		CID := msg.Msg.Email[:strings.Index(msg.Msg.Email, "@")]
-		convoMembersStrings := db.GetAerospikeConvoMembers(CID)
+		convoMembersStrings := db.Convos.GetConvoMembers(CID)
 		convoMembers := pcDatabase.ToConvoMemberArray(convoMembersStrings)
 		var membersArr []string
 		for _, e := range convoMembers {
 			membersArr = append(membersArr, e.Username)
 		}
-		membersArrString, err := json.Marshal(membersArr)
-		if err != nil {
-			ERROR.Printf("error in json.Marshal(membersArr)", err)
-		}
 		// send message
-		data := `{ "cmd" : "SendMessage", "CID" : "` + CID + `", "f_username" : "` + username + `", "t_UIDs" : ` + string(membersArrString) + `, "content" : "` + msg.Msg.Text + `", "m_time": "` + pcDatabase.NowISO8601() + `"}`
-		TRACE.Println("data for SendMessage: " + data)
-		db.SendMessage(data)
+		data := pcDatabase.MessageStruct{
+			Cmd:          "SendMessage",
+			CID:          CID,
+			FromUsername: username,
+			ToUIDs:       membersArr,
+			Content:      pcDatabase.EscapeMarkup(msg.Msg.Text), // plain text, not markup
+			M_time:       pcDatabase.NowISO8601(),
+		}
+		TRACE.Printf("data for SendMessage: %+v", data)
+		db.SendMessage(&data)
 	}
 
 }
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"gopkg.in/igm/sockjs-go.v2/sockjs"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
+	"pingedchat/config"
 	"pingedchat/mailWebhooks"
+	"pingedchat/migrations"
 	"pingedchat/pcDatabase"
+	"strconv"
 	"time"
 )
 
@@ -17,57 +22,257 @@ var (
 	ERROR = log.New(os.Stderr, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
 )
 
-const (
-	PORT       = ":443"
-	PRIV_KEY   = "./PC_private_unencrypted.key"
-	PUBLIC_KEY = "./PC_public.pem"
-)
-
-func redir(w http.ResponseWriter, req *http.Request) {
-	http.Redirect(w, req, "https://FILL_ME.com", http.StatusMovedPermanently)
+func redirHTTP(cfg *config.Config) {
+	redir := http.RedirectHandler(cfg.Server.PublicURL, http.StatusMovedPermanently)
+	if err := http.ListenAndServe(cfg.Server.RedirectAddr, redir); err != nil {
+		log.Fatalf("ListenAndServe error: %v", err)
+	}
 }
 
-func redirHTTP() {
-	if err := http.ListenAndServe(":80", http.HandlerFunc(redir)); err != nil {
+func textWebhooksLoop(cfg *config.Config, hooks *mailWebhooks.Hooks) {
+	if err := http.ListenAndServe(cfg.Server.TextWebhookAddr, http.HandlerFunc(hooks.MandrillTextingPost)); err != nil {
 		log.Fatalf("ListenAndServe error: %v", err)
 	}
 }
 
-func textWebhooksLoop() {
-	if err := http.ListenAndServe(":8118", http.HandlerFunc(mailWebhooks.MandrillTextingPost)); err != nil {
+func emailWebhooksLoop(cfg *config.Config, hooks *mailWebhooks.Hooks) {
+	if err := http.ListenAndServe(cfg.Server.EmailWebhookAddr, http.HandlerFunc(hooks.MandrillEmailPost)); err != nil {
 		log.Fatalf("ListenAndServe error: %v", err)
 	}
 }
 
-func emailWebhooksLoop() {
-	if err := http.ListenAndServe(":8119", http.HandlerFunc(mailWebhooks.MandrillEmailPost)); err != nil {
-		log.Fatalf("ListenAndServe error: %v", err)
+// 200 while every backend answers, 503 otherwise, for the load balancer
+func healthHandler(backend *pcDatabase.Backend) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if err := backend.Healthy(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("OK"))
 	}
 }
 
-func sockjsServerLoop() {
+// calls, errors and time spent per command since startup
+func metricsHandler(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pcDatabase.CommandMetrics.Snapshot())
+}
+
+func sockjsServerLoop(cfg *config.Config, backend *pcDatabase.Backend) {
 	// start sockjs server
 	sockjsOptions := sockjs.DefaultOptions
-	sockjsOptions.SockJSURL = "https://FILL_ME.com/vendor/plugins/sockjs.min.js"
-	http.Handle("/ws/", sockjs.NewHandler("/ws", sockjsOptions, sockHandler))
-	http.Handle("/", http.FileServer(http.Dir("web/")))
-	if err := http.ListenAndServeTLS(PORT, PUBLIC_KEY, PRIV_KEY, nil); err != nil {
+	sockjsOptions.SockJSURL = cfg.Server.SockJSURL
+	http.Handle("/ws/", sockjs.NewHandler("/ws", sockjsOptions, func(session sockjs.Session) {
+		sockHandler(backend, session)
+	}))
+	http.Handle("/health", healthHandler(backend))
+	http.HandleFunc("/metrics", metricsHandler)
+	http.Handle("/", http.FileServer(http.Dir(cfg.Server.WebDir)))
+	if err := http.ListenAndServeTLS(cfg.Server.Addr, cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile, nil); err != nil {
 		ERROR.Printf("ListenAndServe:", err)
 	}
 }
 
+var (
+	devMode     = flag.Bool("dev", false, "keep all data in memory instead of using aerospike, postgres and gnatsd")
+	configFlags = config.RegisterFlags(flag.CommandLine)
+)
+
+func openMigrator(cfg *config.Config) *migrations.Migrator {
+	migrator, err := migrations.Open(cfg.Postgres.ConnectString, cfg.Aerospike.Host, cfg.Aerospike.Port)
+	if err != nil {
+		ERROR.Fatalln("error opening databases for migrations:", err)
+	}
+	return migrator
+}
+
+// exits unless every migration has been applied
+func checkSchema(cfg *config.Config) {
+	migrator := openMigrator(cfg)
+	defer migrator.Close()
+	if err := migrator.Check(); err != nil {
+		ERROR.Fatalln(err)
+	}
+}
+
+// pingedchat migrate [-to version] [up|down|status]
+// up applies everything pending (or up to -to), down reverts the latest
+// migration (or everything after -to), status lists them all
+func migrate(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	to := flags.Int("to", -1, "version to migrate up or down to")
+	flags.Parse(args)
+	migrator := openMigrator(cfg)
+	defer migrator.Close()
+	current, err := migrator.Current()
+	if err != nil {
+		ERROR.Fatalln(err)
+	}
+	switch flags.Arg(0) {
+	case "", "up":
+		if *to < 0 {
+			*to = migrations.Latest()
+		}
+		err = migrator.Up(*to)
+	case "down":
+		if *to < 0 {
+			*to = current - 1
+		}
+		err = migrator.Down(*to)
+	case "status":
+		pending, err := migrator.Pending()
+		if err != nil {
+			ERROR.Fatalln(err)
+		}
+		isPending := make(map[int]bool)
+		for _, migration := range pending {
+			isPending[migration.Version] = true
+		}
+		for _, migration := range migrations.All() {
+			state := "applied"
+			if isPending[migration.Version] {
+				state = "pending"
+			}
+			log.Println(strconv.Itoa(migration.Version) + " " + migration.Name + ": " + state)
+		}
+		return
+	default:
+		ERROR.Fatalln("unknown migrate command " + flags.Arg(0) + ", expected up, down or status")
+	}
+	if err != nil {
+		ERROR.Fatalln(err)
+	}
+	current, err = migrator.Current()
+	if err != nil {
+		ERROR.Fatalln(err)
+	}
+	log.Printf("schema is at version %d, latest is %d", current, migrations.Latest())
+}
+
+// pingedchat migrate-messages [-drop]
+// copies the old per conversation postgres tables into the messages table
+func migrateMessages(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("migrate-messages", flag.ExitOnError)
+	drop := flags.Bool("drop", false, "drop the old tables once copied, only when no old servers are running")
+	flags.Parse(args)
+	checkSchema(cfg)
+	store, err := pcDatabase.OpenPostgresStore(cfg.Postgres)
+	if err != nil {
+		ERROR.Fatalln("error opening postgres:", err)
+	}
+	defer store.Close()
+	migrated, err := store.MigrateLegacyConvoTables(*drop)
+	log.Printf("migrated %d conversations", migrated)
+	if err != nil {
+		ERROR.Fatalln(err)
+	}
+}
+
+// pingedchat migrate-markup
+// converts the messages that are still HTML to markup
+func migrateMarkup(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("migrate-markup", flag.ExitOnError)
+	flags.Parse(args)
+	checkSchema(cfg)
+	store, err := pcDatabase.OpenPostgresStore(cfg.Postgres)
+	if err != nil {
+		ERROR.Fatalln("error opening postgres:", err)
+	}
+	defer store.Close()
+	converted, err := store.MigrateLegacyMarkup()
+	log.Printf("converted %d messages", converted)
+	if err != nil {
+		ERROR.Fatalln(err)
+	}
+}
+
+// pingedchat migrate-users
+// rewrites users still stored with JSON strings as aerospike maps and lists
+func migrateUsers(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("migrate-users", flag.ExitOnError)
+	flags.Parse(args)
+	checkSchema(cfg)
+	store, err := pcDatabase.OpenAerospikeStore(cfg.Aerospike)
+	if err != nil {
+		ERROR.Fatalln("error opening aerospike:", err)
+	}
+	defer store.Close()
+	converted, err := store.ConvertLegacyUserBins()
+	log.Printf("converted %d users", converted)
+	if err != nil {
+		ERROR.Fatalln(err)
+	}
+}
+
+// prints the command catalogue, this is what commands.json is generated from
+func printCommands() {
+	catalogue, err := json.MarshalIndent(pcDatabase.Catalogue(), "", "  ")
+	if err != nil {
+		ERROR.Fatalln(err)
+	}
+	os.Stdout.Write(append(catalogue, '\n'))
+}
+
 func main() {
+	flag.Parse()
+	cfg, err := configFlags.Load()
+	if err != nil {
+		ERROR.Fatalln(err)
+	}
+	switch flag.Arg(0) {
+	case "migrate":
+		migrate(cfg, flag.Args()[1:])
+		return
+	case "migrate-messages":
+		migrateMessages(cfg, flag.Args()[1:])
+		return
+	case "migrate-markup":
+		migrateMarkup(cfg, flag.Args()[1:])
+		return
+	case "migrate-users":
+		migrateUsers(cfg, flag.Args()[1:])
+		return
+	case "commands":
+		printCommands()
+		return
+	}
+	if err := cfg.Validate(); err != nil {
+		ERROR.Fatalln(err)
+	}
+	for _, key := range cfg.MissingKeys() {
+		ERROR.Println(key + " isn't set, the features using it won't work")
+	}
+	TRACE.Println("config: " + cfg.String())
+	pcDatabase.Configure(cfg)
+	// one set of connections shared by every session
+	var backend *pcDatabase.Backend
+	if *devMode {
+		// everything is lost on restart, only for development
+		stores := pcDatabase.NewMemoryStore().Stores()
+		stores.Previews = pcDatabase.NewLinkFetcher(cfg.Previews)
+		backend = pcDatabase.NewBackend(stores)
+	} else {
+		// don't run against tables the code doesn't know about yet
+		checkSchema(cfg)
+		backend, err = pcDatabase.OpenBackend(cfg)
+		if err != nil {
+			ERROR.Fatalln("error connecting to the databases:", err)
+		}
+	}
+	defer backend.Close()
+	backend.StartHealthChecks(pcDatabase.HEALTH_CHECK_INTERVAL)
+	hooks := mailWebhooks.NewHooks(backend, cfg)
 	// to disable trace messages
 	log.SetOutput(ioutil.Discard)
 	// http.HandleFunc("/ws", wsHandler)
 	// start ADM token gette
 	go pcDatabase.Protect(pcDatabase.ADMinit)
-	go pcDatabase.Protect(pcDatabase.StartMessagesTicker)
+	go pcDatabase.Protect(func() { pcDatabase.StartMessagesTicker(backend) })
 	// start HTTP redirect
-	go pcDatabase.Protect(redirHTTP)
-	go pcDatabase.Protect(textWebhooksLoop)
-	go pcDatabase.Protect(emailWebhooksLoop)
-	go pcDatabase.Protect(sockjsServerLoop)
+	go pcDatabase.Protect(func() { redirHTTP(cfg) })
+	go pcDatabase.Protect(func() { textWebhooksLoop(cfg, hooks) })
+	go pcDatabase.Protect(func() { emailWebhooksLoop(cfg, hooks) })
+	go pcDatabase.Protect(func() { sockjsServerLoop(cfg, backend) })
 
 	// go exits when the main function is done, so I guess we'll just keep this party going forever!
 	for {
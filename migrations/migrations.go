@@ -0,0 +1,304 @@
+// Package migrations keeps the postgres and aerospike schema in step with
+// the code.  Every change to the schema is a numbered Migration with an up
+// and a down step, and the versions that have been applied are recorded in
+// postgres, in the schema_migrations table.
+//
+// Run "pingedchat migrate" to bring a database up to date, the server
+// refuses to start while the schema is behind.
+package migrations
+
+import (
+	"crypto/md5"
+	"database/sql"
+	"errors"
+	"fmt"
+	aerospike "github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/types"
+	_ "github.com/lib/pq"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+)
+
+var (
+	// for logging
+	TRACE = log.New(os.Stdout, "TRACE: ", log.Ldate|log.Ltime|log.Lshortfile)
+	ERROR = log.New(os.Stderr, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
+)
+
+const (
+	VERSIONS_TABLE = "schema_migrations"
+	// pg_advisory_xact_lock key, so two migrate runs can't step on each other
+	LOCK_KEY = 7319024
+)
+
+var ErrSchemaBehind = errors.New("database schema is behind, run \"pingedchat migrate\"")
+
+// Target is what a migration step works on.  Tx is the postgres transaction
+// the new version gets recorded in, so postgres changes are all or nothing.
+// Aerospike changes can't be rolled back, so those steps need to be safe to
+// run again.
+type Target struct {
+	Tx        *sql.Tx
+	Aerospike *aerospike.Client
+}
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(t *Target) error
+	Down    func(t *Target) error
+}
+
+// Latest is the version the code expects
+func Latest() int {
+	return all[len(all)-1].Version
+}
+
+// All returns every migration, oldest first
+func All() []Migration {
+	return append([]Migration(nil), all...)
+}
+
+type Migrator struct {
+	postgres  *sql.DB
+	aerospike *aerospike.Client
+}
+
+// Open connects to postgres and aerospike
+func Open(postgresConnect string, aerospikeHost string, aerospikePort int) (*Migrator, error) {
+	postgres, err := sql.Open("postgres", postgresConnect)
+	if err != nil {
+		return nil, err
+	}
+	aerospikeClient, err := aerospike.NewClient(aerospikeHost, aerospikePort)
+	if err != nil {
+		postgres.Close()
+		return nil, err
+	}
+	return &Migrator{postgres: postgres, aerospike: aerospikeClient}, nil
+}
+
+func (m *Migrator) Close() {
+	m.postgres.Close()
+	m.aerospike.Close()
+}
+
+func (m *Migrator) createVersionsTable() error {
+	_, err := m.postgres.Exec(`CREATE TABLE IF NOT EXISTS ` + VERSIONS_TABLE + ` (version integer PRIMARY KEY, name varchar NOT NULL, applied_at timestamptz NOT NULL DEFAULT now());`)
+	return err
+}
+
+func appliedVersions(q interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}) (map[int]bool, error) {
+	applied := make(map[int]bool)
+	rows, err := q.Query(`SELECT version FROM ` + VERSIONS_TABLE + `;`)
+	if err != nil {
+		return applied, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return applied, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// Current returns the highest version applied, 0 for a fresh database
+func (m *Migrator) Current() (int, error) {
+	if err := m.createVersionsTable(); err != nil {
+		return 0, err
+	}
+	applied, err := appliedVersions(m.postgres)
+	if err != nil {
+		return 0, err
+	}
+	current := 0
+	for version := range applied {
+		if version > current {
+			current = version
+		}
+	}
+	return current, nil
+}
+
+// Pending returns the migrations not applied yet, oldest first
+func (m *Migrator) Pending() ([]Migration, error) {
+	if err := m.createVersionsTable(); err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(m.postgres)
+	if err != nil {
+		return nil, err
+	}
+	pending := make([]Migration, 0)
+	for _, migration := range all {
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Check returns ErrSchemaBehind if any migration hasn't been applied
+func (m *Migrator) Check() error {
+	pending, err := m.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%v: %d migration(s) pending, the first is %d %s", ErrSchemaBehind, len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
+}
+
+// runs a single step in its own transaction, skipping it if another run
+// already got there first
+func (m *Migrator) run(migration Migration, up bool) error {
+	tx, err := m.postgres.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(`SELECT pg_advisory_xact_lock($1);`, LOCK_KEY); err != nil {
+		tx.Rollback()
+		return err
+	}
+	applied, err := appliedVersions(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if applied[migration.Version] == up {
+		return tx.Rollback() // nothing to do
+	}
+	target := &Target{Tx: tx, Aerospike: m.aerospike}
+	if up {
+		err = migration.Up(target)
+		if err == nil {
+			_, err = tx.Exec(`INSERT INTO `+VERSIONS_TABLE+` (version, name) VALUES ($1, $2);`, migration.Version, migration.Name)
+		}
+	} else {
+		err = migration.Down(target)
+		if err == nil {
+			_, err = tx.Exec(`DELETE FROM `+VERSIONS_TABLE+` WHERE version = $1;`, migration.Version)
+		}
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Up applies every pending migration up to and including version to
+func (m *Migrator) Up(to int) error {
+	pending, err := m.Pending()
+	if err != nil {
+		return err
+	}
+	for _, migration := range pending {
+		if migration.Version > to {
+			break
+		}
+		TRACE.Println("applying migration " + strconv.Itoa(migration.Version) + " " + migration.Name)
+		if err := m.run(migration, true); err != nil {
+			return fmt.Errorf("migration %d %s: %v", migration.Version, migration.Name, err)
+		}
+	}
+	return nil
+}
+
+// Down reverts every applied migration newer than version to, newest first
+func (m *Migrator) Down(to int) error {
+	if err := m.createVersionsTable(); err != nil {
+		return err
+	}
+	applied, err := appliedVersions(m.postgres)
+	if err != nil {
+		return err
+	}
+	reverting := make([]Migration, 0)
+	for _, migration := range all {
+		if migration.Version > to && applied[migration.Version] {
+			reverting = append(reverting, migration)
+		}
+	}
+	sort.Slice(reverting, func(i, j int) bool {
+		return reverting[i].Version > reverting[j].Version
+	})
+	for _, migration := range reverting {
+		TRACE.Println("reverting migration " + strconv.Itoa(migration.Version) + " " + migration.Name)
+		if err := m.run(migration, false); err != nil {
+			return fmt.Errorf("migration %d %s: %v", migration.Version, migration.Name, err)
+		}
+	}
+	return nil
+}
+
+// aerospike helpers, creating an index that's already there (or dropping one
+// that isn't) is fine since aerospike steps can run more than once
+func createAerospikeIndex(client *aerospike.Client, namespace string, set string, indexName string, binName string, indexType aerospike.IndexType) error {
+	task, err := client.CreateIndex(nil, namespace, set, indexName, binName, indexType)
+	if err != nil {
+		if ae, ok := err.(types.AerospikeError); ok && ae.ResultCode() == types.INDEX_FOUND {
+			return nil
+		}
+		return err
+	}
+	return <-task.OnComplete()
+}
+
+func dropAerospikeIndex(client *aerospike.Client, namespace string, set string, indexName string) error {
+	err := client.DropIndex(nil, namespace, set, indexName)
+	if ae, ok := err.(types.AerospikeError); ok && ae.ResultCode() == types.INDEX_NOTFOUND {
+		return nil
+	}
+	return err
+}
+
+func execAll(tx *sql.Tx, statements ...string) error {
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runs step on every mailbox table, "<username>@pinged.email"
+func forEachMailbox(tx *sql.Tx, step func(table string) error) error {
+	rows, err := tx.Query(`SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() AND table_name LIKE '%@pinged.email';`)
+	if err != nil {
+		return err
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			return err
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if err := step(table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// names of the indexes on a mailbox, usernames can be long enough that the
+// table name plus a suffix goes past postgres' 63 byte identifiers
+func mailboxIndexName(table string, suffix string) string {
+	return fmt.Sprintf("mailbox_%x_%s", md5.Sum([]byte(table)), suffix)
+}
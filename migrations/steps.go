@@ -0,0 +1,200 @@
+package migrations
+
+import (
+	aerospike "github.com/aerospike/aerospike-client-go"
+	"github.com/lib/pq"
+	"strconv"
+)
+
+// every schema change, oldest first.  Never edit or renumber a migration
+// that has shipped, add a new one instead.  Table and index names are spelled
+// out rather than taken from pcDatabase so old steps stay what they were.
+//
+// the steps use IF NOT EXISTS because servers used to create these tables
+// themselves, so databases from before migrations already have them.
+var all = []Migration{
+	{
+		Version: 1,
+		Name:    "create ScheduledMessagesTable",
+		Up: func(t *Target) error {
+			return execAll(t.Tx,
+				`CREATE TABLE IF NOT EXISTS ScheduledMessagesTable (CID varchar NOT NULL, f_username varchar NOT NULL, content varchar NOT NULL, m_time timestamptz NOT NULL, PRIMARY KEY (f_username, m_time) );`)
+		},
+		Down: func(t *Target) error {
+			return execAll(t.Tx, `DROP TABLE IF EXISTS ScheduledMessagesTable;`)
+		},
+	},
+	{
+		Version: 2,
+		Name:    "create partitioned messages table",
+		Up: func(t *Target) error {
+			// every message of every conversation lives here, hash partitioned
+			// on cid so a conversation's messages always sit in the same partition.
+			// (cid, f_username, m_time) is what the old per conversation tables
+			// used as their primary key, keeping it unique makes copying them
+			// over idempotent
+			statements := []string{
+				`CREATE TABLE IF NOT EXISTS messages (cid varchar NOT NULL, mid varchar NOT NULL, f_username varchar NOT NULL, m_time timestamptz NOT NULL, content varchar NOT NULL, PRIMARY KEY (cid, mid), UNIQUE (cid, f_username, m_time) ) PARTITION BY HASH (cid);`,
+			}
+			for i := 0; i < 16; i++ {
+				statements = append(statements, `CREATE TABLE IF NOT EXISTS messages_p`+strconv.Itoa(i)+` PARTITION OF messages FOR VALUES WITH (MODULUS 16, REMAINDER `+strconv.Itoa(i)+`);`)
+			}
+			statements = append(statements, `CREATE INDEX IF NOT EXISTS messages_cid_m_time ON messages (cid, m_time);`)
+			return execAll(t.Tx, statements...)
+		},
+		Down: func(t *Target) error {
+			// drops the partitions with it
+			return execAll(t.Tx, `DROP TABLE IF EXISTS messages;`)
+		},
+	},
+	{
+		Version: 3,
+		Name:    "aerospike phone and email indexes on users.username",
+		Up: func(t *Target) error {
+			// GetUserByPhone and GetUserByEmail query these
+			if err := createAerospikeIndex(t.Aerospike, "users", "username", "phoneindex", "Phone", aerospike.STRING); err != nil {
+				return err
+			}
+			return createAerospikeIndex(t.Aerospike, "users", "username", "emailindex", "Email", aerospike.STRING)
+		},
+		Down: func(t *Target) error {
+			if err := dropAerospikeIndex(t.Aerospike, "users", "username", "phoneindex"); err != nil {
+				return err
+			}
+			return dropAerospikeIndex(t.Aerospike, "users", "username", "emailindex")
+		},
+	},
+	{
+		Version: 4,
+		Name:    "message edits and deletes",
+		Up: func(t *Target) error {
+			// e_time is when a message was last edited or deleted, and
+			// message_edits keeps the content each edit replaced, e_time there
+			// being when it was replaced
+			return execAll(t.Tx,
+				`ALTER TABLE messages ADD COLUMN IF NOT EXISTS e_time timestamptz, ADD COLUMN IF NOT EXISTS deleted boolean NOT NULL DEFAULT false;`,
+				`CREATE TABLE IF NOT EXISTS message_edits (cid varchar NOT NULL, mid varchar NOT NULL, content varchar NOT NULL, e_time timestamptz NOT NULL, PRIMARY KEY (cid, mid, e_time) );`)
+		},
+		Down: func(t *Target) error {
+			return execAll(t.Tx,
+				`DROP TABLE IF EXISTS message_edits;`,
+				`ALTER TABLE messages DROP COLUMN IF EXISTS e_time, DROP COLUMN IF EXISTS deleted;`)
+		},
+	},
+	{
+		Version: 5,
+		Name:    "message reactions",
+		Up: func(t *Target) error {
+			// one row per user per emoji per message
+			return execAll(t.Tx,
+				`CREATE TABLE IF NOT EXISTS message_reactions (cid varchar NOT NULL, mid varchar NOT NULL, username varchar NOT NULL, emoji varchar NOT NULL, r_time timestamptz NOT NULL, PRIMARY KEY (cid, mid, username, emoji) );`)
+		},
+		Down: func(t *Target) error {
+			return execAll(t.Tx, `DROP TABLE IF EXISTS message_reactions;`)
+		},
+	},
+	{
+		Version: 6,
+		Name:    "threaded replies",
+		Up: func(t *Target) error {
+			// a reply has p_mid set to its thread's message, which keeps
+			// count of its replies and when the last one came in
+			return execAll(t.Tx,
+				`ALTER TABLE messages ADD COLUMN IF NOT EXISTS p_mid varchar, ADD COLUMN IF NOT EXISTS reply_count integer NOT NULL DEFAULT 0, ADD COLUMN IF NOT EXISTS last_reply timestamptz;`,
+				`CREATE INDEX IF NOT EXISTS messages_cid_p_mid_m_time ON messages (cid, p_mid, m_time);`)
+		},
+		Down: func(t *Target) error {
+			return execAll(t.Tx,
+				`DROP INDEX IF EXISTS messages_cid_p_mid_m_time;`,
+				`ALTER TABLE messages DROP COLUMN IF EXISTS p_mid, DROP COLUMN IF EXISTS reply_count, DROP COLUMN IF EXISTS last_reply;`)
+		},
+	},
+	{
+		Version: 7,
+		Name:    "message search",
+		Up: func(t *Target) error {
+			// the 'simple' configuration doesn't stem, so SearchMessages can
+			// highlight exactly the words that matched.  Rows copied from the
+			// old per conversation tables later get theirs from migrate-messages
+			return execAll(t.Tx,
+				`ALTER TABLE messages ADD COLUMN IF NOT EXISTS search tsvector;`,
+				`UPDATE messages SET search = to_tsvector('simple', content) WHERE NOT deleted;`,
+				`CREATE INDEX IF NOT EXISTS messages_search ON messages USING GIN (search);`)
+		},
+		Down: func(t *Target) error {
+			return execAll(t.Tx,
+				`DROP INDEX IF EXISTS messages_search;`,
+				`ALTER TABLE messages DROP COLUMN IF EXISTS search;`)
+		},
+	},
+	{
+		Version: 8,
+		Name:    "mailbox paging and search indexes",
+		Up: func(t *Target) error {
+			// every "<username>@pinged.email" table, new mailboxes get these
+			// from CreateMailbox
+			return forEachMailbox(t.Tx, func(table string) error {
+				return execAll(t.Tx,
+					`CREATE INDEX IF NOT EXISTS `+pq.QuoteIdentifier(mailboxIndexName(table, "m_time"))+` ON `+pq.QuoteIdentifier(table)+` (m_time);`,
+					`CREATE INDEX IF NOT EXISTS `+pq.QuoteIdentifier(mailboxIndexName(table, "search"))+` ON `+pq.QuoteIdentifier(table)+` USING GIN (to_tsvector('simple', coalesce(subject, '') || ' ' || coalesce(content, '') || ' ' || from_email || ' ' || to_emails));`)
+			})
+		},
+		Down: func(t *Target) error {
+			return forEachMailbox(t.Tx, func(table string) error {
+				return execAll(t.Tx,
+					`DROP INDEX IF EXISTS `+pq.QuoteIdentifier(mailboxIndexName(table, "m_time"))+`;`,
+					`DROP INDEX IF EXISTS `+pq.QuoteIdentifier(mailboxIndexName(table, "search"))+`;`)
+			})
+		},
+	},
+	{
+		Version: 9,
+		Name:    "per user change log",
+		Up: func(t *Target) error {
+			// user_change_seqs hands out each user's next seq, the row lock
+			// keeps a user's changes committing in seq order
+			return execAll(t.Tx,
+				`CREATE TABLE IF NOT EXISTS user_change_seqs (username varchar PRIMARY KEY, seq bigint NOT NULL);`,
+				`CREATE TABLE IF NOT EXISTS user_changes (username varchar NOT NULL, seq bigint NOT NULL, kind varchar NOT NULL, event varchar NOT NULL, c_time timestamptz NOT NULL, PRIMARY KEY (username, seq) );`)
+		},
+		Down: func(t *Target) error {
+			return execAll(t.Tx,
+				`DROP TABLE IF EXISTS user_changes;`,
+				`DROP TABLE IF EXISTS user_change_seqs;`)
+		},
+	},
+	{
+		Version: 10,
+		Name:    "per device event queues",
+		Up: func(t *Target) error {
+			// same as the change log, device_event_seqs hands out the seqs.
+			// Queues are per user and device since a device can have more
+			// than one user on it
+			return execAll(t.Tx,
+				`CREATE TABLE IF NOT EXISTS device_event_seqs (username varchar NOT NULL, device varchar NOT NULL, seq bigint NOT NULL, PRIMARY KEY (username, device) );`,
+				`CREATE TABLE IF NOT EXISTS device_events (username varchar NOT NULL, device varchar NOT NULL, seq bigint NOT NULL, event varchar NOT NULL, q_time timestamptz NOT NULL, PRIMARY KEY (username, device, seq) );`)
+		},
+		Down: func(t *Target) error {
+			return execAll(t.Tx,
+				`DROP TABLE IF EXISTS device_events;`,
+				`DROP TABLE IF EXISTS device_event_seqs;`)
+		},
+	},
+	{
+		Version: 11,
+		Name:    "markup messages and attachments",
+		Up: func(t *Target) error {
+			// every row so far is HTML.  The server sets markup on what it
+			// writes, anything without it (older servers during the deploy,
+			// migrate-messages) is HTML and converted when it's read
+			return execAll(t.Tx,
+				`ALTER TABLE messages ADD COLUMN IF NOT EXISTS attachments varchar, ADD COLUMN IF NOT EXISTS markup boolean NOT NULL DEFAULT false;`,
+				`ALTER TABLE message_edits ADD COLUMN IF NOT EXISTS markup boolean NOT NULL DEFAULT false;`)
+		},
+		Down: func(t *Target) error {
+			return execAll(t.Tx,
+				`ALTER TABLE message_edits DROP COLUMN IF EXISTS markup;`,
+				`ALTER TABLE messages DROP COLUMN IF EXISTS attachments, DROP COLUMN IF EXISTS markup;`)
+		},
+	},
+}
@@ -15,12 +15,6 @@ var (
 
 	AMAZON_ADM_URL   = "https://api.amazon.com/messaging/registrations/"
 	AMAZON_TOKEN_URL = "https://api.amazon.com/auth/O2/token"
-
-	ADMDebugClientID      = "FILL_ME"
-	ADMProductionClientID = "FILL_ME"
-
-	ADMDebugClientSecret      = "FILL_ME" // MBP
-	ADMProductionClientSecret = "FILL_ME"
 )
 
 type ADMServerStruct struct {
@@ -115,8 +109,8 @@ func updateToken() error {
 	data := url.Values{}
 	data.Set("grant_type", "client_credentials")
 	data.Set("scope", "messaging:push")
-	data.Set("client_id", ADMDebugClientID)
-	data.Set("client_secret", ADMDebugClientSecret)
+	data.Set("client_id", conf.Push.ADMClientID)
+	data.Set("client_secret", conf.Push.ADMClientSecret)
 
 	request, err := http.NewRequest("POST", AMAZON_TOKEN_URL, bytes.NewBufferString(data.Encode()))
 	if err != nil {
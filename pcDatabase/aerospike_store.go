@@ -0,0 +1,566 @@
+package pcDatabase
+
+import (
+	"errors"
+	aerospike "github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/types"
+	"pingedchat/config"
+	"strings"
+)
+
+const (
+	AEROSPIKE_USERS_NAMESPACE      = "users"
+	AEROSPIKE_USERS_USERNAME_TABLE = "username"
+	AEROSPIKE_USERS_ACTIVE_TABLE   = "active"
+	AEROSPIKE_USERS_PHONES_TABLE   = "phones"
+
+	AEROSPIKE_USERS_USERNAME_EMAIL_BIN = "Email"
+	AEROSPIKE_USERS_USERNAME_PHONE_BIN = "Phone"
+
+	AEROSPIKE_CONVOS_NAMESPACE   = "convos"
+	AEROSPIKE_CONVOS_MEMBERS_KEY = "members"
+	AEROSPIKE_CONVOS_NAME_KEY    = "name"
+	AEROSPIKE_CONVOS_MTIME_KEY   = "m_time"
+	AEROSPIKE_CONVOS_FILES_KEY   = "files"
+	AEROSPIKE_CONVOS_BOTS_KEY    = "bots"
+)
+
+// AerospikeStore is the UserStore and ConversationStore backed by aerospike
+type AerospikeStore struct {
+	conn *aerospike.Client
+}
+
+func NewAerospikeStore(conn *aerospike.Client) *AerospikeStore {
+	return &AerospikeStore{conn: conn}
+}
+
+// connects to the aerospike cluster
+func OpenAerospikeStore(c config.AerospikeConfig) (*AerospikeStore, error) {
+	conn, err := aerospike.NewClient(c.Host, c.Port)
+	if err != nil {
+		return nil, err
+	}
+	return NewAerospikeStore(conn), nil
+}
+
+func (s *AerospikeStore) Close() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+func (s *AerospikeStore) Ping() error {
+	if s.conn == nil || !s.conn.IsConnected() {
+		return errors.New("not connected to aerospike")
+	}
+	return nil
+}
+
+// low level helpers
+func (s *AerospikeStore) ReadAerospike(key *aerospike.Key) *aerospike.Record {
+	if s.conn != nil {
+		rec, err := s.conn.Get(nil, key)
+		if err == nil {
+			return rec
+		} else {
+			ERROR.Println("error in ReadAerospike")
+			ERROR.Println(err)
+			return nil
+		}
+	} else {
+		ERROR.Println("s.conn == nil in ReadAerospike")
+		return nil
+	}
+}
+
+func (s *AerospikeStore) WriteAerospikeMultipleBins(key *aerospike.Key, value aerospike.BinMap) bool {
+	if s.conn != nil {
+		writePolicy := &aerospike.WritePolicy{
+			BasePolicy:         *aerospike.NewPolicy(),
+			RecordExistsAction: aerospike.UPDATE,
+			GenerationPolicy:   aerospike.NONE,
+			CommitLevel:        aerospike.COMMIT_ALL,
+			Generation:         0,
+			Expiration:         0,
+			SendKey:            false,
+		}
+		err := s.conn.Put(writePolicy, key, value)
+		if err == nil {
+			return true
+		} else {
+			ERROR.Println("error in WriteAerospikeMultipleBins")
+			ERROR.Println(err)
+			return false
+		}
+	} else {
+		ERROR.Println("s.conn == nil in WriteAerospikeMultipleBins")
+		return false
+	}
+}
+
+func (s *AerospikeStore) UpdateAerospikeSingleBin(key *aerospike.Key, value *aerospike.Bin) bool {
+	if s.conn != nil {
+		writePolicy := &aerospike.WritePolicy{
+			BasePolicy:         *aerospike.NewPolicy(),
+			RecordExistsAction: aerospike.UPDATE, // https://github.com/aerospike/aerospike-client-go/blob/master/record_exists_action.go
+			GenerationPolicy:   aerospike.NONE,
+			CommitLevel:        aerospike.COMMIT_ALL,
+			Generation:         1,
+			Expiration:         0,
+			SendKey:            false,
+		}
+		err := s.conn.PutBins(writePolicy, key, value)
+		if err == nil {
+			return true
+		} else {
+			ERROR.Println("error in UpdateAerospikeSingleBin")
+			ERROR.Println(err)
+			return false
+		}
+	} else {
+		ERROR.Println("s.conn == nil in UpdateAerospikeSingleBin")
+		return false
+	}
+}
+
+func (s *AerospikeStore) DeleteAerospike(key *aerospike.Key) bool {
+	if s.conn != nil {
+		existed, err := s.conn.Delete(nil, key)
+		if err == nil {
+			return existed
+		} else {
+			ERROR.Println("error in DeleteAerospike")
+			ERROR.Println(err)
+			return false
+		}
+	} else {
+		ERROR.Println("s.conn == nil in DeleteAerospike")
+		return false
+	}
+}
+
+// runs an equality query on a user bin, should only be one result
+func (s *AerospikeStore) queryUserByBin(binName string, value string) UserStruct {
+	defer func() {
+		if r := recover(); r != nil {
+			ERROR.Println("Recovered in queryUserByBin", r)
+		}
+	}()
+	stmt := aerospike.NewStatement(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE)
+	stmt.Addfilter(aerospike.NewEqualFilter(binName, value))
+	if s.conn == nil {
+		return UserStruct{} // can't do anything without a connection :(
+	}
+	// now query
+	rs, err := s.conn.Query(nil, stmt)
+	// deal with error
+	if err != nil {
+		ERROR.Println("Error on s.conn.Query in queryUserByBin: ")
+		ERROR.Println(err)
+	}
+
+	// should only be one result
+	for res := range rs.Results() {
+		if res.Err != nil {
+			// handle error here
+			// if you want to exit, cancel the recordset to release the resources
+			ERROR.Println("Error on s.conn.Query in res.Err: ")
+			ERROR.Println(res.Err)
+			rs.Close()
+			return UserStruct{} // there was an error
+		} else {
+			// process record here
+			user := FillUserWithAerospikeBins(res.Record.Bins)
+			rs.Close()
+			return user
+		}
+	}
+	// if here, we didn't return a user above, so return nothing
+	return UserStruct{}
+}
+
+// UserStore
+func (s *AerospikeStore) GetUser(username string) UserStruct {
+	defer func() {
+		if r := recover(); r != nil {
+			ERROR.Println("Recovered in GetUser", r)
+		}
+	}()
+	if strings.TrimSpace(username) == "" {
+		return UserStruct{} // return empty user if they passed in an empty username
+	}
+	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(username))
+	if err == nil {
+		rec := s.ReadAerospike(key)
+		if rec != nil {
+			user := FillUserWithAerospikeBins(rec.Bins)
+			return user
+		} else {
+			return UserStruct{}
+		}
+	} else {
+		ERROR.Println(err)
+		return UserStruct{}
+	}
+}
+
+func (s *AerospikeStore) GetUserGeneration(username string) (UserStruct, uint32) {
+	if strings.TrimSpace(username) == "" {
+		return UserStruct{}, 0
+	}
+	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(username))
+	if err != nil {
+		ERROR.Println(err)
+		return UserStruct{}, 0
+	}
+	rec := s.ReadAerospike(key)
+	if rec == nil {
+		return UserStruct{}, 0
+	}
+	return FillUserWithAerospikeBins(rec.Bins), rec.Generation
+}
+
+func (s *AerospikeStore) CheckAndSetUser(user UserStruct, generation uint32) error {
+	if s.conn == nil {
+		return errors.New("s.conn == nil in CheckAndSetUser")
+	}
+	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(user.UsernameUpper))
+	if err != nil {
+		return err
+	}
+	writePolicy := &aerospike.WritePolicy{
+		BasePolicy:         *aerospike.NewPolicy(),
+		RecordExistsAction: aerospike.UPDATE,
+		GenerationPolicy:   aerospike.EXPECT_GEN_EQUAL,
+		CommitLevel:        aerospike.COMMIT_ALL,
+		Generation:         generation,
+		Expiration:         0,
+		SendKey:            false,
+	}
+	if generation == 0 {
+		writePolicy.RecordExistsAction = aerospike.CREATE_ONLY
+		writePolicy.GenerationPolicy = aerospike.NONE
+	}
+	err = s.conn.Put(writePolicy, key, user.ToAerospikeBins())
+	if ae, ok := err.(types.AerospikeError); ok {
+		switch ae.ResultCode() {
+		case types.GENERATION_ERROR, types.KEY_EXISTS_ERROR:
+			return ErrStaleUser
+		}
+	}
+	return err
+}
+
+func (s *AerospikeStore) GetUserByPhone(phonenum string) UserStruct {
+	return s.queryUserByBin(AEROSPIKE_USERS_USERNAME_PHONE_BIN, phonenum)
+}
+
+func (s *AerospikeStore) GetUserByEmail(email string) UserStruct {
+	return s.queryUserByBin(AEROSPIKE_USERS_USERNAME_EMAIL_BIN, email)
+}
+
+func (s *AerospikeStore) SetUser(user UserStruct) bool {
+	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(user.UsernameUpper))
+	if err == nil {
+		return s.WriteAerospikeMultipleBins(key, user.ToAerospikeBins())
+	} else {
+		ERROR.Println(err)
+		return false
+	}
+}
+
+func (s *AerospikeStore) UpdateUserFields(username string, fields UserFields) bool {
+	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(username))
+	if err != nil {
+		ERROR.Println(err)
+		return false
+	}
+	if len(fields) == 1 {
+		for name, value := range fields {
+			return s.UpdateAerospikeSingleBin(key, aerospike.NewBin(name, value))
+		}
+	}
+	return s.WriteAerospikeMultipleBins(key, aerospike.BinMap(fields))
+}
+
+// runs ops on a user's conversation maps in one go.  ops has to start with
+// an UPDATE_ONLY map write to CID, so nothing changes unless they're in it
+func (s *AerospikeStore) operateUserConvo(username string, ops ...*aerospike.Operation) error {
+	if s.conn == nil {
+		return errors.New("s.conn == nil in operateUserConvo")
+	}
+	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(username))
+	if err != nil {
+		return err
+	}
+	writePolicy := &aerospike.WritePolicy{
+		BasePolicy:         *aerospike.NewPolicy(),
+		RecordExistsAction: aerospike.UPDATE_ONLY,
+		GenerationPolicy:   aerospike.NONE,
+		CommitLevel:        aerospike.COMMIT_ALL,
+		Generation:         0,
+		Expiration:         0,
+		SendKey:            false,
+	}
+	for converted := false; ; converted = true {
+		_, err = s.conn.Operate(writePolicy, key, ops...)
+		ae, ok := err.(types.AerospikeError)
+		if !ok {
+			return err
+		}
+		switch ae.ResultCode() {
+		case types.KEY_NOT_FOUND_ERROR, types.FAIL_ELEMENT_NOT_FOUND:
+			return ErrNotInConvo
+		case types.BIN_TYPE_ERROR:
+			// an older server wrote the user, convert it and try again
+			if !converted {
+				if err := s.convertUser(key); err != nil && err != ErrStaleUser {
+					return err
+				}
+				continue
+			}
+		}
+		return err
+	}
+}
+
+func (s *AerospikeStore) BumpUserConvo(username string, CID string, mtime string) error {
+	updateOnly := aerospike.NewMapPolicy(aerospike.MapOrder.UNORDERED, aerospike.MapWriteMode.UPDATE_ONLY)
+	return s.operateUserConvo(username,
+		aerospike.MapPutOp(updateOnly, AEROSPIKE_USERS_CIDS_BIN, CID, mtime),
+		aerospike.MapIncrementOp(aerospike.DefaultMapPolicy(), AEROSPIKE_USERS_CID_UNREAD_BIN, CID, 1))
+}
+
+func (s *AerospikeStore) SetUserConvoUnread(username string, CID string, count int) error {
+	updateOnly := aerospike.NewMapPolicy(aerospike.MapOrder.UNORDERED, aerospike.MapWriteMode.UPDATE_ONLY)
+	return s.operateUserConvo(username,
+		aerospike.MapPutOp(updateOnly, AEROSPIKE_USERS_CID_UNREAD_BIN, CID, count))
+}
+
+func (s *AerospikeStore) SetUserConvoMtime(username string, CID string, mtime string) error {
+	updateOnly := aerospike.NewMapPolicy(aerospike.MapOrder.UNORDERED, aerospike.MapWriteMode.UPDATE_ONLY)
+	return s.operateUserConvo(username,
+		aerospike.MapPutOp(updateOnly, AEROSPIKE_USERS_CIDS_BIN, CID, mtime))
+}
+
+func (s *AerospikeStore) DeleteUser(username string) bool {
+	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(username))
+	if err == nil {
+		return s.DeleteAerospike(key)
+	} else {
+		ERROR.Println(err)
+		return false
+	}
+}
+
+func (s *AerospikeStore) SetSessionPresence(username string, session string, presence SessionPresence) error {
+	if s.conn == nil {
+		return errors.New("s.conn == nil in SetSessionPresence")
+	}
+	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_ACTIVE_TABLE, strings.ToUpper(username))
+	if err != nil {
+		return err
+	}
+	_, err = s.conn.Operate(aerospike.NewWritePolicy(0, 0), key,
+		aerospike.MapPutOp(aerospike.DefaultMapPolicy(), AEROSPIKE_USERS_ACTIVE_SESSIONS_BIN, session, presenceToBin(presence)),
+		aerospike.PutOp(aerospike.NewBin(AEROSPIKE_USERS_ACTIVE_LAST_SEEN_BIN, presence.Seen)))
+	return err
+}
+
+func (s *AerospikeStore) RemoveSessionPresence(username string, session string, lastSeen string) error {
+	if s.conn == nil {
+		return errors.New("s.conn == nil in RemoveSessionPresence")
+	}
+	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_ACTIVE_TABLE, strings.ToUpper(username))
+	if err != nil {
+		return err
+	}
+	ops := []*aerospike.Operation{aerospike.MapRemoveByKeyOp(AEROSPIKE_USERS_ACTIVE_SESSIONS_BIN, session, aerospike.MapReturnType.NONE)}
+	if lastSeen != "" {
+		ops = append(ops, aerospike.PutOp(aerospike.NewBin(AEROSPIKE_USERS_ACTIVE_LAST_SEEN_BIN, lastSeen)))
+	}
+	_, err = s.conn.Operate(aerospike.NewWritePolicy(0, 0), key, ops...)
+	if ae, ok := err.(types.AerospikeError); ok && ae.ResultCode() == types.KEY_NOT_FOUND_ERROR {
+		return nil // never had any
+	}
+	return err
+}
+
+func (s *AerospikeStore) GetPresence(username string) (map[string]SessionPresence, string, error) {
+	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_ACTIVE_TABLE, strings.ToUpper(username))
+	if err != nil {
+		return nil, "", err
+	}
+	if s.conn == nil {
+		return nil, "", errors.New("s.conn == nil in GetPresence")
+	}
+	rec, err := s.conn.Get(nil, key)
+	if ae, ok := err.(types.AerospikeError); ok && ae.ResultCode() == types.KEY_NOT_FOUND_ERROR {
+		return map[string]SessionPresence{}, "", nil
+	} else if err != nil {
+		return nil, "", err
+	}
+	sessions, lastSeen := presenceFromBins(rec.Bins)
+	return sessions, lastSeen, nil
+}
+
+// ConversationStore
+func (s *AerospikeStore) GetConvoMembers(CID string) []string {
+	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_MEMBERS_KEY)
+	if err == nil {
+		rec := s.ReadAerospike(key)
+		if rec != nil {
+			members := InterfaceArrayToStringArray(rec.Bins["Members"].([]interface{}))
+			return members
+		} else {
+			return make([]string, 0)
+		}
+	} else {
+		ERROR.Println(err)
+		return make([]string, 0)
+	}
+}
+
+func (s *AerospikeStore) GetConvoName(CID string) string {
+	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_NAME_KEY)
+	if err == nil {
+		rec := s.ReadAerospike(key)
+		if rec != nil {
+			name := rec.Bins["Name"].(string)
+			return name
+		} else {
+			return ""
+		}
+	} else {
+		ERROR.Println(err)
+		return ""
+	}
+}
+
+func (s *AerospikeStore) GetConvoMtime(CID string) string {
+	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_MTIME_KEY)
+	if err == nil {
+		rec := s.ReadAerospike(key)
+		if rec != nil {
+			m_time := rec.Bins["M_time"].(string)
+			return m_time
+		} else {
+			return ""
+		}
+	} else {
+		ERROR.Println(err)
+		return ""
+	}
+}
+
+func (s *AerospikeStore) GetConvoFiles(CID string) []string {
+	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_FILES_KEY)
+	if err == nil {
+		rec := s.ReadAerospike(key)
+		if rec != nil {
+			files := InterfaceArrayToStringArray(rec.Bins["Files"].([]interface{}))
+			return files
+		} else {
+			return make([]string, 0)
+		}
+	} else {
+		ERROR.Println(err)
+		return make([]string, 0)
+	}
+}
+
+func (s *AerospikeStore) SetConvoMembers(CID string, members []string) bool {
+	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_MEMBERS_KEY)
+	if err == nil {
+		bins := aerospike.BinMap{
+			"Members": members,
+		}
+		return s.WriteAerospikeMultipleBins(key, bins)
+	} else {
+		ERROR.Println(err)
+		return false
+	}
+}
+
+func (s *AerospikeStore) SetConvoName(CID string, name string) bool {
+	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_NAME_KEY)
+	if err == nil {
+		bins := aerospike.BinMap{
+			"Name": name,
+		}
+		return s.WriteAerospikeMultipleBins(key, bins)
+	} else {
+		ERROR.Println(err)
+		return false
+	}
+}
+
+func (s *AerospikeStore) SetConvoMtime(CID string, mtime string) bool {
+	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_MTIME_KEY)
+	if err == nil {
+		// save as bins
+		bins := aerospike.BinMap{
+			"M_time": mtime,
+		}
+		return s.WriteAerospikeMultipleBins(key, bins)
+	} else {
+		ERROR.Println(err)
+		return false
+	}
+}
+
+func (s *AerospikeStore) SetConvoFiles(CID string, files []string) bool {
+	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_FILES_KEY)
+	if err == nil {
+		// save as bins
+		bins := aerospike.BinMap{
+			"Files": files,
+		}
+		return s.WriteAerospikeMultipleBins(key, bins)
+	} else {
+		ERROR.Println(err)
+		return false
+	}
+}
+
+func (s *AerospikeStore) GetConvoDisabledBots(CID string) []string {
+	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_BOTS_KEY)
+	if err != nil {
+		ERROR.Println(err)
+		return make([]string, 0)
+	}
+	rec := s.ReadAerospike(key)
+	if rec == nil {
+		return make([]string, 0) // every bot is on
+	}
+	bots, _ := rec.Bins["DisabledBots"].([]interface{})
+	return InterfaceArrayToStringArray(bots)
+}
+
+func (s *AerospikeStore) SetConvoDisabledBots(CID string, bots []string) bool {
+	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_BOTS_KEY)
+	if err != nil {
+		ERROR.Println(err)
+		return false
+	}
+	bins := aerospike.BinMap{
+		"DisabledBots": bots,
+	}
+	return s.WriteAerospikeMultipleBins(key, bins)
+}
+
+func (s *AerospikeStore) DeleteConvo(CID string) bool {
+	deleted := false
+	for _, convoKey := range []string{AEROSPIKE_CONVOS_MEMBERS_KEY, AEROSPIKE_CONVOS_NAME_KEY, AEROSPIKE_CONVOS_MTIME_KEY, AEROSPIKE_CONVOS_FILES_KEY, AEROSPIKE_CONVOS_BOTS_KEY} {
+		key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, convoKey)
+		if err != nil {
+			ERROR.Println(err)
+			continue
+		}
+		if s.DeleteAerospike(key) {
+			deleted = true
+		}
+	}
+	return deleted
+}
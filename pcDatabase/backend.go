@@ -0,0 +1,131 @@
+package pcDatabase
+
+import (
+	"github.com/apcera/nats"
+	"pingedchat/config"
+	"sync"
+	"time"
+)
+
+const (
+	HEALTH_CHECK_INTERVAL = time.Second * 10
+)
+
+// Backend is the one set of connections the whole process shares.  It's
+// opened once at startup and handed to every session, webhook and ticker,
+// each of the clients pools its own connections underneath.
+type Backend struct {
+	Stores
+	// result of the last health check, nil when healthy
+	mu        sync.RWMutex
+	healthErr error
+	stop      chan struct{}
+}
+
+// NewBackend wraps already opened stores, eg. the in-memory ones
+func NewBackend(stores Stores) *Backend {
+	return &Backend{Stores: stores}
+}
+
+// OpenBackend connects to aerospike, postgres and gnatsd.  Nothing is left
+// open if any of them fails.
+func OpenBackend(c *config.Config) (*Backend, error) {
+	// aerospike
+	aerospikeStore, err := OpenAerospikeStore(c.Aerospike)
+	if err != nil {
+		return nil, err
+	}
+	// postgres, doesn't open a connection until it's used
+	postgresStore, err := OpenPostgresStore(c.Postgres)
+	if err != nil {
+		aerospikeStore.Close()
+		return nil, err
+	}
+	// nats, keep reconnecting if gnatsd goes away
+	nats_conn, err := nats.Connect(c.Nats.URL, nats.MaxReconnects(-1))
+	if err != nil {
+		aerospikeStore.Close()
+		postgresStore.Close()
+		return nil, err
+	}
+	nats_encodedconn, err := nats.NewEncodedConn(nats_conn, "default")
+	if err != nil {
+		aerospikeStore.Close()
+		postgresStore.Close()
+		nats_conn.Close()
+		return nil, err
+	}
+
+	b := NewBackend(Stores{
+		Users:    aerospikeStore,
+		Convos:   aerospikeStore,
+		Messages: postgresStore,
+		Emails:   postgresStore,
+		Changes:  postgresStore,
+		Queues:   postgresStore,
+		Events:   NewNatsEventBus(nats_conn, nats_encodedconn),
+		Previews: NewLinkFetcher(c.Previews),
+	})
+	if err := b.Check(); err != nil {
+		b.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+// Check pings every backend and remembers the result for Healthy
+func (b *Backend) Check() error {
+	err := b.Stores.Ping()
+	b.mu.Lock()
+	if err != nil && b.healthErr == nil {
+		ERROR.Println("backend unhealthy:", err)
+	} else if err == nil && b.healthErr != nil {
+		TRACE.Println("backend healthy again")
+	}
+	b.healthErr = err
+	b.mu.Unlock()
+	return err
+}
+
+// Healthy returns the error from the last health check, nil if it passed
+func (b *Backend) Healthy() error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.healthErr
+}
+
+// StartHealthChecks runs Check every interval until Close
+func (b *Backend) StartHealthChecks(interval time.Duration) {
+	b.mu.Lock()
+	if b.stop != nil {
+		b.mu.Unlock()
+		return // already running
+	}
+	b.stop = make(chan struct{})
+	stop := b.stop
+	b.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				b.Check()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the health checks and closes every connection, only at shutdown
+func (b *Backend) Close() {
+	b.mu.Lock()
+	if b.stop != nil {
+		close(b.stop)
+		b.stop = nil
+	}
+	b.mu.Unlock()
+	b.Stores.Close()
+}
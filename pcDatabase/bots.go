@@ -0,0 +1,342 @@
+package pcDatabase
+
+import (
+	"github.com/pquerna/ffjson/ffjson"
+	"regexp"
+	"strings"
+)
+
+// BOTS
+// a bot answers messages that match one of its triggers, like @giphy and
+// @forecast.  SendMessage gives the message to the first bot it triggers
+// (as long as the bot isn't turned off in the conversation) before it's
+// saved, and the bot can rewrite it, drop it, reply to everyone as
+// "@name", or reply to just the sender.  Replies to everyone are saved
+// like any message but only go to web devices, replies to the sender are
+// never saved and only go to the sender's web devices as EphemeralMessage.
+//
+// bots are registered in newBotRegistry, GetBots lists them with whether
+// they're on in a conversation and SetConvoBot turns them on and off for
+// everyone in it.  @help can't be turned off.
+
+// sender of a bot's replies, usernames can't start with it
+const BOT_SENDER_PREFIX = "@"
+
+var (
+	ErrUnknownBot      = cmdError(ERR_NOT_FOUND, "there's no bot with that name")
+	ErrBotAlwaysOn     = cmdError(ERR_BAD_REQUEST, "that bot can't be turned off")
+	ErrBotUsernameUsed = cmdError(ERR_BAD_REQUEST, "usernames can't start with "+BOT_SENDER_PREFIX)
+)
+
+type Bot interface {
+	// what it's called, lowercase.  It sends as @name
+	Name() string
+	// the messages it answers, matched against their plain text.  The first
+	// group, if there is one, is the BotMessage's Args
+	Triggers() []*regexp.Regexp
+	// how to use it, for @help and GetBots
+	Help() string
+	Handle(msg BotMessage) BotResponse
+}
+
+// what a bot gets to answer
+type BotMessage struct {
+	CID     string
+	Sender  string
+	Members []string
+	Text    string // the message as plain text
+	Args    string // what the trigger matched as its first group
+	Message MessageStruct
+	Bots    []Bot // the bots on in the conversation, for @help
+}
+
+// a message from a bot, content is markup
+type BotReply struct {
+	Content     string
+	Attachments []MessageAttachment
+}
+
+// what a bot does with a message, the zero value leaves it alone
+type BotResponse struct {
+	// the message is sent with Content and Attachments instead
+	Rewrite     bool
+	Content     string
+	Attachments []MessageAttachment
+	// the message isn't sent at all, for commands like @help
+	Drop bool
+	// sent to everyone after the message
+	Reply *BotReply
+	// sent to just the sender
+	Ephemeral *BotReply
+}
+
+// a bot's reply to just the sender, it isn't saved anywhere
+type EphemeralMessageEvent struct {
+	Cmd          string              `json:"cmd"`
+	CID          string              `json:"CID"`
+	MID          string              `json:"MID"`
+	FromUsername string              `json:"f_username"`
+	M_time       string              `json:"m_time"`
+	Content      string              `json:"content"`
+	Attachments  []MessageAttachment `json:"attachments,omitempty"`
+}
+
+type ConvoBotsEvent struct {
+	Cmd          string   `json:"cmd"`
+	CID          string   `json:"CID"`
+	DisabledBots []string `json:"DisabledBots"`
+}
+
+// BotRegistry is every bot, in the order they're tried
+type BotRegistry struct {
+	bots []Bot
+}
+
+func NewBotRegistry() *BotRegistry {
+	return &BotRegistry{}
+}
+
+var botNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// Register adds a bot, it panics on a bad or taken name like Router.Register
+func (r *BotRegistry) Register(bot Bot) {
+	if !botNamePattern.MatchString(bot.Name()) {
+		panic("bot name " + bot.Name() + " has to be lowercase letters, digits, _ and -")
+	}
+	if r.Get(bot.Name()) != nil {
+		panic("bot " + bot.Name() + " registered twice")
+	}
+	r.bots = append(r.bots, bot)
+}
+
+// Get is the bot called name, nil if there isn't one
+func (r *BotRegistry) Get(name string) Bot {
+	name = strings.TrimPrefix(strings.ToLower(name), BOT_SENDER_PREFIX)
+	for _, bot := range r.bots {
+		if bot.Name() == name {
+			return bot
+		}
+	}
+	return nil
+}
+
+// Enabled is every bot that isn't in disabled
+func (r *BotRegistry) Enabled(disabled []string) []Bot {
+	enabled := make([]Bot, 0, len(r.bots))
+	for _, bot := range r.bots {
+		if !containsString(disabled, bot.Name()) {
+			enabled = append(enabled, bot)
+		}
+	}
+	return enabled
+}
+
+// the first of bots text triggers, with its args
+func matchBot(bots []Bot, text string) (Bot, string) {
+	for _, bot := range bots {
+		for _, trigger := range bot.Triggers() {
+			match := trigger.FindStringSubmatch(text)
+			if match == nil {
+				continue
+			}
+			args := ""
+			if len(match) > 1 {
+				args = strings.TrimSpace(match[1])
+			}
+			return bot, args
+		}
+	}
+	return nil, ""
+}
+
+var bots = newBotRegistry()
+
+func newBotRegistry() *BotRegistry {
+	r := NewBotRegistry()
+	r.Register(helpBot{})
+	r.Register(giphyBot{})
+	r.Register(forecastBot{})
+	return r
+}
+
+// bots that are always on
+func botAlwaysOn(bot Bot) bool {
+	_, help := bot.(helpBot)
+	return help
+}
+
+// HELP
+
+type helpBot struct{}
+
+var helpTriggers = []*regexp.Regexp{regexp.MustCompile(`(?is)^\s*@help(?:\s+@?(\S+))?\s*$`)}
+
+func (helpBot) Name() string {
+	return "help"
+}
+
+func (helpBot) Triggers() []*regexp.Regexp {
+	return helpTriggers
+}
+
+func (helpBot) Help() string {
+	return "@help lists the bots on in this conversation, @help name says how to use one"
+}
+
+func (helpBot) Handle(msg BotMessage) BotResponse {
+	lines := make([]string, 0, len(msg.Bots))
+	for _, bot := range msg.Bots {
+		if msg.Args == "" || strings.EqualFold(msg.Args, bot.Name()) {
+			lines = append(lines, "*@"+bot.Name()+"* "+EscapeMarkup(bot.Help()))
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "there's no @"+EscapeMarkup(msg.Args)+" on in this conversation")
+	}
+	return BotResponse{Drop: true, Ephemeral: &BotReply{Content: strings.Join(lines, "\n")}}
+}
+
+// RUNNING THEM
+
+// the bots that are turned off in CID
+func (db *Database) disabledBots(CID string) []string {
+	if db.Convos == nil {
+		return nil
+	}
+	return db.Convos.GetConvoDisabledBots(CID)
+}
+
+// HandleBots gives msg to the bot it triggers.  A rewrite is done to msg,
+// the bot's replies go out with sendBotReplies once msg is sent.  The bot
+// is nil when none answered
+func (db *Database) HandleBots(msg *MessageStruct) (Bot, BotResponse) {
+	text := MarkupToText(msg.Content)
+	enabled := bots.Enabled(db.disabledBots(msg.CID))
+	bot, args := matchBot(enabled, text)
+	if bot == nil {
+		return nil, BotResponse{}
+	}
+	TRACE.Println("handling @" + bot.Name())
+	members := make([]string, 0)
+	for _, m := range ToConvoMemberArray(db.Convos.GetConvoMembers(msg.CID)) {
+		members = append(members, m.Username)
+	}
+	resp := bot.Handle(BotMessage{
+		CID:     msg.CID,
+		Sender:  msg.FromUsername,
+		Members: members,
+		Text:    text,
+		Args:    args,
+		Message: *msg,
+		Bots:    enabled,
+	})
+	if resp.Rewrite {
+		msg.Content = sanitizeMarkup(resp.Content)
+		msg.Attachments = resp.Attachments
+	}
+	return bot, resp
+}
+
+// sends what bot said about msg, after msg went out (or was dropped)
+func (db *Database) sendBotReplies(msg MessageStruct, bot Bot, resp BotResponse) {
+	if bot == nil {
+		return
+	}
+	if resp.Ephemeral != nil {
+		event, err := ffjson.Marshal(EphemeralMessageEvent{
+			Cmd:          "EphemeralMessage",
+			CID:          msg.CID,
+			MID:          newMessageID(),
+			FromUsername: BOT_SENDER_PREFIX + bot.Name(),
+			M_time:       msg.M_time,
+			Content:      sanitizeMarkup(resp.Ephemeral.Content),
+			Attachments:  resp.Ephemeral.Attachments,
+		})
+		if err != nil {
+			ERROR.Println("error in ffjson.Marshal in sendBotReplies:", err)
+		} else {
+			sender := db.Users.GetUser(msg.FromUsername)
+			db.SendStringToWebDevices(sender.Web, string(event))
+		}
+	}
+	if resp.Reply != nil {
+		// like an autoreply, saved and sent to web devices but never pushed
+		reply := msg
+		reply.MID = newMessageID()
+		reply.ParentMID = ""
+		reply.FromUsername = BOT_SENDER_PREFIX + bot.Name()
+		reply.Content = sanitizeMarkup(resp.Reply.Content)
+		reply.Attachments = resp.Reply.Attachments
+		replyString, err := ffjson.Marshal(reply)
+		if err != nil {
+			ERROR.Println("error in ffjson.Marshal in sendBotReplies:", err)
+			return
+		}
+		if !db.AddMessageToConvo(reply) {
+			// not sent either, nobody could load it again
+			ERROR.Println("Error in adding @" + bot.Name() + "'s reply to postgres")
+			return
+		}
+		for _, member := range reply.ToUIDs {
+			recipient := db.Users.GetUser(member)
+			if recipient.UsernameUpper == "" {
+				continue // user not found
+			}
+			db.sendChangeString(recipient, CHANGE_MESSAGE, string(replyString))
+		}
+	}
+}
+
+// COMMANDS
+
+func (db *Database) GetBots(jsondata *CIDCommandStruct) *BotsResponse {
+	disabled := db.disabledBots(jsondata.CID)
+	resp := &BotsResponse{Bots: make([]BotInfo, 0, len(bots.bots))}
+	for _, bot := range bots.bots {
+		info := BotInfo{
+			Name:     bot.Name(),
+			Help:     bot.Help(),
+			Triggers: make([]string, 0),
+			Enabled:  !containsString(disabled, bot.Name()),
+			AlwaysOn: botAlwaysOn(bot),
+		}
+		for _, trigger := range bot.Triggers() {
+			info.Triggers = append(info.Triggers, trigger.String())
+		}
+		resp.Bots = append(resp.Bots, info)
+	}
+	return resp
+}
+
+func (db *Database) SetConvoBot(jsondata *ConvoBotCmdStruct) error {
+	bot := bots.Get(jsondata.Bot)
+	if bot == nil {
+		return ErrUnknownBot
+	}
+	if botAlwaysOn(bot) && !jsondata.Enabled {
+		return ErrBotAlwaysOn
+	}
+	disabled := db.disabledBots(jsondata.CID)
+	wasEnabled := !containsString(disabled, bot.Name())
+	if wasEnabled == jsondata.Enabled {
+		return nil
+	}
+	if jsondata.Enabled {
+		disabled = removeString(disabled, bot.Name())
+	} else {
+		disabled = append(disabled, bot.Name())
+	}
+	if !db.Convos.SetConvoDisabledBots(jsondata.CID, disabled) {
+		return ErrNotSaved
+	}
+	event, err := ffjson.Marshal(ConvoBotsEvent{Cmd: "update_convo_bots", CID: jsondata.CID, DisabledBots: disabled})
+	if err != nil {
+		ERROR.Println("error in ffjson.Marshal in SetConvoBot:", err)
+		return nil
+	}
+	for _, m := range ToConvoMemberArray(db.Convos.GetConvoMembers(jsondata.CID)) {
+		user := db.Users.GetUser(m.Username)
+		db.sendChangeString(user, CHANGE_CONVO, string(event))
+	}
+	return nil
+}
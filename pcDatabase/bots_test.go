@@ -0,0 +1,92 @@
+package pcDatabase
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// @shout shouts the message and says so
+type shoutBot struct{}
+
+func (shoutBot) Name() string {
+	return "shout"
+}
+
+func (shoutBot) Triggers() []*regexp.Regexp {
+	return []*regexp.Regexp{regexp.MustCompile(`^@shout (.+)$`)}
+}
+
+func (shoutBot) Help() string {
+	return "@shout something"
+}
+
+func (shoutBot) Handle(msg BotMessage) BotResponse {
+	return BotResponse{
+		Rewrite: true,
+		Content: strings.ToUpper(msg.Args),
+		Reply:   &BotReply{Content: "*" + msg.Sender + "* shouted at " + strings.Join(msg.Members, ", ")},
+	}
+}
+
+func TestBots(t *testing.T) {
+	saved := bots
+	bots = newBotRegistry()
+	bots.Register(shoutBot{})
+	defer func() { bots = saved }()
+
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "bob")
+	createTestUser(t, db, "alice")
+	CID := createTestConversation(t, db, "alice", "bob")
+	send := `{"cmd":"SendMessage","CID":"` + CID + `","t_UIDs":["alice","bob"],"m_time":"2015-06-12T19:20:0`
+
+	// the message is rewritten and the bot replies to everyone
+	dispatch(t, db, send+`0.000Z","content":"@shout hi _there_"}`, nil)
+	events := expectEvents(t, db, 4)
+	if !strings.Contains(events[0], `"content":"HI THERE"`) || !strings.Contains(events[3], `"f_username":"@shout"`) {
+		t.Errorf("unexpected events %v", events)
+	}
+	rows, _ := db.Messages.GetAllMessages(CID)
+	if len(rows) != 2 || rows[1].F_username != "@shout" || rows[1].Content != "*alice* shouted at alice, bob" {
+		t.Errorf("conversation has messages %+v", rows)
+	}
+
+	// @help only goes to alice and isn't saved
+	dispatch(t, db, send+`1.000Z","content":"@help"}`, nil)
+	if event := expectEvents(t, db, 1)[0]; !strings.Contains(event, `"cmd":"EphemeralMessage"`) || !strings.Contains(event, "*@shout* @shout something") {
+		t.Errorf("@help sent %s", event)
+	}
+	if rows, _ := db.Messages.GetAllMessages(CID); len(rows) != 2 {
+		t.Errorf("@help was saved: %+v", rows)
+	}
+
+	// turned off it's just a message
+	if env := dispatch(t, db, `{"cmd":"SetConvoBot","CID":"`+CID+`","bot":"@shout","enabled":false}`, nil); !env.OK {
+		t.Fatalf("SetConvoBot returned %+v", env)
+	}
+	if event := expectEvents(t, db, 2)[0]; !strings.Contains(event, `"DisabledBots":["shout"]`) {
+		t.Errorf("unexpected event %s", event)
+	}
+	dispatch(t, db, send+`2.000Z","content":"@shout hi"}`, nil)
+	if event := expectEvents(t, db, 2)[0]; !strings.Contains(event, `"content":"@shout hi"`) {
+		t.Errorf("unexpected event %s", event)
+	}
+	var resp BotsResponse
+	dispatch(t, db, `{"cmd":"GetBots","CID":"`+CID+`"}`, &resp)
+	for _, bot := range resp.Bots {
+		if bot.Enabled != (bot.Name != "shout") || bot.AlwaysOn != (bot.Name == "help") {
+			t.Errorf("GetBots got %+v", bot)
+		}
+	}
+	var convo ConvoDataStruct
+	dispatch(t, db, `{"cmd":"GetConvoData","CID":"`+CID+`"}`, &convo)
+	if len(convo.DisabledBots) != 1 {
+		t.Errorf("GetConvoData has DisabledBots %v", convo.DisabledBots)
+	}
+
+	expectError(t, db, `{"cmd":"SetConvoBot","CID":"`+CID+`","bot":"help","enabled":false}`, ERR_BAD_REQUEST)
+	expectError(t, db, `{"cmd":"SetConvoBot","CID":"`+CID+`","bot":"nobody","enabled":true}`, ERR_NOT_FOUND)
+	expectError(t, db, `{"cmd":"CreateUser","Username":"@giphy","Password":"secret123","Token":"x"}`, ERR_BAD_REQUEST)
+}
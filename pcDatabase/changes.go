@@ -0,0 +1,171 @@
+package pcDatabase
+
+import (
+	"github.com/pquerna/ffjson/ffjson"
+	"strconv"
+	"strings"
+)
+
+// CHANGE LOG
+// everything sent to a user's web devices that changes what they have
+// (messages, conversations and their members, friends, emails, scheduled
+// messages and the user itself) is also added to the user's change log, with
+// a seq that goes up by one per change.  A client that was away asks for
+// GetChangesSince with the cursor it got last time and gets the same events
+// it missed, a page at a time, instead of asking for every conversation and
+// the mailbox again.  Typing, presence and the like aren't logged, they
+// don't mean anything later.
+//
+// only the latest CHANGE_LOG_SIZE changes are kept.  When some of the ones
+// after the cursor are gone (or the client has no cursor yet) the answer is
+// SnapshotRequired: the client loads everything again with ValidateUser,
+// GetConvoData and GetAllEmails and carries on from the Cursor that came
+// with it.  Changes can arrive twice, once live and again from the log, so
+// clients apply them by their CID, MID, email key and so on
+
+const (
+	CHANGE_MESSAGE   = "message"   // sent, edited or deleted messages and reactions
+	CHANGE_CONVO     = "convo"     // conversations made, joined, left or renamed, files and read times
+	CHANGE_FRIENDS   = "friends"   // friend requests and friends
+	CHANGE_EMAIL     = "email"     // new emails, drafts and flags
+	CHANGE_SCHEDULED = "scheduled" // scheduled messages
+	CHANGE_USER      = "user"      // the whole user, eg. after a device is added
+
+	// changes kept per user
+	CHANGE_LOG_SIZE = 5000
+	// changes per GetChangesSince
+	CHANGES_PAGE_SIZE = 200
+)
+
+var (
+	ErrChangesDown     = cmdError(ERR_UNAVAILABLE, "changes can't be reached right now, try again")
+	ErrBadChangeCursor = cmdError(ERR_BAD_REQUEST, "that cursor isn't from GetChangesSince")
+)
+
+type EmailChangedEvent struct {
+	Cmd       string `json:"cmd"`
+	FromEmail string
+	Subject   string
+	RecvTime  string
+	// the flag that changed, "" for a new email or draft
+	Flag   string `json:"flag,omitempty"`
+	Value  bool   `json:"value"`
+	M_time string
+}
+
+// EmailReceived, an email that just came into the mailbox.  Content is its
+// HTML, escaped
+type EmailReceivedEvent struct {
+	Cmd         string `json:"cmd"`
+	FromEmail   string
+	ToEmails    []string
+	RecvEmail   string
+	Subject     string
+	Content     string
+	Attachments []string
+	Spam        bool `json:",string"`
+	RecvTime    string
+}
+
+// adds event to username's change log, sending it is up to the caller
+func (db *Database) recordChange(username string, kind string, event string) {
+	if db.Changes == nil {
+		return
+	}
+	if _, err := db.Changes.AppendChange(username, kind, event); err != nil {
+		ERROR.Println("error in AppendChange for "+username+":", err)
+	}
+}
+
+// sendChangeString logs event for user and sends it to their web devices,
+// see sendToUser
+func (db *Database) sendChangeString(user UserStruct, kind string, event string) {
+	if user.UsernameUpper == "" {
+		return
+	}
+	db.recordChange(user.UsernameUpper, kind, event)
+	db.sendToUser(user, event)
+}
+
+// sendChange is sendChangeString for anything ffjson can marshal
+func (db *Database) sendChange(user UserStruct, kind string, v interface{}) {
+	event, err := ffjson.Marshal(v)
+	if err != nil {
+		ERROR.Println("error in ffjson.Marshal in sendChange:", err)
+		return
+	}
+	db.sendChangeString(user, kind, string(event))
+}
+
+// an email in username's mailbox changed
+func (db *Database) sendEmailChange(username string, event EmailChangedEvent) {
+	if event.Cmd == "" {
+		event.Cmd = "EmailChanged"
+	}
+	db.sendChange(db.Users.GetUser(strings.ToUpper(username)), CHANGE_EMAIL, event)
+}
+
+// SendEmailReceived sends a new email in username's mailbox to their web
+// devices, for the mail webhooks
+func (db *Database) SendEmailReceived(username string, event EmailReceivedEvent) {
+	event.Cmd = "EmailReceived"
+	db.sendChange(db.Users.GetUser(strings.ToUpper(username)), CHANGE_EMAIL, event)
+}
+
+func formatChangeCursor(seq int64) string {
+	return strconv.FormatInt(seq, 10)
+}
+
+func parseChangeCursor(cursor string) (int64, error) {
+	seq, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, ErrBadChangeCursor
+	}
+	return seq, nil
+}
+
+// GetChangesSince is what changed for the caller after Cursor, oldest first
+func (db *Database) GetChangesSince(jsondata *ChangesCmdStruct) (*ChangesResponse, error) {
+	if db.Changes == nil {
+		return nil, ErrChangesDown
+	}
+	// the latest seq first, anything after it is newer than the answer
+	last, err := db.Changes.LastChange(jsondata.Username)
+	if err != nil {
+		ERROR.Println("error in LastChange for "+jsondata.Username+":", err)
+		return nil, ErrChangesDown
+	}
+	resp := &ChangesResponse{Changes: make([]ChangeStruct, 0), Cursor: formatChangeCursor(last)}
+	if jsondata.Cursor == "" {
+		resp.SnapshotRequired = true
+		return resp, nil
+	}
+	after, err := parseChangeCursor(jsondata.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if after > last {
+		resp.SnapshotRequired = true // not one of ours
+		return resp, nil
+	}
+	changes, err := db.Changes.GetChanges(jsondata.Username, after, CHANGES_PAGE_SIZE+1)
+	if err != nil {
+		ERROR.Println("error in GetChanges for "+jsondata.Username+":", err)
+		return nil, ErrChangesDown
+	}
+	// the one after the cursor is gone, so are some of the others
+	if after < last && (len(changes) == 0 || changes[0].Seq != after+1) {
+		resp.SnapshotRequired = true
+		return resp, nil
+	}
+	if len(changes) > CHANGES_PAGE_SIZE {
+		changes = changes[:CHANGES_PAGE_SIZE]
+		resp.More = true
+	}
+	resp.Changes = changes
+	resp.Cursor = formatChangeCursor(after)
+	if len(changes) > 0 {
+		resp.Cursor = formatChangeCursor(changes[len(changes)-1].Seq)
+	}
+	return resp, nil
+}
@@ -0,0 +1,88 @@
+package pcDatabase
+
+import (
+	"strings"
+	"testing"
+)
+
+func getChanges(t *testing.T, db *Database, cursor string) ChangesResponse {
+	var resp ChangesResponse
+	if env := dispatch(t, db, `{"cmd":"GetChangesSince","cursor":"`+cursor+`"}`, &resp); !env.OK {
+		t.Fatalf("GetChangesSince(%q) returned %+v", cursor, env)
+	}
+	return resp
+}
+
+func TestGetChangesSince(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	CID, MID := sendTestMessage(t, db)
+
+	// a new client loads everything and carries on from the cursor
+	first := getChanges(t, db, "")
+	if !first.SnapshotRequired || len(first.Changes) != 0 {
+		t.Fatalf("without a cursor got %+v", first)
+	}
+	// the conversation and the message are in alice's log already
+	if first.Cursor != "2" {
+		t.Errorf("cursor %s, expected 2", first.Cursor)
+	}
+
+	dispatch(t, db, `{"cmd":"EditMessage","CID":"`+CID+`","MID":"`+MID+`","content":"hi bob"}`, nil)
+	expectEvents(t, db, 2)
+	resp := getChanges(t, db, first.Cursor)
+	if resp.SnapshotRequired || resp.More || len(resp.Changes) != 1 {
+		t.Fatalf("after the edit got %+v", resp)
+	}
+	if change := resp.Changes[0]; change.Kind != CHANGE_MESSAGE || !strings.Contains(string(change.Event), `"MessageEdited"`) {
+		t.Errorf("unexpected change %+v", change)
+	}
+	if resp.Cursor != "3" {
+		t.Errorf("cursor %s, expected 3", resp.Cursor)
+	}
+	// nothing new
+	if again := getChanges(t, db, resp.Cursor); len(again.Changes) != 0 || again.SnapshotRequired || again.Cursor != resp.Cursor {
+		t.Errorf("with nothing new got %+v", again)
+	}
+
+	// more than a page comes back a page at a time
+	for i := 0; i < CHANGES_PAGE_SIZE+5; i++ {
+		db.Changes.AppendChange("alice", CHANGE_USER, `{"cmd":"UpdateUser"}`)
+	}
+	page := getChanges(t, db, resp.Cursor)
+	if len(page.Changes) != CHANGES_PAGE_SIZE || !page.More {
+		t.Fatalf("first page has %d changes, more %v", len(page.Changes), page.More)
+	}
+	if page = getChanges(t, db, page.Cursor); len(page.Changes) != 5 || page.More {
+		t.Fatalf("second page has %d changes, more %v", len(page.Changes), page.More)
+	}
+
+	// once the log has moved past the cursor it has to start over
+	for i := 0; i <= CHANGE_LOG_SIZE; i++ {
+		db.Changes.AppendChange("alice", CHANGE_USER, `{"cmd":"UpdateUser"}`)
+	}
+	if old := getChanges(t, db, page.Cursor); !old.SnapshotRequired || len(old.Changes) != 0 {
+		t.Errorf("with a cursor that's too old got %d changes, snapshot %v", len(old.Changes), old.SnapshotRequired)
+	}
+	if ahead := getChanges(t, db, "999999"); !ahead.SnapshotRequired {
+		t.Errorf("a cursor from the future didn't need a snapshot")
+	}
+	expectError(t, db, `{"cmd":"GetChangesSince","cursor":"three"}`, ERR_BAD_REQUEST)
+}
+
+func TestEmailReceivedIsLogged(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "alice")
+	cursor := getChanges(t, db, "").Cursor
+
+	db.SendEmailReceived("alice", EmailReceivedEvent{FromEmail: "bob@example.com", Subject: "hi", Spam: true})
+	event := expectEvents(t, db, 1)[0]
+	if !strings.Contains(event, `"cmd":"EmailReceived"`) || !strings.Contains(event, `"Spam":"true"`) {
+		t.Errorf("unexpected event %s", event)
+	}
+	resp := getChanges(t, db, cursor)
+	if len(resp.Changes) != 1 || resp.Changes[0].Kind != CHANGE_EMAIL || !strings.Contains(string(resp.Changes[0].Event), "bob@example.com") {
+		t.Errorf("after the email got %+v", resp)
+	}
+}
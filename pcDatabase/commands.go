@@ -0,0 +1,558 @@
+package pcDatabase
+
+import (
+	"strings"
+	"time"
+)
+
+// every command the clients can send, Run() and the login loop in conn.go
+// dispatch through this.  Adding a command here adds it to the catalogue,
+// regenerate commands.json with "pingedchat commands > commands.json".
+
+// CommandMetrics counts calls, errors and time per command, see /metrics
+var CommandMetrics = NewMetrics()
+
+var router = newCommandRouter()
+
+// Catalogue describes the envelope, the error codes and every command's
+// request and response
+func Catalogue() CatalogueInfo {
+	return router.Catalogue()
+}
+
+func newCommandRouter() *Router {
+	r := NewRouter()
+	r.Use(LogCommands, CommandMetrics.Middleware, RateLimit(COMMAND_RATE, COMMAND_BURST), RequireLogin, BindIdentity)
+
+	// LOGIN
+	r.Register(Command{
+		Name:     "CreateUser",
+		Doc:      "registers a new user and logs the session in, the web token is subscribed to the user's updates and the user comes back with a sessionToken for ResumeSession",
+		Handler:  (*Database).CreateUser,
+		Required: []string{"Username", "Password", "Token"},
+		Validate: validateNewUser,
+		Public:   true,
+	})
+	r.Register(Command{
+		Name:     "ValidateUser",
+		Doc:      "logs the session in and starts a login session, the user comes back with a sessionToken for ResumeSession, a bad password is invalid_credentials",
+		Handler:  (*Database).ValidateUser,
+		Required: []string{"Username", "Token"},
+		Public:   true,
+	})
+	r.Register(Command{
+		Name:     "GetPasswordResetUser",
+		Doc:      "security questions for resetting a user's password, not_found if there's no such user",
+		Handler:  (*Database).GetPasswordResetUser,
+		Required: []string{"Username"},
+		Public:   true,
+	})
+	r.Register(Command{
+		Name:     "ResetUserPassword",
+		Doc:      "sets a new password after two security questions are answered, secQuests is a JSON array of {Question, Answer}",
+		Handler:  (*Database).ResetUserPassword,
+		Required: []string{"Username", "Password"},
+		Validate: validateNewUser,
+		Public:   true,
+	})
+
+	// SESSIONS
+	r.Register(Command{
+		Name:     "ResumeSession",
+		Doc:      "logs the session back in with a sessionToken from CreateUser, ValidateUser or RefreshSession, an expired or revoked one is invalid_credentials",
+		Handler:  (*Database).ResumeSession,
+		Required: []string{"SessionToken"},
+		Public:   true,
+	})
+	r.Register(Command{
+		Name:    "RefreshSession",
+		Doc:     "a new sessionToken with a new expiry for the current session, the old token stops working",
+		Handler: (*Database).RefreshSession,
+	})
+	r.Register(Command{
+		Name:    "RevokeSession",
+		Doc:     "ends one of the caller's sessions (the current one without an ID), its device gets SessionRevoked and is disconnected",
+		Handler: (*Database).RevokeSession,
+	})
+	r.Register(Command{
+		Name:    "GetSessions",
+		Doc:     "the caller's live sessions",
+		Handler: (*Database).GetSessions,
+	})
+
+	// USER
+	r.Register(Command{
+		Name:     "DeleteUser",
+		Doc:      "deletes the user and removes them from their friends, sent to every web device",
+		Handler:  (*Database).DeleteUser,
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "GetUserByUsername",
+		Doc:      "public profile of a user",
+		Handler:  (*Database).GetUserByUsername,
+		Required: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "GetUserByEmail",
+		Doc:      "the user registered with an email",
+		Handler:  (*Database).GetUserByEmail,
+		Required: []string{"Email"},
+	})
+	r.Register(Command{
+		Name:    "MatchUsers",
+		Doc:     "finds users among the phone's contacts",
+		Handler: (*Database).MatchUsers,
+	})
+	r.Register(Command{
+		Name:     "ChangeUserPassword",
+		Doc:      "changes the password, the old one has to match",
+		Handler:  (*Database).ChangeUserPassword,
+		Required: []string{"OldPassword", "NewPassword"},
+		Identity: []string{"Username"},
+		Validate: validateNewPassword,
+	})
+	r.Register(Command{
+		Name:     "ChangeProfilePic",
+		Doc:      "sets the user's profile picture and updates it in their friends' lists",
+		Handler:  (*Database).ChangeProfilePic,
+		Required: []string{"ProfilePic"},
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "ChangeUserPhone",
+		Doc:      "sets the user's phone number, it has to be a valid number",
+		Handler:  (*Database).ChangeUserPhone,
+		Required: []string{"Phone"},
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "ChangeUserEmail",
+		Doc:      "sets the user's email, it can't belong to another user",
+		Handler:  (*Database).ChangeUserEmail,
+		Required: []string{"Email"},
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "SaveAutoreplyMessage",
+		Doc:      "sets the autoreply sent once per conversation, an empty message turns it off",
+		Handler:  (*Database).SaveAutoreplyMessage,
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "AddToQuota",
+		Doc:      "adds storage to the user's quota, sent to every web device",
+		Handler:  (*Database).AddToQuota,
+		Required: []string{"Quota"},
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "AddToQuotaUsed",
+		Doc:      "adds to the storage the user has used, sent to every web device, quota_exceeded once the quota is reached",
+		Handler:  (*Database).AddToQuotaUsed,
+		Required: []string{"QuotaUsed"},
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:    "GetS3PolicyData",
+		Doc:     "signed S3 upload policy",
+		Handler: (*Database).GetS3PolicyData,
+	})
+
+	// SCHEDULED MESSAGES
+	r.Register(Command{
+		Name:     "AddScheduledMessage",
+		Doc:      "schedules a message to be sent to a conversation at Time, sent to every web device",
+		Handler:  (*Database).AddScheduledMessage,
+		Required: []string{"CID", "Time", "Content"},
+		Identity: []string{"Username"},
+		Member:   true,
+		Validate: validateScheduledTime,
+	})
+	r.Register(Command{
+		Name:     "RemoveScheduledMessage",
+		Doc:      "unschedules a message, the user is sent to every web device",
+		Handler:  (*Database).RemoveScheduledMessage,
+		Required: []string{"CID", "Time"},
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "RemoveAllScheduledMessages",
+		Doc:      "unschedules all of the user's messages",
+		Handler:  (*Database).RemoveAllScheduledMessages,
+		Identity: []string{"Username"},
+	})
+
+	// DEVICES
+	for _, dev := range []struct {
+		name    string
+		handler interface{}
+		doc     string
+	}{
+		{"AddAndroidDev", (*Database).AddAndroidDev, "registers an android push token"},
+		{"ChangeAndroidDev", (*Database).ChangeAndroidDev, "replaces old_device with device"},
+		{"RemoveAndroidDev", (*Database).RemoveAndroidDev, "removes an android push token"},
+		{"AddIosDev", (*Database).AddIosDev, "registers an iOS push token"},
+		{"ChangeIosDev", (*Database).ChangeIosDev, "replaces old_device with device"},
+		{"RemoveIosDev", (*Database).RemoveIosDev, "removes an iOS push token"},
+		{"AddFireosDev", (*Database).AddFireosDev, "registers a Fire OS push token"},
+		{"ChangeFireosDev", (*Database).ChangeFireosDev, "replaces old_device with device"},
+		{"RemoveFireosDev", (*Database).RemoveFireosDev, "removes a Fire OS push token"},
+		{"RemoveWebDev", (*Database).RemoveWebDev, "removes a web token"},
+	} {
+		r.Register(Command{
+			Name:     dev.name,
+			Doc:      dev.doc + ", the user is sent to every web device",
+			Handler:  dev.handler,
+			Required: []string{"Device"},
+			Identity: []string{"Username"},
+		})
+	}
+
+	// FRIENDS
+	r.Register(Command{
+		Name:     "AddFriend",
+		Doc:      "sends a friend request from the caller to friend_UID",
+		Handler:  (*Database).AddFriend,
+		Required: []string{"FriendUID"},
+		Identity: []string{"UID"},
+	})
+	r.Register(Command{
+		Name:     "AcceptFriendRequest",
+		Doc:      "accepts friend_UID's request to the caller",
+		Handler:  (*Database).AcceptFriendRequest,
+		Required: []string{"FriendUID"},
+		Identity: []string{"UID"},
+	})
+	r.Register(Command{
+		Name:     "DenyFriendRequest",
+		Doc:      "denies friend_UID's request to the caller",
+		Handler:  (*Database).DenyFriendRequest,
+		Required: []string{"FriendUID"},
+		Identity: []string{"UID"},
+	})
+	r.Register(Command{
+		Name:     "RemoveFriend",
+		Doc:      "removes the caller and friend_UID from each other's friends",
+		Handler:  (*Database).RemoveFriend,
+		Required: []string{"FriendUID"},
+		Identity: []string{"UID"},
+	})
+	r.Register(Command{
+		Name:     "SendEmailInvite",
+		Doc:      "invites comma separated emails and phones to PingedChat, answers with the ones that are already users",
+		Handler:  (*Database).SendEmailInvite,
+		Identity: []string{"FromUsername"},
+	})
+
+	// CONVERSATIONS
+	r.Register(Command{
+		Name:     "CreateConversation",
+		Doc:      "creates a conversation, Members has to include the caller and the new CID is sent to every member's web devices",
+		Handler:  (*Database).CreateConversation,
+		Required: []string{"Members", "M_time"},
+	})
+	r.Register(Command{
+		Name:     "AddUsersToConversation",
+		Doc:      "adds Members to the conversation",
+		Handler:  (*Database).AddUsersToConversation,
+		Required: []string{"CID", "Members"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "RemoveUserFromConversation",
+		Doc:      "the caller leaves the conversation",
+		Handler:  (*Database).RemoveUserFromConversation,
+		Required: []string{"CID"},
+		Identity: []string{"Username"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "ChangeConvoName",
+		Doc:      "renames the conversation",
+		Handler:  (*Database).ChangeConvoName,
+		Required: []string{"CID"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "UpdateConvoFiles",
+		Doc:      "adds a file to the conversation's file list",
+		Handler:  (*Database).UpdateConvoFiles,
+		Required: []string{"CID", "FileURL"},
+		Identity: []string{"FromUsername"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "GetConvoData",
+		Doc:      "the conversation with its messages since M_time, or the latest 50 without M_time",
+		Handler:  (*Database).GetConvoData,
+		Required: []string{"CID"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "GetAllConvoData",
+		Doc:      "the conversation with all of its messages",
+		Handler:  (*Database).GetAllConvoData,
+		Required: []string{"CID"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "GetMoreConvoMessages",
+		Doc:      "the 50 messages before M_time, latest first",
+		Handler:  (*Database).GetMoreConvoMessages,
+		Required: []string{"CID", "M_time"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "SendMessage",
+		Doc:      "sends a message to the conversation, pushed to the devices of the t_UIDs that are members, with p_MID it's a reply in that message's thread.  content is markup, attachments are images and files",
+		Handler:  (*Database).SendMessage,
+		Required: []string{"CID", "ToUIDs"},
+		Identity: []string{"FromUsername"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "EditMessage",
+		Doc:      "replaces the content of a message the caller sent, MessageEdited is sent to every member",
+		Handler:  (*Database).EditMessage,
+		Required: []string{"CID", "MID", "Content"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "DeleteMessage",
+		Doc:      "blanks out a message the caller sent, MessageDeleted is sent to every member",
+		Handler:  (*Database).DeleteMessage,
+		Required: []string{"CID", "MID"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "GetMessageEdits",
+		Doc:      "what a message said before each edit, oldest first",
+		Handler:  (*Database).GetMessageEdits,
+		Required: []string{"CID", "MID"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "SearchMessages",
+		Doc:      "finds messages in the caller's conversations, best match first with highlighted snippets, a page at a time",
+		Handler:  (*Database).SearchMessages,
+		Required: []string{"Query"},
+	})
+	r.Register(Command{
+		Name:     "GetThread",
+		Doc:      "a message and the replies in its thread after M_time and after_MID (the last reply loaded), a page at a time",
+		Handler:  (*Database).GetThread,
+		Required: []string{"CID", "MID"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "MuteConversation",
+		Doc:      "stops or restarts pushes to the caller's phones for the conversation, threads they're in still push",
+		Handler:  (*Database).MuteConversation,
+		Required: []string{"CID"},
+		Identity: []string{"Username"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "AddReaction",
+		Doc:      "reacts to a message with an emoji, ReactionAdded is sent to every member's web devices",
+		Handler:  (*Database).AddReaction,
+		Required: []string{"CID", "MID", "Emoji"},
+		Identity: []string{"Username"},
+		Member:   true,
+		Validate: validateReaction,
+	})
+	r.Register(Command{
+		Name:     "RemoveReaction",
+		Doc:      "takes back the caller's emoji on a message, ReactionRemoved is sent to every member's web devices",
+		Handler:  (*Database).RemoveReaction,
+		Required: []string{"CID", "MID", "Emoji"},
+		Identity: []string{"Username"},
+		Member:   true,
+		Validate: validateReaction,
+	})
+	r.Register(Command{
+		Name:     "GetReadReceipts",
+		Doc:      "which members have read a message, or up to M_time, or the latest message",
+		Handler:  (*Database).GetReadReceipts,
+		Required: []string{"CID"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "HideReadTime",
+		Doc:      "stops or starts sharing when the caller read each conversation with the other members",
+		Handler:  (*Database).HideReadTime,
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "GetChangesSince",
+		Doc:      "what changed for the caller after cursor, a page at a time.  SnapshotRequired when the log doesn't go back that far, or without a cursor",
+		Handler:  (*Database).GetChangesSince,
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:    "ResumeEvents",
+		Doc:     "the events queued for this device after seq, a page at a time, acking everything up to seq.  Gap when some of them are gone",
+		Handler: (*Database).ResumeEvents,
+	})
+	r.Register(Command{
+		Name:    "AckEvents",
+		Doc:     "this device has every event up to seq, they're dropped from its queue",
+		Handler: (*Database).AckEvents,
+	})
+	r.Register(Command{
+		Name:     "SetPresence",
+		Doc:      "sets the session's state to online, away or dnd and keeps it from timing out, friends and conversation members see the change",
+		Handler:  (*Database).SetPresence,
+		Required: []string{"State"},
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "GetPresence",
+		Doc:      "the state and last seen of each of usernames, anyone who isn't a friend or conversation member is offline",
+		Handler:  (*Database).GetPresence,
+		Required: []string{"Usernames"},
+		Identity: []string{"Username"},
+		Validate: validatePresenceLookup,
+	})
+	r.Register(Command{
+		Name:     "HideLastSeen",
+		Doc:      "stops or starts sharing when the caller was last online, the state is still shared",
+		Handler:  (*Database).HideLastSeen,
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "SetTyping",
+		Doc:      "whether the caller is typing in the conversation, sent to the other members and never saved.  It runs out unless sent again",
+		Handler:  (*Database).SetTyping,
+		Required: []string{"CID"},
+		Identity: []string{"Username"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "UpdateUserStatus",
+		Doc:      "updates the caller's read time in the conversation, sent to every member.  Without NewReadTime it's SetTyping",
+		Handler:  (*Database).UpdateUserStatus,
+		Required: []string{"CID"},
+		Identity: []string{"Username"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "UpdateUnreadCount",
+		Doc:      "sets the user's unread count for the conversation",
+		Handler:  (*Database).UpdateUnreadCount,
+		Required: []string{"CID"},
+		Identity: []string{"Username"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "UpdateConvoMtime",
+		Doc:      "sets the user's m_time for the conversation",
+		Handler:  (*Database).UpdateConvoMtime,
+		Required: []string{"CID", "Mtime"},
+		Identity: []string{"Username"},
+		Member:   true,
+	})
+
+	// BOTS
+	r.Register(Command{
+		Name:     "GetBots",
+		Doc:      "every bot, with how to use it and whether it's on in the conversation",
+		Handler:  (*Database).GetBots,
+		Required: []string{"CID"},
+		Member:   true,
+	})
+	r.Register(Command{
+		Name:     "SetConvoBot",
+		Doc:      "turns a bot on or off for everyone in the conversation, update_convo_bots is sent to every member",
+		Handler:  (*Database).SetConvoBot,
+		Required: []string{"CID", "Bot"},
+		Member:   true,
+	})
+
+	// EMAILS
+	r.Register(Command{
+		Name:     "GetAllEmails",
+		Doc:      "the user's emails changed since M_time, newest first a page at a time",
+		Handler:  (*Database).GetAllEmails,
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "SearchEmails",
+		Doc:      "the user's emails matching the query and filters, newest first a page at a time",
+		Handler:  (*Database).SearchEmails,
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "SendEmailMessage",
+		Doc:      "sends an email from the caller's address and saves it to their mailbox",
+		Handler:  (*Database).SendEmailMessage,
+		Required: []string{"FromEmail", "ToEmails"},
+	})
+	r.Register(Command{
+		Name:     "MarkEmailUnread",
+		Doc:      "sets the unread flag on the email keyed by FromEmail, Subject and RecvTime",
+		Handler:  (*Database).MarkEmailUnread,
+		Required: []string{"RecvTime"},
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "MarkEmailStarred",
+		Doc:      "sets the starred flag on the email keyed by FromEmail, Subject and RecvTime",
+		Handler:  (*Database).MarkEmailStarred,
+		Required: []string{"RecvTime"},
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "MarkEmailDeleted",
+		Doc:      "moves the email keyed by FromEmail, Subject and RecvTime to or from the trash",
+		Handler:  (*Database).MarkEmailDeleted,
+		Required: []string{"RecvTime"},
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "AddNewDraft",
+		Doc:      "saves a draft, keyed by its RecvTime",
+		Handler:  (*Database).AddNewDraft,
+		Required: []string{"RecvTime"},
+		Identity: []string{"Username"},
+	})
+	r.Register(Command{
+		Name:     "RemoveDeletedEmails",
+		Doc:      "empties the user's trash",
+		Handler:  (*Database).RemoveDeletedEmails,
+		Identity: []string{"Username"},
+	})
+	return r
+}
+
+const (
+	MIN_PASSWORD_LENGTH = 6
+)
+
+// bots send as @name, so nobody else can
+func validateNewUser(req interface{}) error {
+	user := req.(*CreateUserCmdStruct)
+	if strings.HasPrefix(user.Username, BOT_SENDER_PREFIX) {
+		return ErrBotUsernameUsed
+	}
+	if len(user.Password) < MIN_PASSWORD_LENGTH {
+		return cmdError(ERR_BAD_REQUEST, "password needs at least %d characters", MIN_PASSWORD_LENGTH)
+	}
+	return nil
+}
+
+func validateNewPassword(req interface{}) error {
+	if len(req.(*ChangeUserPasswordStruct).NewPassword) < MIN_PASSWORD_LENGTH {
+		return cmdError(ERR_BAD_REQUEST, "NewPassword needs at least %d characters", MIN_PASSWORD_LENGTH)
+	}
+	return nil
+}
+
+// the ticker compares times, so they have to parse
+func validateScheduledTime(req interface{}) error {
+	if _, err := time.Parse(time.RFC3339Nano, req.(*ScheduledMessagesCmdStruct).Time); err != nil {
+		return cmdError(ERR_BAD_REQUEST, "Time isn't an RFC 3339 time: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package pcDatabase
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// commands that aren't about the caller or a conversation, everything else
+// has to name its caller in Identity or be a Member command
+var notUserScoped = map[string]string{
+	"GetUserByUsername":  "looks up someone else's public profile",
+	"GetUserByEmail":     "looks up someone else's public profile",
+	"MatchUsers":         "looks up the phone's contacts",
+	"GetS3PolicyData":    "signs an upload, nothing about the user",
+	"CreateConversation": "the handler checks the caller is in Members",
+	"SendEmailMessage":   "the handler checks FromEmail is the caller's",
+	"RefreshSession":     "only touches the session's own login",
+	"RevokeSession":      "only looks in the caller's sessions",
+	"GetSessions":        "only lists the caller's sessions",
+	"SearchMessages":     "only looks in the caller's CIDs",
+	"ResumeEvents":       "only reads the session's own device queue",
+	"AckEvents":          "only trims the session's own device queue",
+}
+
+// a request with every required field set, strings are all a time so
+// AddScheduledMessage's Time and the password lengths pass validation
+func testRequest(cmd *Command, fields map[string]interface{}) string {
+	req := reflect.New(cmd.request).Elem()
+	for _, name := range cmd.Required {
+		f := req.FieldByName(name)
+		switch f.Kind() {
+		case reflect.String:
+			f.SetString("2015-06-12T19:20:00.000Z")
+		case reflect.Slice:
+			f.Set(reflect.Append(f, reflect.ValueOf("2015-06-12T19:20:00.000Z").Convert(f.Type().Elem())))
+		default:
+			f.SetUint(1)
+		}
+	}
+	for name, v := range fields {
+		req.FieldByName(name).Set(reflect.ValueOf(v))
+	}
+	// not every request struct has a Cmd field
+	msg := map[string]interface{}{}
+	encoded, _ := json.Marshal(req.Interface())
+	json.Unmarshal(encoded, &msg)
+	msg["cmd"] = cmd.Name
+	encoded, _ = json.Marshal(msg)
+	return string(encoded)
+}
+
+// bob and carol have a conversation alice isn't in, the session is alice's
+func newIdentityTest(t *testing.T) (*Database, string) {
+	db := newTestDatabase(t)
+	createTestUser(t, db, "bob")
+	createTestUser(t, db, "carol")
+	CID := createTestConversation(t, db, "bob", "carol")
+	createTestUser(t, db, "alice")
+	return db, CID
+}
+
+func TestEveryCommandIsBoundToTheCaller(t *testing.T) {
+	for name, cmd := range router.commands {
+		_, skipped := notUserScoped[name]
+		if !cmd.Public && !skipped && len(cmd.Identity) == 0 && !cmd.Member {
+			t.Errorf("%s trusts its request, give it Identity or Member", name)
+		}
+		if cmd.Public && (len(cmd.Identity) > 0 || cmd.Member) {
+			t.Errorf("%s is public, there's no caller to bind", name)
+		}
+	}
+}
+
+func TestCommandsRejectAnotherUser(t *testing.T) {
+	db, CID := newIdentityTest(t)
+	defer db.Close()
+
+	for name, cmd := range router.commands {
+		if len(cmd.Identity) == 0 {
+			continue
+		}
+		bob := db.Users.GetUser("BOB")
+		fields := map[string]interface{}{}
+		for _, field := range cmd.Identity {
+			fields[field] = "bob"
+		}
+		if cmd.Member {
+			fields["CID"] = CID
+		}
+		expectError(t, db, testRequest(cmd, fields), ERR_FORBIDDEN)
+		if after := db.Users.GetUser("BOB"); !reflect.DeepEqual(bob, after) {
+			t.Errorf("%s as bob changed bob to %+v", name, after)
+		}
+	}
+}
+
+func TestCommandsRejectNonMembers(t *testing.T) {
+	db, CID := newIdentityTest(t)
+	defer db.Close()
+
+	for name, cmd := range router.commands {
+		if !cmd.Member {
+			continue
+		}
+		members := db.Convos.GetConvoMembers(CID)
+		messages, _ := db.Messages.GetAllMessages(CID)
+		expectError(t, db, testRequest(cmd, map[string]interface{}{"CID": CID}), ERR_FORBIDDEN)
+		if after := db.Convos.GetConvoMembers(CID); !reflect.DeepEqual(members, after) {
+			t.Errorf("%s by a non-member changed the members to %v", name, after)
+		}
+		if after, _ := db.Messages.GetAllMessages(CID); len(after) != len(messages) {
+			t.Errorf("%s by a non-member changed the messages to %v", name, after)
+		}
+	}
+}
+
+func TestIdentityIsFilledInFromTheSession(t *testing.T) {
+	db, _ := newIdentityTest(t)
+	defer db.Close()
+
+	env := dispatch(t, db, `{"cmd":"ChangeProfilePic","ProfilePic":"alice.png"}`, nil)
+	if !env.OK {
+		t.Fatalf("ChangeProfilePic without Username returned %+v", env)
+	}
+	if pic := db.Users.GetUser("ALICE").ProfilePic; pic != "alice.png" {
+		t.Errorf("alice's profile pic is %q", pic)
+	}
+	// naming yourself is fine too, in any case
+	env = dispatch(t, db, `{"cmd":"ChangeProfilePic","Username":"ALICE","ProfilePic":"alice2.png"}`, nil)
+	if !env.OK || db.Users.GetUser("ALICE").ProfilePic != "alice2.png" {
+		t.Errorf("ChangeProfilePic as ALICE returned %+v", env)
+	}
+}
+
+func TestMembersCanUseTheirConversation(t *testing.T) {
+	db, _ := newIdentityTest(t)
+	defer db.Close()
+	CID := createTestConversation(t, db, "alice", "bob")
+
+	env := dispatch(t, db, `{"cmd":"SendMessage","CID":"`+CID+`","t_UIDs":["alice","bob","carol"],"content":"hi","m_time":"2015-06-12T19:20:00.000Z"}`, nil)
+	if !env.OK {
+		t.Fatalf("SendMessage returned %+v", env)
+	}
+	// carol isn't in the conversation, so she doesn't hear about it
+	for _, event := range expectEvents(t, db, 2) {
+		if !strings.Contains(event, `"f_username":"alice"`) || strings.Contains(event, "carol") {
+			t.Errorf("unexpected event %s", event)
+		}
+	}
+	carol := db.Users.GetUser("CAROL")
+	if len(carol.GetCIDStructs()) != 1 {
+		t.Errorf("carol's conversations are %+v", carol.GetCIDStructs())
+	}
+
+	convo := ConvoDataStruct{}
+	env = dispatch(t, db, `{"cmd":"GetConvoData","CID":"`+CID+`"}`, &convo)
+	if !env.OK || len(convo.Messages) != 1 || convo.Messages[0].F_username != "alice" {
+		t.Errorf("GetConvoData returned %+v with %+v", env, convo)
+	}
+}
+
+func TestCreateConversationNeedsTheCaller(t *testing.T) {
+	db, _ := newIdentityTest(t)
+	defer db.Close()
+
+	expectError(t, db, `{"cmd":"CreateConversation","Members":["bob","carol"],"M_time":"2015-06-12T19:20:00.000Z"}`, ERR_FORBIDDEN)
+	bob := db.Users.GetUser("BOB")
+	if CIDs := bob.GetCIDStructs(); len(CIDs) != 1 {
+		t.Errorf("bob was added to %+v", CIDs)
+	}
+}
+
+func TestSendEmailMessageOnlyFromTheCaller(t *testing.T) {
+	db, _ := newIdentityTest(t)
+	defer db.Close()
+
+	expectError(t, db, `{"cmd":"SendEmailMessage","FromEmail":"bob@`+conf.Mail.Domain+`","ToEmails":["eve@example.com"]}`, ERR_FORBIDDEN)
+}
+
+func TestGetUserByEmailIsPublic(t *testing.T) {
+	db, _ := newIdentityTest(t)
+	defer db.Close()
+
+	user := UserStruct{}
+	env := dispatch(t, db, `{"cmd":"GetUserByEmail","email":"bob@example.com"}`, &user)
+	if !env.OK || user.Username != "bob" {
+		t.Fatalf("GetUserByEmail returned %+v with %+v", env, user)
+	}
+	if user.Password != "" || user.SecQuests != "" || len(user.Web) != 0 {
+		t.Errorf("GetUserByEmail gave away %+v", user)
+	}
+}
@@ -1,14 +1,13 @@
 package pcDatabase
 
 import (
-	"database/sql"
-	"encoding/json"
-	aerospike "github.com/aerospike/aerospike-client-go"
-	"github.com/apcera/nats"
+	"context"
 	_ "github.com/lib/pq"
 	"gopkg.in/igm/sockjs-go.v2/sockjs"
 	"log"
 	"os"
+	"pingedchat/config"
+	"sync"
 	"time"
 )
 
@@ -18,196 +17,244 @@ var (
 	ERROR = log.New(os.Stderr, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
 )
 
+// settings for the push, mail, S3 and weather helpers
+var conf = config.Default()
+
+// Configure sets the config the push, mail, S3 and weather helpers use, call
+// it once at startup before anything else
+func Configure(c *config.Config) {
+	conf = c
+}
+
+// Database is a single session on top of the shared Backend, it only owns
+// the sockjs session and the event bus subscriptions of its web devices
 type Database struct {
-	aerospike_conn   *aerospike.Client
-	postgres_conn    *sql.DB
-	nats_conn        *nats.Conn
-	nats_encodedconn *nats.EncodedConn
+	// storage backends shared by every session, see stores.go and backend.go
+	Stores
+	// event bus subscriptions for this session's web devices
+	subscriptions []EventSubscription
 	// sockjs
 	sockjsSession *sockjs.Session
+	// the logged in user, their web token and session, empty until CreateUser,
+	// ValidateUser or ResumeSession
+	username string
+	token    string
+	session  string
+	// commands allowed for this session, see RateLimit
+	limiter *tokenBucket
+	// conversations the user is typing in and when that runs out, and the
+	// timer for the first one to run out, see typing.go
+	typing      map[string]time.Time
+	typingTimer *time.Timer
+	// this session in its user's presence, see presence.go
+	presenceID    string
+	presenceUser  string
+	presenceState string
+	presenceSeen  time.Time
+	// cancelled when the socket closes or the session is disconnected, stops
+	// Run and the writer
+	ctx    context.Context
+	cancel context.CancelFunc
 	// channels
 	Receive      chan string // public for conn.go
 	nats_receive chan string
+	outbound     chan outboundMsg // to the client, drained by write()
+}
+
+// OUTBOUND
+const (
+	// messages waiting for a client, when it can't keep up with this many
+	// it's disconnected
+	OUTBOUND_QUEUE_SIZE = 256
+	// sockjs close status sent to a client that can't keep up
+	SLOW_CLIENT_STATUS = 4002
+)
+
+// a message for the client, or when status is set the socket is closed
+// once everything queued before it is sent
+type outboundMsg struct {
+	text   string
+	status uint32
+	reason string
 }
 
-func (db *Database) Connect(sockSession *sockjs.Session) {
+// Connect sets up the session on top of the shared backend, sockSession is
+// nil for webhooks and the scheduled messages ticker.  The session stops
+// when ctx is cancelled
+func (db *Database) Connect(ctx context.Context, backend *Backend, sockSession *sockjs.Session) {
 	// TRACE.Println("in Database.connect()")
 	db.sockjsSession = sockSession
-	var err error
-	// aerospike
-	db.aerospike_conn, err = aerospike.NewClient("127.0.0.1", 3000)
-	if err != nil {
-		// ERROR.Println("error connecting to aerospike")
-		ERROR.Println(err)
-	}
-	// postgres
-	db.postgres_conn, err = sql.Open("postgres", "user=postgres password=postgres dbname=pingedchatdb host=127.0.0.1")
-	if err != nil {
-		// ERROR.Println("error opening postgres")
-		ERROR.Println(err)
-	}
-	// doesn't open a connection.  Ping it to open a connection.
-	// err = db.postgres_conn.Ping()
-	// if err != nil {
-	// 	ERROR.Println("error connecting to postgres")
-	// 	ERROR.Println(err)
-	// }
-	// TRACE.Println("connecting NATS")
-	db.nats_conn, err = nats.Connect("nats://127.0.0.1:4222")
-	if err != nil {
-		// ERROR.Println("error connecting to nats_conn")
-		ERROR.Println(err)
-	}
-	db.nats_encodedconn, err = nats.NewEncodedConn(db.nats_conn, "default")
-	if err != nil {
-		ERROR.Println("error connecting to nats_encodedconn")
-		ERROR.Println(err)
-	}
+	db.Stores = backend.Stores
+	db.ctx, db.cancel = context.WithCancel(ctx)
 
 	// make channel for receiving messages used in run()
 	db.Receive = make(chan string)
 	db.nats_receive = make(chan string)
+	db.outbound = make(chan outboundMsg, OUTBOUND_QUEUE_SIZE)
+}
+
+// Context is done once the session has stopped
+func (db *Database) Context() context.Context {
+	return db.ctx
+}
+
+// Authenticate marks the session as logged in, done by CreateUser,
+// ValidateUser and ResumeSession
+func (db *Database) Authenticate(username string, token string, session string) {
+	db.username = username
+	db.token = token
+	db.session = session
+}
+
+// Username is the logged in user, "" before login
+func (db *Database) Username() string {
+	return db.username
+}
+
+// Token is the web token the session logged in with
+func (db *Database) Token() string {
+	return db.token
+}
+
+// Session is the ID of the login session, see sessions.go
+func (db *Database) Session() string {
+	return db.session
 }
 
 func (db *Database) IsConnected() bool {
-	if db.aerospike_conn == nil ||
-		db.postgres_conn == nil ||
-		db.nats_conn == nil ||
-		db.nats_encodedconn == nil {
+	if db.Users == nil ||
+		db.Convos == nil ||
+		db.Messages == nil ||
+		db.Emails == nil ||
+		db.Changes == nil ||
+		db.Queues == nil ||
+		db.Events == nil {
 		return false
 	} else {
 		return true
 	}
 }
 
-func (db *Database) IsAerospikePostgresConnected() bool {
-	if db.aerospike_conn == nil ||
-		db.postgres_conn == nil {
-		return false
-	} else {
-		return true
+// subscribe a web device token to this session, messages end up in nats_receive
+func (db *Database) subscribe(subject string) {
+	if db.Events == nil {
+		return
 	}
+	sub, err := db.Events.Subscribe(subject, db.nats_receive)
+	if err != nil {
+		ERROR.Println("error subscribing to " + subject)
+		ERROR.Println(err)
+		return
+	}
+	db.subscriptions = append(db.subscriptions, sub)
 }
 
 func (db *Database) Close() {
 	TRACE.Println("in Database.close()")
-	if db.aerospike_conn != nil {
-		db.aerospike_conn.Close()
-		db.aerospike_conn = nil
-	}
-	if db.postgres_conn != nil {
-		db.postgres_conn.Close()
-		db.postgres_conn = nil
-	}
-	if db.nats_conn != nil {
-		db.nats_conn.Close()
-		db.nats_conn = nil
-	}
-	if db.nats_encodedconn != nil {
-		db.nats_encodedconn.Close()
-		db.nats_encodedconn = nil
+	db.cancel()
+	// stop deliveries before closing nats_receive
+	for _, sub := range db.subscriptions {
+		if err := sub.Unsubscribe(); err != nil {
+			ERROR.Println(err)
+		}
 	}
+	db.subscriptions = nil
+	// the backend stays open for the other sessions
+	db.Stores = Stores{}
 
-	// close channels, will close run() also
 	close(db.Receive)
 	close(db.nats_receive)
 }
 
+// Dispatch runs one command from the client through the router, returns the
+// JSON to send back or "" if there's nothing to send
+func (db *Database) Dispatch(msg string) string {
+	return router.Dispatch(db, msg)
+}
+
+// Run answers commands from Receive and forwards events from the user's web
+// devices until the session's context is done, call Close after it returns
 func (db *Database) Run() {
-	// TRACE.Println("in Database.run()")
-	// map of functions in db
-	functs := map[string]func(string) string{
-		"DeleteUser":                 db.DeleteUser,
-		"CreateConversation":         db.CreateConversation,
-		"GetConvoData":               db.GetConvoData,
-		"GetMoreConvoMessages":       db.GetMoreConvoMessages,
-		"GetAllConvoData":            db.GetAllConvoData,
-		"GetAllEmails":               db.GetAllEmails,
-		"GetUserByUsername":          db.GetUserByUsername,
-		"GetUserByEmail":             db.GetUserByEmail,
-		"MatchUsers":                 db.MatchUsers,
-		"GetPasswordResetUser":       db.GetPasswordResetUser,
-		"ChangeUserPassword":         db.ChangeUserPassword,
-		"AddToQuota":                 db.AddToQuota,
-		"AddToQuotaUsed":             db.AddToQuotaUsed,
-		"AddScheduledMessage":        db.AddScheduledMessage,
-		"RemoveScheduledMessage":     db.RemoveScheduledMessage,
-		"RemoveAllScheduledMessages": db.RemoveAllScheduledMessages,
-		"AddFriend":                  db.AddFriend,
-		"AcceptFriendRequest":        db.AcceptFriendRequest,
-		"DenyFriendRequest":          db.DenyFriendRequest,
-		"RemoveFriend":               db.RemoveFriend,
-		"SaveAutoreplyMessage":       db.SaveAutoreplyMessage,
-		"ChangeProfilePic":           db.ChangeProfilePic,
-		"ChangeUserPhone":            db.ChangeUserPhone,
-		"ChangeUserEmail":            db.ChangeUserEmail,
-		"AddAndroidDev":              db.AddAndroidDev,
-		"ChangeAndroidDev":           db.ChangeAndroidDev,
-		"RemoveAndroidDev":           db.RemoveAndroidDev,
-		"AddIosDev":                  db.AddIosDev,
-		"ChangeIosDev":               db.ChangeIosDev,
-		"RemoveIosDev":               db.RemoveIosDev,
-		"AddFireosDev":               db.AddFireosDev,
-		"ChangeFireosDev":            db.ChangeFireosDev,
-		"RemoveFireosDev":            db.RemoveFireosDev,
-		"RemoveWebDev":               db.RemoveWebDev,
-		"GetS3PolicyData":            db.GetS3PolicyData,
-		"AddUsersToConversation":     db.AddUsersToConversation,
-		"RemoveUserFromConversation": db.RemoveUserFromConversation,
-		"ChangeConvoName":            db.ChangeConvoName,
-		"UpdateConvoFiles":           db.UpdateConvoFiles,
-		"SendMessage":                db.SendMessage,
-		"UpdateUserStatus":           db.UpdateUserStatus,
-		"UpdateConvoMtime":           db.UpdateConvoMtime,
-		"UpdateUnreadCount":          db.UpdateUnreadCount,
-		"SendEmailInvite":            db.SendEmailInvite,
-		"SendEmailMessage":           db.SendEmailMessage,
-		"MarkEmailUnread":            db.MarkEmailUnread,
-		"MarkEmailStarred":           db.MarkEmailStarred,
-		"AddNewDraft":                db.AddNewDraft,
-		"MarkEmailDeleted":           db.MarkEmailDeleted,
-		"RemoveDeletedEmails":        db.RemoveDeletedEmails,
-	}
+	var writer sync.WaitGroup
+	writer.Add(1)
+	go func() {
+		defer writer.Done()
+		db.write()
+	}()
+	defer writer.Wait()
+	defer db.cancel()
+	defer db.clearTyping()
+	defer db.endPresence()
+	presenceTick := time.NewTicker(PRESENCE_CHECK_INTERVAL)
+	defer presenceTick.Stop()
 
 	for {
 		select {
-		case msg, ok := <-db.Receive:
-			if !ok {
-				ERROR.Println("closing db.run()")
-				return
-			}
+		case <-db.ctx.Done():
+			return
+		case now := <-db.typingExpired():
+			db.expireTyping(now)
+		case now := <-presenceTick.C:
+			db.refreshPresence(now)
+		case msg := <-db.Receive:
 			// TRACE.Println("in msg := <-db.Receive")
 			// TRACE.Println(msg)
-			cmdJSON := CommonCmdStruct{}
-			err := json.Unmarshal([]byte(msg), &cmdJSON)
-			if err != nil {
-				ERROR.Println(err)
-			}
-			retstr := `{"retcmd":"bad_command"}`
-			if _, ok := functs[cmdJSON.Cmd]; ok {
-				// startTime := time.Now().UTC()
-				retstr = functs[cmdJSON.Cmd](msg)
-				// endTime := time.Now().UTC()
-				// TRACE.Println("ElapsedTime in seconds: ", endTime.Sub(startTime))
-			} else {
-				ERROR.Println("Command " + string(cmdJSON.Cmd) + " was not found and will not be executed.")
-			}
+			retstr := db.Dispatch(msg)
+			db.trackPresence()
 			TRACE.Println("str returned from command is: " + string(retstr))
 			if retstr != "" {
-				(*db.sockjsSession).Send(retstr)
-			}
-		case msg, ok := <-db.nats_receive:
-			if !ok {
-				ERROR.Println("closing db.run(), error in db.nats_receive")
-				return
+				db.send(outboundMsg{text: retstr})
 			}
+		case msg := <-db.nats_receive:
 			// don't do anything, just send to client
 			TRACE.Println("nats_receive: " + string(msg))
-			(*db.sockjsSession).Send(msg)
-		default:
-			// TRACE.Println("db.run() default")
-			time.Sleep(50 * time.Millisecond)
+			db.send(outboundMsg{text: msg})
+			// this session was revoked from another device
+			if ID, ok := revokedSession(msg); ok && ID == db.session {
+				db.Authenticate("", "", "")
+				db.trackPresence()
+				db.send(outboundMsg{status: SESSION_REVOKED_STATUS, reason: "session revoked"})
+			}
 		}
 	}
+}
+
+// send queues a message for write(), a client too slow to keep the queue
+// from filling up is disconnected
+func (db *Database) send(msg outboundMsg) {
+	select {
+	case db.outbound <- msg:
+	default:
+		ERROR.Println("outbound queue full for " + db.username + ", disconnecting")
+		db.disconnect(SLOW_CLIENT_STATUS, "too slow")
+	}
+}
+
+// disconnect closes the socket and stops the session, conn.go's Recv()
+// fails and it cleans up
+func (db *Database) disconnect(status uint32, reason string) {
+	if db.sockjsSession != nil {
+		(*db.sockjsSession).Close(status, reason)
+	}
+	db.cancel()
+}
 
+// write is the only thing sending to the socket once Run has started
+func (db *Database) write() {
+	for {
+		select {
+		case <-db.ctx.Done():
+			return
+		case msg := <-db.outbound:
+			if msg.status != 0 {
+				db.disconnect(msg.status, msg.reason)
+				return
+			}
+			if err := (*db.sockjsSession).Send(msg.text); err != nil {
+				ERROR.Println("error sending to the client, stopping the session:", err)
+				db.cancel()
+				return
+			}
+		}
+	}
 }
@@ -1,11 +1,8 @@
 package pcDatabase
 
 import (
-	"database/sql"
 	"encoding/base64"
 	"encoding/json"
-	aerospike "github.com/aerospike/aerospike-client-go"
-	"github.com/lib/pq"
 	"github.com/mostafah/mandrill"
 	"github.com/pquerna/ffjson/ffjson"
 	"github.com/twinj/uuid"
@@ -16,494 +13,43 @@ import (
 	"time"
 )
 
-const (
-	AEROSPIKE_USERS_NAMESPACE      = "users"
-	AEROSPIKE_USERS_USERNAME_TABLE = "username"
-	AEROSPIKE_USERS_ACTIVE_TABLE   = "active"
-	AEROSPIKE_USERS_PHONES_TABLE   = "phones"
-
-	AEROSPIKE_USERS_USERNAME_EMAIL_BIN = "Email"
-	AEROSPIKE_USERS_USERNAME_PHONE_BIN = "Phone"
-
-	AEROSPIKE_CONVOS_NAMESPACE   = "convos"
-	AEROSPIKE_CONVOS_MEMBERS_KEY = "members"
-	AEROSPIKE_CONVOS_NAME_KEY    = "name"
-	AEROSPIKE_CONVOS_MTIME_KEY   = "m_time"
-	AEROSPIKE_CONVOS_FILES_KEY   = "files"
-)
-
-// Database functions
-func (db *Database) ReadAerospike(key *aerospike.Key) *aerospike.Record {
-	if db.aerospike_conn != nil {
-		rec, err := db.aerospike_conn.Get(nil, key)
-		if err == nil {
-			return rec
-		} else {
-			ERROR.Println("error in ReadAerospike")
-			ERROR.Println(err)
-			return nil
-		}
-	} else {
-		ERROR.Println("db.aerospike_conn == nil in ReadAerospike")
-		return nil
-	}
-}
-
-func (db *Database) WriteAerospikeMultipleBins(key *aerospike.Key, value aerospike.BinMap) bool {
-	if db.aerospike_conn != nil {
-		writePolicy := &aerospike.WritePolicy{
-			BasePolicy:         *aerospike.NewPolicy(),
-			RecordExistsAction: aerospike.UPDATE,
-			GenerationPolicy:   aerospike.NONE,
-			CommitLevel:        aerospike.COMMIT_ALL,
-			Generation:         0,
-			Expiration:         0,
-			SendKey:            false,
-		}
-		err := db.aerospike_conn.Put(writePolicy, key, value)
-		if err == nil {
-			return true
-		} else {
-			ERROR.Println("error in WriteAerospikeMultipleBins")
-			ERROR.Println(err)
-			return false
-		}
-	} else {
-		ERROR.Println("db.aerospike_conn == nil in WriteAerospikeMultipleBins")
-		return false
-	}
-}
-
-func (db *Database) UpdateAerospikeSingleBin(key *aerospike.Key, value *aerospike.Bin) bool {
-	if db.aerospike_conn != nil {
-		writePolicy := &aerospike.WritePolicy{
-			BasePolicy:         *aerospike.NewPolicy(),
-			RecordExistsAction: aerospike.UPDATE, // https://github.com/aerospike/aerospike-client-go/blob/master/record_exists_action.go
-			GenerationPolicy:   aerospike.NONE,
-			CommitLevel:        aerospike.COMMIT_ALL,
-			Generation:         1,
-			Expiration:         0,
-			SendKey:            false,
-		}
-		err := db.aerospike_conn.PutBins(writePolicy, key, value)
-		if err == nil {
-			return true
-		} else {
-			ERROR.Println("error in UpdateAerospikeSingleBin")
-			ERROR.Println(err)
-			return false
-		}
-	} else {
-		ERROR.Println("db.aerospike_conn == nil in UpdateAerospikeSingleBin")
-		return false
-	}
-}
-
-func (db *Database) ReplaceAerospikeSingleBin(key *aerospike.Key, value *aerospike.Bin) bool {
-	if db.aerospike_conn != nil {
-		writePolicy := &aerospike.WritePolicy{
-			BasePolicy:         *aerospike.NewPolicy(),
-			RecordExistsAction: aerospike.REPLACE, // https://github.com/aerospike/aerospike-client-go/blob/master/record_exists_action.go
-			GenerationPolicy:   aerospike.NONE,
-			CommitLevel:        aerospike.COMMIT_ALL,
-			Generation:         1,
-			Expiration:         0,
-			SendKey:            false,
-		}
-		err := db.aerospike_conn.PutBins(writePolicy, key, value)
-		if err == nil {
-			return true
-		} else {
-			ERROR.Println("error in ReplaceAerospikeSingleBin")
-			ERROR.Println(err)
-			return false
-		}
-	} else {
-		ERROR.Println("db.aerospike_conn == nil in ReplaceAerospikeSingleBin")
-		return false
-	}
-}
-
-func (db *Database) DeleteAerospike(key *aerospike.Key) bool {
-	if db.aerospike_conn != nil {
-		existed, err := db.aerospike_conn.Delete(nil, key)
-		if err == nil {
-			return existed
-		} else {
-			ERROR.Println("error in DeleteAerospike")
-			ERROR.Println(err)
-			return false
-		}
-	} else {
-		ERROR.Println("db.aerospike_conn == nil in DeleteAerospike")
-		return false
-	}
-}
-
-// getters
-func (db *Database) GetAerospikeUser(username string) UserStruct {
-	defer func() {
-		if r := recover(); r != nil {
-			ERROR.Println("Recovered in GetAerospikeUser", r)
-		}
-	}()
-	if strings.TrimSpace(username) == "" {
-		return UserStruct{} // return empty user if they passed in an empty username
-	}
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(username))
-	if err == nil {
-		rec := db.ReadAerospike(key)
-		if rec != nil {
-			user := FillUserWithAerospikeBins(rec.Bins)
-			// TRACE.Println("In GetAerospikeUser, user = " + user.ToJSONString())
-			return user
-		} else {
-			return UserStruct{}
-		}
-	} else {
-		ERROR.Println(err)
-		return UserStruct{}
-	}
-}
-
-func (db *Database) GetAerospikeUserActive(username string) *aerospike.Record {
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_ACTIVE_TABLE, strings.ToUpper(username))
-	if err == nil {
-		rec := db.ReadAerospike(key)
-		return rec
-	} else {
-		ERROR.Println(err)
-		return nil
-	}
-}
-
-/*
-//   NOW USING AQL TO QUERY INSTEAD OF ANOTHER TABLE
-func (db *Database) GetAerospikeUserByPhone(phonenum string) string {
-    key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_PHONES_TABLE, phonenum)
-    if err == nil {
-		rec := db.ReadAerospike(key)
-		Username := rec.Bins["Username"].(string)
-		return Username
-    } else {
-        ERROR.Println(err)
-        return ""
-    }
-}
-*/
-
-func (db *Database) GetAerospikeUserByPhone(phonenum string) UserStruct {
-	defer func() {
-		if r := recover(); r != nil {
-			ERROR.Println("Recovered in GetAerospikeUserByPhone", r)
-		}
-	}()
-	stmt := aerospike.NewStatement(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE)
-	stmt.Addfilter(aerospike.NewEqualFilter(AEROSPIKE_USERS_USERNAME_PHONE_BIN, phonenum))
-	if db.aerospike_conn == nil {
-		return UserStruct{} // can't do anything without a connection :(
-	}
-	// now query
-	rs, err := db.aerospike_conn.Query(nil, stmt)
-	// deal with error
-	if err != nil {
-		ERROR.Println("Error on db.aerospike_conn.Query in GetAerospikeUserByPhone: ")
-		ERROR.Println(err)
-	}
-
-	// should only be one result
-	for res := range rs.Results() {
-		if res.Err != nil {
-			// handle error here
-			// if you want to exit, cancel the recordset to release the resources
-			ERROR.Println("Error on db.aerospike_conn.Query in res.Err: ")
-			ERROR.Println(res.Err)
-			rs.Close()
-			return UserStruct{} // there was an error
-		} else {
-			// process record here
-			// TRACE.Println("res.Record.Bins: ")
-			// TRACE.Println(res.Record.Bins)
-			user := FillUserWithAerospikeBins(res.Record.Bins)
-			rs.Close()
-			return user
-		}
-	}
-	// if here, we didn't return a user above, so return nothing
-	return UserStruct{}
-}
-
-func (db *Database) GetAerospikeUserByEmail(email string) UserStruct {
-	defer func() {
-		if r := recover(); r != nil {
-			ERROR.Println("Recovered in GetAerospikeUserByEmail", r)
-		}
-	}()
-	stmt := aerospike.NewStatement(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE)
-	stmt.Addfilter(aerospike.NewEqualFilter(AEROSPIKE_USERS_USERNAME_EMAIL_BIN, email))
-	// TRACE.Println("in GetAerospikeUserByEmail, email = " + email)
-	if db.aerospike_conn == nil {
-		return UserStruct{} // can't do anything without a connection :(
-	}
-	// now query
-	rs, err := db.aerospike_conn.Query(nil, stmt)
-	// deal with error
+// marshals v and publishes it to active web devices
+func (db *Database) SendToWebDevices(webDevices []string, v interface{}) {
+	content, err := json.Marshal(v)
 	if err != nil {
-		ERROR.Println("Error on db.aerospike_conn.Query in GetAerospikeUserByEmail: ")
-		ERROR.Println(err)
-	}
-
-	// should only be one result
-	for res := range rs.Results() {
-		if res.Err != nil {
-			// handle error here
-			// if you want to exit, cancel the recordset to release the resources
-			ERROR.Println("Error on db.aerospike_conn.Query in res.Err: ")
-			ERROR.Println(res.Err)
-			rs.Close()
-			return UserStruct{} // there was an error
-		} else {
-			// process record here
-			// TRACE.Println("res.Record.Bins: ")
-			// TRACE.Println(res.Record.Bins)
-			user := FillUserWithAerospikeBins(res.Record.Bins)
-			rs.Close()
-			return user
-		}
-	}
-	// if here, we didn't return a user above, so return nothing
-	return UserStruct{}
-}
-
-func (db *Database) GetAerospikeConvoMembers(CID string) []string {
-	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_MEMBERS_KEY)
-	if err == nil {
-		rec := db.ReadAerospike(key)
-		if rec != nil {
-			members := InterfaceArrayToStringArray(rec.Bins["Members"].([]interface{}))
-			return members
-		} else {
-			return make([]string, 0)
-		}
-	} else {
-		ERROR.Println(err)
-		return make([]string, 0)
-	}
-}
-
-func (db *Database) GetAerospikeConvoName(CID string) string {
-	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_NAME_KEY)
-	if err == nil {
-		rec := db.ReadAerospike(key)
-		if rec != nil {
-			name := rec.Bins["Name"].(string)
-			return name
-		} else {
-			return ""
-		}
-	} else {
-		ERROR.Println(err)
-		return ""
-	}
-}
-
-func (db *Database) GetAerospikeConvoMtime(CID string) string {
-	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_MTIME_KEY)
-	if err == nil {
-		rec := db.ReadAerospike(key)
-		if rec != nil {
-			m_time := rec.Bins["M_time"].(string)
-			return m_time
-		} else {
-			return ""
-		}
-	} else {
-		ERROR.Println(err)
-		return ""
-	}
-}
-
-func (db *Database) GetAerospikeConvoFiles(CID string) []string {
-	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_FILES_KEY)
-	if err == nil {
-		rec := db.ReadAerospike(key)
-		if rec != nil {
-			files := InterfaceArrayToStringArray(rec.Bins["Files"].([]interface{}))
-			return files
-		} else {
-			return make([]string, 0)
-		}
-	} else {
-		ERROR.Println(err)
-		return make([]string, 0)
-	}
-}
-
-// setters
-func (db *Database) SetAerospikeUser(username string, data aerospike.BinMap) bool {
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(username))
-	if err == nil {
-		return db.WriteAerospikeMultipleBins(key, data)
-	} else {
-		ERROR.Println(err)
-		return false
-	}
-}
-
-func (db *Database) SetAerospikeUserActive(username string, devices aerospike.BinMap) bool {
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_ACTIVE_TABLE, strings.ToUpper(username))
-	if err == nil {
-		return db.WriteAerospikeMultipleBins(key, devices)
-	} else {
-		ERROR.Println(err)
-		return false
-	}
-}
-
-func (db *Database) SetAerospikeUserPhone(phonenum string, user aerospike.BinMap) bool {
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_PHONES_TABLE, phonenum)
-	if err == nil {
-		return db.WriteAerospikeMultipleBins(key, user)
-	} else {
-		ERROR.Println(err)
-		return false
-	}
-}
-
-func (db *Database) SetAerospikeConvoMembers(CID string, members []string) bool {
-	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_MEMBERS_KEY)
-	if err == nil {
-		bins := aerospike.BinMap{
-			"Members": members,
-		}
-		return db.WriteAerospikeMultipleBins(key, bins)
-	} else {
-		ERROR.Println(err)
-		return false
-	}
-}
-
-func (db *Database) SetAerospikeConvoName(CID string, name string) bool {
-	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_NAME_KEY)
-	if err == nil {
-		bins := aerospike.BinMap{
-			"Name": name,
-		}
-		return db.WriteAerospikeMultipleBins(key, bins)
-	} else {
-		ERROR.Println(err)
-		return false
-	}
-}
-
-func (db *Database) SetAerospikeConvoMtime(CID string, mtime string) bool {
-	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_MTIME_KEY)
-	if err == nil {
-		// save as bins
-		bins := aerospike.BinMap{
-			"M_time": mtime,
-		}
-		return db.WriteAerospikeMultipleBins(key, bins)
-	} else {
-		ERROR.Println(err)
-		return false
-	}
-}
-
-func (db *Database) SetAerospikeConvoFiles(CID string, files []string) bool {
-	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_FILES_KEY)
-	if err == nil {
-		// save as bins
-		bins := aerospike.BinMap{
-			"Files": files,
-		}
-		return db.WriteAerospikeMultipleBins(key, bins)
-	} else {
-		ERROR.Println(err)
-		return false
-	}
-}
-
-// deleters
-func (db *Database) DeleteAerospikeUser(username string) bool {
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(username))
-	if err == nil {
-		return db.DeleteAerospike(key)
-	} else {
-		ERROR.Println(err)
-		return false
-	}
-}
-
-func (db *Database) DeleteAerospikeUserActive(username string) bool {
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_ACTIVE_TABLE, strings.ToUpper(username))
-	if err == nil {
-		return db.DeleteAerospike(key)
-	} else {
-		ERROR.Println(err)
-		return false
-	}
-}
-
-func (db *Database) DeleteAerospikeUserPhone(phonenum string) bool {
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_PHONES_TABLE, phonenum)
-	if err == nil {
-		return db.DeleteAerospike(key)
-	} else {
-		ERROR.Println(err)
-		return false
-	}
-}
-
-func (db *Database) DeleteAerospikeConvoMembers(CID string) bool {
-	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_MEMBERS_KEY)
-	if err == nil {
-		return db.DeleteAerospike(key)
-	} else {
-		ERROR.Println(err)
-		return false
-	}
-}
-
-func (db *Database) DeleteAerospikeConvoName(CID string) bool {
-	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_NAME_KEY)
-	if err == nil {
-		return db.DeleteAerospike(key)
-	} else {
-		ERROR.Println(err)
-		return false
-	}
-}
-
-func (db *Database) DeleteAerospikeConvoMtime(CID string) bool {
-	key, err := aerospike.NewKey(AEROSPIKE_CONVOS_NAMESPACE, CID, AEROSPIKE_CONVOS_MTIME_KEY)
-	if err == nil {
-		return db.DeleteAerospike(key)
-	} else {
-		ERROR.Println(err)
-		return false
+		ERROR.Println("error in json.Marshal in SendToWebDevices:", err)
+		return
 	}
+	db.SendStringToWebDevices(webDevices, string(content))
 }
 
 // helper for publishing to active web devices
 func (db *Database) SendStringToWebDevices(webDevices []string, content string) {
-	if db.nats_encodedconn == nil {
-		return // don't do anything if nats is nil!
+	if db.Events == nil {
+		return // don't do anything if there's no event bus!
 	}
 	for _, webz := range webDevices {
-		db.nats_encodedconn.Publish(webz, content)
+		db.Events.Publish(webz, content)
 	}
 }
 
+var (
+	ErrUsernameTaken = cmdError(ERR_CONFLICT, "Username already taken.")
+	ErrUserNotSaved  = cmdError(ERR_UNAVAILABLE, "the user couldn't be saved, try again")
+	ErrUserNotFound  = cmdError(ERR_NOT_FOUND, "there's no user with that name")
+	ErrBadPassword   = cmdError(ERR_INVALID_CREDENTIALS, "wrong username or password")
+	ErrNotSaved      = cmdError(ERR_UNAVAILABLE, "the change couldn't be saved, try again")
+	ErrMessagesDown  = cmdError(ERR_UNAVAILABLE, "messages can't be reached right now, try again")
+	ErrEmailsDown    = cmdError(ERR_UNAVAILABLE, "emails can't be reached right now, try again")
+)
+
 // actual Database functions
-func (db *Database) CreateUser(data string) UserStruct {
-	jsondata := CreateUserCmdStruct{}
-	json.Unmarshal([]byte(data), &jsondata)
+func (db *Database) CreateUser(jsondata *CreateUserCmdStruct) (*UserStruct, error) {
 	// check if user already exists
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
+	storeduser := db.Users.GetUser(strings.ToUpper(jsondata.Username))
 	if storeduser.UsernameUpper != "" {
 		// TRACE.Println("user already found in db, don't register!")
-		return UserStruct{}
+		return nil, ErrUsernameTaken
 	}
 	// hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(jsondata.Password), bcrypt.DefaultCost)
@@ -521,7 +67,7 @@ func (db *Database) CreateUser(data string) UserStruct {
 		SecQuests:     jsondata.SecQuests,
 		Quota:         2000000, // 2GB
 		QuotaUsed:     0,
-		ProfilePic:    "https://pingedchat-us1.s3.amazonaws.com/defaultprofile.png",
+		ProfilePic:    S3PublicURL() + "defaultprofile.png",
 		// Friends: make([]string, 0), // we use a struct, so we Marshal and Unmarshal into a string here
 		// PendingFriends: make([]string, 0), // we use a struct, so we Marshal and Unmarshal into a string here
 		CIDs:              "",
@@ -534,23 +80,6 @@ func (db *Database) CreateUser(data string) UserStruct {
 	}
 	user.Web[0] = jsondata.Token
 
-	// create postgres table for email
-	// email table structure:
-	// [   FROM_EMAIL   |   TO_EMAILS    |  RECV_EMAIL  |  SUBJECT  |  CONTENT  | ATTACHMENTS |  STARRED  |  UNREAD  |   SPAM   |  DRAFT  |  DELETED  |  RECV_TIME  |    M_TIME   ]
-	// [     varchar    |    varchar     |   varchar    |  varchar  |  varchar  |   varchar   |  boolean  |  boolean |  boolean | boolean |  boolean  | timestamptz |  timestamptz ]
-	PostgresTableNamestring := `"` + jsondata.Username + `@pinged.email` + `"`
-	CurString := "CREATE TABLE " + PostgresTableNamestring + " (from_email varchar NOT NULL, to_emails varchar NOT NULL, recv_email varchar NOT NULL, subject varchar, content varchar, attachments varchar, starred boolean, unread boolean, spam boolean, draft boolean, deleted boolean, recv_time timestamptz NOT NULL, m_time timestamptz NOT NULL, PRIMARY KEY (from_email, recv_time) );"
-	// TRACE.Println("CurString: " + CurString)
-	if db.postgres_conn != nil {
-		_, createerr := db.postgres_conn.Exec(CurString)
-		if createerr != nil {
-			ERROR.Println("error creating email postgres table in CreateUser: ", createerr)
-			// maybe add flag to user struct to show email wasn't created successfully?
-		}
-	} else {
-		// maybe add flag to user struct to show email wasn't created successfully?
-	}
-
 	// hash each security question also
 	secQuests := user.GetSecurityQuestionStructs()
 	for i, e := range secQuests {
@@ -562,138 +91,126 @@ func (db *Database) CreateUser(data string) UserStruct {
 		secQuests[i].Answer = string(hashedAnswer)
 	}
 	user.SaveSecurityQuestionStructs(secQuests) // save back to user
-	userbins := user.ToAerospikeBins()
-	writeSuccess := db.SetAerospikeUser(user.UsernameUpper, userbins)
-	if writeSuccess {
-		// TRACE.Println("in CreateUser, user = " + user.ToJSONString())
-		// link NATS
-		if db.nats_encodedconn != nil {
-			db.nats_encodedconn.BindRecvChan(jsondata.Token, db.nats_receive)
+	session, sessionToken := user.startSession(jsondata.Token, time.Now())
+	// generation 0 only creates, so two people registering the same name at
+	// once can't both get it
+	if err := db.Users.CheckAndSetUser(user, 0); err == ErrStaleUser {
+		return nil, ErrUsernameTaken
+	} else if err != nil {
+		ERROR.Println("error saving the user in CreateUser:", err)
+		return nil, ErrUserNotSaved
+	}
+	// TRACE.Println("in CreateUser, user = " + user.ToJSONString())
+
+	// create mailbox for email
+	if db.Emails != nil {
+		createerr := db.Emails.CreateMailbox(jsondata.Username)
+		if createerr != nil {
+			ERROR.Println("error creating email mailbox in CreateUser: ", createerr)
+			// maybe add flag to user struct to show email wasn't created successfully?
 		}
-		return user
 	} else {
-		return UserStruct{}
+		// maybe add flag to user struct to show email wasn't created successfully?
 	}
-
+	// link NATS
+	db.subscribe(jsondata.Token)
+	db.Authenticate(user.Username, jsondata.Token, session)
+	user.SessionToken = sessionToken
+	return &user, nil
 }
 
-func (db *Database) ValidateUser(data string) UserStruct {
-	jsondata := ValidateUserCmdStruct{}
-	json.Unmarshal([]byte(data), &jsondata)
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
+func (db *Database) ValidateUser(jsondata *ValidateUserCmdStruct) (*UserStruct, error) {
+	storeduser := db.Users.GetUser(strings.ToUpper(jsondata.Username))
 	err := bcrypt.CompareHashAndPassword([]byte(storeduser.Password), []byte(jsondata.Password))
 	if err == nil {
 		// TRACE.Println("user password match!")
-		web_devs := storeduser.Web
-		storeduser.Web = append(web_devs, jsondata.Token)
-		webBin := aerospike.NewBin("Web", storeduser.Web)
-		key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-		if err == nil {
-			db.UpdateAerospikeSingleBin(key, webBin)
+		var session, sessionToken string
+		storeduser, err = db.updateUser(storeduser.UsernameUpper, func(user *UserStruct) error {
+			// a device logging in again keeps its one entry
+			user.Web = append(removeString(user.Web, jsondata.Token), jsondata.Token)
+			session, sessionToken = user.startSession(jsondata.Token, time.Now())
+			db.refreshCIDMtimes(user)
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 		// link NATS
-		if db.nats_encodedconn != nil {
-			db.nats_encodedconn.BindRecvChan(jsondata.Token, db.nats_receive)
-		}
-		// create CID struct using latest data
-		userCIDs := storeduser.GetCIDStructs()
-		for i, _ := range userCIDs {
-			userCIDs[i].M_time = db.GetAerospikeConvoMtime(userCIDs[i].CID)
-		}
-		storeduser.SaveCIDStructs(userCIDs)
-		db.SetAerospikeUser(storeduser.UsernameUpper, storeduser.ToAerospikeBins())
-		return storeduser
+		db.subscribe(jsondata.Token)
+		db.Authenticate(storeduser.Username, jsondata.Token, session)
+		storeduser.SessionToken = sessionToken
+		return &storeduser, nil
 	} else {
 		// TRACE.Println("incorrect user password")
-		return UserStruct{}
+		return nil, ErrBadPassword
+	}
+}
+
+// create CID structs using latest data
+func (db *Database) refreshCIDMtimes(user *UserStruct) {
+	userCIDs := user.GetCIDStructs()
+	for i, _ := range userCIDs {
+		userCIDs[i].M_time = db.Convos.GetConvoMtime(userCIDs[i].CID)
 	}
+	user.SaveCIDStructs(userCIDs)
 }
 
-func (db *Database) DeleteUser(data string) string {
-	jsondata := ValidateUserCmdStruct{}
-	json.Unmarshal([]byte(data), &jsondata)
+func (db *Database) DeleteUser(jsondata *ValidateUserCmdStruct) (*UsernameCmdStruct, error) {
 	// first get user and update all devices that the user has been deleted
-	user := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	db.SendStringToWebDevices(user.Web, data)
+	user := db.Users.GetUser(strings.ToUpper(jsondata.Username))
+	if user.Username == "" {
+		return nil, ErrUserNotFound
+	}
+	deleted := &UsernameCmdStruct{Cmd: "DeleteUser", Username: user.Username}
+	db.SendToWebDevices(user.Web, deleted)
 	// now loop through all pending friends (both incoming and outgoing) and
 	// accepted friends and remove user from all lists
 	// first loop through incoming friend requests and remove from outgoing friend requests
-	userIncomingPendingFriends := user.GetIncomingPendingFriendStructs()
-	for _, pendingFriend := range userIncomingPendingFriends {
-		friend := db.GetAerospikeUser(strings.ToUpper(pendingFriend.Username))
-		friendOutgoingPendingFriends := friend.GetOutgoingPendingFriendStructs()
-		for i2, pendingFriend2 := range friendOutgoingPendingFriends {
-			if strings.ToUpper(pendingFriend2.Username) == strings.ToUpper(user.UsernameUpper) {
-				friendOutgoingPendingFriends = append(friendOutgoingPendingFriends[:i2], friendOutgoingPendingFriends[i2+1:]...)
-				friend.SaveOutgoingPendingFriendStructs(friendOutgoingPendingFriends)
-				db.SetAerospikeUser(friend.UsernameUpper, friend.ToAerospikeBins()) // save back
-				// send update string
-				db.SendStringToWebDevices(friend.Web, friend.ToJSONStringWithCmd("UpdateUser"))
-				break
-			}
-		}
+	for _, pendingFriend := range user.GetIncomingPendingFriendStructs() {
+		db.updateUserAndNotify(pendingFriend.Username, "UpdateUser", CHANGE_FRIENDS, func(friend *UserStruct) (removed bool) {
+			outgoing, removed := removeFriend(friend.GetOutgoingPendingFriendStructs(), user.Username)
+			friend.SaveOutgoingPendingFriendStructs(outgoing)
+			return
+		})
 	}
 
 	// now loop through outgoing friend requests and delete from friend's incoming requests
-	userOutgoingPendingFriends := user.GetOutgoingPendingFriendStructs()
-	for _, pendingFriend := range userOutgoingPendingFriends {
-		friend := db.GetAerospikeUser(strings.ToUpper(pendingFriend.Username))
-		friendIncomingPendingFriends := friend.GetIncomingPendingFriendStructs()
-		for i2, pendingFriend2 := range friendIncomingPendingFriends {
-			if strings.ToUpper(pendingFriend2.Username) == strings.ToUpper(user.UsernameUpper) {
-				friendIncomingPendingFriends = append(friendIncomingPendingFriends[:i2], friendIncomingPendingFriends[i2+1:]...)
-				friend.SaveIncomingPendingFriendStructs(friendIncomingPendingFriends)
-				db.SetAerospikeUser(friend.UsernameUpper, friend.ToAerospikeBins()) // save back
-				// send update string
-				db.SendStringToWebDevices(friend.Web, friend.ToJSONStringWithCmd("UpdateUser"))
-				break
-			}
-		}
+	for _, pendingFriend := range user.GetOutgoingPendingFriendStructs() {
+		db.updateUserAndNotify(pendingFriend.Username, "UpdateUser", CHANGE_FRIENDS, func(friend *UserStruct) (removed bool) {
+			incoming, removed := removeFriend(friend.GetIncomingPendingFriendStructs(), user.Username)
+			friend.SaveIncomingPendingFriendStructs(incoming)
+			return
+		})
 	}
 
 	// now loop through accepted friends and remove from their friend lists
-	userFriends := user.GetFriendStructs()
-	for _, acceptedFriend := range userFriends {
-		friend := db.GetAerospikeUser(strings.ToUpper(acceptedFriend.Username))
-		friendFriends := friend.GetFriendStructs()
-		for i2, pendingFriend2 := range friendFriends {
-			if strings.ToUpper(pendingFriend2.Username) == strings.ToUpper(user.UsernameUpper) {
-				friendFriends = append(friendFriends[:i2], friendFriends[i2+1:]...)
-				friend.SaveFriendStructs(friendFriends)
-				db.SetAerospikeUser(friend.UsernameUpper, friend.ToAerospikeBins()) // save back
-				// send update string
-				db.SendStringToWebDevices(friend.Web, friend.ToJSONStringWithCmd("UpdateUser"))
-				break
-			}
-		}
+	for _, acceptedFriend := range user.GetFriendStructs() {
+		db.updateUserAndNotify(acceptedFriend.Username, "UpdateUser", CHANGE_FRIENDS, func(friend *UserStruct) (removed bool) {
+			friends, removed := removeFriend(friend.GetFriendStructs(), user.Username)
+			friend.SaveFriendStructs(friends)
+			return
+		})
 	}
 
 	// now actually delete user
-	db.DeleteAerospikeUser(strings.ToUpper(user.UsernameUpper))
+	db.Users.DeleteUser(strings.ToUpper(user.UsernameUpper))
 	// delete email database
-	CurString := `DROP TABLE "` + user.Username + `@pinged.email";`
-	// TRACE.Println("cur_string = " + CurString)
-	if db.postgres_conn == nil {
-		ERROR.Println("db.postgres_conn == nil in DeleteUser") // can't do anything with no database connection :(
-	}
-	_, droperr := db.postgres_conn.Exec(CurString)
+	droperr := db.Emails.DropMailbox(user.Username)
 	if droperr != nil {
-		ERROR.Println("error dropping Postgres table in DeleteUser: ", droperr)
+		ERROR.Println("error dropping mailbox in DeleteUser: ", droperr)
 	}
-	return data // return DeleteUser, so web app knows to delete user
+	if db.Changes != nil {
+		if err := db.Changes.DeleteChanges(user.Username); err != nil {
+			ERROR.Println("error deleting the change log in DeleteUser: ", err)
+		}
+	}
+	return deleted, nil // return DeleteUser, so web app knows to delete user
 }
 
-func (db *Database) GetPasswordResetUser(data string) string {
-	cmdJSON := CommonCmdStruct{}
-	err := json.Unmarshal([]byte(data), &cmdJSON)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-	user := db.GetAerospikeUser(strings.ToUpper(cmdJSON.Username))
+func (db *Database) GetPasswordResetUser(cmdJSON *UsernameCmdStruct) (*PasswordResetUserStruct, error) {
+	user := db.Users.GetUser(strings.ToUpper(cmdJSON.Username))
 	if user.Username == "" {
-		ERROR.Println("user in GetPasswordResetUser does not exist")
-		return data // just send back what we got
+		return nil, ErrUserNotFound
 	}
 	// TRACE.Println("user.SecQuests = " + user.SecQuests)
 	secQuests := user.GetSecurityQuestionStructs()
@@ -703,29 +220,17 @@ func (db *Database) GetPasswordResetUser(data string) string {
 	}
 	// TRACE.Println("questions in GetPasswordResetUser:")
 	// TRACE.Println(retuser.Questions)
-	retuser.Cmd = "GetPasswordResetUser" // return same as incoming command
 	retuser.Username = cmdJSON.Username
-	retUserString, err := ffjson.Marshal(retuser)
-	if err != nil {
-		ERROR.Println("Error in ffjson.Marshal(retuser)")
-		ERROR.Println(err)
-	}
-	// TRACE.Println("retUserString = "  + string(retUserString))
-	return string(retUserString)
+	return &retuser, nil
 }
 
-func (db *Database) ResetUserPassword(data string) string {
+func (db *Database) ResetUserPassword(jsondata *CreateUserCmdStruct) error {
 	// TRACE.Println("in ResetUserPassword")
-	jsondata := CreateUserCmdStruct{}
-	json.Unmarshal([]byte(data), &jsondata)
 	// check if user already exists
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
+	storeduser := db.Users.GetUser(strings.ToUpper(jsondata.Username))
 	if storeduser.UsernameUpper == "" {
 		// TRACE.Println("user not found, returning from ResetUserPassword")
-		return `{"cmd":"ResetUserPassword", "success":"false"}`
-	}
-	if len(jsondata.Password) < 6 {
-		return `{"cmd":"ResetUserPassword", "success":"false"}`
+		return ErrUserNotFound
 	}
 	// compare answers
 	// TRACE.Println("storeduser.SecQuests = " + storeduser.SecQuests)
@@ -765,598 +270,330 @@ func (db *Database) ResetUserPassword(data string) string {
 		if err != nil {
 			ERROR.Println("Error in bcrypt.GenerateFromPassword()")
 			ERROR.Println(err)
-			return `{"cmd":"ResetUserPassword", "success":"false"}` // no password update
+			return err // no password update
 		}
 		// TRACE.Println("setting new hashed password to " + storeduser.Username)
-		storeduser.Password = string(hashedPassword)
-		passwordBin := aerospike.NewBin("Password", storeduser.Password)
-		key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-		if err == nil {
-			db.UpdateAerospikeSingleBin(key, passwordBin)
+		var revoked []SessionStruct
+		_, err = db.updateUser(storeduser.UsernameUpper, func(user *UserStruct) error {
+			user.Password = string(hashedPassword)
+			// whoever knew the old password is logged out everywhere
+			revoked = revokeSessions(user, "")
+			return nil
+		})
+		if err != nil {
+			return err
 		}
-		// return success
-		return `{"cmd":"ResetUserPassword", "success":"true"}`
+		db.sendSessionsRevoked(storeduser.UsernameUpper, revoked)
+		return nil
 	}
 	// if here, then we didn't successfully complete the password reset above
-	return `{"cmd":"ResetUserPassword", "success":"false"}`
+	return cmdError(ERR_INVALID_CREDENTIALS, "two security answers have to match")
 }
 
-func (db *Database) ChangeUserPassword(data string) string {
-	jsondata := ChangeUserPasswordStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
+func (db *Database) ChangeUserPassword(jsondata *ChangeUserPasswordStruct) error {
+	storeduser := db.Users.GetUser(strings.ToUpper(jsondata.Username))
 	// compare old password first
 	err2 := bcrypt.CompareHashAndPassword([]byte(storeduser.Password), []byte(jsondata.OldPassword))
-	if err2 == nil {
-		// passwords match!  now set new password.
-		hashedPassword, err3 := bcrypt.GenerateFromPassword([]byte(jsondata.NewPassword), bcrypt.DefaultCost)
-		if err2 != nil {
-			ERROR.Println("Error in bcrypt.GenerateFromPassword()")
-			ERROR.Println(err3)
-			return `{"cmd":"ResetUserPassword", "success":"false"}` // no password update
-		}
-		// TRACE.Println("setting new hashed password to " + storeduser.Username)
-		storeduser.Password = string(hashedPassword)
-		passwordBin := aerospike.NewBin("Password", storeduser.Password)
-		key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-		if err == nil {
-			db.UpdateAerospikeSingleBin(key, passwordBin)
-		}
-		// return success
-		return `{"cmd":"ChangeUserPassword", "success":"true"}`
-	} else {
-		return `{"cmd":"ChangeUserPassword", "success":"false"}`
+	if err2 != nil {
+		return ErrBadPassword
 	}
-	// if here, we were not successful somewhere
-	return `{"cmd":"ChangeUserPassword", "success":"false"}`
-}
-
-func (db *Database) AddToQuota(data string) string {
-	jsondata := QuotaCmdStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
+	// passwords match!  now set new password.
+	hashedPassword, err3 := bcrypt.GenerateFromPassword([]byte(jsondata.NewPassword), bcrypt.DefaultCost)
+	if err3 != nil {
+		ERROR.Println("Error in bcrypt.GenerateFromPassword()")
+		ERROR.Println(err3)
+		return err3 // no password update
+	}
+	// TRACE.Println("setting new hashed password to " + storeduser.Username)
+	var revoked []SessionStruct
+	_, err := db.updateUser(storeduser.UsernameUpper, func(user *UserStruct) error {
+		user.Password = string(hashedPassword)
+		// the other devices have to log in with the new password
+		revoked = revokeSessions(user, db.Session())
+		return nil
+	})
 	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
+		return err
 	}
-	// get stored user
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
+	db.sendSessionsRevoked(storeduser.UsernameUpper, revoked)
+	return nil
+}
+
+func (db *Database) AddToQuota(jsondata *QuotaCmdStruct) error {
 	// add to quota
-	storeduser.Quota += jsondata.Quota
-	// save back
-	QuotaUsedBin := aerospike.NewBin("Quota", int(storeduser.Quota))
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, QuotaUsedBin)
+	storeduser, err := db.updateUser(jsondata.Username, func(user *UserStruct) error {
+		user.Quota += jsondata.Quota
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	// send our string we received to all devices, they'll add it in themselves easily enough
-	db.SendStringToWebDevices(storeduser.Web, data) // send to all web devices
-	return ""                                       // we sent to web devices above, so don't send twice
+	// send the command we received to all devices, they'll add it in themselves easily enough
+	db.SendToWebDevices(storeduser.Web, jsondata) // send to all web devices
+	return nil
 }
 
-func (db *Database) AddToQuotaUsed(data string) string {
-	jsondata := QuotaCmdStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
+func (db *Database) AddToQuotaUsed(jsondata *QuotaCmdStruct) error {
+	storeduser, err := db.updateUser(jsondata.Username, func(user *UserStruct) error {
+		user.QuotaUsed += jsondata.QuotaUsed
+		if user.QuotaUsed > user.Quota {
+			return cmdError(ERR_QUOTA_EXCEEDED, "You are over your storage quota!  Please add more space to keep uploading media.")
+		} else if user.QuotaUsed == user.Quota {
+			return cmdError(ERR_QUOTA_EXCEEDED, "You have reached your storage quota!  Please add more space to keep uploading media.")
+		}
+		return nil
+	})
 	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-	// get stored user
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	storeduser.QuotaUsed += jsondata.QuotaUsed
-	if storeduser.QuotaUsed > storeduser.Quota {
-		return `{"cmd":"AddToQuotaUsed", "message":"You are over your storage quota!  Please add more space to keep uploading media."}`
-	} else if storeduser.QuotaUsed == storeduser.Quota {
-		return `{"cmd":"AddToQuotaUsed", "message":"You have reached your storage quota!  Please add more space to keep uploading media."}`
-	}
-	// save back
-	QuotaUsedBin := aerospike.NewBin("QuotaUsed", int(storeduser.QuotaUsed))
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, QuotaUsedBin)
+		return err
 	}
-	// send our string we received to all devices, they'll add it in themselves easily enough
-	db.SendStringToWebDevices(storeduser.Web, data) // send to all web devices
-	return ""                                       // we sent to web devices above, so don't send twice
+	// send the command we received to all devices, they'll add it in themselves easily enough
+	db.SendToWebDevices(storeduser.Web, jsondata) // send to all web devices
+	return nil
 }
 
-func (db *Database) AddScheduledMessage(data string) string {
-	jsondata := ScheduledMessagesCmdStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
+func (db *Database) AddScheduledMessage(jsondata *ScheduledMessagesCmdStruct) error {
+	if db.Messages == nil {
+		return ErrMessagesDown // the ticker couldn't send it
 	}
-	// get stored user
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
 	// create new scheduled message and append
 	newScheduledMessage := ScheduledMessagesStruct{
 		CID:     jsondata.CID,
 		Time:    jsondata.Time,
 		Content: jsondata.Content,
 	}
-	userScheduledMessages := storeduser.GetScheduledMessagesStructs()
-	userScheduledMessages = append(userScheduledMessages, newScheduledMessage)
-	storeduser.SaveScheduledMessagesStructs(userScheduledMessages)
-	// now save user's scheduled messages back
-	ScheduledMessagesBin := aerospike.NewBin("SchedMessages", storeduser.ScheduledMessages)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, ScheduledMessagesBin)
+	storeduser, err := db.updateUser(jsondata.Username, func(user *UserStruct) error {
+		user.SaveScheduledMessagesStructs(append(user.GetScheduledMessagesStructs(), newScheduledMessage))
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	// send our string we received to all devices, they'll add it in themselves easily enough
-	db.SendStringToWebDevices(storeduser.Web, data) // send to all web devices
+	// send the command we received to all devices, they'll add it in themselves easily enough
+	db.sendChange(storeduser, CHANGE_SCHEDULED, jsondata) // send to all web devices
 
-	// now we'll add to new postgres table
-	CurString := "INSERT INTO " + POSTGRES_SCHEDULED_MESSAGES_TABLE + " (CID, f_username, content, m_time) VALUES ($1, $2, $3, $4)" // conversation ID is the table name
-	// TRACE.Println("cur_string = " + CurString)
-	if db.postgres_conn == nil {
-		ERROR.Println("db.postgres_conn == nil in AddScheduledMessage") // can't do anything with no database connection :(
-	}
-	_, inserterr := db.postgres_conn.Exec(CurString, jsondata.CID, jsondata.Username, jsondata.Content, jsondata.Time)
+	// now we'll add to the scheduled messages store
+	inserterr := db.Messages.AddScheduledMessage(*jsondata)
 	if inserterr != nil {
-		ERROR.Println("error adding to Postgres table in AddScheduledMessage: ", inserterr)
-		return ""
+		ERROR.Println("error adding scheduled message in AddScheduledMessage: ", inserterr)
+		return inserterr
 	}
-	return "" // we sent to web devices above, so don't send twice
+	return nil
 }
 
-func (db *Database) RemoveScheduledMessage(data string) string {
+func (db *Database) RemoveScheduledMessage(jsondata *ScheduledMessagesCmdStruct) error {
 	// TRACE.Println("removing user " + username + " android device " + android)
-	jsondata := ScheduledMessagesCmdStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	userScheduledMessages := storeduser.GetScheduledMessagesStructs()
-	// loop through web devices, if web device is not the token add to slice
-	// then save back to the user
-	for i, e := range userScheduledMessages {
-		TRACE.Println("e.Time = " + e.Time + " , jsondata.Time = " + jsondata.Time)
-		eTime, _ := time.Parse(time.RFC3339Nano, e.Time)
-		jsonTime, _ := time.Parse(time.RFC3339Nano, jsondata.Time)
-		TRACE.Println("eTime.Equal(jsonTime) : ")
-		TRACE.Println(eTime.Equal(jsonTime))
-		if eTime.Equal(jsonTime) && e.CID == jsondata.CID && e.Content == jsondata.Content {
-			userScheduledMessages = append(userScheduledMessages[:i], userScheduledMessages[i+1:]...) // splice out
+	jsonTime, _ := time.Parse(time.RFC3339Nano, jsondata.Time)
+	storeduser, err := db.updateUser(jsondata.Username, func(user *UserStruct) error {
+		// keep every scheduled message but the one being removed
+		kept := make([]ScheduledMessagesStruct, 0)
+		for _, e := range user.GetScheduledMessagesStructs() {
+			eTime, _ := time.Parse(time.RFC3339Nano, e.Time)
+			if !(eTime.Equal(jsonTime) && e.CID == jsondata.CID && e.Content == jsondata.Content) {
+				kept = append(kept, e)
+			}
 		}
+		user.SaveScheduledMessagesStructs(kept)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	storeduser.SaveScheduledMessagesStructs(userScheduledMessages)
 	TRACE.Println("in RemoveScheduledMessage, storeduser.ScheduledMessages = ")
 	TRACE.Println(storeduser.ScheduledMessages)
-	ScheduledMessagesBin := aerospike.NewBin("SchedMessages", storeduser.ScheduledMessages)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, ScheduledMessagesBin)
-	}
 	retstr := storeduser.ToJSONStringWithCmd("RemoveScheduledMessage")
-	db.SendStringToWebDevices(storeduser.Web, string(retstr)) // send to all web devices
+	db.sendChangeString(storeduser, CHANGE_SCHEDULED, retstr) // send to all web devices
 
 	// now remove from db if there
-	CurString := `DELETE FROM ` + POSTGRES_SCHEDULED_MESSAGES_TABLE + ` WHERE CID = $1 AND m_time = $2 AND f_username = $3 AND content = $4;`
-	TRACE.Println("cur_string = " + CurString)
-	if db.postgres_conn == nil {
-		ERROR.Println("in handleScheduledMessages, db.postgres_conn == nil , so returning :(")
-		return "" // can't do anything with no database connection :(
+	deleteErr := db.Messages.RemoveScheduledMessage(*jsondata)
+	if deleteErr != nil {
+		logPqError("RemoveScheduledMessage", deleteErr)
+		return ErrNotSaved // it'd still be sent
 	}
-	m_time, _ := time.Parse(time.RFC3339Nano, jsondata.Time)
-	_, deleteErr := db.postgres_conn.Exec(CurString, jsondata.CID, m_time, jsondata.Username, jsondata.Content)
-	if deleteErr, ok := deleteErr.(*pq.Error); ok {
-		ERROR.Println("pq delete error in RemoveScheduledMessage:", deleteErr.Code.Name())
-		ERROR.Println(deleteErr)
-	}
-	return "" // we sent to web devices above, so don't send twice
+	return nil
 }
 
-func (db *Database) RemoveAllScheduledMessages(data string) string {
+func (db *Database) RemoveAllScheduledMessages(jsondata *ScheduledMessagesCmdStruct) error {
 	// TRACE.Println("removing user " + username + " android device " + android)
-	jsondata := ScheduledMessagesCmdStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
+	storeduser, err := db.updateUser(jsondata.Username, func(user *UserStruct) error {
+		user.ScheduledMessages = "" // simply clear them out like this
+		return nil
+	})
 	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	storeduser.ScheduledMessages = "" // simply clear them out like this
-	ScheduledMessagesBin := aerospike.NewBin("SchedMessages", storeduser.ScheduledMessages)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, ScheduledMessagesBin)
+		return err
 	}
 	retstr := `{"cmd":"RemoveAllScheduledMessages"}`
-	db.SendStringToWebDevices(storeduser.Web, string(retstr)) // send to all web devices
+	db.sendChangeString(storeduser, CHANGE_SCHEDULED, retstr) // send to all web devices
 
 	// now remove from db if there
-	CurString := `DELETE FROM ` + POSTGRES_SCHEDULED_MESSAGES_TABLE + ` WHERE f_username = $1;`
-	TRACE.Println("cur_string = " + CurString)
-	if db.postgres_conn == nil {
-		ERROR.Println("in handleScheduledMessages, db.postgres_conn == nil , so returning :(")
-		return "" // can't do anything with no database connection :(
+	deleteErr := db.Messages.RemoveAllScheduledMessages(jsondata.Username)
+	if deleteErr != nil {
+		logPqError("RemoveAllScheduledMessages", deleteErr)
+		return ErrNotSaved // they'd still be sent
 	}
-	_, deleteErr := db.postgres_conn.Exec(CurString, jsondata.Username)
-	if deleteErr, ok := deleteErr.(*pq.Error); ok {
-		ERROR.Println("pq delete error in RemoveAllScheduledMessage:", deleteErr.Code.Name())
-		ERROR.Println(deleteErr)
-	}
-	return "" // we sent to web devices above, so don't send twice
+	return nil
 }
 
-func (db *Database) AddAndroidDev(data string) string {
-	jsondata := ChangeDeviceStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	// first make sure that the device is not already stored
-	dev_found := false
-	for _, e := range storeduser.Android {
-		if e == jsondata.Device {
-			dev_found = true
-			break
+// adds device unless it's already there
+func addDevice(devices []string, device string) ([]string, bool) {
+	for _, e := range devices {
+		if e == device {
+			return devices, false
 		}
 	}
-	if !dev_found {
-		TRACE.Println("adding android device : " + jsondata.Device)
-		// device is not added to user, so append and save
-		storeduser.Android = append(storeduser.Android, jsondata.Device)
-		androidBin := aerospike.NewBin("Android", storeduser.Android)
-		key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-		if err == nil {
-			db.UpdateAerospikeSingleBin(key, androidBin)
-		}
-		retstr := storeduser.ToJSONStringWithCmd("AddAndroidDev")
-		db.SendStringToWebDevices(storeduser.Web, string(retstr)) // send to all web devices
-		return ""                                                 // we sent to web devices above, so don't send twice
-	}
-	return ""
+	return append(devices, device), true
 }
 
-func (db *Database) ChangeAndroidDev(data string) string {
-	jsondata := ChangeDeviceStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
+func removeDevice(devices []string, device string) ([]string, bool) {
+	kept := removeString(devices, device)
+	return kept, len(kept) != len(devices)
+}
 
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	// first, remove old device
-	for i, e := range storeduser.Android {
-		if e == jsondata.OldDevice {
-			storeduser.Android = append(storeduser.Android[:i], storeduser.Android[i+1:]...) // splice out
-			break
-		}
-	}
-	// now make sure that the new device is not already stored
-	dev_found := false
-	for _, e := range storeduser.Android {
-		if e == jsondata.Device {
-			dev_found = true
-			break
-		}
-	}
-	if !dev_found {
+func (db *Database) AddAndroidDev(jsondata *ChangeDeviceStruct) error {
+	return db.updateUserAndNotify(jsondata.Username, "AddAndroidDev", CHANGE_USER, func(user *UserStruct) (added bool) {
 		TRACE.Println("adding android device : " + jsondata.Device)
-		// device is not added to user, so append and save
-		storeduser.Android = append(storeduser.Android, jsondata.Device)
-		androidBin := aerospike.NewBin("Android", storeduser.Android)
-		key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-		if err == nil {
-			db.UpdateAerospikeSingleBin(key, androidBin)
-		}
-		retstr := storeduser.ToJSONStringWithCmd("AddAndroidDev")
-		db.SendStringToWebDevices(storeduser.Web, string(retstr)) // send to all web devices
-		return ""                                                 // we sent to web devices above, so don't send twice
-	}
-	return ""
+		user.Android, added = addDevice(user.Android, jsondata.Device)
+		return
+	})
 }
 
-func (db *Database) RemoveAndroidDev(data string) string {
-	// TRACE.Println("removing user " + username + " android device " + android)
-	jsondata := ChangeDeviceStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	android_devs := storeduser.Android
-	// loop through web devices, if web device is not the token add to slice
-	// then save back to the user
-	for i, e := range android_devs {
-		if e == jsondata.Device {
-			storeduser.Android = append(storeduser.Android[:i], storeduser.Android[i+1:]...) // splice out
-		}
-	}
-	TRACE.Println("in RemoveAndroidDev, storeduser.Android = ")
-	TRACE.Println(storeduser.Android)
-	androidBin := aerospike.NewBin("Android", storeduser.Android)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, androidBin)
-	}
-	retstr := storeduser.ToJSONStringWithCmd("RemoveAndroidDev")
-	db.SendStringToWebDevices(storeduser.Web, string(retstr)) // send to all web devices
-	return ""                                                 // we sent to web devices above, so don't send twice
+func (db *Database) ChangeAndroidDev(jsondata *ChangeDeviceStruct) error {
+	return db.updateUserAndNotify(jsondata.Username, "AddAndroidDev", CHANGE_USER, func(user *UserStruct) bool {
+		var removed, added bool
+		// first, remove old device
+		user.Android, removed = removeDevice(user.Android, jsondata.OldDevice)
+		user.Android, added = addDevice(user.Android, jsondata.Device)
+		return removed || added
+	})
 }
 
-func (db *Database) AddIosDev(data string) string {
-	jsondata := ChangeDeviceStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	// first make sure that the device is not already stored
-	dev_found := false
-	for _, e := range storeduser.Ios {
-		if e == jsondata.Device {
-			dev_found = true
-			break
-		}
-	}
-	if !dev_found {
-		// device is not added to user, so append and save
-		storeduser.Ios = append(storeduser.Ios, jsondata.Device)
-		iosBin := aerospike.NewBin("Ios", storeduser.Ios)
-		key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-		if err == nil {
-			db.UpdateAerospikeSingleBin(key, iosBin)
-		}
-		retstr := storeduser.ToJSONStringWithCmd("AddIosDev")
-		db.SendStringToWebDevices(storeduser.Web, string(retstr)) // send to all web devices
-		return ""                                                 // we sent to web devices above, so don't send twice
-	}
-	return ""
+func (db *Database) RemoveAndroidDev(jsondata *ChangeDeviceStruct) error {
+	// TRACE.Println("removing user " + username + " android device " + android)
+	return db.updateUserAndNotify(jsondata.Username, "RemoveAndroidDev", CHANGE_USER, func(user *UserStruct) (removed bool) {
+		user.Android, removed = removeDevice(user.Android, jsondata.Device)
+		return
+	})
 }
 
-func (db *Database) ChangeIosDev(data string) string {
-	jsondata := ChangeDeviceStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
+func (db *Database) AddIosDev(jsondata *ChangeDeviceStruct) error {
+	return db.updateUserAndNotify(jsondata.Username, "AddIosDev", CHANGE_USER, func(user *UserStruct) (added bool) {
+		user.Ios, added = addDevice(user.Ios, jsondata.Device)
+		return
+	})
+}
 
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	// first, remove old device
-	for i, e := range storeduser.Ios {
-		if e == jsondata.Device {
-			storeduser.Ios = append(storeduser.Ios[:i], storeduser.Ios[i+1:]...) // splice out
-		}
-	}
-	// now make sure that the new device is not already stored
-	dev_found := false
-	for _, e := range storeduser.Ios {
-		if e == jsondata.Device {
-			dev_found = true
-			break
-		}
-	}
-	if !dev_found {
-		// device is not added to user, so append and save
-		storeduser.Ios = append(storeduser.Ios, jsondata.Device)
-		iosBin := aerospike.NewBin("Ios", storeduser.Ios)
-		key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-		if err == nil {
-			db.UpdateAerospikeSingleBin(key, iosBin)
-		}
-		retstr := storeduser.ToJSONStringWithCmd("AddIosDev")
-		db.SendStringToWebDevices(storeduser.Web, string(retstr)) // send to all web devices
-		return ""                                                 // we sent to web devices above, so don't send twice
-	}
-	return ""
+func (db *Database) ChangeIosDev(jsondata *ChangeDeviceStruct) error {
+	return db.updateUserAndNotify(jsondata.Username, "AddIosDev", CHANGE_USER, func(user *UserStruct) bool {
+		var removed, added bool
+		// first, remove old device
+		user.Ios, removed = removeDevice(user.Ios, jsondata.OldDevice)
+		user.Ios, added = addDevice(user.Ios, jsondata.Device)
+		return removed || added
+	})
 }
 
-func (db *Database) RemoveIosDev(data string) string {
+func (db *Database) RemoveIosDev(jsondata *ChangeDeviceStruct) error {
 	// TRACE.Println("removing user " + username + " ios device " + ios)
-	jsondata := ChangeDeviceStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	ios_devs := storeduser.Ios
-	// loop through web devices, if web device is not the token add to slice
-	// then save back to the user
-	for i, e := range ios_devs {
-		if e == jsondata.Device {
-			storeduser.Ios = append(storeduser.Ios[:i], storeduser.Ios[i+1:]...) // splice out
-		}
-	}
-	iosBin := aerospike.NewBin("Ios", storeduser.Ios)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, iosBin)
-	}
-	retstr := storeduser.ToJSONStringWithCmd("RemoveIosDev")
-	db.SendStringToWebDevices(storeduser.Web, string(retstr)) // send to all web devices
-	return ""                                                 // we sent to web devices above, so don't send twice
+	return db.updateUserAndNotify(jsondata.Username, "RemoveIosDev", CHANGE_USER, func(user *UserStruct) (removed bool) {
+		user.Ios, removed = removeDevice(user.Ios, jsondata.Device)
+		return
+	})
 }
 
-func (db *Database) AddFireosDev(data string) string {
-	jsondata := ChangeDeviceStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	// first make sure that the device is not already stored
-	dev_found := false
-	for _, e := range storeduser.Fireos {
-		if e == jsondata.Device {
-			dev_found = true
-			break
-		}
-	}
-	if !dev_found {
-		// device is not added to user, so append and save
-		storeduser.Fireos = append(storeduser.Fireos, jsondata.Device)
-		fireosBin := aerospike.NewBin("Fireos", storeduser.Fireos)
-		key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-		if err == nil {
-			db.UpdateAerospikeSingleBin(key, fireosBin)
-		}
-		retstr := storeduser.ToJSONStringWithCmd("AddFireosDev")
-		db.SendStringToWebDevices(storeduser.Web, string(retstr)) // send to all web devices
-		return ""                                                 // we sent to web devices above, so don't send twice
-	}
-	return ""
+func (db *Database) AddFireosDev(jsondata *ChangeDeviceStruct) error {
+	return db.updateUserAndNotify(jsondata.Username, "AddFireosDev", CHANGE_USER, func(user *UserStruct) (added bool) {
+		user.Fireos, added = addDevice(user.Fireos, jsondata.Device)
+		return
+	})
 }
 
-func (db *Database) ChangeFireosDev(data string) string {
-	jsondata := ChangeDeviceStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	// first, remove old device
-	for i, e := range storeduser.Fireos {
-		if e == jsondata.Device {
-			storeduser.Fireos = append(storeduser.Fireos[:i], storeduser.Fireos[i+1:]...) // splice out
-		}
-	}
-	// now make sure that the device is not already stored
-	dev_found := false
-	for _, e := range storeduser.Fireos {
-		if e == jsondata.Device {
-			dev_found = true
-			break
-		}
-	}
-	if !dev_found {
-		// device is not added to user, so append and save
-		storeduser.Fireos = append(storeduser.Fireos, jsondata.Device)
-		fireosBin := aerospike.NewBin("Fireos", storeduser.Fireos)
-		key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-		if err == nil {
-			db.UpdateAerospikeSingleBin(key, fireosBin)
-		}
-		retstr := storeduser.ToJSONStringWithCmd("AddFireosDev")
-		db.SendStringToWebDevices(storeduser.Web, string(retstr)) // send to all web devices
-		return ""                                                 // we sent to web devices above, so don't send twice
-	}
-	return ""
+func (db *Database) ChangeFireosDev(jsondata *ChangeDeviceStruct) error {
+	return db.updateUserAndNotify(jsondata.Username, "AddFireosDev", CHANGE_USER, func(user *UserStruct) bool {
+		var removed, added bool
+		// first, remove old device
+		user.Fireos, removed = removeDevice(user.Fireos, jsondata.OldDevice)
+		user.Fireos, added = addDevice(user.Fireos, jsondata.Device)
+		return removed || added
+	})
 }
 
-func (db *Database) RemoveFireosDev(data string) string {
+func (db *Database) RemoveFireosDev(jsondata *ChangeDeviceStruct) error {
 	// TRACE.Println("removing user " + username + " fireos device " + fireos)
-	jsondata := ChangeDeviceStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
+	return db.updateUserAndNotify(jsondata.Username, "RemoveFireosDev", CHANGE_USER, func(user *UserStruct) (removed bool) {
+		user.Fireos, removed = removeDevice(user.Fireos, jsondata.Device)
+		return
+	})
+}
 
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	fireos_devs := storeduser.Fireos
-	// loop through web devices, if web device is not the token add to slice
-	// then save back to the user
-	for i, e := range fireos_devs {
-		if e == jsondata.Device {
-			storeduser.Fireos = append(storeduser.Fireos[:i], storeduser.Fireos[i+1:]...) // splice out
-		}
+func (db *Database) RemoveUserWebToken(username string, token string) *UserStruct {
+	// TRACE.Println("removing user " + username + " web token " + token)
+	if strings.TrimSpace(username) == "" || strings.TrimSpace(token) == "" {
+		return nil
 	}
-	fireosBin := aerospike.NewBin("Fireos", storeduser.Fireos)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, fireosBin)
+	storeduser, err := db.removeWebToken(username, token)
+	if err != nil {
+		return nil
 	}
-	retstr := storeduser.ToJSONStringWithCmd("RemoveFireosDev")
-	db.SendStringToWebDevices(storeduser.Web, string(retstr)) // send to all web devices
-	return ""                                                 // we sent to web devices above, so don't send twice
+	return &storeduser
 }
 
-func (db *Database) RemoveUserWebToken(username string, token string) string {
-	// TRACE.Println("removing user " + username + " web token " + token)
-	if strings.TrimSpace(username) == "" || strings.TrimSpace(token) == "" {
-		return ""
-	}
-	storeduser := db.GetAerospikeUser(strings.ToUpper(username))
-	// check if length of web devices is > 0
-	if len(storeduser.Web) < 1 {
-		return "" // can't splice nothing
-	} else if len(storeduser.Web) == 1 {
-		// we know there's only the one web device, and we most likely lost internet connection, so clear the array
-		storeduser.Web = make([]string, 0) // empty array
-	} else {
-		// user has multiple devices online simultaneously, so loop through web devices
-		for i, e := range storeduser.Web {
-			if e == token || e == "" { // we remove if it equals the token, or if empty string
-				if len(storeduser.Web) <= (i + 1) {
-					storeduser.Web = storeduser.Web[:i] // take everything up to this point
-				} else {
-					storeduser.Web = append(storeduser.Web[:i], storeduser.Web[i+1:]...) // splice out
+// takes token off username's web devices
+func (db *Database) removeWebToken(username string, token string) (UserStruct, error) {
+	return db.updateUser(username, func(user *UserStruct) error {
+		// check if length of web devices is > 0
+		if len(user.Web) < 1 {
+			return errNoChange // can't splice nothing
+		} else if len(user.Web) == 1 {
+			// we know there's only the one web device, and we most likely lost internet connection, so clear the array
+			user.Web = make([]string, 0) // empty array
+		} else {
+			// user has multiple devices online simultaneously, so loop through web devices
+			for i, e := range user.Web {
+				if e == token || e == "" { // we remove if it equals the token, or if empty string
+					user.Web = append(user.Web[:i], user.Web[i+1:]...) // splice out
+					break
 				}
-				break
 			}
 		}
-	}
-	// then save back to the user
-	webBin := aerospike.NewBin("Web", storeduser.Web)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, webBin)
-	}
-	return storeduser.ToJSONStringWithCmd("RemoveWebDev")
+		return nil
+	})
 }
 
-func (db *Database) RemoveWebDev(data string) string {
-	jsondata := ChangeDeviceStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
+func (db *Database) RemoveWebDev(jsondata *ChangeDeviceStruct) (*UserStruct, error) {
+	user, err := db.removeWebToken(jsondata.Username, jsondata.Device)
 	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
+		return nil, err
 	}
-	return db.RemoveUserWebToken(jsondata.Username, jsondata.Device)
+	return &user, nil
 }
 
 // conversations
-func (db *Database) CreateConversation(data string) string {
+// IsConvoMember says if username is one of CID's members
+func (db *Database) IsConvoMember(CID string, username string) bool {
+	for _, m := range ToConvoMemberArray(db.Convos.GetConvoMembers(CID)) {
+		if strings.ToUpper(m.Username) == strings.ToUpper(username) {
+			return true
+		}
+	}
+	return false
+}
+
+func (db *Database) CreateConversation(jsondata *CIDCommandStruct) error {
 	// TRACE.Println("data in CreateConversation = " + data)
-	jsondata := CIDCommandStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
+	// the caller has to be in their own conversation
+	callerFound := false
+	for _, m := range jsondata.Members {
+		if strings.ToUpper(m) == strings.ToUpper(db.Username()) {
+			callerFound = true
+		}
+	}
+	if !callerFound {
+		return ErrNotMember
 	}
 	CID := uuid.NewV4()
 	CIDstring := uuid.Formatter(CID, uuid.CleanHyphen)
 	// TRACE.Println("new generated CID: " + CIDstring)
 	// TRACE.Println("jsondata in CreateConversation:")
 	// TRACE.Println(jsondata)
-	PostgresCIDstring := `"` + CIDstring + `"`
-	CurString := "CREATE TABLE " + PostgresCIDstring + " (f_username varchar NOT NULL, m_time timestamptz NOT NULL, content varchar NOT NULL, PRIMARY KEY (f_username, m_time) );"
-	// TRACE.Println("CurString: " + CurString)
-	if db.postgres_conn == nil {
-		return "" // can't do anything with no database connection :(
-	}
-	_, createerr := db.postgres_conn.Exec(CurString)
-	if createerr != nil {
-		ERROR.Println("error:", createerr)
-		return ""
-	}
 	// save to new CID in aerospike
 	var memberArray ConvoMemberArray
 	// loop through members
@@ -1365,9 +602,9 @@ func (db *Database) CreateConversation(data string) string {
 		// add CID to each member
 		db.AddConversationToUser(CIDstring, jsondata.Name, string(m), jsondata.M_time) // add CID to user
 	}
-	db.SetAerospikeConvoMembers(CIDstring, memberArray.ToStringArray())
-	db.SetAerospikeConvoName(CIDstring, jsondata.Name)
-	db.SetAerospikeConvoMtime(CIDstring, jsondata.M_time)
+	db.Convos.SetConvoMembers(CIDstring, memberArray.ToStringArray())
+	db.Convos.SetConvoName(CIDstring, jsondata.Name)
+	db.Convos.SetConvoMtime(CIDstring, jsondata.M_time)
 
 	// send message out to each user with new CID and all data
 	jsondata.CID = CIDstring
@@ -1377,61 +614,48 @@ func (db *Database) CreateConversation(data string) string {
 	if err != nil {
 		ERROR.Println("err in ffjson.Marshal(jsondata) in CreateConversation:")
 		ERROR.Println(err)
-		return "" // return on error :(
+		return err // return on error :(
 	}
 	for _, m := range memberArray {
-		member := db.GetAerospikeUser(m.Username)
-		db.SendStringToWebDevices(member.Web, string(datastr))
+		member := db.Users.GetUser(m.Username)
+		db.sendChangeString(member, CHANGE_CONVO, string(datastr))
 	}
-
-	return "" // return nothing, it should already be sent above in SendStringToWebDevices
+	return nil
 }
 
 func (db *Database) AddConversationToUser(newCID string, name string, username string, m_time string) {
 	// TRACE.Println("adding CID " + newCID + " named " + name + " to username " + username + " at m_time " + m_time)
-	storeduser := db.GetAerospikeUser(strings.ToUpper(username))
-	CIDFound := false
-	storedCIDs := storeduser.GetCIDStructs()
-	for i, e := range storedCIDs {
-		if e.CID == newCID {
-			// if the user already is a part of the conversation, update the name and m_time
-			// storeduser.CIDs[i].Name = name
-			storedCIDs[i].M_time = m_time
-			CIDFound = true
-			break
+	db.updateUser(username, func(user *UserStruct) error {
+		CIDFound := false
+		storedCIDs := user.GetCIDStructs()
+		for i, e := range storedCIDs {
+			if e.CID == newCID {
+				// if the user already is a part of the conversation, update the name and m_time
+				// storeduser.CIDs[i].Name = name
+				storedCIDs[i].M_time = m_time
+				CIDFound = true
+				break
+			}
 		}
-	}
-	if !CIDFound {
-		newCID := UserCIDStruct{
-			// Name: name,
-			CID:         newCID,
-			M_time:      m_time,
-			UnreadCount: 0,
+		if !CIDFound {
+			newCID := UserCIDStruct{
+				// Name: name,
+				CID:         newCID,
+				M_time:      m_time,
+				UnreadCount: 0,
+			}
+			storedCIDs = append(storedCIDs, newCID)
 		}
-		storedCIDs = append(storedCIDs, newCID)
-	}
-	// TRACE.Println("storedCIDs:")
-	// TRACE.Println(storedCIDs)
-	storeduser.SaveCIDStructs(storedCIDs)
-	// TRACE.Println("user after saveCIDStructs: " + storeduser.ToJSONString())
-	// save back
-	CIDBin := aerospike.NewBin("CIDs", storeduser.CIDs)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, CIDBin)
-	}
+		// TRACE.Println("storedCIDs:")
+		// TRACE.Println(storedCIDs)
+		user.SaveCIDStructs(storedCIDs)
+		return nil
+	})
 }
 
-func (db *Database) AddUsersToConversation(data string) string {
+func (db *Database) AddUsersToConversation(jsondata *CIDCommandStruct) {
 	// jsondata.Members are new members
-	jsondata := CIDCommandStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-
-	convoMembersStrings := db.GetAerospikeConvoMembers(jsondata.CID)
+	convoMembersStrings := db.Convos.GetConvoMembers(jsondata.CID)
 	convoMembers := ToConvoMemberArray(convoMembersStrings)
 
 	newUserAdded := false
@@ -1439,10 +663,10 @@ func (db *Database) AddUsersToConversation(data string) string {
 		TRACE.Println("newUser = " + newUser)
 		userInConversation := false
 		for _, m := range convoMembers {
-			if strings.ToUpper(m.Username) == strings.ToUpper(jsondata.Username) {
+			if strings.ToUpper(m.Username) == strings.ToUpper(newUser) {
 				userInConversation = true
 				break
-			} // end if strings.ToUpper(m.Username) == strings.ToUpper(jsondata.Username)
+			} // end if strings.ToUpper(m.Username) == strings.ToUpper(newUser)
 		} // end for _, m := range convoMembers
 
 		// only add to conversation if user is not already in conversation
@@ -1457,32 +681,25 @@ func (db *Database) AddUsersToConversation(data string) string {
 			sort.Sort(convoMembers) // alphabetize by Username
 			newUserAdded = true
 			// append new CID to user struct
-			user := db.GetAerospikeUser(newUser)
-			userCIDs := user.GetCIDStructs()
 			newCID := UserCIDStruct{
 				CID:         jsondata.CID,
 				M_time:      jsondata.M_time,
 				UnreadCount: 0,
 			}
-			userCIDs = append(userCIDs, newCID)
-			TRACE.Println("new userCIDs:")
-			TRACE.Println(userCIDs)
-			user.SaveCIDStructs(userCIDs)
-			CIDBin := aerospike.NewBin("CIDs", user.CIDs)
-			key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(user.UsernameUpper))
-			if err == nil {
-				db.UpdateAerospikeSingleBin(key, CIDBin)
-			}
+			db.updateUser(newUser, func(user *UserStruct) error {
+				user.SaveCIDStructs(append(user.GetCIDStructs(), newCID))
+				return nil
+			})
 		} // end if !userInConversation
 	} // end for _, newUser := range jsondata.Members
 
 	if newUserAdded {
 		// save to general CID Aerospike space
-		db.SetAerospikeConvoMembers(jsondata.CID, convoMembers.ToStringArray())
+		db.Convos.SetConvoMembers(jsondata.CID, convoMembers.ToStringArray())
 		// update time of convo
-		db.SetAerospikeConvoMtime(jsondata.CID, jsondata.M_time)
+		db.Convos.SetConvoMtime(jsondata.CID, jsondata.M_time)
 
-		membersString, err := ffjson.Marshal(convoMembers)
+		membersString, err := ffjson.Marshal(db.membersSeenBy(convoMembers, ""))
 		if err != nil {
 			ERROR.Println("Error in ffjson.Marshal(convoMembers) in RemoveUserFromConversation")
 			ERROR.Println(err)
@@ -1491,34 +708,22 @@ func (db *Database) AddUsersToConversation(data string) string {
 		// loop through users and send message if online saying to update information
 		updateString := `{"cmd":"AddUsersToConversation", "CID":"` + jsondata.CID + `", "M_time:":"` + jsondata.M_time + `", "Members":` + string(membersString) + `}`
 		for _, m := range convoMembers {
-			user := db.GetAerospikeUser(m.Username) // maybe use GetAerospikeUserActive later
-			for _, webz := range user.Web {
-				if db.nats_encodedconn != nil {
-					db.nats_encodedconn.Publish(webz, string(updateString))
-				}
-			}
+			user := db.Users.GetUser(m.Username)
+			db.sendChangeString(user, CHANGE_CONVO, updateString)
 		}
 	}
 
-	return ""
 }
 
-func (db *Database) RemoveUserFromConversation(data string) string {
-	jsondata := CIDCommandStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-
-	convoMembersStrings := db.GetAerospikeConvoMembers(jsondata.CID)
+func (db *Database) RemoveUserFromConversation(jsondata *CIDCommandStruct) *UserStruct {
+	convoMembersStrings := db.Convos.GetConvoMembers(jsondata.CID)
 	convoMembers := ToConvoMemberArray(convoMembersStrings)
 
 	userRemoved := false
 	for i, m := range convoMembers {
 		if strings.ToUpper(m.Username) == strings.ToUpper(jsondata.Username) {
 			convoMembers = append(convoMembers[:i], convoMembers[i+1:]...) // splice out
-			db.SetAerospikeConvoMembers(jsondata.CID, convoMembers.ToStringArray())
+			db.Convos.SetConvoMembers(jsondata.CID, convoMembers.ToStringArray())
 			userRemoved = true
 			break
 		}
@@ -1526,82 +731,60 @@ func (db *Database) RemoveUserFromConversation(data string) string {
 
 	if userRemoved {
 		// update time of convo
-		db.SetAerospikeConvoMtime(jsondata.CID, jsondata.M_time)
+		db.Convos.SetConvoMtime(jsondata.CID, jsondata.M_time)
 		// loop through users and send message if online saying to update information
-		membersString, err := ffjson.Marshal(convoMembers)
+		membersString, err := ffjson.Marshal(db.membersSeenBy(convoMembers, ""))
 		if err != nil {
 			ERROR.Println("Error in ffjson.Marshal(convoMembers) in RemoveUserFromConversation")
 			ERROR.Println(err)
 		}
 		updateString := `{"cmd":"update_convo_members", "CID":"` + jsondata.CID + `", "Members":` + string(membersString) + `}`
 		for _, m := range convoMembers {
-			user := db.GetAerospikeUser(m.Username) // maybe use GetAerospikeUserActive later
-			for _, webz := range user.Web {
-				if db.nats_encodedconn != nil {
-					db.nats_encodedconn.Publish(webz, string(updateString))
-				}
-			}
+			user := db.Users.GetUser(m.Username)
+			db.sendChangeString(user, CHANGE_CONVO, updateString)
 		}
+		// the one who left only finds out from the log, the caller gets the user back
+		db.recordChange(jsondata.Username, CHANGE_CONVO, updateString)
 
 		// now update user struct
-		user := db.GetAerospikeUser(jsondata.Username)
-		userCIDs := user.GetCIDStructs()
-		for i, CID := range userCIDs {
-			if CID.CID == jsondata.CID {
-				// splice out
-				userCIDs = append(userCIDs[:i], userCIDs[i+1:]...) // splice out
-				break                                              // only one CID removed
+		user, err := db.updateUser(jsondata.Username, func(user *UserStruct) error {
+			userCIDs := user.GetCIDStructs()
+			for i, CID := range userCIDs {
+				if CID.CID == jsondata.CID {
+					// splice out
+					userCIDs = append(userCIDs[:i], userCIDs[i+1:]...) // splice out
+					break                                              // only one CID removed
+				}
 			}
-		}
-		user.SaveCIDStructs(userCIDs)
+			user.SaveCIDStructs(userCIDs)
+			return nil
+		})
 		// TRACE.Println("saving user: " + user.ToJSONString())
-		CIDBin := aerospike.NewBin("CIDs", user.CIDs)
-		key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(user.UsernameUpper))
 		if err == nil {
-			db.UpdateAerospikeSingleBin(key, CIDBin)
-			return user.ToJSONStringWithCmd("RemoveUserFromConversation")
+			return &user
 		}
 	}
 
-	return ""
+	return nil
 }
 
-func (db *Database) ChangeConvoName(data string) string {
-	jsondata := CIDCommandStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
+func (db *Database) ChangeConvoName(jsondata *CIDCommandStruct) {
+	db.Convos.SetConvoName(jsondata.CID, jsondata.Name)
 
-	db.SetAerospikeConvoName(jsondata.CID, jsondata.Name)
-
-	convoMembersStrings := db.GetAerospikeConvoMembers(jsondata.CID)
+	convoMembersStrings := db.Convos.GetConvoMembers(jsondata.CID)
 	convoMembers := ToConvoMemberArray(convoMembersStrings)
 
 	updateString := `{"cmd":"update_convo_name", "CID":"` + jsondata.CID + `", "name":"` + jsondata.Name + `"}`
 	// loop through users and send message if online saying to update information
 	for _, m := range convoMembers {
-		user := db.GetAerospikeUser(m.Username) // maybe use GetAerospikeUserActive later
-		for _, webz := range user.Web {
-			if db.nats_encodedconn != nil {
-				db.nats_encodedconn.Publish(webz, string(updateString))
-			}
-		}
+		user := db.Users.GetUser(m.Username)
+		db.sendChangeString(user, CHANGE_CONVO, updateString)
 	}
 
-	return ""
 }
 
-func (db *Database) UpdateConvoFiles(data string) string {
-	jsondata := CmdConvoAddFileStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-
-	curFilesStringArr := db.GetAerospikeConvoFiles(jsondata.CID)
+func (db *Database) UpdateConvoFiles(jsondata *CmdConvoAddFileStruct) {
+	curFilesStringArr := db.Convos.GetConvoFiles(jsondata.CID)
 	curFilesStructArr := ToConvoFileListArray(curFilesStringArr)
 
 	// loop through and see if filename exists, if it does, delete it and re-add it
@@ -1621,334 +804,122 @@ func (db *Database) UpdateConvoFiles(data string) string {
 
 	// add newFile to array and save back
 	curFilesStructArr = append(curFilesStructArr, newFile)
-	db.SetAerospikeConvoFiles(jsondata.CID, curFilesStructArr.ToStringArray())
+	db.Convos.SetConvoFiles(jsondata.CID, curFilesStructArr.ToStringArray())
 
 	// send to every user in the group to update their CID files
-	convoMembersStrings := db.GetAerospikeConvoMembers(jsondata.CID)
+	convoMembersStrings := db.Convos.GetConvoMembers(jsondata.CID)
 	convoMembers := ToConvoMemberArray(convoMembersStrings)
-	if db.nats_encodedconn != nil {
-		// TRACE.Println("looping through each member and publishing user status update to them")
-		for _, e := range convoMembers {
-			recipient := db.GetAerospikeUser(strings.ToUpper(e.Username))
-			db.SendStringToWebDevices(recipient.Web, data)
-		}
+	// TRACE.Println("looping through each member and publishing user status update to them")
+	for _, e := range convoMembers {
+		recipient := db.Users.GetUser(strings.ToUpper(e.Username))
+		db.sendChange(recipient, CHANGE_CONVO, jsondata)
 	}
-
-	return ""
 }
 
-func (db *Database) GetConvoData(data string) string {
-	jsondata := CIDCommandStruct{}
-	json.Unmarshal([]byte(data), &jsondata)
+func (db *Database) GetConvoData(jsondata *CIDCommandStruct) (*ConvoDataStruct, error) {
 	// TRACE.Println("jsondata in GetConvoData:")
 	// TRACE.Println(jsondata)
-	var rows *sql.Rows
+	var retMessages []ConvoRowStruct
 	var err error
-	retMessages := make([]ConvoRowStruct, 0)
-	PostgresCIDstring := `"` + jsondata.CID + `"`
-	// TRACE.Println("PostgresCIDsstring = " + string(PostgresCIDstring))
-	if db.postgres_conn == nil {
-		return "" // can't do anything with no database connection :(
+	if db.Messages == nil {
+		return nil, ErrMessagesDown // can't do anything with no database connection :(
 	}
 	if jsondata.M_time != "" {
 		// use m_time to get most recent messages
-		// TRACE.Println("Using m_time to get most recent messages from postgres")
-		// need to put the timestamp in single quotes
-		// VALID:
-		// SELECT f_username, content, m_time FROM  "4730d9e6-b719-411a-b179-62f35528c7d5" WHERE m_time > '2015-06-12T19:16:29.119Z'::timestamptz
-		rows, err = db.postgres_conn.Query(`SELECT f_username, content, m_time FROM ` + PostgresCIDstring + ` WHERE m_time > '` + jsondata.M_time + `'::timestamptz ORDER BY m_time ASC;`) // conversation ID is the table name, return 20 latest messages
-	} else {
-		// no m_time, so get 20 most recent messages
-		// TRACE.Println("getting most recent 20 messages from postgres")
-		// Average ms for 20 rows: 497ms
-		// Average ms for 50 rows: 469.25ms // I KNOW, RIGHT???
-		PostgresString := `WITH results AS (SELECT f_username, content, m_time FROM ` + PostgresCIDstring + ` ORDER BY m_time DESC LIMIT 50) SELECT * FROM results ORDER BY m_time ASC;`
-		// TRACE.Println("PostgresString = " + string(PostgresString))
-		rows, err = db.postgres_conn.Query(PostgresString)
-	}
-	if err, ok := err.(*pq.Error); ok {
-		ERROR.Println("pq error:", err.Code.Name())
-		ERROR.Println(err)
+		retMessages, err = db.Messages.GetMessagesSince(jsondata.CID, jsondata.M_time)
 	} else {
-		defer rows.Close()
-		for rows.Next() {
-			var SQLF_username string
-			var SQLContent string
-			var SQLM_time time.Time
-			if err := rows.Scan(&SQLF_username, &SQLContent, &SQLM_time); err != nil {
-				ERROR.Println(err)
-			}
-			rowStruct := ConvoRowStruct{
-				F_username: SQLF_username,
-				Content:    SQLContent,
-				M_time:     SQLM_time,
-			}
-			// convoRow, err := ffjson.Marshal(rowStruct)
-			if err != nil {
-				ERROR.Println("Error in ffjson.Marshal(rowStruct)")
-				ERROR.Println(err)
-			}
-			// TRACE.Println("convoRow: " + string(convoRow))
-			retMessages = append(retMessages, rowStruct)
-		}
+		// no m_time, so get 50 most recent messages
+		retMessages, err = db.Messages.GetLatestMessages(jsondata.CID, 50)
+	}
+	if err != nil {
+		logPqError("GetConvoData", err)
+		return nil, ErrMessagesDown
 	}
 
 	// now get from Aerospike
-	retName := db.GetAerospikeConvoName(jsondata.CID)
-	retMtime := db.GetAerospikeConvoMtime(jsondata.CID)
-	retMembers := db.GetAerospikeConvoMembers(jsondata.CID)
-	retFiles := db.GetAerospikeConvoFiles(jsondata.CID)
+	retName := db.Convos.GetConvoName(jsondata.CID)
+	retMtime := db.Convos.GetConvoMtime(jsondata.CID)
+	retMembers := db.membersSeenBy(ToConvoMemberArray(db.Convos.GetConvoMembers(jsondata.CID)), db.Username())
+	retFiles := db.Convos.GetConvoFiles(jsondata.CID)
 
 	retCmd := ConvoDataStruct{
-		Cmd:      "GetConvoData",
-		CID:      jsondata.CID,
-		Name:     retName,
-		M_time:   retMtime,
-		Members:  retMembers,
-		Files:    retFiles,
-		Messages: retMessages,
+		CID:          jsondata.CID,
+		Name:         retName,
+		M_time:       retMtime,
+		Members:      retMembers.ToStringArray(),
+		Files:        retFiles,
+		Messages:     retMessages,
+		DisabledBots: db.disabledBots(jsondata.CID),
 	}
-	retCmdString, err := ffjson.Marshal(retCmd)
-	if err != nil {
-		ERROR.Println("Error in ffjson.Marshal(retCmd)")
-		ERROR.Println(err)
-	}
-	// TRACE.Println("GetConvoData returning " + string(retCmdString))
-	return string(retCmdString)
+	return &retCmd, nil
 }
 
-func (db *Database) GetAllConvoData(data string) string {
-	jsondata := CIDCommandStruct{}
-	json.Unmarshal([]byte(data), &jsondata)
+func (db *Database) GetAllConvoData(jsondata *CIDCommandStruct) (*ConvoDataStruct, error) {
 	// TRACE.Println("jsondata in GetConvoData:")
 	// TRACE.Println(jsondata)
-	var rows *sql.Rows
-	var err error
-	retMessages := make([]ConvoRowStruct, 0)
-	PostgresCIDstring := `"` + jsondata.CID + `"`
-	// TRACE.Println("PostgresCIDsstring = " + string(PostgresCIDstring))
-	if db.postgres_conn == nil {
-		return "" // can't do anything with no database connection :(
+	if db.Messages == nil {
+		return nil, ErrMessagesDown // can't do anything with no database connection :(
 	}
-
-	rows, err = db.postgres_conn.Query(`SELECT f_username, content, m_time FROM ` + PostgresCIDstring + `;`) // conversation ID is the table name, return 20 latest messages
-	if err, ok := err.(*pq.Error); ok {
-		ERROR.Println("pq error:", err.Code.Name())
-		ERROR.Println(err)
-	} else {
-		defer rows.Close()
-		for rows.Next() {
-			var SQLF_username string
-			var SQLContent string
-			var SQLM_time time.Time
-			if err := rows.Scan(&SQLF_username, &SQLContent, &SQLM_time); err != nil {
-				ERROR.Println(err)
-			}
-			rowStruct := ConvoRowStruct{
-				F_username: SQLF_username,
-				Content:    SQLContent,
-				M_time:     SQLM_time,
-			}
-			// convoRow, err := ffjson.Marshal(rowStruct)
-			if err != nil {
-				ERROR.Println("Error in ffjson.Marshal(rowStruct)")
-				ERROR.Println(err)
-			}
-			// TRACE.Println("convoRow: " + string(convoRow))
-			retMessages = append(retMessages, rowStruct)
-		}
+	retMessages, err := db.Messages.GetAllMessages(jsondata.CID)
+	if err != nil {
+		logPqError("GetAllConvoData", err)
+		return nil, ErrMessagesDown
 	}
 
 	// now get from Aerospike
-	retName := db.GetAerospikeConvoName(jsondata.CID)
-	retMtime := db.GetAerospikeConvoMtime(jsondata.CID)
-	retMembers := db.GetAerospikeConvoMembers(jsondata.CID)
-	retFiles := db.GetAerospikeConvoFiles(jsondata.CID)
+	retName := db.Convos.GetConvoName(jsondata.CID)
+	retMtime := db.Convos.GetConvoMtime(jsondata.CID)
+	retMembers := db.membersSeenBy(ToConvoMemberArray(db.Convos.GetConvoMembers(jsondata.CID)), db.Username())
+	retFiles := db.Convos.GetConvoFiles(jsondata.CID)
 
 	retCmd := ConvoDataStruct{
-		Cmd:      "GetConvoData",
-		CID:      jsondata.CID,
-		Name:     retName,
-		M_time:   retMtime,
-		Members:  retMembers,
-		Files:    retFiles,
-		Messages: retMessages,
-	}
-	retCmdString, err := ffjson.Marshal(retCmd)
-	if err != nil {
-		ERROR.Println("Error in ffjson.Marshal(retCmd)")
-		ERROR.Println(err)
+		CID:          jsondata.CID,
+		Name:         retName,
+		M_time:       retMtime,
+		Members:      retMembers.ToStringArray(),
+		Files:        retFiles,
+		Messages:     retMessages,
+		DisabledBots: db.disabledBots(jsondata.CID),
 	}
-	// TRACE.Println("GetConvoData returning " + string(retCmdString))
-	return string(retCmdString)
+	return &retCmd, nil
 }
 
-func (db *Database) GetMoreConvoMessages(data string) string {
-	jsondata := CIDCommandStruct{}
-	json.Unmarshal([]byte(data), &jsondata)
+func (db *Database) GetMoreConvoMessages(jsondata *CIDCommandStruct) (*ConvoDataStruct, error) {
 	// TRACE.Println("jsondata in GetConvoData:")
 	// TRACE.Println(jsondata)
-	var rows *sql.Rows
-	var err error
-	retMessages := make([]ConvoRowStruct, 0)
-	PostgresCIDstring := `"` + jsondata.CID + `"`
-	// TRACE.Println("PostgresCIDsstring = " + string(PostgresCIDstring))
-	if db.postgres_conn == nil {
-		return "" // can't do anything with no database connection :(
-	}
-	if jsondata.M_time == "" {
-		return "" // we need an M_time to get more messages from some point in time
+	if db.Messages == nil {
+		return nil, ErrMessagesDown // can't do anything with no database connection :(
 	}
 	// in GetConvoData, I bulk add to the javascript db so I return in order of earliest -> latest
 	// but here, I want to push to the top each message, so I return in order of lastest -> earliest
 	// which when pushed individually, keeps the whole list earliest -> latest
 	// the javascript db sorts by m_time, so insertion order isn't big factor
-	rows, err = db.postgres_conn.Query(`SELECT f_username, content, m_time FROM ` + PostgresCIDstring + ` WHERE m_time < '` + jsondata.M_time + `'::timestamptz ORDER BY m_time DESC LIMIT 50;`)
-	if err, ok := err.(*pq.Error); ok {
-		ERROR.Println("pq error:", err.Code.Name())
-		ERROR.Println(err)
-	} else {
-		defer rows.Close()
-		for rows.Next() {
-			var SQLF_username string
-			var SQLContent string
-			var SQLM_time time.Time
-			if err := rows.Scan(&SQLF_username, &SQLContent, &SQLM_time); err != nil {
-				ERROR.Println(err)
-			}
-			rowStruct := ConvoRowStruct{
-				F_username: SQLF_username,
-				Content:    SQLContent,
-				M_time:     SQLM_time,
-			}
-			// convoRow, err := ffjson.Marshal(rowStruct)
-			if err != nil {
-				ERROR.Println("Error in ffjson.Marshal(rowStruct)")
-				ERROR.Println(err)
-			}
-			// TRACE.Println("convoRow: " + string(convoRow))
-			retMessages = append(retMessages, rowStruct)
-		}
+	retMessages, err := db.Messages.GetMessagesBefore(jsondata.CID, jsondata.M_time, 50)
+	if err != nil {
+		logPqError("GetMoreConvoMessages", err)
+		return nil, ErrMessagesDown
 	}
 
 	retCmd := ConvoDataStruct{
-		Cmd:      "GetMoreConvoMessages",
 		CID:      jsondata.CID,
 		Messages: retMessages,
 	}
-	retCmdString, err := ffjson.Marshal(retCmd)
-	if err != nil {
-		ERROR.Println("Error in ffjson.Marshal(retCmd)")
-		ERROR.Println(err)
-	}
-	// TRACE.Println("GetConvoData returning " + string(retCmdString))
-	return string(retCmdString)
+	return &retCmd, nil
 }
 
 // EMAILS
-func (db *Database) GetAllEmails(data string) string {
-	jsondata := CIDCommandStruct{}
-	json.Unmarshal([]byte(data), &jsondata)
-	// TRACE.Println("jsondata in GetAllEmails:")
-	// TRACE.Println(jsondata)
-	// get user for correct email username
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	if storeduser.Username == "" {
-		return ""
-	}
-	var rows *sql.Rows
-	var err error
-	retEmails := make([]EmailRowStruct, 0)
-	PostgresEmailstring := `"` + storeduser.Username + `@pinged.email"`
-	// TRACE.Println("PostgresCIDsstrPostgresEmailstringing = " + string(PostgresEmailstring))
-	if db.postgres_conn == nil {
-		return "" // can't do anything with no database connection :(
-	}
-	if jsondata.M_time != "" {
-		// use m_time to get most recent messages
-		// TRACE.Println("Using m_time to get most recent messages from postgres")
-		// need to put the timestamp in single quotes
-		// VALID:
-		// SELECT f_username, content, m_time FROM  "4730d9e6-b719-411a-b179-62f35528c7d5" WHERE m_time > '2015-06-12T19:16:29.119Z'::timestamptz
-		rows, err = db.postgres_conn.Query(`SELECT from_email, to_emails, recv_email, subject, content, attachments, starred, unread, spam, draft, deleted, recv_time, m_time FROM ` + PostgresEmailstring + ` WHERE m_time > '` + jsondata.M_time + `'::timestamptz ORDER BY m_time DESC;`)
-	} else {
-		// no m_time, so get 20 most recent messages
-		// TRACE.Println("getting most recent 20 messages from postgres")
-		// Average ms for 20 rows: 497ms
-		// Average ms for 50 rows: 469.25ms // I KNOW, RIGHT???
-		PostgresString := `SELECT from_email, to_emails, recv_email, subject, content, attachments, starred, unread, spam, draft, deleted, recv_time, m_time FROM ` + PostgresEmailstring + ` ORDER BY m_time DESC;`
-		// TRACE.Println("PostgresString = " + string(PostgresString))
-		rows, err = db.postgres_conn.Query(PostgresString)
-	}
-	if err, ok := err.(*pq.Error); ok {
-		ERROR.Println("pq error:", err.Code.Name())
-		ERROR.Println(err)
-	} else {
-		defer rows.Close()
-		for rows.Next() {
-			var SqlFromEmail string
-			var SqlToEmails string
-			var SqlRecvEmail string
-			var SqlSubject string
-			var SqlContent string
-			var SqlAttachments string
-			var SqlStarred bool
-			var SqlUnread bool
-			var SqlSpam bool
-			var SqlDraft bool
-			var SqlDeleted bool
-			var SqlRecvTime time.Time
-			var SqlM_time time.Time
-			if err := rows.Scan(&SqlFromEmail, &SqlToEmails, &SqlRecvEmail, &SqlSubject, &SqlContent, &SqlAttachments, &SqlStarred, &SqlUnread, &SqlSpam, &SqlDraft, &SqlDeleted, &SqlRecvTime, &SqlM_time); err != nil {
-				ERROR.Println(err)
-			}
-			rowStruct := EmailRowStruct{
-				FromEmail:   SqlFromEmail,
-				ToEmails:    SqlToEmails,
-				RecvEmail:   SqlRecvEmail,
-				Subject:     SqlSubject,
-				Content:     SqlContent,
-				Attachments: SqlAttachments,
-				Starred:     SqlStarred,
-				Unread:      SqlUnread,
-				Spam:        SqlSpam,
-				Draft:       SqlDraft,
-				Deleted:     SqlDeleted,
-				RecvTime:    SqlRecvTime,
-				M_time:      SqlM_time,
-			}
-			// convoRow, err := ffjson.Marshal(rowStruct)
-			if err != nil {
-				ERROR.Println("Error in ffjson.Marshal(rowStruct)")
-				ERROR.Println(err)
-			}
-			// TRACE.Println("convoRow: " + string(convoRow))
-			retEmails = append(retEmails, rowStruct)
-		}
-	}
-
-	retCmd := EmailDataStruct{
-		Cmd:    "GetAllEmails",
-		Emails: retEmails,
-	}
-	retCmdString, err := json.Marshal(retCmd)
-	if err != nil {
-		ERROR.Println("Error in ffjson.Marshal(retCmd)")
-		ERROR.Println(err)
-	}
-	// TRACE.Println("GetConvoData returning " + string(retCmdString))
-	return string(retCmdString)
+// GetAllEmails is the user's emails changed since M_time, or all of them
+// without it, EMAIL_PAGE_SIZE at a time
+func (db *Database) GetAllEmails(jsondata *EmailPageCmdStruct) (*EmailDataStruct, error) {
+	return db.emailPage(jsondata.Username, EmailQuery{Since: jsondata.M_time}, jsondata.Cursor)
 }
 
 // USER
-func (db *Database) GetUserByUsername(data string) string {
-	cmdJSON := CommonCmdStruct{}
-	err := json.Unmarshal([]byte(data), &cmdJSON)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
+func (db *Database) GetUserByUsername(cmdJSON *UsernameCmdStruct) (*UserStruct, error) {
+	user := db.Users.GetUser(strings.ToUpper(cmdJSON.Username))
+	if user.Username == "" {
+		return nil, ErrUserNotFound
 	}
-	user := db.GetAerospikeUser(strings.ToUpper(cmdJSON.Username))
 	// remove some fields that we don't want to send back
 	retUser := UserStruct{
 		Username:      user.Username,
@@ -1960,33 +931,33 @@ func (db *Database) GetUserByUsername(data string) string {
 		ProfilePic:    user.ProfilePic,
 	}
 	// TRACE.Println("In GetUserByUsername, returning " + user.ToJSONStringWithCmd("GetUserByUsername"))
-	return retUser.ToJSONStringWithCmd("GetUserByUsername")
+	return &retUser, nil
 }
 
-func (db *Database) GetUserByEmail(data string) string {
-	cmdJSON := CreateUserCmdStruct{} // it works, has both cmd and email
-	err := json.Unmarshal([]byte(data), &cmdJSON)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
+func (db *Database) GetUserByEmail(cmdJSON *EmailCmdStruct) (*UserStruct, error) {
+	user := db.Users.GetUserByEmail(cmdJSON.Email)
+	if user.Username == "" {
+		return nil, cmdError(ERR_NOT_FOUND, "there's no user with that email")
 	}
-	user := db.GetAerospikeUserByEmail(cmdJSON.Email)
-	// TRACE.Println("In GetUserByUsername, returning " + user.ToJSONStringWithCmd("GetUserByUsername"))
-	return user.ToJSONStringWithCmd("GetUserByEmail")
+	// same fields as GetUserByUsername, it's somebody else's user
+	retUser := UserStruct{
+		Username:      user.Username,
+		UsernameUpper: user.UsernameUpper,
+		Email:         user.Email,
+		Phone:         user.Phone,
+		PhoneGateway:  user.PhoneGateway,
+		Friends:       user.Friends,
+		ProfilePic:    user.ProfilePic,
+	}
+	return &retUser, nil
 }
 
-func (db *Database) MatchUsers(data string) string {
-	cmdJSON := MatchUsersCmdStruct{} // it works, has both cmd and email
-	err := json.Unmarshal([]byte(data), &cmdJSON)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
+func (db *Database) MatchUsers(cmdJSON *MatchUsersCmdStruct) *MatchUsersResponse {
 	foundUsers := make([]MatchUsersReturnStruct, 0) // start off empty
 	// loop through and add to foundUsers
 	for _, phone := range cmdJSON.Phones {
 		// JSON passed in is {'Name': contacts[i].displayName, 'PhoneNum': contacts[i].phoneNumbers[j].value}
-		user := db.GetAerospikeUserByPhone(phone.PhoneNum)
+		user := db.Users.GetUserByPhone(phone.PhoneNum)
 		if user.Username != "" {
 			newUser := MatchUsersReturnStruct{
 				Username:    user.Username,
@@ -2000,7 +971,7 @@ func (db *Database) MatchUsers(data string) string {
 	}
 	for _, email := range cmdJSON.Emails {
 		// JSON passed in is {'Name': contacts[i].displayName, 'Email': contacts[i].emails[j].value}
-		user := db.GetAerospikeUserByEmail(email.Email)
+		user := db.Users.GetUserByEmail(email.Email)
 		if user.Username != "" {
 			newUser := MatchUsersReturnStruct{
 				Username:    user.Username,
@@ -2012,416 +983,293 @@ func (db *Database) MatchUsers(data string) string {
 			foundUsers = append(foundUsers, newUser)
 		}
 	}
-	retUserString, err := ffjson.Marshal(foundUsers)
-	if err != nil {
-		ERROR.Println("Error in ffjson.Marshal(retUserString)")
-		ERROR.Println(err)
-	}
-	return `{"cmd":"MatchUsers","MatchedUsers":` + string(retUserString) + `}`
+	return &MatchUsersResponse{MatchedUsers: foundUsers}
 }
 
 // FRIENDS
-func (db *Database) AddFriend(data string) string {
-	jsondata := FriendCmdStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
+func (db *Database) AddFriend(jsondata *FriendCmdStruct) error {
+	friend := db.Users.GetUser(strings.ToUpper(jsondata.FriendUID))
+	if friend.Username == "" {
+		return ErrUserNotFound
 	}
 	// get user, add friend, save back
-	user := db.GetAerospikeUser(strings.ToUpper(jsondata.UID))
-	// make sure friend isn't already added to user
 	friendFound := false
-	userFriends := user.GetFriendStructs()
-	for _, friend := range userFriends {
-		if strings.ToUpper(friend.Username) == strings.ToUpper(jsondata.FriendUID) {
-			friendFound = true
-			break
-		}
-	}
-	// check both incoming and outgoing friend requests
-	userIncomingPendingFriends := user.GetIncomingPendingFriendStructs()
-	for _, friend := range userIncomingPendingFriends {
-		if strings.ToUpper(friend.Username) == strings.ToUpper(jsondata.FriendUID) {
-			friendFound = true
-			break
-		}
-	}
-
-	userOutgoingFriendRequests := user.GetOutgoingPendingFriendStructs()
-	for _, friend := range userOutgoingFriendRequests {
-		if strings.ToUpper(friend.Username) == strings.ToUpper(jsondata.FriendUID) {
-			friendFound = true
-			break
+	user, err := db.updateUser(jsondata.UID, func(user *UserStruct) error {
+		// make sure friend isn't already added to user, check both incoming
+		// and outgoing friend requests too
+		friendFound = hasFriend(user.GetFriendStructs(), jsondata.FriendUID) ||
+			hasFriend(user.GetIncomingPendingFriendStructs(), jsondata.FriendUID) ||
+			hasFriend(user.GetOutgoingPendingFriendStructs(), jsondata.FriendUID)
+		if friendFound {
+			return errNoChange
 		}
-	}
-
-	if !friendFound {
 		TRACE.Println("FriendUID " + jsondata.FriendUID + " was not found as a friend, and is being added.")
-		// get user, update PendingFriends, save back
-		friend := db.GetAerospikeUser(strings.ToUpper(jsondata.FriendUID))
 		// save outgoing friend request to user who requested it
 		newOutgoingFriend := UserFriendStruct{
 			Username:   friend.Username,
 			ProfilePic: friend.ProfilePic,
 		}
-		userOutgoingFriendRequests = append(userOutgoingFriendRequests, newOutgoingFriend)
-		user.SaveOutgoingPendingFriendStructs(userOutgoingFriendRequests)
-		OutPendFriendBin := aerospike.NewBin("OutPendFriend", user.OutgoingPendingFriends)
-		userkey, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(user.Username))
-		if err == nil {
-			TRACE.Println("Saving user.OutgoingPendingFriends to " + user.UsernameUpper)
-			TRACE.Println("updated user.OutgoingPendingFriends = " + user.OutgoingPendingFriends)
-			db.UpdateAerospikeSingleBin(userkey, OutPendFriendBin)
-		}
-		// add profile pic and user who requested the friend to be added
-		newFriend := UserFriendStruct{
-			Username:   user.Username,
-			ProfilePic: user.ProfilePic,
-			Message:    jsondata.Message,
-		}
-		// save friend
-		friendPendingFriends := friend.GetIncomingPendingFriendStructs()
-		friendPendingFriends = append(friendPendingFriends, newFriend)
-		friend.SaveIncomingPendingFriendStructs(friendPendingFriends)
-		InPendFriendBin := aerospike.NewBin("InPendFriend", friend.IncomingPendingFriends)
-		friendkey, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(friend.Username))
-		if err == nil {
-			TRACE.Println("saving friend.IncomingPendingFriends to " + friend.UsernameUpper)
-			TRACE.Println("updated friend.IncomingPendingFriends = " + friend.IncomingPendingFriends)
-			db.UpdateAerospikeSingleBin(friendkey, InPendFriendBin)
-		}
-
-		// send to all active web devices for both users
-		webstrFriend := `{"cmd":"AddIncomingFriend","Friend":{"Username":"` + user.Username + `","ProfilePic":"` + user.ProfilePic + `","Message":"` + jsondata.Message + `"}}`
-		db.SendStringToWebDevices(friend.Web, webstrFriend)
-		// now send to other friend
-		webstrUser := `{"cmd":"AddOutgoingFriend","Friend":{"Username":"` + user.Username + `","ProfilePic":"` + user.ProfilePic + `","Message":"` + jsondata.Message + `"}}`
-		db.SendStringToWebDevices(user.Web, webstrUser)
+		user.SaveOutgoingPendingFriendStructs(append(user.GetOutgoingPendingFriendStructs(), newOutgoingFriend))
+		TRACE.Println("updated user.OutgoingPendingFriends = " + user.OutgoingPendingFriends)
+		return nil
+	})
+	if err != nil || friendFound {
+		return err
+	}
+	// add profile pic and user who requested the friend to be added
+	newFriend := UserFriendStruct{
+		Username:   user.Username,
+		ProfilePic: user.ProfilePic,
+		Message:    jsondata.Message,
+	}
+	// save friend
+	friend, err = db.updateUser(friend.UsernameUpper, func(friend *UserStruct) error {
+		if hasFriend(friend.GetIncomingPendingFriendStructs(), user.Username) {
+			return errNoChange
+		}
+		friend.SaveIncomingPendingFriendStructs(append(friend.GetIncomingPendingFriendStructs(), newFriend))
+		TRACE.Println("updated friend.IncomingPendingFriends = " + friend.IncomingPendingFriends)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	return "" // we did our sending above in if !friendFound
+
+	// send to all active web devices for both users
+	webstrFriend := `{"cmd":"AddIncomingFriend","Friend":{"Username":"` + user.Username + `","ProfilePic":"` + user.ProfilePic + `","Message":"` + jsondata.Message + `"}}`
+	db.sendChangeString(friend, CHANGE_FRIENDS, webstrFriend)
+	// now send to other friend
+	webstrUser := `{"cmd":"AddOutgoingFriend","Friend":{"Username":"` + user.Username + `","ProfilePic":"` + user.ProfilePic + `","Message":"` + jsondata.Message + `"}}`
+	db.sendChangeString(user, CHANGE_FRIENDS, webstrUser)
+	return nil
 }
 
-func (db *Database) AcceptFriendRequest(data string) string {
-	jsondata := FriendCmdStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
+func (db *Database) AcceptFriendRequest(jsondata *FriendCmdStruct) error {
+	friend := db.Users.GetUser(strings.ToUpper(jsondata.FriendUID))
+	if friend.Username == "" {
+		return ErrUserNotFound
 	}
 	// get user, add friend, save back
-	user := db.GetAerospikeUser(strings.ToUpper(jsondata.UID))
-	friend := db.GetAerospikeUser(strings.ToUpper(jsondata.FriendUID))
-	// make sure friend isn't already added to user
 	friendFound := false
-	userFriends := user.GetFriendStructs()
-	for _, friend := range userFriends {
-		if strings.ToUpper(friend.Username) == strings.ToUpper(jsondata.FriendUID) {
-			friendFound = true
-			break
+	user, err := db.updateUser(jsondata.UID, func(user *UserStruct) error {
+		// make sure friend isn't already added to user
+		userFriends := user.GetFriendStructs()
+		if friendFound = hasFriend(userFriends, jsondata.FriendUID); friendFound {
+			return errNoChange
 		}
-	}
-
-	if !friendFound {
 		// TRACE.Println("FriendUID " + jsondata.FriendUID + " was not found as a friend, and is being added.")
 		// remove from IncomingPendingFriends
-		userIncomingPendingFriends := user.GetIncomingPendingFriendStructs()
-		for i, pendingFriend := range userIncomingPendingFriends {
-			if strings.ToUpper(pendingFriend.Username) == strings.ToUpper(jsondata.FriendUID) {
-				userIncomingPendingFriends = append(userIncomingPendingFriends[:i], userIncomingPendingFriends[i+1:]...)
-				user.SaveIncomingPendingFriendStructs(userIncomingPendingFriends)
-				break
-			}
-		}
-		// remove from friend's OutgoingPendingFriends
-		friendOutgoingPendingFriends := friend.GetOutgoingPendingFriendStructs()
-		for i, pendingFriend := range friendOutgoingPendingFriends {
-			if strings.ToUpper(pendingFriend.Username) == strings.ToUpper(user.UsernameUpper) {
-				friendOutgoingPendingFriends = append(friendOutgoingPendingFriends[:i], friendOutgoingPendingFriends[i+1:]...)
-				friend.SaveOutgoingPendingFriendStructs(friendOutgoingPendingFriends)
-				break
-			}
-		}
+		incoming, _ := removeFriend(user.GetIncomingPendingFriendStructs(), jsondata.FriendUID)
+		user.SaveIncomingPendingFriendStructs(incoming)
 		// now add to friends
 		newFriend := UserFriendStruct{
 			Username:   friend.Username,
 			ProfilePic: friend.ProfilePic,
 		}
-		userFriends = append(userFriends, newFriend)
-		user.SaveFriendStructs(userFriends)
-		// now save back
-		db.SetAerospikeUser(user.UsernameUpper, user.ToAerospikeBins())
-		// now append user to friend
-		newUserFriend := UserFriendStruct{
-			Username:   user.Username,
-			ProfilePic: user.ProfilePic,
-		}
-		friendFriends := friend.GetFriendStructs()
-		friendFriends = append(friendFriends, newUserFriend)
-		friend.SaveFriendStructs(friendFriends)
-		// save to aerospike
-		db.SetAerospikeUser(friend.UsernameUpper, friend.ToAerospikeBins())
-
-		// send to both friend and user on any active device
-		webstrFriend := `{"cmd":"AcceptFriendRequest", "Friend":{"Username":"` + user.Username + `", "ProfilePic":"` + user.ProfilePic + `"}}`
-		db.SendStringToWebDevices(friend.Web, webstrFriend)
-		// now send to other friend
-		webstrUser := `{"cmd":"AcceptFriendRequest", "Friend":{"Username":"` + friend.Username + `", "ProfilePic":"` + friend.ProfilePic + `"}}`
-		db.SendStringToWebDevices(user.Web, webstrUser)
+		user.SaveFriendStructs(append(userFriends, newFriend))
+		return nil
+	})
+	if err != nil || friendFound {
+		return err
 	}
-	return ""
-}
-
-func (db *Database) DenyFriendRequest(data string) string {
-	jsondata := FriendCmdStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
+	// now append user to friend
+	newUserFriend := UserFriendStruct{
+		Username:   user.Username,
+		ProfilePic: user.ProfilePic,
+	}
+	friend, err = db.updateUser(friend.UsernameUpper, func(friend *UserStruct) error {
+		// remove from friend's OutgoingPendingFriends
+		outgoing, _ := removeFriend(friend.GetOutgoingPendingFriendStructs(), user.Username)
+		friend.SaveOutgoingPendingFriendStructs(outgoing)
+		friendFriends := friend.GetFriendStructs()
+		if !hasFriend(friendFriends, user.Username) {
+			friend.SaveFriendStructs(append(friendFriends, newUserFriend))
+		}
+		return nil
+	})
 	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
+		return err
 	}
 
+	// send to both friend and user on any active device
+	webstrFriend := `{"cmd":"AcceptFriendRequest", "Friend":{"Username":"` + user.Username + `", "ProfilePic":"` + user.ProfilePic + `"}}`
+	db.sendChangeString(friend, CHANGE_FRIENDS, webstrFriend)
+	// now send to other friend
+	webstrUser := `{"cmd":"AcceptFriendRequest", "Friend":{"Username":"` + friend.Username + `", "ProfilePic":"` + friend.ProfilePic + `"}}`
+	db.sendChangeString(user, CHANGE_FRIENDS, webstrUser)
+	return nil
+}
+
+func (db *Database) DenyFriendRequest(jsondata *FriendCmdStruct) error {
 	// get user, deny friend, save back
-	user := db.GetAerospikeUser(strings.ToUpper(jsondata.UID))
-	userPendingFriends := user.GetIncomingPendingFriendStructs()
-	for i, pendingFriend := range userPendingFriends {
-		if strings.ToUpper(pendingFriend.Username) == strings.ToUpper(jsondata.FriendUID) {
-			userPendingFriends = append(userPendingFriends[:i], userPendingFriends[i+1:]...)
-			user.SaveIncomingPendingFriendStructs(userPendingFriends)
-			userbins := user.ToAerospikeBins()
-			db.SetAerospikeUser(user.UsernameUpper, userbins)
-			break
+	user, err := db.updateUser(jsondata.UID, func(user *UserStruct) error {
+		incoming, removed := removeFriend(user.GetIncomingPendingFriendStructs(), jsondata.FriendUID)
+		if !removed {
+			return errNoChange
 		}
+		user.SaveIncomingPendingFriendStructs(incoming)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// get friend, deny user, save back
-	friend := db.GetAerospikeUser(strings.ToUpper(jsondata.FriendUID))
-	friendOutgoingPendingFriends := friend.GetOutgoingPendingFriendStructs()
-	TRACE.Println("friendOutgoingPendingFriends:")
-	TRACE.Println(friendOutgoingPendingFriends)
-	for i, pendingFriend := range friendOutgoingPendingFriends {
-		TRACE.Println("comparing " + pendingFriend.Username + " == " + jsondata.UID)
-		if strings.ToUpper(pendingFriend.Username) == strings.ToUpper(jsondata.UID) {
-			friendOutgoingPendingFriends = append(friendOutgoingPendingFriends[:i], friendOutgoingPendingFriends[i+1:]...)
-			friend.SaveOutgoingPendingFriendStructs(friendOutgoingPendingFriends)
-			friendbins := friend.ToAerospikeBins()
-			db.SetAerospikeUser(friend.UsernameUpper, friendbins)
-			break
+	friend, err := db.updateUser(jsondata.FriendUID, func(friend *UserStruct) error {
+		outgoing, removed := removeFriend(friend.GetOutgoingPendingFriendStructs(), jsondata.UID)
+		if !removed {
+			return errNoChange
 		}
+		friend.SaveOutgoingPendingFriendStructs(outgoing)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// send to both friend and user on any active device
 	webstrFriend := `{"cmd":"DenyFriendRequest", "Friend":"` + user.Username + `"}`
-	db.SendStringToWebDevices(friend.Web, webstrFriend)
+	db.sendChangeString(friend, CHANGE_FRIENDS, webstrFriend)
 	// now send to other friend
 	webstrUser := `{"cmd":"DenyFriendRequest", "Friend":"` + friend.Username + `"}`
-	db.SendStringToWebDevices(user.Web, webstrUser)
-	return "" // we sent to web devices above already
+	db.sendChangeString(user, CHANGE_FRIENDS, webstrUser)
+	return nil
 }
 
-func (db *Database) RemoveFriend(data string) string {
-	jsondata := FriendCmdStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-
+func (db *Database) RemoveFriend(jsondata *FriendCmdStruct) error {
 	// get user, remove friend, save back
-	user := db.GetAerospikeUser(strings.ToUpper(jsondata.UID))
-	userFriends := user.GetFriendStructs()
-	for i, pendingFriend := range userFriends {
-		if strings.ToUpper(pendingFriend.Username) == strings.ToUpper(jsondata.FriendUID) {
-			userFriends = append(userFriends[:i], userFriends[i+1:]...)
-			user.SaveFriendStructs(userFriends)
-			friendsBin := aerospike.NewBin("Friends", user.Friends)
-			key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(user.UsernameUpper))
-			if err == nil {
-				db.UpdateAerospikeSingleBin(key, friendsBin)
-			}
-			break
+	user, err := db.updateUser(jsondata.UID, func(user *UserStruct) error {
+		friends, removed := removeFriend(user.GetFriendStructs(), jsondata.FriendUID)
+		if !removed {
+			return errNoChange
 		}
+		user.SaveFriendStructs(friends)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	// get friend, deny user, save back
-	friend := db.GetAerospikeUser(strings.ToUpper(jsondata.FriendUID))
-	friendFriends := friend.GetFriendStructs()
-	TRACE.Println("friendFriends:")
-	TRACE.Println(friendFriends)
-	for i, pendingFriend := range friendFriends {
-		TRACE.Println("comparing " + pendingFriend.Username + " == " + jsondata.UID)
-		if strings.ToUpper(pendingFriend.Username) == strings.ToUpper(jsondata.UID) {
-			friendFriends = append(friendFriends[:i], friendFriends[i+1:]...)
-			friend.SaveFriendStructs(friendFriends)
-			friendsBin := aerospike.NewBin("Friends", friend.Friends)
-			key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(friend.UsernameUpper))
-			if err == nil {
-				db.UpdateAerospikeSingleBin(key, friendsBin)
-			}
-			break
+	// get friend, remove user, save back
+	friend, err := db.updateUser(jsondata.FriendUID, func(friend *UserStruct) error {
+		friends, removed := removeFriend(friend.GetFriendStructs(), jsondata.UID)
+		if !removed {
+			return errNoChange
 		}
+		friend.SaveFriendStructs(friends)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// send to both friend and user on any active device
 	webstrFriend := `{"cmd":"RemoveFriend", "Friend":"` + user.Username + `"}`
-	db.SendStringToWebDevices(friend.Web, webstrFriend)
+	db.sendChangeString(friend, CHANGE_FRIENDS, webstrFriend)
 	// now send to other friend
 	webstrUser := `{"cmd":"RemoveFriend", "Friend":"` + friend.Username + `"}`
-	db.SendStringToWebDevices(user.Web, webstrUser)
-	return "" // we sent to web devices above already
+	db.sendChangeString(user, CHANGE_FRIENDS, webstrUser)
+	return nil
 }
 
-func (db *Database) SaveAutoreplyMessage(data string) string {
-	// get user
-	jsondata := AutoreplyList{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-	user := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	if user.AutoreplyMessage == jsondata.Message {
-		return `{"cmd":"AddAutoreplyMessage","ret_msg":"Autoreply message has not changed."}` // autoreply message is the same, so don't do anything
-	} else {
+func (db *Database) SaveAutoreplyMessage(jsondata *AutoreplyList) error {
+	_, err := db.updateUser(jsondata.Username, func(user *UserStruct) error {
+		if user.AutoreplyMessage == jsondata.Message {
+			return errNoChange // autoreply message is the same, so don't do anything
+		}
 		// autoreply message is different, so set and clear for each CID
 		user.AutoreplyMessage = jsondata.Message
-		// now update user struct
 		userCIDs := user.GetCIDStructs()
 		for i, _ := range userCIDs {
 			userCIDs[i].AutoreplySent = 0
 		}
 		user.SaveCIDStructs(userCIDs)
-		// TRACE.Println("saving user: " + user.ToJSONString())
-		bins := aerospike.BinMap{
-			"CIDs":          user.CIDs,
-			"AutoreplyNote": user.AutoreplyMessage,
-		}
-		key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(user.UsernameUpper))
-		if err == nil {
-			db.WriteAerospikeMultipleBins(key, bins)
-		} else { // return error
-			return `{"cmd":"AddAutoreplyMessage","ret_msg":"An error occured trying to save the autoreply message on PingedChat servers."}`
-		}
-	}
-	return `{"cmd":"AddAutoreplyMessage","ret_msg":"Autoreply message saved successfully on PingedChat servers."}`
+		return nil
+	})
+	return err
 }
 
-func (db *Database) ChangeProfilePic(data string) string {
-	// get user
-	jsondata := UserStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
+func (db *Database) ChangeProfilePic(jsondata *ProfilePicCmdStruct) (*UserStruct, error) {
 	// get user, save profile pic back
-	user := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	user.ProfilePic = jsondata.ProfilePic
-	profilePicBin := aerospike.NewBin("ProfilePic", user.ProfilePic)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(user.UsernameUpper))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, profilePicBin)
+	user, err := db.updateUser(jsondata.Username, func(user *UserStruct) error {
+		user.ProfilePic = jsondata.ProfilePic
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	// loop through friends and update user's profile pic
-	userFriends := user.GetFriendStructs()
-	for _, element := range userFriends {
-		friend := db.GetAerospikeUser(strings.ToUpper(element.Username))
-		friendFriends := friend.GetFriendStructs()
-		for i, e := range friendFriends {
-			if strings.ToUpper(e.Username) == user.UsernameUpper {
-				friendFriends[i].ProfilePic = user.ProfilePic
-				friend.SaveFriendStructs(friendFriends)
-				// save to aerospike
-				friendsBin := aerospike.NewBin("Friends", friend.Friends)
-				key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(friend.UsernameUpper))
-				if err == nil {
-					db.UpdateAerospikeSingleBin(key, friendsBin)
+	for _, element := range user.GetFriendStructs() {
+		db.updateUser(element.Username, func(friend *UserStruct) error {
+			friendFriends := friend.GetFriendStructs()
+			for i, e := range friendFriends {
+				if strings.ToUpper(e.Username) == user.UsernameUpper {
+					friendFriends[i].ProfilePic = user.ProfilePic
+					friend.SaveFriendStructs(friendFriends)
+					return nil
 				}
-				break // continue on to outer for loop
 			}
-		}
+			return errNoChange
+		})
 	}
-	return user.ToJSONStringWithCmd("ChangeProfilePic")
+	return &user, nil
 }
 
-func (db *Database) ChangeUserPhone(data string) string {
-	jsondata := ChangeAccountStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
-	}
-
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
+func (db *Database) ChangeUserPhone(jsondata *ChangeAccountStruct) (*UserStruct, error) {
+	storeduser := db.Users.GetUser(strings.ToUpper(jsondata.Username))
 	if storeduser.Username == "" {
-		return ""
+		return nil, ErrUserNotFound
 	}
 	phonenum := validatePhoneAndGetPhoneGateway(jsondata.Phone)
 	if phonenum == "" {
 		ERROR.Printf("validatePhoneAndGetPhoneGateway in ChangeUserPhone says the number isn't any good!")
-		return ""
+		return nil, cmdError(ERR_BAD_REQUEST, "Phone isn't a valid phone number")
 	}
 	// valid phone number and user if we didn't return above
-	storeduser.Phone = formatPhoneNumber(jsondata.Phone)
-	storeduser.PhoneGateway = phonenum
-	phoneBin := aerospike.NewBin("Phone", storeduser.Phone)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, phoneBin)
-	}
-	phoneGatewayBin := aerospike.NewBin("PhoneGateway", storeduser.PhoneGateway)
-	phoneGatewayKey, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(phoneGatewayKey, phoneGatewayBin)
-	}
-	return storeduser.ToJSONStringWithCmd("ChangeUserPhone")
-}
-
-func (db *Database) ChangeUserEmail(data string) string {
-	jsondata := ChangeAccountStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
+	storeduser, err := db.updateUser(storeduser.UsernameUpper, func(user *UserStruct) error {
+		user.Phone = formatPhoneNumber(jsondata.Phone)
+		user.PhoneGateway = phonenum
+		return nil
+	})
 	if err != nil {
-		ERROR.Println("error:", err)
-		return ""
+		return nil, err
 	}
+	return &storeduser, nil
+}
 
-	storeduser := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
+func (db *Database) ChangeUserEmail(jsondata *ChangeAccountStruct) (*UserStruct, error) {
+	storeduser := db.Users.GetUser(strings.ToUpper(jsondata.Username))
 	if storeduser.Username == "" {
-		return ""
+		return nil, ErrUserNotFound
 	}
 	// make sure email isn't registered to anybody else
 	// TODO:  validation?
-	if jsondata.Email == "" {
-		return "" // can't have an empty email!
-	}
-	existingUser := db.GetAerospikeUserByEmail(jsondata.Email)
+	existingUser := db.Users.GetUserByEmail(jsondata.Email)
 	if existingUser.Username != "" {
-		return "" // we had a user already with that username
+		return nil, cmdError(ERR_CONFLICT, "that email belongs to another user")
 	}
-	storeduser.Email = jsondata.Email
-	emailBin := aerospike.NewBin("Email", storeduser.Email)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(storeduser.UsernameUpper))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, emailBin)
+	storeduser, err := db.updateUser(storeduser.UsernameUpper, func(user *UserStruct) error {
+		user.Email = jsondata.Email
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return storeduser.ToJSONStringWithCmd("ChangeUserEmail")
+	return &storeduser, nil
 }
 
-func (db *Database) GetS3PolicyData(data string) string {
+func (db *Database) GetS3PolicyData(jsondata *EmptyCmdStruct) *S3PolicyResponse {
 	// TRACE.Println("in GetS3PolicyData")
-	s3data := s3Sign()
-	// TRACE.Println("s3Data = " + s3data)
-	return s3data
+	return s3Sign()
 }
 
 // DA BIG BOYS
+// data.MID must already be set, see newMessageID()
 func (db *Database) AddMessageToConvo(data MessageStruct) bool {
-	PostgresCIDstring := `"` + data.CID + `"`
-	CurString := "INSERT INTO " + PostgresCIDstring + " (f_username, content, m_time) VALUES ($1, $2, $3)" // conversation ID is the table name
-	// TRACE.Println("cur_string = " + CurString)
-	if db.postgres_conn == nil {
+	if db.Messages == nil {
 		return false // can't do anything with no database connection :(
 	}
-	_, inserterr := db.postgres_conn.Exec(CurString, data.FromUsername, data.Content, data.M_time)
+	inserterr := db.Messages.AddMessage(data)
 	if inserterr != nil {
 		ERROR.Println("error:", inserterr)
 		return false
@@ -2429,30 +1277,42 @@ func (db *Database) AddMessageToConvo(data MessageStruct) bool {
 	return true
 }
 
-func (db *Database) HandleBots(message string) string {
-	if strings.HasPrefix(strings.ToLower(message), giphyPrefix) {
-		// handle giphy
-		TRACE.Println("handling giphy bot")
-		message = rockGiphy(message[len(giphyPrefix):])
-	} else if strings.HasPrefix(strings.ToLower(message), forecastPrefix) {
-		message = GetWeather(message[len(forecastPrefix):])
+func (db *Database) SendMessage(msg *MessageStruct) error {
+	jsondata := *msg // copy, the autoreplies below reuse it
+	// a reply goes in its thread, whoever's in it gets pushed even if muted
+	var participants map[string]bool
+	if jsondata.ParentMID != "" {
+		parentMID, err := db.threadRoot(jsondata.CID, jsondata.ParentMID, false)
+		if err != nil {
+			return err
+		}
+		jsondata.ParentMID = parentMID
+		participants = db.threadParticipants(jsondata.CID, parentMID)
 	}
-	return message
-}
-
-func (db *Database) SendMessage(data string) string {
-	jsondata := MessageStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
+	jsondata.Content = sanitizeMarkup(jsondata.Content)
+	attachments, err := sanitizeAttachments(jsondata.Attachments)
 	if err != nil {
-		ERROR.Println("error in SendMessage Unmarshalling into MessageStruct:", err)
-		return ""
-	}
-	message := db.HandleBots(jsondata.Content)
-	jsondata.Content = message
-	// add message to convo
-	messageAdded := db.AddMessageToConvo(jsondata)
-	if !messageAdded {
+		return err
+	}
+	jsondata.Attachments = attachments
+	bot, botResp := db.HandleBots(&jsondata)
+	if botResp.Drop {
+		db.sendBotReplies(jsondata, bot, botResp)
+		return nil
+	}
+	jsondata.MID = newMessageID() // clients don't get to pick message IDs
+	// only members of the conversation get it
+	var toUIDs []string
+	for _, member := range jsondata.ToUIDs {
+		if db.IsConvoMember(jsondata.CID, member) {
+			toUIDs = append(toUIDs, member)
+		}
+	}
+	jsondata.ToUIDs = toUIDs
+	// add message to convo, nobody hears about a message that wasn't saved
+	if !db.AddMessageToConvo(jsondata) {
 		ERROR.Println("Error in adding message to postgres")
+		return ErrMessagesDown
 	}
 
 	// save any autoreplies to be sent
@@ -2460,7 +1320,7 @@ func (db *Database) SendMessage(data string) string {
 
 	for _, member := range jsondata.ToUIDs {
 		// TRACE.Println("SendMessage member = " + string(member))
-		recipient := db.GetAerospikeUser(member)
+		recipient := db.Users.GetUser(member)
 		if recipient.UsernameUpper == "" {
 			continue // user not found
 		}
@@ -2471,7 +1331,8 @@ func (db *Database) SendMessage(data string) string {
 		// otherwise they're sitting at their computer and getting pushes about it
 		// or pushed to the very device they used to send the message!
 		isSelf := (recipient.UsernameUpper == strings.ToUpper(jsondata.FromUsername))
-		TRACE.Printf("isSelf: %d", isSelf)
+		TRACE.Printf("isSelf: %t", isSelf)
+		push := !isSelf && (!convoMuted(recipient, jsondata.CID) || participants[recipient.UsernameUpper])
 
 		// do the real magic, sending to devices!
 		hasAndroid := false
@@ -2479,10 +1340,8 @@ func (db *Database) SendMessage(data string) string {
 		hasIos := false
 		hasWeb := false
 
-		if !isSelf {
+		if push {
 			// android first
-			TRACE.Println("len(recipient.Android) = ")
-			TRACE.Println(len(recipient.Android))
 			if len(recipient.Android) > 0 {
 				// TRACE.Println("pushing to Android device")
 				PushToAndroid(recipient.Android, jsondata)
@@ -2490,80 +1349,78 @@ func (db *Database) SendMessage(data string) string {
 			}
 
 			// fireos next
-			TRACE.Println("len(recipient.Fireos) = ")
-			TRACE.Println(len(recipient.Fireos))
 			if len(recipient.Fireos) > 0 {
 				// TRACE.Println("pushing to Fireos device")
 				PushToFireos(recipient.Fireos, jsondata)
 				hasFireos = true
 			}
 
-			TRACE.Println("len(recipient.Ios) = ")
-			TRACE.Println(len(recipient.Ios))
 			if len(recipient.Ios) > 0 {
 				// TRACE.Println("pushing to iOS device")
 				PushToIos(recipient.Ios, jsondata)
 				hasIos = true
 			}
-		} // if (!isSelf)
+		} // if (push)
 
 		webzString, err := ffjson.Marshal(jsondata) // do this for HandleBots content that's been updated
 		if err != nil {
 			ERROR.Println("Error in ffjson.Marshal(jsondata) in SendMessage")
 		} else {
 			// TRACE.Println("webzString = " + string(webzString))
-			for _, webz := range recipient.Web {
-				// TRACE.Println("web device: " + webz)
-				if db.nats_encodedconn != nil {
-					db.nats_encodedconn.Publish(webz, string(webzString))
-				}
-				hasWeb = true
-			}
+			db.sendChangeString(recipient, CHANGE_MESSAGE, string(webzString))
+			hasWeb = len(recipient.Web) > 0
 		}
 
 		// send SMS if nothing else registered
-		if !hasAndroid && !hasFireos && !hasIos && !hasWeb && !isSelf {
+		if !hasAndroid && !hasFireos && !hasIos && !hasWeb && push {
 			// TODO: sendSMS
 			TRACE.Println("sending SMS")
 			if (strings.ToUpper(recipient.Username) != strings.ToUpper(jsondata.FromUsername)) && (recipient.Phone != "") {
-				convoName := db.GetAerospikeConvoName(jsondata.CID)
+				convoName := db.Convos.GetConvoName(jsondata.CID)
 				PushToSMS(recipient.PhoneGateway, jsondata, convoName, recipient.Username)
 			}
 		}
 
-		// loop through user CIDs and modify m_time
+		// bump the recipient's m_time and unread_count for the convo
 		//        alternative is to just use convos->[CID]->m_time, but how does that get updated to the user?
-		// also unread_count.  Shoot, may have to loop through anyways.
-		recipientCIDs := recipient.GetCIDStructs()
-		for index, element := range recipientCIDs {
-			if element.CID == jsondata.CID {
-				// TRACE.Println("Updating user CID at index ")
-				// TRACE.Println(index)
-				// TRACE.Println(" , element = ")
-				// TRACE.Println(element)
-				// check for autoreply
-				if recipient.AutoreplyMessage != "" && element.AutoreplySent == 0 {
-					TRACE.Println(recipient.Username + " needs to send an autoreply")
-					autoreplies = append(autoreplies, AutoreplyList{recipient.Username, recipient.AutoreplyMessage})
-					element.AutoreplySent = 1 // we're sending later, so mark it as such
-				}
-				element.M_time = jsondata.M_time
-				element.UnreadCount++
-				recipientCIDs[index] = element
-				recipient.SaveCIDStructs(recipientCIDs)
-				// TRACE.Println("saving recipient: " + recipient.ToJSONString())
-				CIDsBin := aerospike.NewBin("CIDs", recipient.CIDs)
-				key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(recipient.UsernameUpper))
-				if err == nil {
-					db.UpdateAerospikeSingleBin(key, CIDsBin)
+		if recipient.AutoreplyMessage == "" {
+			// nothing to check first, so it's done on the record in place
+			if err := db.Users.BumpUserConvo(recipient.UsernameUpper, jsondata.CID, jsondata.M_time); err != nil && err != ErrNotInConvo {
+				ERROR.Println("error in BumpUserConvo for "+recipient.Username+":", err)
+			}
+			continue
+		}
+		// the autoreply only goes out once per convo, so this one reads the user
+		sendAutoreply := false
+		recipient, err = db.updateUser(recipient.UsernameUpper, func(recipient *UserStruct) error {
+			sendAutoreply = false
+			recipientCIDs := recipient.GetCIDStructs()
+			for index, element := range recipientCIDs {
+				if element.CID == jsondata.CID {
+					// check for autoreply
+					if recipient.AutoreplyMessage != "" && element.AutoreplySent == 0 {
+						sendAutoreply = true
+						element.AutoreplySent = 1 // we're sending later, so mark it as such
+					}
+					element.M_time = jsondata.M_time
+					element.UnreadCount++
+					recipientCIDs[index] = element
+					recipient.SaveCIDStructs(recipientCIDs)
+					return nil
 				}
-				break
 			}
+			return errNoChange
+		})
+		if err == nil && sendAutoreply {
+			TRACE.Println(recipient.Username + " needs to send an autoreply")
+			autoreplies = append(autoreplies, AutoreplyList{recipient.Username, recipient.AutoreplyMessage})
 		}
 	} // end for ToUIDs loop
 
 	// update time of convo
-	db.SetAerospikeConvoMtime(jsondata.CID, jsondata.M_time)
+	db.Convos.SetConvoMtime(jsondata.CID, jsondata.M_time)
+	db.unfurlLinks(jsondata)
+	db.sendBotReplies(jsondata, bot, botResp)
 
 	// send autoreplies, only to web devices though (not worth a push notification)
 	if len(autoreplies) > 0 {
@@ -2571,43 +1428,43 @@ func (db *Database) SendMessage(data string) string {
 		for _, reply := range autoreplies {
 			// format message for sending
 			// we'll use same jsondata as before, since it's going to the same group as before
-			jsondata.Content = "AUTOREPLY: " + reply.Message
+			jsondata.Content = sanitizeMarkup("AUTOREPLY: " + reply.Message)
+			jsondata.Attachments = nil
 			jsondata.FromUsername = reply.Username
+			jsondata.MID = newMessageID()
 			autoReplyString, err := ffjson.Marshal(jsondata) // do this for HandleBots content that's been updated
 			if err != nil {
 				ERROR.Println("Error in ffjson.Marshal(jsondata) in SendMessage autoreplies")
 			} else {
-				// add to db
-				messageAdded := db.AddMessageToConvo(jsondata)
-				if !messageAdded {
-					ERROR.Println("Error in adding message to postgres")
+				// add to db, it isn't sent if it wasn't saved
+				if !db.AddMessageToConvo(jsondata) {
+					ERROR.Println("Error in adding autoreply to postgres")
+					continue
 				}
 				// send to users
 				for _, member := range jsondata.ToUIDs {
 					// we have a member who needs the autoreply sent to them
-					recipient := db.GetAerospikeUser(member)
+					recipient := db.Users.GetUser(member)
 					if recipient.UsernameUpper == "" {
 						continue // user not found
 					}
-					db.SendStringToWebDevices(recipient.Web, string(autoReplyString))
+					db.sendChangeString(recipient, CHANGE_MESSAGE, string(autoReplyString))
 				}
 			}
 		}
 	}
-
-	return ""
+	return nil
 }
 
-func (db *Database) UpdateUserStatus(data string) string {
-	jsondata := CmdConvoMember{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error in UpdateUserStatus Unmarshalling into MessageStruct:", err)
-		return ""
-	}
+func (db *Database) UpdateUserStatus(jsondata *CmdConvoMember) {
 	// TRACE.Println("jsondata.ReadTime: " + jsondata.ReadTime)
 	// TRACE.Println(jsondata)
-	convoMembersStrings := db.GetAerospikeConvoMembers(jsondata.CID)
+	// typing isn't saved any more, it's only sent out, see typing.go
+	if jsondata.NewReadTime == "" {
+		db.setTyping(jsondata.CID, jsondata.Typing)
+		return
+	}
+	convoMembersStrings := db.Convos.GetConvoMembers(jsondata.CID)
 	convoMembers := ToConvoMemberArray(convoMembersStrings)
 	// TRACE.Println("convoMembers: ")
 	// TRACE.Println(convoMembers)
@@ -2616,105 +1473,102 @@ func (db *Database) UpdateUserStatus(data string) string {
 	UsernameUpper := strings.ToUpper(jsondata.Username)
 	for i, e := range convoMembers {
 		if strings.ToUpper(e.Username) == UsernameUpper {
-			if jsondata.NewReadTime != "" {
-				if convoMembers[i].ReadTime < jsondata.NewReadTime {
-					convoMembers[i].ReadTime = jsondata.NewReadTime
-				}
+			if convoMembers[i].ReadTime < jsondata.NewReadTime {
+				convoMembers[i].ReadTime = jsondata.NewReadTime
 			}
-			convoMembers[i].Typing = jsondata.Typing // possible bug if user is updating read_time while actively typing
-			// cause then the code will default to False for JSON Unmarshal and set Active to False
 			break
 		}
 	}
 	// save back
-	db.SetAerospikeConvoMembers(jsondata.CID, convoMembers.ToStringArray())
+	db.Convos.SetConvoMembers(jsondata.CID, convoMembers.ToStringArray())
 	// setting updated M_time to now so users know to get updated data when offline
 	newMTime := getCurrentUTCISOTimeString()
-	db.SetAerospikeConvoMtime(jsondata.CID, newMTime)
+	db.Convos.SetConvoMtime(jsondata.CID, newMTime)
 	// now loop through users and send update status string to them
 	jsondata.Cmd = "UpdateUserStatus" // want to send Cmd back
 	jsonBytes, err := ffjson.Marshal(jsondata)
+	// the others don't get the read time of a user that hides it
+	hiddenBytes := jsonBytes
+	if db.Users.GetUser(UsernameUpper).HideReadTime {
+		hidden := *jsondata
+		hidden.OldReadTime, hidden.NewReadTime = "", ""
+		hiddenBytes, err = ffjson.Marshal(hidden)
+	}
 	if err != nil {
 		ERROR.Println("error in ffjson.Marshal in UpdateUserStatus:")
 		ERROR.Println(err)
 	} else {
-		jsonString := string(jsonBytes)
-		if db.nats_encodedconn != nil {
+		if db.Events != nil {
 			// TRACE.Println("looping through each member and publishing user status update to them")
 			for _, e := range convoMembers {
-				recipient := db.GetAerospikeUser(strings.ToUpper(e.Username))
-				db.SendStringToWebDevices(recipient.Web, jsonString)
+				recipient := db.Users.GetUser(strings.ToUpper(e.Username))
+				if recipient.UsernameUpper == UsernameUpper {
+					db.sendChangeString(recipient, CHANGE_CONVO, string(jsonBytes))
+				} else {
+					db.sendChangeString(recipient, CHANGE_CONVO, string(hiddenBytes))
+				}
 			}
 		}
 	}
 
-	return ""
 }
 
-func (db *Database) UpdateUnreadCount(data string) string {
-	jsondata := CmdConvoUnreadCount{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error in UpdateUnreadCount Unmarshalling into MessageStruct:", err)
-		return ""
-	}
-	// get user, update unread count, save back
-	user := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	CIDs := user.GetCIDStructs()
-	for i, e := range CIDs {
-		if e.CID == jsondata.CID {
-			CIDs[i].UnreadCount = jsondata.UnreadCount
-			user.SaveCIDStructs(CIDs)
-			CIDsBin := aerospike.NewBin("CIDs", user.CIDs)
-			key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(user.UsernameUpper))
-			if err == nil {
-				db.UpdateAerospikeSingleBin(key, CIDsBin)
-			}
-			break // no need to continue on
-		}
-	}
-	return ""
+func (db *Database) UpdateUnreadCount(jsondata *CmdConvoUnreadCount) error {
+	// set in place on the user's record, see user_bins.go
+	err := db.Users.SetUserConvoUnread(jsondata.Username, jsondata.CID, jsondata.UnreadCount)
+	return userConvoError("SetUserConvoUnread", jsondata.Username, err)
 }
 
-func (db *Database) UpdateConvoMtime(data string) string {
-	jsondata := CmdConvoMtimeCount{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error in UpdateConvoMtime Unmarshalling into MessageStruct:", err)
-		return ""
-	}
-	// get user, update unread count, save back
-	user := db.GetAerospikeUser(strings.ToUpper(jsondata.Username))
-	CIDs := user.GetCIDStructs()
-	for i, e := range CIDs {
-		if e.CID == jsondata.CID {
-			CIDs[i].M_time = jsondata.Mtime
-			user.SaveCIDStructs(CIDs)
-			CIDsBin := aerospike.NewBin("CIDs", user.CIDs)
-			key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(user.UsernameUpper))
-			if err == nil {
-				db.UpdateAerospikeSingleBin(key, CIDsBin)
+func (db *Database) UpdateConvoMtime(jsondata *CmdConvoMtimeCount) error {
+	// set in place on the user's record, see user_bins.go
+	err := db.Users.SetUserConvoMtime(jsondata.Username, jsondata.CID, jsondata.Mtime)
+	return userConvoError("SetUserConvoMtime", jsondata.Username, err)
+}
+
+// the error to send back for a failed in place conversation update
+func userConvoError(name string, username string, err error) error {
+	switch err {
+	case nil:
+		return nil
+	case ErrNotInConvo:
+		return ErrNotMember
+	}
+	ERROR.Println("error in "+name+" for "+username+":", err)
+	return ErrNotSaved
+}
+
+// MuteConversation stops pushes to the caller's phones for CID, they still
+// get the messages on the web and replies in threads they're in
+func (db *Database) MuteConversation(jsondata *MuteCmdStruct) error {
+	muted := 0
+	if jsondata.Muted {
+		muted = 1
+	}
+	_, err := db.updateUser(jsondata.Username, func(user *UserStruct) error {
+		CIDs := user.GetCIDStructs()
+		for i, convo := range CIDs {
+			if convo.CID == jsondata.CID {
+				if convo.Muted == muted {
+					return errNoChange
+				}
+				CIDs[i].Muted = muted
+				user.SaveCIDStructs(CIDs)
+				return nil
 			}
-			break // no need to continue on
 		}
-	}
-	return ""
+		return ErrNotMember
+	})
+	return err
 }
 
-func (db *Database) SendEmailInvite(data string) string {
-	jsondata := InviteEmailStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error in UpdateUserStatus Unmarshalling into MessageStruct:", err)
-		return ""
-	}
+func (db *Database) SendEmailInvite(jsondata *InviteEmailStruct) *InviteResponse {
 	// now split emails into recipients
 	// emails should be delimited by ',' (a comma)
 	recipients := strings.Split(jsondata.Emails, ",")
 	// send email
-	mandrill.Key = MANDRILL_API_KEY
+	mandrill.Key = conf.Mail.MandrillAPIKey
 	// you can test your API key with Ping
-	err = mandrill.Ping()
+	err := mandrill.Ping()
 	// everything is OK if err is nil
 	if err != nil {
 		ERROR.Println("error in SendEmailInvite mandrill.Ping()")
@@ -2726,7 +1580,7 @@ func (db *Database) SendEmailInvite(data string) string {
 	for _, rec := range recipients {
 		TRACE.Println("SendEmailInvite, rec = " + rec)
 		// first look if email is registered to a user
-		emailUser := db.GetAerospikeUserByEmail(rec)
+		emailUser := db.Users.GetUserByEmail(rec)
 		if emailUser.Username != "" {
 			// we have a user!
 			foundUsers = append(foundUsers, FoundUserStruct{
@@ -2756,11 +1610,11 @@ func (db *Database) SendEmailInvite(data string) string {
 	// now handle phone numbers
 	// must verify correct phone number and send email/text
 	phones := strings.Split(jsondata.Phones, ",")
-	TRACE.Printf("phones : ", phones)
+	TRACE.Printf("phones : %v", phones)
 	for _, p := range phones {
 		TRACE.Println("SendEmailInvite, p = " + p)
 		// first see if phone number is registered to a user
-		phoneUser := db.GetAerospikeUserByPhone(p)
+		phoneUser := db.Users.GetUserByPhone(p)
 		if phoneUser.Username != "" {
 			// we have a user!
 			foundUsers = append(foundUsers, FoundUserStruct{
@@ -2792,65 +1646,68 @@ func (db *Database) SendEmailInvite(data string) string {
 	// return found users if any!
 	if len(foundUsers) > 0 {
 		// send back found users
-		userstr, err := ffjson.Marshal(foundUsers)
-		if err != nil {
-			ERROR.Println("error in ffjson.Marshal")
-			ERROR.Println(err)
-			return ""
-		}
-		return `{"cmd":"SendEmailInvite", "FoundUsers":` + string(userstr) + `}`
+		return &InviteResponse{FoundUsers: foundUsers}
 	} else {
-		return ""
+		return nil
 	}
 }
 
-// insert into postgres
+// insert into the user's mailbox
 func (db *Database) AddEmailToDb(Username string, From string, ToEmails string, RecvEmail string, Subject string, Content string, Attachments string, Starred bool, Unread bool, Spam bool, SentTime string, ModifiedTime string) bool {
 	// email table structure:
 	// [   FROM_EMAIL   |   TO_EMAILS    |  RECV_EMAIL  |  SUBJECT  |  CONTENT  | ATTACHMENTS |  STARRED  |  UNREAD  |   SPAM   |  DRAFT  |  DELETED  |  RECV_TIME  |    M_TIME   ]
 	// [     varchar    |    varchar     |   varchar    |  varchar  |  varchar  |   varchar   |  boolean  |  boolean |  boolean | boolean |  boolean  | timestamptz |  timestamptz ]
-	PostgresTableNamestring := `"` + Username + `@pinged.email` + `"`
-	CurString := "INSERT INTO " + PostgresTableNamestring + " (from_email, to_emails, recv_email, subject, content, attachments, starred, unread, spam, draft, deleted, recv_time, m_time) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)" // conversation ID is the table name
-	// TRACE.Println("cur_string = " + CurString)
-	if db.postgres_conn == nil {
-		var err error
-		TRACE.Println("opening postgres connection in AddEmailToDb()")
-		db.postgres_conn, err = sql.Open("postgres", "user=postgres password=postgres dbname=testdb host=pingedchat-postgrestest.ctnuy46b8e6b.us-east-1.rds.amazonaws.com")
-		if err != nil {
-			ERROR.Println("error opening postgres: ", err)
-			return false
-		}
+	if db.Emails == nil {
+		ERROR.Println("db.Emails == nil in AddEmailToDb") // can't do anything with no database connection :(
+		return false
 	}
 	if Attachments == "null" {
 		// no attachments will send attachments as "null", we want an empty string though
 		Attachments = ""
 	}
-	_, inserterr := db.postgres_conn.Exec(CurString, From, ToEmails, RecvEmail, Subject, Content, Attachments, Starred, Unread, Spam, false, false, SentTime, ModifiedTime) // unread by default, not in drafts or trash by default
+	email := EmailRowStruct{
+		FromEmail:   From,
+		ToEmails:    ToEmails,
+		RecvEmail:   RecvEmail,
+		Subject:     Subject,
+		Content:     Content,
+		Attachments: Attachments,
+		Starred:     Starred,
+		Unread:      Unread,
+		Spam:        Spam,
+		Draft:       false, // not in drafts or trash by default
+		Deleted:     false,
+		RecvTime:    parseTimeString(SentTime),
+		M_time:      parseTimeString(ModifiedTime),
+	}
+	inserterr := db.Emails.AddEmail(Username, email)
 	if inserterr != nil {
 		ERROR.Println("error:", inserterr)
 		return false
 	}
+	db.sendEmailChange(Username, EmailChangedEvent{FromEmail: From, Subject: Subject, RecvTime: SentTime, M_time: ModifiedTime})
 	return true
 }
 
-func (db *Database) SendEmailMessage(data string) string {
-	jsondata := SendEmailCmdStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error in SendEmailMessage Unmarshalling into MessageStruct:", err)
-		return ""
+func (db *Database) SendEmailMessage(jsondata *SendEmailCmdStruct) (*SentEmailStruct, error) {
+	// only send from our own domain, and only from the caller's address
+	if strings.Index(jsondata.FromEmail, "@"+conf.Mail.Domain) < 0 {
+		return nil, cmdError(ERR_BAD_REQUEST, "FromEmail has to be a %s address", conf.Mail.Domain)
+	}
+	username := jsondata.FromEmail[:strings.Index(jsondata.FromEmail, "@"+conf.Mail.Domain)]
+	if strings.ToUpper(username) != strings.ToUpper(db.Username()) {
+		return nil, ErrNotYou
 	}
 	// send email
-	mandrill.Key = MANDRILL_API_KEY
+	mandrill.Key = conf.Mail.MandrillAPIKey
 	// you can test your API key with Ping
-	err = mandrill.Ping()
+	err := mandrill.Ping()
 	// everything is OK if err is nil
 	if err != nil {
 		ERROR.Println("error in SendEmailMessage mandrill.Ping()")
 		ERROR.Println(err)
 	}
-	username := jsondata.FromEmail[:strings.Index(jsondata.FromEmail, "@pinged.email")]
-	user := db.GetAerospikeUser(username)
+	user := db.Users.GetUser(username)
 	msg := mandrill.NewMessage()
 	for _, rec := range jsondata.ToEmails {
 		msg.AddRecipient(rec, "") // params are email, name
@@ -2872,18 +1729,14 @@ func (db *Database) SendEmailMessage(data string) string {
 	if err != nil {
 		ERROR.Println("error in SendEmailMessage msg.Send(false)")
 		ERROR.Println(err)
-		if res[0].Status != "sent" {
+		if len(res) > 0 && res[0].Status != "sent" {
 			ERROR.Println("res.Status in SendEmailMessage = " + res[0].Status)
 			ERROR.Println("res.RejectionReason in SendEmailMessage = " + res[0].RejectionReason)
+			return nil, cmdError(ERR_SEND_FAILED, "the email was %s", res[0].Status)
 		}
-		return `{"cmd":"SendEmailMessage","Status":"` + res[0].Status + `"}`
+		return nil, cmdError(ERR_UNAVAILABLE, "the email couldn't be sent, try again")
 	}
 	// add email to database
-	if jsondata.FromEmail == "" {
-		return `{"cmd":"SendEmailMessage","Status":"FromEmail was invalid"}`
-	} else if strings.Index(jsondata.FromEmail, "@pinged.email") < 0 {
-		return `{"cmd":"SendEmailMessage","Status":"FromEmail was invalid"}`
-	}
 	ToEmailBytes, err := json.Marshal(jsondata.ToEmails)
 	ToEmailStr := string(ToEmailBytes)
 	if err != nil {
@@ -2898,185 +1751,135 @@ func (db *Database) SendEmailMessage(data string) string {
 	AttachmentsStr := string(AttachmentsBytes)
 	db.AddEmailToDb(username, jsondata.FromEmail, ToEmailStr, "", jsondata.Subject, jsondata.Content, AttachmentsStr, false, false, false, t_s, t_s)
 	// update EmailMtime
-	EmailMTimeBin := aerospike.NewBin("EmailMtime", t_s)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(username))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, EmailMTimeBin)
-	}
-	// format return string
-	msgStr := `{"FromEmail":"` + jsondata.FromEmail + `",` +
-		`"ToEmails":` + ToEmailStr + `,` +
-		`"Subject":"` + jsondata.Subject + `",` +
-		`"Content":"` + html.EscapeString(jsondata.Content) + `",` +
-		`"Attachments":` + AttachmentsStr + `,` +
-		`"RecvTime":"` + t_s + `",` +
-		`"M_time":"` + t_s + `"` +
-		`}`
-	return `{"cmd":"SendEmailMessage","Status":"Sent","Msg":` + msgStr + `}`
-}
-
-func (db *Database) MarkEmailUnread(data string) string {
-	jsondata := UpdateEmailCmdStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error in MarkEmailUnread Unmarshalling into MessageStruct:", err)
-		return ""
+	db.Users.UpdateUserFields(username, UserFields{"EmailMtime": t_s})
+	sent := &SentEmailStruct{
+		FromEmail:   jsondata.FromEmail,
+		ToEmails:    jsondata.ToEmails,
+		Subject:     jsondata.Subject,
+		Content:     html.EscapeString(jsondata.Content),
+		Attachments: mail_attachments,
+		RecvTime:    t_s,
+		M_time:      t_s,
 	}
+	return sent, nil
+}
+
+func (db *Database) MarkEmailUnread(jsondata *UpdateEmailCmdStruct) error {
 	if jsondata.EmailMtime == "" {
 		t := time.Now().UTC()
 		jsondata.EmailMtime = t.Format(ISO8601_SECONDS) // time string
 	}
-	PostgresTableName := `"` + jsondata.Username + `@pinged.email"`
-	CurString := "UPDATE " + PostgresTableName + " SET unread = $1 , m_time = $2 WHERE from_email = $3 AND subject = $4 AND recv_time = $5"
-	// TRACE.Println("cur_string = " + CurString)
-	if db.postgres_conn == nil {
-		ERROR.Println("db.postgres_conn == nil in MarkEmailUnread") // can't do anything with no database connection :(
+	if db.Emails == nil {
+		ERROR.Println("db.Emails == nil in MarkEmailUnread") // can't do anything with no database connection :(
+		return ErrEmailsDown
 	}
-	_, inserterr := db.postgres_conn.Exec(CurString, jsondata.Unread, jsondata.EmailMtime, jsondata.FromEmail, jsondata.Subject, jsondata.RecvTime)
+	key := EmailKey{FromEmail: jsondata.FromEmail, Subject: jsondata.Subject, RecvTime: jsondata.RecvTime}
+	inserterr := db.Emails.SetEmailFlag(jsondata.Username, key, EMAIL_FLAG_UNREAD, jsondata.Unread, jsondata.EmailMtime)
 	if inserterr != nil {
-		ERROR.Println("error adding to Postgres table in MarkEmailUnread: ", inserterr)
-		return `{"cmd":"MarkEmailUnread","Success":false}`
+		ERROR.Println("error updating email in MarkEmailUnread: ", inserterr)
+		return ErrEmailsDown
 	}
 	// update aerospike user struct EmailMtime
-	EmailMTimeBin := aerospike.NewBin("EmailMtime", jsondata.EmailMtime)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(jsondata.Username))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, EmailMTimeBin)
-	} else {
-		return `{"cmd":"MarkEmailUnread","Success":false}`
+	if !db.Users.UpdateUserFields(jsondata.Username, UserFields{"EmailMtime": jsondata.EmailMtime}) {
+		return ErrNotSaved
 	}
-	return `{"cmd":"MarkEmailUnread","Success":true}`
+	db.sendEmailChange(jsondata.Username, EmailChangedEvent{FromEmail: jsondata.FromEmail, Subject: jsondata.Subject, RecvTime: jsondata.RecvTime, Flag: EMAIL_FLAG_UNREAD, Value: jsondata.Unread, M_time: jsondata.EmailMtime})
+	return nil
 }
 
-func (db *Database) MarkEmailStarred(data string) string {
-	jsondata := UpdateEmailCmdStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error in MarkEmailStarred Unmarshalling into MessageStruct:", err)
-		return ""
-	}
+func (db *Database) MarkEmailStarred(jsondata *UpdateEmailCmdStruct) error {
 	if jsondata.EmailMtime == "" {
 		t := time.Now().UTC()
 		jsondata.EmailMtime = t.Format(ISO8601_SECONDS) // time string
 	}
-	PostgresTableName := `"` + jsondata.Username + `@pinged.email"`
-	CurString := "UPDATE " + PostgresTableName + " SET starred = $1 , m_time = $2 WHERE from_email = $3 AND subject = $4 AND recv_time = $5"
-	// TRACE.Println("cur_string = " + CurString)
-	if db.postgres_conn == nil {
-		ERROR.Println("db.postgres_conn == nil in MarkEmailStarred") // can't do anything with no database connection :(
+	if db.Emails == nil {
+		ERROR.Println("db.Emails == nil in MarkEmailStarred") // can't do anything with no database connection :(
+		return ErrEmailsDown
 	}
-	_, inserterr := db.postgres_conn.Exec(CurString, jsondata.Starred, jsondata.EmailMtime, jsondata.FromEmail, jsondata.Subject, jsondata.RecvTime)
+	key := EmailKey{FromEmail: jsondata.FromEmail, Subject: jsondata.Subject, RecvTime: jsondata.RecvTime}
+	inserterr := db.Emails.SetEmailFlag(jsondata.Username, key, EMAIL_FLAG_STARRED, jsondata.Starred, jsondata.EmailMtime)
 	if inserterr != nil {
-		ERROR.Println("error adding to Postgres table in MarkEmailStarred: ", inserterr)
-		return `{"cmd":"MarkEmailStarred","Success":false}`
+		ERROR.Println("error updating email in MarkEmailStarred: ", inserterr)
+		return ErrEmailsDown
 	}
 	// update aerospike user struct EmailMtime
-	EmailMTimeBin := aerospike.NewBin("EmailMtime", jsondata.EmailMtime)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(jsondata.Username))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, EmailMTimeBin)
-	} else {
-		return `{"cmd":"MarkEmailStarred","Success":false}`
+	if !db.Users.UpdateUserFields(jsondata.Username, UserFields{"EmailMtime": jsondata.EmailMtime}) {
+		return ErrNotSaved
 	}
-	return `{"cmd":"MarkEmailStarred","Success":true}`
+	db.sendEmailChange(jsondata.Username, EmailChangedEvent{FromEmail: jsondata.FromEmail, Subject: jsondata.Subject, RecvTime: jsondata.RecvTime, Flag: EMAIL_FLAG_STARRED, Value: jsondata.Starred, M_time: jsondata.EmailMtime})
+	return nil
 }
 
 // we'll use recv_time as the key for drafts
 // each draft has it's own recv_time, which is when it's created.  we'll update m_time when it updates.
-func (db *Database) AddNewDraft(data string) string {
-	jsondata := UpdateEmailCmdStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error in MarkEmailDeleted Unmarshalling into MessageStruct:", err)
-		return ""
-	}
+func (db *Database) AddNewDraft(jsondata *UpdateEmailCmdStruct) error {
 	if jsondata.EmailMtime == "" {
 		t := time.Now().UTC()
 		jsondata.EmailMtime = t.Format(ISO8601_SECONDS) // time string
 	}
-	PostgresTableName := `"` + jsondata.Username + `@pinged.email"`
-	// first remove draft that may be there
-	CurString := "DELETE FROM " + PostgresTableName + " WHERE draft = true AND recv_time = $1"
-	if db.postgres_conn == nil {
-		ERROR.Println("db.postgres_conn == nil in AddNewDraft") // can't do anything with no database connection :(
-	}
-	_, deleteerr := db.postgres_conn.Exec(CurString, jsondata.RecvTime)
-	if deleteerr != nil {
-		ERROR.Println("error deleting from Postgres table in AddNewDraft: ", deleteerr)
+	if db.Emails == nil {
+		ERROR.Println("db.Emails == nil in AddNewDraft") // can't do anything with no database connection :(
+		return ErrEmailsDown
 	}
 	// create ToEmails string
-	ToEmailBytes, err := json.Marshal(jsondata.ToEmails)
+	ToEmailBytes, _ := json.Marshal(jsondata.ToEmails)
 	ToEmailStr := string(ToEmailBytes)
-	// now re-insert
-	CurString = "INSERT INTO " + PostgresTableName + " (from_email, to_emails, recv_email, subject, content, attachments, starred, unread, spam, draft, deleted, recv_time, m_time) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)"
-	// TRACE.Println("cur_string = " + CurString)
 	// TODO: attachments.  Save?  Delete?
-	_, inserterr := db.postgres_conn.Exec(CurString, jsondata.FromEmail, ToEmailStr, "", jsondata.Subject, jsondata.Content, "", false, false, false, true, false, jsondata.RecvTime, jsondata.EmailMtime)
+	draft := EmailRowStruct{
+		FromEmail: jsondata.FromEmail,
+		ToEmails:  ToEmailStr,
+		Subject:   jsondata.Subject,
+		Content:   jsondata.Content,
+		RecvTime:  parseTimeString(jsondata.RecvTime),
+		M_time:    parseTimeString(jsondata.EmailMtime),
+	}
+	// replaces the draft that may be there
+	inserterr := db.Emails.SaveDraft(jsondata.Username, draft)
 	if inserterr != nil {
-		ERROR.Println("error adding to Postgres table in AddNewDraft: ", inserterr)
-		return `{"cmd":"AddNewDraft","Success":false}`
+		ERROR.Println("error saving draft in AddNewDraft: ", inserterr)
+		return ErrEmailsDown
 	}
 	// update aerospike user struct EmailMtime
-	EmailMTimeBin := aerospike.NewBin("EmailMtime", jsondata.EmailMtime)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(jsondata.Username))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, EmailMTimeBin)
-	} else {
-		return `{"cmd":"AddNewDraft","Success":false}`
+	if !db.Users.UpdateUserFields(jsondata.Username, UserFields{"EmailMtime": jsondata.EmailMtime}) {
+		return ErrNotSaved
 	}
-	return `{"cmd":"AddNewDraft","Success":true}`
+	db.sendEmailChange(jsondata.Username, EmailChangedEvent{FromEmail: jsondata.FromEmail, Subject: jsondata.Subject, RecvTime: jsondata.RecvTime, M_time: jsondata.EmailMtime})
+	return nil
 }
 
-func (db *Database) MarkEmailDeleted(data string) string {
-	jsondata := UpdateEmailCmdStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error in MarkEmailDeleted Unmarshalling into MessageStruct:", err)
-		return ""
-	}
+func (db *Database) MarkEmailDeleted(jsondata *UpdateEmailCmdStruct) error {
 	if jsondata.EmailMtime == "" {
 		t := time.Now().UTC()
 		jsondata.EmailMtime = t.Format(ISO8601_SECONDS) // time string
 	}
-	PostgresTableName := `"` + jsondata.Username + `@pinged.email"`
-	CurString := "UPDATE " + PostgresTableName + " SET deleted = $1 , m_time = $2 WHERE from_email = $3 AND subject = $4 AND recv_time = $5"
-	// TRACE.Println("cur_string = " + CurString)
-	if db.postgres_conn == nil {
-		ERROR.Println("db.postgres_conn == nil in MarkEmailDeleted") // can't do anything with no database connection :(
+	if db.Emails == nil {
+		ERROR.Println("db.Emails == nil in MarkEmailDeleted") // can't do anything with no database connection :(
+		return ErrEmailsDown
 	}
-	_, inserterr := db.postgres_conn.Exec(CurString, jsondata.Deleted, jsondata.EmailMtime, jsondata.FromEmail, jsondata.Subject, jsondata.RecvTime)
+	key := EmailKey{FromEmail: jsondata.FromEmail, Subject: jsondata.Subject, RecvTime: jsondata.RecvTime}
+	inserterr := db.Emails.SetEmailFlag(jsondata.Username, key, EMAIL_FLAG_DELETED, jsondata.Deleted, jsondata.EmailMtime)
 	if inserterr != nil {
-		ERROR.Println("error adding to Postgres table in MarkEmailDeleted: ", inserterr)
-		return `{"cmd":"MarkEmailDeleted","Success":false}`
+		ERROR.Println("error updating email in MarkEmailDeleted: ", inserterr)
+		return ErrEmailsDown
 	}
 	// update aerospike user struct EmailMtime
-	EmailMTimeBin := aerospike.NewBin("EmailMtime", jsondata.EmailMtime)
-	key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(jsondata.Username))
-	if err == nil {
-		db.UpdateAerospikeSingleBin(key, EmailMTimeBin)
-	} else {
-		return `{"cmd":"MarkEmailDeleted","Success":false}`
+	if !db.Users.UpdateUserFields(jsondata.Username, UserFields{"EmailMtime": jsondata.EmailMtime}) {
+		return ErrNotSaved
 	}
-	return `{"cmd":"MarkEmailDeleted","Success":true}`
+	db.sendEmailChange(jsondata.Username, EmailChangedEvent{FromEmail: jsondata.FromEmail, Subject: jsondata.Subject, RecvTime: jsondata.RecvTime, Flag: EMAIL_FLAG_DELETED, Value: jsondata.Deleted, M_time: jsondata.EmailMtime})
+	return nil
 }
 
-func (db *Database) RemoveDeletedEmails(data string) string {
-	jsondata := UpdateEmailCmdStruct{}
-	err := json.Unmarshal([]byte(data), &jsondata)
-	if err != nil {
-		ERROR.Println("error in RemoveDeletedEmails Unmarshalling into MessageStruct:", err)
-		return ""
-	}
-	PostgresTableName := `"` + jsondata.Username + `@pinged.email"`
-	CurString := "DELETE FROM " + PostgresTableName + " WHERE deleted = true"
-	// TRACE.Println("cur_string = " + CurString)
-	if db.postgres_conn == nil {
-		ERROR.Println("db.postgres_conn == nil in RemoveDeletedEmails") // can't do anything with no database connection :(
+func (db *Database) RemoveDeletedEmails(jsondata *UpdateEmailCmdStruct) error {
+	if db.Emails == nil {
+		ERROR.Println("db.Emails == nil in RemoveDeletedEmails") // can't do anything with no database connection :(
+		return ErrEmailsDown
 	}
-	_, deleteerr := db.postgres_conn.Exec(CurString)
+	deleteerr := db.Emails.RemoveDeletedEmails(jsondata.Username)
 	if deleteerr != nil {
-		ERROR.Println("error deleting from Postgres table in RemoveDeletedEmails: ", deleteerr)
-		return `{"cmd":"RemoveDeletedEmails","Success":false}`
+		ERROR.Println("error removing deleted emails in RemoveDeletedEmails: ", deleteerr)
+		return ErrEmailsDown
 	}
-	return `{"cmd":"RemoveDeletedEmails","Success":true}`
+	db.sendEmailChange(jsondata.Username, EmailChangedEvent{Cmd: "RemoveDeletedEmails"})
+	return nil
 }
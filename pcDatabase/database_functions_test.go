@@ -0,0 +1,204 @@
+package pcDatabase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestDatabase(t *testing.T) *Database {
+	db := &Database{}
+	db.Connect(context.Background(), NewBackend(NewMemoryStore().Stores()), nil)
+	if !db.IsConnected() {
+		t.Fatal("database should be connected to memory stores")
+	}
+	return db
+}
+
+func createTestUser(t *testing.T, db *Database, username string) *UserStruct {
+	user, err := db.CreateUser(&CreateUserCmdStruct{
+		Cmd:      "CreateUser",
+		Username: username,
+		Password: "secret",
+		Email:    username + "@example.com",
+		Token:    username + "-web",
+	})
+	if err != nil || user.UsernameUpper != strings.ToUpper(username) {
+		t.Fatalf("CreateUser(%s) returned %+v, %v", username, user, err)
+	}
+	return user
+}
+
+// reads n messages published to the session's web devices
+func expectEvents(t *testing.T, db *Database, n int) []string {
+	events := make([]string, 0, n)
+	for len(events) < n {
+		select {
+		case msg := <-db.nats_receive:
+			events = append(events, msg)
+		case <-time.After(time.Second):
+			t.Fatalf("got %d events, expected %d: %v", len(events), n, events)
+		}
+	}
+	return events
+}
+
+func createTestConversation(t *testing.T, db *Database, members ...string) string {
+	err := db.CreateConversation(&CIDCommandStruct{
+		Cmd:     "CreateConversation",
+		Name:    "test convo",
+		M_time:  "2015-06-12T19:16:29.119Z",
+		Members: members,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, db, len(members))
+	user := db.Users.GetUser(members[0])
+	CIDs := user.GetCIDStructs()
+	if len(CIDs) != 1 {
+		t.Fatalf("%s has %d conversations, expected 1", members[0], len(CIDs))
+	}
+	return CIDs[0].CID
+}
+
+func TestCreateConversation(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "alice")
+	createTestUser(t, db, "bob")
+
+	CID := createTestConversation(t, db, "alice", "bob")
+	bob := db.Users.GetUser("bob")
+	bobCIDs := bob.GetCIDStructs()
+	if len(bobCIDs) != 1 || bobCIDs[0].CID != CID {
+		t.Fatalf("bob's conversations are %+v, expected %s", bobCIDs, CID)
+	}
+	if name := db.Convos.GetConvoName(CID); name != "test convo" {
+		t.Errorf("conversation name is %q", name)
+	}
+	if members := db.Convos.GetConvoMembers(CID); len(members) != 2 {
+		t.Errorf("conversation has members %v", members)
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "alice")
+	createTestUser(t, db, "bob")
+	CID := createTestConversation(t, db, "alice", "bob")
+
+	db.SendMessage(&MessageStruct{
+		Cmd:          "SendMessage",
+		CID:          CID,
+		FromUsername: "alice",
+		ToUIDs:       []string{"alice", "bob"},
+		M_time:       "2015-06-12T19:20:00.000Z",
+		Content:      "hi bob",
+	})
+	for _, event := range expectEvents(t, db, 2) {
+		if !strings.Contains(event, "hi bob") {
+			t.Errorf("unexpected event %s", event)
+		}
+	}
+
+	convoData, err := db.GetConvoData(&CIDCommandStruct{Cmd: "GetConvoData", CID: CID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(convoData.Messages) != 1 || convoData.Messages[0].Content != "hi bob" || convoData.Messages[0].F_username != "alice" {
+		t.Fatalf("GetConvoData returned messages %+v", convoData.Messages)
+	}
+	if convoData.Messages[0].MID == "" {
+		t.Error("message was stored without a MID")
+	}
+	if convoData.M_time != "2015-06-12T19:20:00.000Z" {
+		t.Errorf("conversation m_time is %s", convoData.M_time)
+	}
+
+	// only messages after M_time
+	convoData, err = db.GetConvoData(&CIDCommandStruct{Cmd: "GetConvoData", CID: CID, M_time: "2015-06-12T19:20:00.000Z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(convoData.Messages) != 0 {
+		t.Errorf("GetConvoData since the last message returned %+v", convoData.Messages)
+	}
+
+	bob := db.Users.GetUser("bob")
+	bobCIDs := bob.GetCIDStructs()
+	if bobCIDs[0].UnreadCount != 1 {
+		t.Errorf("bob's unread count is %d, expected 1", bobCIDs[0].UnreadCount)
+	}
+}
+
+// a message store that can't save anything
+type unsavedMessages struct {
+	MessageStore
+}
+
+func (unsavedMessages) AddMessage(data MessageStruct) error {
+	return errors.New("messages are down")
+}
+
+func TestSendMessageNotSaved(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "bob")
+	createTestUser(t, db, "alice")
+	CID := createTestConversation(t, db, "alice", "bob")
+	db.Messages = unsavedMessages{db.Messages}
+
+	expectError(t, db, `{"cmd":"SendMessage","CID":"`+CID+`","t_UIDs":["alice","bob"],"m_time":"2015-06-12T19:20:00.000Z","content":"hi bob"}`, ERR_UNAVAILABLE)
+	select {
+	case event := <-db.nats_receive:
+		t.Errorf("the unsaved message was sent out: %s", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+	bob := db.Users.GetUser("bob")
+	if bobCIDs := bob.GetCIDStructs(); bobCIDs[0].UnreadCount != 0 {
+		t.Errorf("bob's unread count is %d for a message that wasn't saved", bobCIDs[0].UnreadCount)
+	}
+}
+
+func TestAddFriend(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "alice")
+	createTestUser(t, db, "bob")
+
+	if err := db.AddFriend(&FriendCmdStruct{Cmd: "AddFriend", UID: "alice", FriendUID: "bob", Message: "hey"}); err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, db, 2)
+	if err := db.AddFriend(&FriendCmdStruct{Cmd: "AddFriend", UID: "alice", FriendUID: "nobody"}); err != ErrUserNotFound {
+		t.Errorf("AddFriend for a user that doesn't exist returned %v", err)
+	}
+
+	alice := db.Users.GetUser("alice")
+	outgoing := alice.GetOutgoingPendingFriendStructs()
+	if len(outgoing) != 1 || outgoing[0].Username != "bob" {
+		t.Errorf("alice's outgoing friend requests are %+v", outgoing)
+	}
+	bob := db.Users.GetUser("bob")
+	incoming := bob.GetIncomingPendingFriendStructs()
+	if len(incoming) != 1 || incoming[0].Username != "alice" || incoming[0].Message != "hey" {
+		t.Errorf("bob's incoming friend requests are %+v", incoming)
+	}
+}
+
+func TestChangeDevice(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "alice")
+
+	db.AddIosDev(&ChangeDeviceStruct{Cmd: "AddIosDev", Username: "alice", Device: "old-phone"})
+	db.ChangeIosDev(&ChangeDeviceStruct{Cmd: "ChangeIosDev", Username: "alice", Device: "new-phone", OldDevice: "old-phone"})
+	expectEvents(t, db, 2)
+	if alice := db.Users.GetUser("alice"); strings.Join(alice.Ios, ",") != "new-phone" {
+		t.Errorf("alice's iOS devices are %v", alice.Ios)
+	}
+}
@@ -0,0 +1,137 @@
+package pcDatabase
+
+import (
+	"context"
+	"gopkg.in/igm/sockjs-go.v2/sockjs"
+	"sync"
+	"testing"
+	"time"
+)
+
+// a sockjs session that records what's sent, Send blocks while stalled
+type fakeSocket struct {
+	mu     sync.Mutex
+	sent   []string
+	status uint32
+	stall  chan struct{}
+	closed chan struct{}
+}
+
+func newFakeSocket() *fakeSocket {
+	return &fakeSocket{stall: make(chan struct{}), closed: make(chan struct{})}
+}
+
+func (s *fakeSocket) ID() string            { return "fake" }
+func (s *fakeSocket) Recv() (string, error) { <-s.closed; return "", context.Canceled }
+func (s *fakeSocket) Send(msg string) error {
+	select {
+	case <-s.stall:
+	case <-s.closed:
+		return context.Canceled
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, msg)
+	return nil
+}
+func (s *fakeSocket) Close(status uint32, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status == 0 {
+		s.status = status
+		close(s.closed)
+	}
+	return nil
+}
+
+func (s *fakeSocket) messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.sent...)
+}
+
+// alice logged in on a fake socket with Run going, the returned channel is
+// closed when Run returns
+func newRunningDatabase(t *testing.T, ctx context.Context, socket *fakeSocket) (*Database, chan struct{}) {
+	db := &Database{}
+	var session sockjs.Session = socket
+	db.Connect(ctx, NewBackend(NewMemoryStore().Stores()), &session)
+	createTestUser(t, db, "alice")
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		db.Run()
+	}()
+	return db, stopped
+}
+
+func expectStopped(t *testing.T, stopped chan struct{}) {
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Run didn't stop")
+	}
+}
+
+func TestRunAnswersCommandsAndForwardsEvents(t *testing.T) {
+	socket := newFakeSocket()
+	close(socket.stall)
+	ctx, cancel := context.WithCancel(context.Background())
+	db, stopped := newRunningDatabase(t, ctx, socket)
+
+	db.Receive <- `{"cmd":"GetSessions","reqId":"1"}`
+	db.Events.Publish("alice-web", `{"cmd":"UpdateUser"}`)
+	deadline := time.Now().Add(time.Second)
+	for len(socket.messages()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if sent := socket.messages(); len(sent) != 2 {
+		t.Errorf("sent %v", sent)
+	}
+
+	cancel()
+	expectStopped(t, stopped)
+	db.Close()
+}
+
+func TestRunDisconnectsSlowClients(t *testing.T) {
+	socket := newFakeSocket() // never sends
+	db, stopped := newRunningDatabase(t, context.Background(), socket)
+
+	for i := 0; i < OUTBOUND_QUEUE_SIZE+2; i++ {
+		select {
+		case db.nats_receive <- `{"cmd":"UpdateUser"}`:
+		case <-db.Context().Done():
+		}
+	}
+	expectStopped(t, stopped)
+	if socket.status != SLOW_CLIENT_STATUS {
+		t.Errorf("socket was closed with %d", socket.status)
+	}
+	db.Close()
+}
+
+func TestRunClosesRevokedSessions(t *testing.T) {
+	socket := newFakeSocket()
+	close(socket.stall)
+	db, stopped := newRunningDatabase(t, context.Background(), socket)
+	session := db.Session()
+
+	other := newTestConnection(db)
+	defer other.Close()
+	dispatch(t, other, `{"cmd":"ValidateUser","username":"alice","password":"secret","token":"alice-phone"}`, nil)
+	if env := dispatch(t, other, `{"cmd":"RevokeSession","ID":"`+session+`"}`, nil); !env.OK {
+		t.Fatalf("RevokeSession returned %+v", env)
+	}
+	expectStopped(t, stopped)
+	if socket.status != SESSION_REVOKED_STATUS {
+		t.Errorf("socket was closed with %d", socket.status)
+	}
+	// the client hears why before the socket closes
+	if sent := socket.messages(); len(sent) != 1 {
+		t.Errorf("sent %v", sent)
+	} else if ID, ok := revokedSession(sent[0]); !ok || ID != session {
+		t.Errorf("sent %s", sent[0])
+	}
+	db.Close()
+}
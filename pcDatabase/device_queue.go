@@ -0,0 +1,138 @@
+package pcDatabase
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DEVICE QUEUES
+// nats forgets an event as soon as it's published, so a browser tab that's
+// reconnecting used to miss whatever went out in the meantime.  Now every
+// event sent to a user (see sendToUser) is also queued for each of their web
+// devices, the ones online and the ones with a session that hasn't expired,
+// and the live event carries the device's eventSeq.  A tab that comes back
+// sends ResumeEvents with the last eventSeq it has and gets the rest in
+// order, and AckEvents as it goes so the queue can be trimmed.  Anything
+// older than DEVICE_QUEUE_RETENTION is dropped anyway, when some of what the
+// tab is missing is gone the answer is Gap and it catches up with
+// GetChangesSince instead.  The queues are per user and device, someone
+// else logging in with the same web token only gets their own.
+//
+// typing and presence don't go through the queues, they're only worth
+// anything live
+
+const (
+	// how long a device's events are kept when it doesn't ack them
+	DEVICE_QUEUE_RETENTION = 24 * time.Hour
+	// events kept per device
+	DEVICE_QUEUE_SIZE = 1000
+	// events per ResumeEvents
+	DEVICE_EVENTS_PAGE_SIZE = 200
+)
+
+var ErrQueuesDown = cmdError(ERR_UNAVAILABLE, "queued events can't be reached right now, try again")
+
+// withEventSeq adds "eventSeq" as the last field of a JSON object event,
+// anything else is left as it is.  It goes last so the events Run looks for
+// (SessionRevoked) still start the same
+func withEventSeq(event string, seq int64) string {
+	trimmed := strings.TrimSpace(event)
+	if !strings.HasPrefix(trimmed, "{") || !strings.HasSuffix(trimmed, "}") {
+		return event
+	}
+	body := strings.TrimSpace(trimmed[:len(trimmed)-1])
+	field := `"eventSeq":` + strconv.FormatInt(seq, 10) + `}`
+	if body == "{" {
+		return body + field
+	}
+	return body + "," + field
+}
+
+// the web devices events for user are queued for, online or not
+func queueDevices(user UserStruct, now time.Time) []string {
+	devices := append([]string(nil), user.Web...)
+	for _, session := range user.GetSessionStructs() {
+		if !sessionExpired(session, now) && session.Device != "" && !containsString(devices, session.Device) {
+			devices = append(devices, session.Device)
+		}
+	}
+	return devices
+}
+
+// sendToUser queues event for each of user's web devices and publishes it
+// to the ones that are online
+func (db *Database) sendToUser(user UserStruct, event string) {
+	if db.Queues == nil {
+		db.SendStringToWebDevices(user.Web, event)
+		return
+	}
+	for _, device := range queueDevices(user, time.Now()) {
+		seq, err := db.Queues.EnqueueDeviceEvent(user.Username, device, event)
+		if err != nil {
+			ERROR.Println("error in EnqueueDeviceEvent for "+user.Username+":", err)
+		}
+		if !containsString(user.Web, device) {
+			continue
+		} else if err != nil {
+			db.SendStringToWebDevices([]string{device}, event)
+		} else {
+			db.SendStringToWebDevices([]string{device}, withEventSeq(event, seq))
+		}
+	}
+}
+
+// ResumeEvents is what was queued for this session's user on its device
+// after Seq, a page at a time.  Everything up to Seq is acked
+func (db *Database) ResumeEvents(jsondata *DeviceEventsCmdStruct) (*DeviceEventsResponse, error) {
+	if db.Queues == nil {
+		return nil, ErrQueuesDown
+	}
+	device := db.Token()
+	last, err := db.Queues.LastDeviceEvent(db.Username(), device)
+	if err != nil {
+		ERROR.Println("error in LastDeviceEvent:", err)
+		return nil, ErrQueuesDown
+	}
+	resp := &DeviceEventsResponse{Events: make([]json.RawMessage, 0), Seq: last}
+	if jsondata.Seq > last {
+		resp.Gap = true // not one of this device's
+		return resp, nil
+	}
+	if err := db.Queues.AckDeviceEvents(db.Username(), device, jsondata.Seq); err != nil {
+		ERROR.Println("error in AckDeviceEvents:", err)
+	}
+	events, err := db.Queues.GetDeviceEvents(db.Username(), device, jsondata.Seq, DEVICE_EVENTS_PAGE_SIZE+1)
+	if err != nil {
+		ERROR.Println("error in GetDeviceEvents:", err)
+		return nil, ErrQueuesDown
+	}
+	// the one after Seq is gone, it was too old
+	if jsondata.Seq < last && (len(events) == 0 || events[0].Seq != jsondata.Seq+1) {
+		resp.Gap = true
+		return resp, nil
+	}
+	if len(events) > DEVICE_EVENTS_PAGE_SIZE {
+		events = events[:DEVICE_EVENTS_PAGE_SIZE]
+		resp.More = true
+	}
+	resp.Seq = jsondata.Seq
+	for _, e := range events {
+		resp.Events = append(resp.Events, json.RawMessage(withEventSeq(e.Event, e.Seq)))
+		resp.Seq = e.Seq
+	}
+	return resp, nil
+}
+
+// AckEvents says this session's device has everything up to Seq
+func (db *Database) AckEvents(jsondata *DeviceEventsCmdStruct) error {
+	if db.Queues == nil {
+		return ErrQueuesDown
+	}
+	if err := db.Queues.AckDeviceEvents(db.Username(), db.Token(), jsondata.Seq); err != nil {
+		ERROR.Println("error in AckDeviceEvents:", err)
+		return ErrQueuesDown
+	}
+	return nil
+}
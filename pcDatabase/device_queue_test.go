@@ -0,0 +1,81 @@
+package pcDatabase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithEventSeq(t *testing.T) {
+	for event, want := range map[string]string{
+		`{"cmd":"SessionRevoked","ID":"x"}`: `{"cmd":"SessionRevoked","ID":"x","eventSeq":7}`,
+		`{}`:                                `{"eventSeq":7}`,
+		`[1]`:                               `[1]`,
+	} {
+		if got := withEventSeq(event, 7); got != want {
+			t.Errorf("withEventSeq(%s) = %s, expected %s", event, got, want)
+		}
+	}
+}
+
+func TestResumeEvents(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "bob")
+	createTestUser(t, db, "alice")
+	CID := createTestConversation(t, db, "alice", "bob")
+
+	// alice's tab drops, the conversation is renamed while it's away
+	db.RemoveUserWebToken("alice", "alice-web")
+	db.ChangeConvoName(&CIDCommandStruct{CID: CID, Name: "renamed"})
+	if event := expectEvents(t, db, 1)[0]; !strings.Contains(event, `"eventSeq":2`) {
+		t.Errorf("bob got %s, expected eventSeq 2", event)
+	}
+
+	// and it comes back with the conversation, the first event it had
+	var resp DeviceEventsResponse
+	if env := dispatch(t, db, `{"cmd":"ResumeEvents","seq":1}`, &resp); !env.OK {
+		t.Fatalf("ResumeEvents returned %+v", env)
+	}
+	if resp.Gap || resp.More || len(resp.Events) != 1 || resp.Seq != 2 {
+		t.Fatalf("ResumeEvents got %+v", resp)
+	}
+	if event := string(resp.Events[0]); !strings.Contains(event, "update_convo_name") || !strings.Contains(event, `"eventSeq":2`) {
+		t.Errorf("unexpected event %s", event)
+	}
+	dispatch(t, db, `{"cmd":"AckEvents","seq":2}`, nil)
+	if left, _ := db.Queues.GetDeviceEvents("alice", "alice-web", 0, 10); len(left) != 0 {
+		t.Errorf("acked events are still queued: %+v", left)
+	}
+
+	// too much went out while it was away to keep it all
+	for i := 0; i < DEVICE_QUEUE_SIZE+1; i++ {
+		db.Queues.EnqueueDeviceEvent("alice", "alice-web", `{"cmd":"UpdateUser"}`)
+	}
+	if env := dispatch(t, db, `{"cmd":"ResumeEvents","seq":2}`, &resp); !env.OK || !resp.Gap || len(resp.Events) != 0 {
+		t.Errorf("with events gone ResumeEvents got %+v", resp)
+	}
+	if resp.Seq != DEVICE_QUEUE_SIZE+3 {
+		t.Errorf("seq %d after the gap, expected %d", resp.Seq, DEVICE_QUEUE_SIZE+3)
+	}
+}
+
+func TestDeviceQueuesPerUser(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "alice")
+
+	// bob used this browser before alice logged in on it
+	db.Queues.EnqueueDeviceEvent("bob", "alice-web", `{"cmd":"SendMessage","content":"for bob"}`)
+	var resp DeviceEventsResponse
+	if env := dispatch(t, db, `{"cmd":"ResumeEvents","seq":0}`, &resp); !env.OK {
+		t.Fatalf("ResumeEvents returned %+v", env)
+	}
+	for _, event := range resp.Events {
+		if strings.Contains(string(event), "for bob") {
+			t.Errorf("alice got bob's event %s", event)
+		}
+	}
+	if left, _ := db.Queues.GetDeviceEvents("bob", "alice-web", 0, 10); len(left) != 1 {
+		t.Errorf("bob's queue has %+v", left)
+	}
+}
@@ -0,0 +1,173 @@
+package pcDatabase
+
+import (
+	"encoding/base64"
+	"github.com/pquerna/ffjson/ffjson"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EMAIL SEARCH
+// GetAllEmails and SearchEmails both go through EmailStore.GetEmails a page
+// at a time, most recently modified first, and hand back a cursor for the
+// next page.  SearchEmails also filters on the flags, attachments and when
+// the email was received, and its query works like SearchMessages'.
+//
+// every mailbox has a GIN index on the words of its emails, made by
+// CreateMailbox (and a migration for older mailboxes), see emailSearchVector
+
+// emails per GetAllEmails or SearchEmails
+const EMAIL_PAGE_SIZE = 50
+
+// the last email of a page, (from_email, recv_time) is the mailbox's key
+type emailCursor struct {
+	M_time    string `json:"t"`
+	FromEmail string `json:"f"`
+	RecvTime  string `json:"r"`
+}
+
+func encodeEmailCursor(email EmailRowStruct) string {
+	cursor, _ := ffjson.Marshal(emailCursor{
+		M_time:    email.M_time.UTC().Format(time.RFC3339Nano),
+		FromEmail: email.FromEmail,
+		RecvTime:  email.RecvTime.UTC().Format(time.RFC3339Nano),
+	})
+	return base64.RawURLEncoding.EncodeToString(cursor)
+}
+
+func decodeEmailCursor(s string) (*emailCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrBadCursor
+	}
+	cursor := &emailCursor{}
+	if err := ffjson.Unmarshal(data, cursor); err != nil || parseTimeString(cursor.M_time).IsZero() || parseTimeString(cursor.RecvTime).IsZero() {
+		return nil, ErrBadCursor
+	}
+	return cursor, nil
+}
+
+// whether a comes before b in a page, newest m_time first
+func emailBefore(a EmailRowStruct, b EmailRowStruct) bool {
+	switch {
+	case !a.M_time.Equal(b.M_time):
+		return a.M_time.After(b.M_time)
+	case a.FromEmail != b.FromEmail:
+		return a.FromEmail > b.FromEmail
+	}
+	return a.RecvTime.After(b.RecvTime)
+}
+
+// the email the cursor was made from, as far as ordering goes
+func (cursor emailCursor) email() EmailRowStruct {
+	return EmailRowStruct{M_time: parseTimeString(cursor.M_time), FromEmail: cursor.FromEmail, RecvTime: parseTimeString(cursor.RecvTime)}
+}
+
+func emailHasAttachments(email EmailRowStruct) bool {
+	return email.Attachments != "" && email.Attachments != "[]" && email.Attachments != "null"
+}
+
+// whether email is one query asks for, apart from the cursor.  For stores
+// that look at every email
+func (query EmailQuery) matches(email EmailRowStruct) bool {
+	flags := []struct {
+		want *bool
+		has  bool
+	}{
+		{query.Starred, email.Starred},
+		{query.Unread, email.Unread},
+		{query.Spam, email.Spam},
+		{query.Draft, email.Draft},
+		{query.Deleted, email.Deleted},
+		{query.Attachments, emailHasAttachments(email)},
+	}
+	for _, flag := range flags {
+		if flag.want != nil && *flag.want != flag.has {
+			return false
+		}
+	}
+	switch {
+	case query.Since != "" && !email.M_time.After(parseTimeString(query.Since)):
+		return false
+	case query.After != "" && email.RecvTime.Before(parseTimeString(query.After)):
+		return false
+	case query.Before != "" && !email.RecvTime.Before(parseTimeString(query.Before)):
+		return false
+	}
+	if query.Search != "" {
+		text := strings.Join([]string{email.Subject, email.Content, email.FromEmail, email.ToEmails}, " ")
+		return parseSearchQuery(query.Search).rank(text) > 0
+	}
+	return true
+}
+
+// sorts emails and cuts them down to the page after query.Cursor
+func pageEmails(emails []EmailRowStruct, query EmailQuery) []EmailRowStruct {
+	sort.Slice(emails, func(i, j int) bool {
+		return emailBefore(emails[i], emails[j])
+	})
+	page := make([]EmailRowStruct, 0)
+	for _, email := range emails {
+		if query.Cursor != nil && !emailBefore(query.Cursor.email(), email) {
+			continue
+		}
+		if query.Limit > 0 && len(page) == query.Limit {
+			break
+		}
+		page = append(page, email)
+	}
+	return page
+}
+
+// a page of username's emails matching query, query.Cursor and Limit are set
+// from cursor here
+func (db *Database) emailPage(username string, query EmailQuery, cursor string) (*EmailDataStruct, error) {
+	storeduser := db.Users.GetUser(strings.ToUpper(username))
+	if storeduser.Username == "" {
+		return nil, ErrUserNotFound
+	}
+	if db.Emails == nil {
+		return nil, ErrEmailsDown // can't do anything with no database connection :(
+	}
+	if cursor != "" {
+		c, err := decodeEmailCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		query.Cursor = c
+	}
+	query.Limit = EMAIL_PAGE_SIZE + 1 // one more to know if there's another page
+	emails, err := db.Emails.GetEmails(storeduser.Username, query)
+	if err != nil {
+		logPqError("emailPage", err)
+		return nil, ErrEmailsDown
+	}
+	retCmd := EmailDataStruct{Emails: emails}
+	if len(emails) > EMAIL_PAGE_SIZE {
+		retCmd.Emails = emails[:EMAIL_PAGE_SIZE]
+		retCmd.Cursor = encodeEmailCursor(retCmd.Emails[EMAIL_PAGE_SIZE-1])
+	}
+	return &retCmd, nil
+}
+
+// SearchEmails is a page of the caller's emails matching the query and filters
+func (db *Database) SearchEmails(jsondata *SearchEmailsCmdStruct) (*EmailDataStruct, error) {
+	if jsondata.Query != "" && parseSearchQuery(jsondata.Query).empty() {
+		return nil, ErrEmptySearch
+	}
+	if err := checkTimes(jsondata.After, jsondata.Before); err != nil {
+		return nil, err
+	}
+	return db.emailPage(jsondata.Username, EmailQuery{
+		Search:      jsondata.Query,
+		Starred:     jsondata.Starred,
+		Unread:      jsondata.Unread,
+		Spam:        jsondata.Spam,
+		Draft:       jsondata.Draft,
+		Deleted:     jsondata.Deleted,
+		Attachments: jsondata.Attachments,
+		After:       jsondata.After,
+		Before:      jsondata.Before,
+	}, jsondata.Cursor)
+}
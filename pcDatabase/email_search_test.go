@@ -0,0 +1,76 @@
+package pcDatabase
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSearchEmails(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "alice")
+	recv := time.Date(2015, 6, 12, 19, 20, 0, 0, time.UTC)
+	for i, email := range []EmailRowStruct{
+		{FromEmail: "bob@example.com", Subject: "quarterly report", Content: "attached", Attachments: `["report.pdf"]`},
+		{FromEmail: "carol@example.com", Subject: "lunch", Content: "the report can wait", Starred: true},
+		{FromEmail: "spam@example.com", Subject: "cheap reports", Content: "buy now", Spam: true, Attachments: "[]"},
+	} {
+		email.ToEmails = "alice@pinged.email"
+		email.RecvTime = recv.Add(time.Duration(i) * time.Hour)
+		email.M_time = email.RecvTime
+		if err := db.Emails.AddEmail("alice", email); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found := EmailDataStruct{}
+	dispatch(t, db, `{"cmd":"SearchEmails","query":"report"}`, &found)
+	if len(found.Emails) != 2 || found.Emails[0].FromEmail != "carol@example.com" || found.Cursor != "" {
+		t.Errorf("searching for report found %+v", found)
+	}
+	found = EmailDataStruct{}
+	dispatch(t, db, `{"cmd":"SearchEmails","query":"carol","starred":true}`, &found)
+	if len(found.Emails) != 1 || found.Emails[0].Subject != "lunch" {
+		t.Errorf("starred from carol found %+v", found.Emails)
+	}
+	found = EmailDataStruct{}
+	dispatch(t, db, `{"cmd":"SearchEmails","attachments":true,"spam":false}`, &found)
+	if len(found.Emails) != 1 || found.Emails[0].Subject != "quarterly report" {
+		t.Errorf("attachments that aren't spam found %+v", found.Emails)
+	}
+	found = EmailDataStruct{}
+	dispatch(t, db, `{"cmd":"SearchEmails","after":"2015-06-12T20:00:00Z","before":"2015-06-12T21:00:00Z"}`, &found)
+	if len(found.Emails) != 1 || found.Emails[0].Subject != "lunch" {
+		t.Errorf("received between 20:00 and 21:00 found %+v", found.Emails)
+	}
+	expectError(t, db, `{"cmd":"SearchEmails","after":"yesterday"}`, ERR_BAD_REQUEST)
+}
+
+func TestGetAllEmailsPages(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "alice")
+	recv := time.Date(2015, 6, 12, 19, 20, 0, 0, time.UTC)
+	for i := 0; i < EMAIL_PAGE_SIZE+3; i++ {
+		// all modified at once, so the pages go by the key
+		db.Emails.AddEmail("alice", EmailRowStruct{FromEmail: fmt.Sprintf("%d@example.com", i), ToEmails: "alice@pinged.email", RecvTime: recv, M_time: recv})
+	}
+	first := EmailDataStruct{}
+	dispatch(t, db, `{"cmd":"GetAllEmails"}`, &first)
+	if len(first.Emails) != EMAIL_PAGE_SIZE || first.Cursor == "" {
+		t.Fatalf("the first page has %d emails and cursor %q", len(first.Emails), first.Cursor)
+	}
+	second := EmailDataStruct{}
+	dispatch(t, db, `{"cmd":"GetAllEmails","cursor":"`+first.Cursor+`"}`, &second)
+	if len(second.Emails) != 3 || second.Cursor != "" {
+		t.Fatalf("the second page is %+v", second)
+	}
+	for _, email := range first.Emails {
+		for _, other := range second.Emails {
+			if email.FromEmail == other.FromEmail {
+				t.Errorf("%s is on both pages", email.FromEmail)
+			}
+		}
+	}
+}
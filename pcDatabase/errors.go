@@ -0,0 +1,76 @@
+package pcDatabase
+
+import (
+	"fmt"
+)
+
+// error codes sent back in the envelope's error.code, the clients switch on
+// these so never rename one, only add new ones (and to errorCodes below)
+const (
+	ERR_BAD_REQUEST         = "bad_request"
+	ERR_UNKNOWN_COMMAND     = "unknown_command"
+	ERR_NOT_LOGGED_IN       = "not_logged_in"
+	ERR_INVALID_CREDENTIALS = "invalid_credentials"
+	ERR_FORBIDDEN           = "forbidden"
+	ERR_NOT_FOUND           = "not_found"
+	ERR_CONFLICT            = "conflict"
+	ERR_QUOTA_EXCEEDED      = "quota_exceeded"
+	ERR_SEND_FAILED         = "send_failed"
+	ERR_RATE_LIMITED        = "rate_limited"
+	ERR_UNAVAILABLE         = "unavailable"
+	ERR_INTERNAL            = "internal"
+)
+
+// ErrorCodeInfo documents an error code in the catalogue
+type ErrorCodeInfo struct {
+	Code  string `json:"code"`
+	Doc   string `json:"doc"`
+	Retry bool   `json:"retry,omitempty"` // the same command can succeed if it's sent again later
+}
+
+var errorCodes = []ErrorCodeInfo{
+	{ERR_BAD_REQUEST, "the request isn't valid JSON, is missing a required field or has a bad value", false},
+	{ERR_UNKNOWN_COMMAND, "there's no command with that name", false},
+	{ERR_NOT_LOGGED_IN, "only the public commands can be sent before CreateUser or ValidateUser", false},
+	{ERR_INVALID_CREDENTIALS, "wrong username, password or security answers", false},
+	{ERR_FORBIDDEN, "the command names another user or a conversation the caller isn't in", false},
+	{ERR_NOT_FOUND, "the user, conversation or email doesn't exist", false},
+	{ERR_CONFLICT, "the username, email or phone already belongs to someone", false},
+	{ERR_QUOTA_EXCEEDED, "the user's storage quota is used up", false},
+	{ERR_SEND_FAILED, "the email or text was rejected", false},
+	{ERR_RATE_LIMITED, "too many commands, wait a moment", true},
+	{ERR_UNAVAILABLE, "a database is unreachable", true},
+	{ERR_INTERNAL, "something went wrong on the server", true},
+}
+
+// CommandError is a failed command, it's sent back as the envelope's error
+type CommandError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Retry   bool   `json:"retry,omitempty"`
+}
+
+func (e *CommandError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// cmdError makes a CommandError, Retry comes from the code
+func cmdError(code string, format string, args ...interface{}) *CommandError {
+	err := &CommandError{Code: code, Message: fmt.Sprintf(format, args...)}
+	for _, info := range errorCodes {
+		if info.Code == code {
+			err.Retry = info.Retry
+		}
+	}
+	return err
+}
+
+// toCommandError turns any error from a handler into a CommandError, errors
+// that aren't CommandErrors already are internal
+func toCommandError(err error) *CommandError {
+	if cmdErr, ok := err.(*CommandError); ok {
+		return cmdErr
+	}
+	ERROR.Println("internal error:", err)
+	return cmdError(ERR_INTERNAL, "something went wrong, try again")
+}
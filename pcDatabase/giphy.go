@@ -5,11 +5,38 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
 )
 
-const (
-	giphyPrefix = "@giphy "
-)
+// @giphy cats, the message gets giphy's gif for cats
+type giphyBot struct{}
+
+var giphyTriggers = []*regexp.Regexp{regexp.MustCompile(`(?is)^\s*@giphy\s+(.+)$`)}
+
+func (giphyBot) Name() string {
+	return "giphy"
+}
+
+func (giphyBot) Triggers() []*regexp.Regexp {
+	return giphyTriggers
+}
+
+func (giphyBot) Help() string {
+	return "@giphy something adds a gif of something"
+}
+
+func (giphyBot) Handle(msg BotMessage) BotResponse {
+	gif, ok := rockGiphy(msg.Args)
+	if !ok {
+		return BotResponse{Ephemeral: &BotReply{Content: "giphy has no gif for that"}}
+	}
+	return BotResponse{
+		Rewrite:     true,
+		Content:     msg.Message.Content,
+		Attachments: append(msg.Message.Attachments, gif),
+	}
+}
 
 type GiphyImageData struct {
 	URL    string `json:"url"`
@@ -43,13 +70,14 @@ type GiphyTranslateResponse struct {
 	Data GiphyGif `json:"data"`
 }
 
-func rockGiphy(q string) string {
+// rockGiphy is the gif giphy has for q, false when there's none
+func rockGiphy(q string) (MessageAttachment, bool) {
 	TRACE.Printf("Searching for %q", q)
 	url := fmt.Sprintf("http://api.giphy.com/v1/gifs/translate?s=%s&api_key=dc6zaTOxFJmzC", url.QueryEscape(q)) // default testing API key
 	resp, err := http.Get(url)
 	if err != nil {
 		ERROR.Println(err)
-		return ""
+		return MessageAttachment{}, false
 	}
 	defer resp.Body.Close()
 	giphyResp := GiphyTranslateResponse{}
@@ -57,12 +85,16 @@ func rockGiphy(q string) string {
 	if err := dec.Decode(&giphyResp); err != nil {
 		ERROR.Println("err in decoding giphyresp")
 		ERROR.Println(err)
-		return q // return original message back
+		return MessageAttachment{}, false
 	}
 	TRACE.Println("giphyResp: ")
 	TRACE.Println(giphyResp)
-	msg := "NO RESULTS. I’M A MONSTER."
-	msg = fmt.Sprintf(`@giphy %s:<br> <img src="%s" />`, q, giphyResp.Data.Images.Original.URL)
-	TRACE.Println("msg from rockGiphy = " + msg)
-	return msg
+	original := giphyResp.Data.Images.Original
+	if !safeAttachmentURL(original.URL) {
+		return MessageAttachment{}, false // NO RESULTS. I'M A MONSTER.
+	}
+	gif := MessageAttachment{Type: ATTACHMENT_GIF, URL: original.URL, Title: q}
+	gif.Width, _ = strconv.Atoi(original.Width)
+	gif.Height, _ = strconv.Atoi(original.Height)
+	return gif, true
 }
@@ -2,6 +2,7 @@ package pcDatabase
 
 import (
 	"github.com/ronniekritou/gotelapi"
+	"github.com/twinj/uuid"
 	"runtime/debug"
 	"strings"
 	"time"
@@ -34,6 +35,20 @@ func getCurrentUTCISOTimeString() string {
 	return t.Format(ISO8601_SECONDS)
 }
 
+// message IDs are uuids, same format as CIDs
+func newMessageID() string {
+	return uuid.Formatter(uuid.NewV4(), uuid.CleanHyphen)
+}
+
+// clients send either RFC3339 with fractional seconds or ISO8601 to the second
+func parseTimeString(timeString string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, timeString)
+	if err != nil {
+		t, _ = time.Parse(ISO8601_SECONDS, timeString)
+	}
+	return t
+}
+
 var invalidPhoneNumbers = [...]string{
 	"911",
 	"+1911",
@@ -101,7 +116,7 @@ func validatePhoneAndGetPhoneGateway(phone string) string {
 	// lookup user phone number
 	if telapi_helper.AuthToken == "" {
 		var err error
-		telapi_helper, err = telapi.CreateClient(TELAPI_TESTING_SID, TELAPI_TESTING_AUTH)
+		telapi_helper, err = telapi.CreateClient(conf.Telapi.SID, conf.Telapi.AuthToken)
 		if err != nil {
 			ERROR.Printf("telapi.CreateClient err: ", err)
 		}
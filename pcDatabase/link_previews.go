@@ -0,0 +1,482 @@
+package pcDatabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"pingedchat/config"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// LINK PREVIEWS
+// once a message is sent, the first few http(s) links in it are fetched in
+// the background and what the pages say about themselves (OpenGraph tags,
+// oEmbed, or at least the <title>) is added to the message as ATTACHMENT_LINK
+// attachments.  Every member's web devices get a MessageUpdated event with
+// the message's attachments when they're there.  Previews are only made when
+// a message is sent, not when it's edited.
+//
+// the pages are fetched by the server, so HTTPLinkFetcher only goes to
+// public addresses on the default ports, checked when it connects (so a
+// name can't resolve to a public address and then a private one) and again
+// for every redirect.  It reads at most MaxKB of a page within
+// TimeoutSeconds, and config.PreviewsConfig can limit the hosts.  Previews
+// are cached per URL by CachedLinkFetcher, failures too but not as long.
+//
+// Stores.Previews is the fetcher, nil turns previews off and tests put a stub
+// there.
+
+const (
+	// links in a message that get a preview
+	MAX_LINK_PREVIEWS = 3
+	// for all of a message's previews
+	LINK_PREVIEWS_DEADLINE = 15 * time.Second
+	// redirects followed per page
+	LINK_PREVIEW_REDIRECTS = 3
+	// how long a preview, or that there's none, is kept
+	LINK_PREVIEW_TTL         = 6 * time.Hour
+	LINK_PREVIEW_FAILURE_TTL = 10 * time.Minute
+	// URLs cached
+	LINK_PREVIEW_CACHE_SIZE = 10000
+	// characters kept
+	MAX_PREVIEW_TITLE       = 200
+	MAX_PREVIEW_DESCRIPTION = 300
+
+	LINK_PREVIEW_USER_AGENT = "PingedChat-LinkPreview/1.0"
+)
+
+var (
+	ErrNoPreview = errors.New("no preview for that link")
+	// where HTTPLinkFetcher won't go
+	ErrPreviewForbidden = errors.New("link previews aren't fetched from there")
+)
+
+// the address ranges that aren't on the public internet, or shouldn't be
+// reached from the server
+var privateNetworks = parseCIDRs(
+	"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16",
+	"172.16.0.0/12", "192.0.0.0/24", "192.0.2.0/24", "192.168.0.0/16", "198.18.0.0/15",
+	"198.51.100.0/24", "203.0.113.0/24", "224.0.0.0/4", "240.0.0.0/4",
+	"::/128", "::1/128", "64:ff9b::/96", "100::/64", "2001:db8::/32", "fc00::/7", "fe80::/10", "ff00::/8",
+)
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// publicIP is false for loopback, private, link local, multicast and
+// reserved addresses, v4 in v6 included
+func publicIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// MessageUpdated, the message's attachments after its previews were added
+type MessageUpdatedEvent struct {
+	Cmd         string              `json:"cmd"`
+	CID         string              `json:"CID"`
+	MID         string              `json:"MID"`
+	Attachments []MessageAttachment `json:"attachments"`
+}
+
+// the links in content that get a preview, in order and once each
+func previewLinks(content string) []string {
+	links := make([]string, 0)
+	var walk func(nodes []markupNode)
+	walk = func(nodes []markupNode) {
+		for _, n := range nodes {
+			if len(links) == MAX_LINK_PREVIEWS {
+				return
+			}
+			if n.kind == nodeLink && safeAttachmentURL(n.url) && !containsString(links, n.url) {
+				links = append(links, n.url)
+			}
+			walk(n.children)
+		}
+	}
+	walk(parseMarkup(content))
+	return links
+}
+
+// unfurlLinks adds the previews of msg's links in the background
+func (db *Database) unfurlLinks(msg MessageStruct) {
+	if db.Previews == nil || db.Messages == nil {
+		return
+	}
+	links := previewLinks(msg.Content)
+	if len(links) == 0 {
+		return
+	}
+	go db.addLinkPreviews(msg.CID, msg.MID, links)
+}
+
+func (db *Database) addLinkPreviews(CID string, MID string, links []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), LINK_PREVIEWS_DEADLINE)
+	defer cancel()
+	previews := make([]MessageAttachment, 0, len(links))
+	for _, link := range links {
+		preview, err := db.Previews.FetchPreview(ctx, link)
+		if err != nil {
+			if err != ErrNoPreview && err != ErrPreviewForbidden {
+				TRACE.Println("no preview for "+link+":", err)
+			}
+			continue
+		}
+		previews = append(previews, preview)
+	}
+	if len(previews) == 0 {
+		return
+	}
+	attachments, err := db.Messages.AddAttachments(CID, MID, previews)
+	if err == ErrNoMessage {
+		return // deleted in the meantime
+	} else if err != nil {
+		logPqError("AddAttachments", err)
+		return
+	}
+	db.sendToConvoMembers(CID, MessageUpdatedEvent{
+		Cmd:         "MessageUpdated",
+		CID:         CID,
+		MID:         MID,
+		Attachments: attachments,
+	})
+}
+
+// NewLinkFetcher is the cached HTTPLinkFetcher c asks for, nil when
+// previews are off
+func NewLinkFetcher(c config.PreviewsConfig) LinkFetcher {
+	if !c.Enabled {
+		return nil
+	}
+	return NewCachedLinkFetcher(NewHTTPLinkFetcher(c))
+}
+
+// CACHE
+
+type cachedPreview struct {
+	preview MessageAttachment
+	err     error
+	expires time.Time
+}
+
+// CachedLinkFetcher keeps what another LinkFetcher found for each URL
+type CachedLinkFetcher struct {
+	fetcher LinkFetcher
+	mu      sync.Mutex
+	cache   map[string]cachedPreview
+}
+
+func NewCachedLinkFetcher(fetcher LinkFetcher) *CachedLinkFetcher {
+	return &CachedLinkFetcher{fetcher: fetcher, cache: make(map[string]cachedPreview)}
+}
+
+func (c *CachedLinkFetcher) FetchPreview(ctx context.Context, rawURL string) (MessageAttachment, error) {
+	now := time.Now()
+	c.mu.Lock()
+	cached, ok := c.cache[rawURL]
+	c.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.preview, cached.err
+	}
+	preview, err := c.fetcher.FetchPreview(ctx, rawURL)
+	if ctx.Err() != nil {
+		return preview, err // out of time, that says nothing about the page
+	}
+	cached = cachedPreview{preview: preview, err: err, expires: now.Add(LINK_PREVIEW_TTL)}
+	if err != nil {
+		cached.expires = now.Add(LINK_PREVIEW_FAILURE_TTL)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.cache) >= LINK_PREVIEW_CACHE_SIZE {
+		for key, e := range c.cache {
+			if now.After(e.expires) {
+				delete(c.cache, key)
+			}
+		}
+		// still full, any of them can go
+		for key := range c.cache {
+			if len(c.cache) < LINK_PREVIEW_CACHE_SIZE {
+				break
+			}
+			delete(c.cache, key)
+		}
+	}
+	c.cache[rawURL] = cached
+	return preview, err
+}
+
+// FETCHING
+
+// HTTPLinkFetcher fetches the pages themselves
+type HTTPLinkFetcher struct {
+	client   *http.Client
+	allow    []string
+	deny     []string
+	maxBytes int64
+}
+
+func splitHosts(hosts string) []string {
+	split := make([]string, 0)
+	for _, host := range strings.Split(hosts, ",") {
+		if host = strings.Trim(strings.ToLower(strings.TrimSpace(host)), "."); host != "" {
+			split = append(split, host)
+		}
+	}
+	return split
+}
+
+func NewHTTPLinkFetcher(c config.PreviewsConfig) *HTTPLinkFetcher {
+	timeout := time.Duration(c.TimeoutSeconds) * time.Second
+	f := &HTTPLinkFetcher{
+		allow:    splitHosts(c.Allow),
+		deny:     splitHosts(c.Deny),
+		maxBytes: int64(c.MaxKB) * 1024,
+	}
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		// the address it's really connecting to, after DNS
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil || !publicIP(net.ParseIP(host)) {
+				return ErrPreviewForbidden
+			}
+			return nil
+		},
+	}
+	f.client = &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:                 nil, // a proxy would connect for us, unchecked
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConns:          20,
+			IdleConnTimeout:       time.Minute,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > LINK_PREVIEW_REDIRECTS {
+				return ErrNoPreview
+			}
+			return f.checkURL(req.URL)
+		},
+	}
+	return f
+}
+
+func hostMatches(host string, hosts []string) bool {
+	for _, h := range hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkURL is nil for the URLs that can be fetched, the address is checked
+// when connecting
+func (f *HTTPLinkFetcher) checkURL(u *url.URL) error {
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" || u.User != nil {
+		return ErrPreviewForbidden
+	}
+	if port := u.Port(); port != "" && !(scheme == "http" && port == "80") && !(scheme == "https" && port == "443") {
+		return ErrPreviewForbidden
+	}
+	host := strings.Trim(strings.ToLower(u.Hostname()), ".")
+	if host == "" || hostMatches(host, f.deny) || (len(f.allow) > 0 && !hostMatches(host, f.allow)) {
+		return ErrPreviewForbidden
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+		return ErrPreviewForbidden
+	}
+	return nil
+}
+
+// GETs u, returns up to maxBytes of the body if its type is one of types
+func (f *HTTPLinkFetcher) get(ctx context.Context, u *url.URL, types ...string) ([]byte, *url.URL, error) {
+	if err := f.checkURL(u); err != nil {
+		return nil, nil, err
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("User-Agent", LINK_PREVIEW_USER_AGENT)
+	req.Header.Set("Accept", strings.Join(types, ", "))
+	resp, err := f.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrPreviewForbidden) {
+			return nil, nil, ErrPreviewForbidden
+		}
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, ErrNoPreview
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !containsString(types, mediaType) {
+		return nil, nil, ErrNoPreview
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, f.maxBytes))
+	return body, resp.Request.URL, err
+}
+
+func (f *HTTPLinkFetcher) FetchPreview(ctx context.Context, rawURL string) (MessageAttachment, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return MessageAttachment{}, ErrNoPreview
+	}
+	page, final, err := f.get(ctx, u, "text/html", "application/xhtml+xml")
+	if err != nil {
+		return MessageAttachment{}, err
+	}
+	preview, oembed := parsePreview(final, string(page))
+	preview.URL = rawURL
+	if (preview.Title == "" || preview.Image == "") && oembed != nil {
+		// the oEmbed fills in what the page didn't say
+		if body, _, err := f.get(ctx, oembed, "application/json", "text/json"); err == nil {
+			fillFromOEmbed(&preview, final, body)
+		}
+	}
+	if preview.Title == "" && preview.Description == "" {
+		return MessageAttachment{}, ErrNoPreview
+	}
+	return preview, nil
+}
+
+// PARSING
+
+var (
+	previewMetaPattern  = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	previewLinkPattern  = regexp.MustCompile(`(?is)<link\s[^>]*>`)
+	previewTitlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+)
+
+func tagAttrs(tag string) map[string]string {
+	attrs := make(map[string]string)
+	for _, attr := range legacyAttrPattern.FindAllStringSubmatch(tag, -1) {
+		attrs[strings.ToLower(attr[1])] = html.UnescapeString(strings.Trim(attr[2], `"'`))
+	}
+	return attrs
+}
+
+// previewText is s cleaned up and cut to max characters
+func previewText(s string, max int) string {
+	s = strings.Join(strings.Fields(sanitizeMarkup(s)), " ")
+	if runes := []rune(s); len(runes) > max {
+		s = strings.TrimSpace(string(runes[:max-1])) + "…"
+	}
+	return s
+}
+
+// previewImage is ref resolved against the page, "" unless it's http(s)
+func previewImage(page *url.URL, ref string) string {
+	u, err := page.Parse(strings.TrimSpace(ref))
+	if err != nil || !safeAttachmentURL(u.String()) {
+		return ""
+	}
+	return u.String()
+}
+
+// parsePreview reads the OpenGraph (or twitter card) tags of a page, the
+// description and the title as a fallback.  oembed is the page's oEmbed
+// link, if it has one
+func parsePreview(page *url.URL, body string) (MessageAttachment, *url.URL) {
+	meta := make(map[string]string)
+	for _, tag := range previewMetaPattern.FindAllString(body, -1) {
+		attrs := tagAttrs(tag)
+		key := strings.ToLower(attrs["property"])
+		if key == "" {
+			key = strings.ToLower(attrs["name"])
+		}
+		if _, seen := meta[key]; key != "" && !seen {
+			meta[key] = attrs["content"]
+		}
+	}
+	first := func(keys ...string) string {
+		for _, key := range keys {
+			if value := strings.TrimSpace(meta[key]); value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+	preview := MessageAttachment{
+		Type:        ATTACHMENT_LINK,
+		Title:       previewText(first("og:title", "twitter:title"), MAX_PREVIEW_TITLE),
+		Description: previewText(first("og:description", "twitter:description", "description"), MAX_PREVIEW_DESCRIPTION),
+		SiteName:    previewText(first("og:site_name"), MAX_PREVIEW_TITLE),
+	}
+	if image := first("og:image:secure_url", "og:image", "twitter:image"); image != "" {
+		preview.Image = previewImage(page, image)
+	}
+	if preview.Title == "" {
+		if match := previewTitlePattern.FindStringSubmatch(body); match != nil {
+			preview.Title = previewText(html.UnescapeString(match[1]), MAX_PREVIEW_TITLE)
+		}
+	}
+	var oembed *url.URL
+	for _, tag := range previewLinkPattern.FindAllString(body, -1) {
+		attrs := tagAttrs(tag)
+		if strings.ToLower(attrs["type"]) == "application/json+oembed" && attrs["href"] != "" {
+			oembed, _ = page.Parse(attrs["href"])
+			break
+		}
+	}
+	return preview, oembed
+}
+
+type oEmbedResponse struct {
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	ProviderName string `json:"provider_name"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+func fillFromOEmbed(preview *MessageAttachment, page *url.URL, body []byte) {
+	var oembed oEmbedResponse
+	if err := json.Unmarshal(body, &oembed); err != nil {
+		return
+	}
+	if preview.Title == "" {
+		preview.Title = previewText(oembed.Title, MAX_PREVIEW_TITLE)
+	}
+	if preview.Description == "" && oembed.AuthorName != "" {
+		preview.Description = previewText(oembed.AuthorName, MAX_PREVIEW_DESCRIPTION)
+	}
+	if preview.SiteName == "" {
+		preview.SiteName = previewText(oembed.ProviderName, MAX_PREVIEW_TITLE)
+	}
+	if preview.Image == "" && oembed.ThumbnailURL != "" {
+		preview.Image = previewImage(page, oembed.ThumbnailURL)
+	}
+}
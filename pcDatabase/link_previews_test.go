@@ -0,0 +1,133 @@
+package pcDatabase
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"pingedchat/config"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// answers every link with its own URL as the title
+type stubLinkFetcher struct {
+	mu      sync.Mutex
+	fetches int
+}
+
+func (s *stubLinkFetcher) FetchPreview(ctx context.Context, rawURL string) (MessageAttachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetches++
+	if strings.Contains(rawURL, "nothing") {
+		return MessageAttachment{}, ErrNoPreview
+	}
+	return MessageAttachment{Type: ATTACHMENT_LINK, URL: rawURL, Title: "about " + rawURL}, nil
+}
+
+func TestLinkPreviews(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	stub := &stubLinkFetcher{}
+	db.Previews = NewCachedLinkFetcher(stub)
+	createTestUser(t, db, "bob")
+	createTestUser(t, db, "alice")
+	CID := createTestConversation(t, db, "alice", "bob")
+
+	send := `{"cmd":"SendMessage","CID":"` + CID + `","t_UIDs":["alice","bob"],"content":"see https://a.com and [this](https://a.com) or https://nothing.com","m_time":"2015-06-12T19:20:0`
+	for i := 0; i < 2; i++ {
+		if env := dispatch(t, db, send+strconv.Itoa(i)+`.000Z"}`, nil); !env.OK {
+			t.Fatalf("SendMessage returned %+v", env)
+		}
+		// the message, then its preview
+		events := expectEvents(t, db, 4)
+		if updated := events[3]; !strings.Contains(updated, `"cmd":"MessageUpdated"`) || !strings.Contains(updated, "about https://a.com") {
+			t.Errorf("expected the preview, got %s", updated)
+		}
+	}
+	if stub.fetches != 2 {
+		t.Errorf("the links were fetched %d times, expected once each", stub.fetches)
+	}
+	rows, _ := db.Messages.GetAllMessages(CID)
+	for _, row := range rows {
+		if len(row.Attachments) != 1 || row.Attachments[0].Type != ATTACHMENT_LINK || row.Attachments[0].URL != "https://a.com" {
+			t.Errorf("message has attachments %+v", row.Attachments)
+		}
+	}
+}
+
+func TestPreviewLinks(t *testing.T) {
+	links := previewLinks("https://a.com `https://code.com` [b](https://b.com) mailto:x@y.com https://a.com https://c.com https://d.com")
+	if strings.Join(links, " ") != "https://a.com https://b.com https://c.com" {
+		t.Errorf("previewLinks got %v", links)
+	}
+}
+
+func TestPreviewURLChecks(t *testing.T) {
+	for ip, public := range map[string]bool{
+		"8.8.8.8": true, "2606:4700::1111": true,
+		"127.0.0.1": false, "10.1.2.3": false, "172.20.0.1": false, "192.168.1.1": false,
+		"169.254.169.254": false, "100.64.0.1": false, "0.0.0.0": false, "::1": false,
+		"fd00::1": false, "fe80::1": false, "::ffff:127.0.0.1": false, "64:ff9b::a00:1": false,
+	} {
+		if publicIP(net.ParseIP(ip)) != public {
+			t.Errorf("publicIP(%s) should be %v", ip, public)
+		}
+	}
+
+	f := NewHTTPLinkFetcher(config.PreviewsConfig{Enabled: true, Deny: "bad.com", TimeoutSeconds: 1, MaxKB: 1})
+	for link, ok := range map[string]bool{
+		"https://a.com/x":          true,
+		"http://a.com:80/x":        true,
+		"https://a.com:8443/x":     false,
+		"ftp://a.com/x":            false,
+		"https://user@a.com/x":     false,
+		"https://bad.com/x":        false,
+		"https://www.bad.com/x":    false,
+		"https://notbad.com/x":     true,
+		"http://127.0.0.1/x":       false,
+		"http://[::1]/x":           false,
+		"http://169.254.169.254/x": false,
+	} {
+		u, _ := url.Parse(link)
+		if err := f.checkURL(u); (err == nil) != ok {
+			t.Errorf("checkURL(%s) = %v", link, err)
+		}
+	}
+	f = NewHTTPLinkFetcher(config.PreviewsConfig{Enabled: true, Allow: "a.com, b.com", TimeoutSeconds: 1, MaxKB: 1})
+	for link, ok := range map[string]bool{"https://a.com": true, "https://img.b.com": true, "https://c.com": false} {
+		u, _ := url.Parse(link)
+		if err := f.checkURL(u); (err == nil) != ok {
+			t.Errorf("with an allow list checkURL(%s) = %v", link, err)
+		}
+	}
+}
+
+func TestParsePreview(t *testing.T) {
+	page, _ := url.Parse("https://a.com/posts/1")
+	preview, oembed := parsePreview(page, `<html><head>
+		<title>fallback</title>
+		<meta property="og:title" content="A &amp; B">
+		<meta name="description" content="  about
+			things ">
+		<meta property="og:image" content="/cover.png">
+		<link rel="alternate" type="application/json+oembed" href="https://a.com/oembed?u=1">
+	</head></html>`)
+	if preview.Title != "A & B" || preview.Description != "about things" || preview.Image != "https://a.com/cover.png" {
+		t.Errorf("parsePreview got %+v", preview)
+	}
+	if oembed == nil || oembed.String() != "https://a.com/oembed?u=1" {
+		t.Errorf("oembed link %v", oembed)
+	}
+
+	preview, _ = parsePreview(page, `<title>Just a <b>title</b></title><meta property="og:image" content="javascript:alert(1)">`)
+	if preview.Title != "Just a <b>title</b>" || preview.Image != "" {
+		t.Errorf("parsePreview got %+v", preview)
+	}
+	fillFromOEmbed(&preview, page, []byte(`{"author_name":"bob","thumbnail_url":"https://a.com/t.jpg"}`))
+	if preview.Description != "bob" || preview.Image != "https://a.com/t.jpg" {
+		t.Errorf("after the oEmbed %+v", preview)
+	}
+}
@@ -0,0 +1,119 @@
+package pcDatabase
+
+import (
+	"errors"
+)
+
+// turning the messages from before content was markup (see richtext.go)
+// into markup for good.
+//
+// rows with markup unset are HTML, the MessageStore converts them every
+// time they're read, and their search column is made from the HTML.
+// MigrateLegacyMarkup rewrites them, and the edits they kept, so that stops.
+// Old servers still write HTML rows (with markup unset) so run it again
+// once they're all gone.
+
+// rows converted per query
+const markupMigrationBatch = 500
+
+// rewrites one page of messages after (cid, mid), returns the last key and
+// how many there were on the page
+func (s *PostgresStore) convertLegacyMessages(cid string, mid string) (string, string, int, error) {
+	rows, err := s.conn.Query(`SELECT cid, mid, content FROM `+POSTGRES_MESSAGES_TABLE+` WHERE NOT markup AND (cid, mid) > ($1, $2) ORDER BY cid, mid LIMIT $3;`, cid, mid, markupMigrationBatch)
+	if err != nil {
+		return cid, mid, 0, err
+	}
+	type legacyRow struct{ cid, mid, content string }
+	var page []legacyRow
+	for rows.Next() {
+		var row legacyRow
+		if err := rows.Scan(&row.cid, &row.mid, &row.content); err != nil {
+			rows.Close()
+			return cid, mid, 0, err
+		}
+		page = append(page, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return cid, mid, 0, err
+	}
+	for _, row := range page {
+		content, attachments := legacyHTMLToMarkup(row.content)
+		column, err := attachmentsColumn(attachments)
+		if err != nil {
+			return cid, mid, 0, err
+		}
+		// not if it was edited or deleted in the meantime
+		if _, err := s.conn.Exec(`UPDATE `+POSTGRES_MESSAGES_TABLE+` SET content = $4, attachments = $5, markup = true, search = CASE WHEN deleted THEN NULL ELSE to_tsvector(`+searchConfig+`, $4) END WHERE cid = $1 AND mid = $2 AND content = $3 AND NOT markup;`,
+			row.cid, row.mid, row.content, content, column); err != nil {
+			return cid, mid, 0, err
+		}
+		cid, mid = row.cid, row.mid
+	}
+	return cid, mid, len(page), nil
+}
+
+// the same for message_edits, keyed by (cid, mid, e_time)
+func (s *PostgresStore) convertLegacyEdits() (int, error) {
+	converted := 0
+	for {
+		rows, err := s.conn.Query(`SELECT cid, mid, e_time::text, content FROM `+POSTGRES_MESSAGE_EDITS_TABLE+` WHERE NOT markup LIMIT $1;`, markupMigrationBatch)
+		if err != nil {
+			return converted, err
+		}
+		type legacyEdit struct{ cid, mid, etime, content string }
+		var page []legacyEdit
+		for rows.Next() {
+			var edit legacyEdit
+			if err := rows.Scan(&edit.cid, &edit.mid, &edit.etime, &edit.content); err != nil {
+				rows.Close()
+				return converted, err
+			}
+			page = append(page, edit)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return converted, err
+		}
+		for _, edit := range page {
+			content, _ := legacyHTMLToMarkup(edit.content)
+			if _, err := s.conn.Exec(`UPDATE `+POSTGRES_MESSAGE_EDITS_TABLE+` SET content = $4, markup = true WHERE cid = $1 AND mid = $2 AND e_time = $3::timestamptz;`, edit.cid, edit.mid, edit.etime, content); err != nil {
+				return converted, err
+			}
+		}
+		converted += len(page)
+		if len(page) < markupMigrationBatch {
+			return converted, nil
+		}
+	}
+}
+
+// MigrateLegacyMarkup converts every message and edit that's still HTML to
+// markup.  It's safe to run while the server is up and to run again if it
+// gets interrupted.  Returns how many messages were converted.
+func (s *PostgresStore) MigrateLegacyMarkup() (int, error) {
+	if s.conn == nil {
+		return 0, errNoPostgres
+	}
+	converted := 0
+	cid, mid := "", ""
+	for {
+		var n int
+		var err error
+		cid, mid, n, err = s.convertLegacyMessages(cid, mid)
+		if err != nil {
+			logPqError("MigrateLegacyMarkup", err)
+			return converted, errors.New("some messages could not be converted, run again to retry them")
+		}
+		converted += n
+		TRACE.Printf("converted %d messages", converted)
+		if n < markupMigrationBatch {
+			break
+		}
+	}
+	if _, err := s.convertLegacyEdits(); err != nil {
+		logPqError("MigrateLegacyMarkup", err)
+		return converted, errors.New("some edits could not be converted, run again to retry them")
+	}
+	return converted, nil
+}
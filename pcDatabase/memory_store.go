@@ -0,0 +1,1023 @@
+package pcDatabase
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore implements every store interface in process, for tests and for
+// running the server without aerospike, postgres and gnatsd (main.go -dev).
+// It behaves like the real backends as far as the handlers can tell.
+type MemoryStore struct {
+	mu sync.Mutex
+	// users are kept as normalized bins, same as they come back from aerospike
+	users       map[string]map[string]interface{}
+	generations map[string]uint32 // per user, like aerospike's record generation
+	usersActive map[string]map[string]interface{}
+	convos      map[string]*memoryConvo
+	messages    map[string][]ConvoRowStruct
+	edits       map[string][]MessageEditStruct // by CID + "/" + MID
+	reactions   map[string][]memoryReaction    // by CID + "/" + MID, oldest first
+	scheduled   []ScheduledMessagesCmdStruct
+	mailboxes   map[string][]EmailRowStruct
+	changes     map[string][]ChangeStruct // oldest first
+	changeSeqs  map[string]int64
+	deviceQueue map[string][]DeviceEventStruct // by deviceQueueKey, oldest first
+	deviceSeqs  map[string]int64               // by deviceQueueKey
+	// event bus
+	subscriptions map[string][]*memorySubscription
+}
+
+type memoryReaction struct {
+	username string
+	emoji    string
+}
+
+type memoryConvo struct {
+	members []string
+	name    string
+	mtime   string
+	files   []string
+	bots    []string // disabled
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		users:         make(map[string]map[string]interface{}),
+		generations:   make(map[string]uint32),
+		usersActive:   make(map[string]map[string]interface{}),
+		convos:        make(map[string]*memoryConvo),
+		messages:      make(map[string][]ConvoRowStruct),
+		edits:         make(map[string][]MessageEditStruct),
+		reactions:     make(map[string][]memoryReaction),
+		mailboxes:     make(map[string][]EmailRowStruct),
+		changes:       make(map[string][]ChangeStruct),
+		changeSeqs:    make(map[string]int64),
+		deviceQueue:   make(map[string][]DeviceEventStruct),
+		deviceSeqs:    make(map[string]int64),
+		subscriptions: make(map[string][]*memorySubscription),
+	}
+}
+
+// Stores returns the MemoryStore wired in as every backend
+func (m *MemoryStore) Stores() Stores {
+	return Stores{
+		Users:    m,
+		Convos:   m,
+		Messages: m,
+		Emails:   m,
+		Changes:  m,
+		Queues:   m,
+		Events:   m,
+	}
+}
+
+// aerospike hands back lists as []interface{} and numbers as int, and
+// FillUserWithAerospikeBins expects exactly that
+func normalizeBins(bins map[string]interface{}) map[string]interface{} {
+	normalized := make(map[string]interface{}, len(bins))
+	for name, value := range bins {
+		switch v := value.(type) {
+		case []string:
+			list := make([]interface{}, len(v))
+			for i, e := range v {
+				list[i] = e
+			}
+			normalized[name] = list
+		case uint32:
+			normalized[name] = int(v)
+		default:
+			normalized[name] = value
+		}
+	}
+	return normalized
+}
+
+func copyStrings(arr []string) []string {
+	return append(make([]string, 0, len(arr)), arr...)
+}
+
+// UserStore
+func (m *MemoryStore) GetUser(username string) UserStruct {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bins, ok := m.users[strings.ToUpper(strings.TrimSpace(username))]
+	if !ok {
+		return UserStruct{}
+	}
+	return FillUserWithAerospikeBins(normalizeBins(bins))
+}
+
+// same as an aerospike equality query, but an empty value never matches
+func (m *MemoryStore) getUserByBin(binName string, value string) UserStruct {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if value == "" {
+		return UserStruct{}
+	}
+	for _, bins := range m.users {
+		if bins[binName] == value {
+			return FillUserWithAerospikeBins(normalizeBins(bins))
+		}
+	}
+	return UserStruct{}
+}
+
+func (m *MemoryStore) GetUserByEmail(email string) UserStruct {
+	return m.getUserByBin(AEROSPIKE_USERS_USERNAME_EMAIL_BIN, email)
+}
+
+func (m *MemoryStore) GetUserByPhone(phonenum string) UserStruct {
+	return m.getUserByBin(AEROSPIKE_USERS_USERNAME_PHONE_BIN, phonenum)
+}
+
+func (m *MemoryStore) SetUser(user UserStruct) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToUpper(user.UsernameUpper)
+	m.users[key] = normalizeBins(user.ToAerospikeBins())
+	m.generations[key]++
+	return true
+}
+
+func (m *MemoryStore) GetUserGeneration(username string) (UserStruct, uint32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToUpper(strings.TrimSpace(username))
+	bins, ok := m.users[key]
+	if !ok {
+		return UserStruct{}, 0
+	}
+	return FillUserWithAerospikeBins(normalizeBins(bins)), m.generations[key]
+}
+
+func (m *MemoryStore) CheckAndSetUser(user UserStruct, generation uint32) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToUpper(user.UsernameUpper)
+	_, exists := m.users[key]
+	if (generation == 0 && exists) || (generation != 0 && (!exists || m.generations[key] != generation)) {
+		return ErrStaleUser
+	}
+	// the bins in user replace the stored ones, same as an aerospike put
+	updated := make(map[string]interface{})
+	for name, value := range m.users[key] {
+		updated[name] = value
+	}
+	for name, value := range normalizeBins(user.ToAerospikeBins()) {
+		updated[name] = value
+	}
+	m.users[key] = updated
+	m.generations[key]++
+	return nil
+}
+
+func (m *MemoryStore) UpdateUserFields(username string, fields UserFields) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToUpper(username)
+	bins, ok := m.users[key]
+	if !ok {
+		// aerospike creates the record on update
+		bins = make(map[string]interface{})
+	}
+	updated := normalizeBins(bins)
+	for name, value := range normalizeBins(fields) {
+		updated[name] = value
+	}
+	m.users[key] = updated
+	m.generations[key]++
+	return true
+}
+
+// edits the CIDs and CIDUnread map bins of a user in CID, see user_bins.go
+func (m *MemoryStore) updateUserConvo(username string, CID string, edit func(mtimes, unread map[interface{}]interface{})) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToUpper(username)
+	bins, ok := m.users[key]
+	if !ok {
+		return ErrNotInConvo
+	}
+	mtimes := copyBinMap(bins[AEROSPIKE_USERS_CIDS_BIN])
+	if _, ok := mtimes[CID]; !ok {
+		return ErrNotInConvo
+	}
+	unread := copyBinMap(bins[AEROSPIKE_USERS_CID_UNREAD_BIN])
+	edit(mtimes, unread)
+	updated := normalizeBins(bins)
+	updated[AEROSPIKE_USERS_CIDS_BIN] = mtimes
+	updated[AEROSPIKE_USERS_CID_UNREAD_BIN] = unread
+	m.users[key] = updated
+	m.generations[key]++
+	return nil
+}
+
+func (m *MemoryStore) BumpUserConvo(username string, CID string, mtime string) error {
+	return m.updateUserConvo(username, CID, func(mtimes, unread map[interface{}]interface{}) {
+		mtimes[CID] = mtime
+		count, _ := unread[CID].(int)
+		unread[CID] = count + 1
+	})
+}
+
+func (m *MemoryStore) SetUserConvoUnread(username string, CID string, count int) error {
+	return m.updateUserConvo(username, CID, func(mtimes, unread map[interface{}]interface{}) {
+		unread[CID] = count
+	})
+}
+
+func (m *MemoryStore) SetUserConvoMtime(username string, CID string, mtime string) error {
+	return m.updateUserConvo(username, CID, func(mtimes, unread map[interface{}]interface{}) {
+		mtimes[CID] = mtime
+	})
+}
+
+func (m *MemoryStore) DeleteUser(username string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToUpper(username)
+	_, existed := m.users[key]
+	delete(m.users, key)
+	delete(m.generations, key)
+	return existed
+}
+
+// presence is kept as bins, the same as aerospike has them
+func (m *MemoryStore) SetSessionPresence(username string, session string, presence SessionPresence) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToUpper(username)
+	bins := m.usersActive[key]
+	if bins == nil {
+		bins = make(map[string]interface{})
+		m.usersActive[key] = bins
+	}
+	sessions := copyBinMap(bins[AEROSPIKE_USERS_ACTIVE_SESSIONS_BIN])
+	sessions[session] = presenceToBin(presence)
+	bins[AEROSPIKE_USERS_ACTIVE_SESSIONS_BIN] = sessions
+	bins[AEROSPIKE_USERS_ACTIVE_LAST_SEEN_BIN] = presence.Seen
+	return nil
+}
+
+func (m *MemoryStore) RemoveSessionPresence(username string, session string, lastSeen string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bins := m.usersActive[strings.ToUpper(username)]
+	if bins == nil {
+		return nil
+	}
+	sessions := copyBinMap(bins[AEROSPIKE_USERS_ACTIVE_SESSIONS_BIN])
+	delete(sessions, session)
+	bins[AEROSPIKE_USERS_ACTIVE_SESSIONS_BIN] = sessions
+	if lastSeen != "" {
+		bins[AEROSPIKE_USERS_ACTIVE_LAST_SEEN_BIN] = lastSeen
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetPresence(username string) (map[string]SessionPresence, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sessions, lastSeen := presenceFromBins(m.usersActive[strings.ToUpper(username)])
+	return sessions, lastSeen, nil
+}
+
+// ConversationStore
+func (m *MemoryStore) convo(CID string) *memoryConvo {
+	convo, ok := m.convos[CID]
+	if !ok {
+		convo = &memoryConvo{}
+		m.convos[CID] = convo
+	}
+	return convo
+}
+
+func (m *MemoryStore) GetConvoMembers(CID string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if convo, ok := m.convos[CID]; ok {
+		return copyStrings(convo.members)
+	}
+	return make([]string, 0)
+}
+
+func (m *MemoryStore) SetConvoMembers(CID string, members []string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.convo(CID).members = copyStrings(members)
+	return true
+}
+
+func (m *MemoryStore) GetConvoName(CID string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if convo, ok := m.convos[CID]; ok {
+		return convo.name
+	}
+	return ""
+}
+
+func (m *MemoryStore) SetConvoName(CID string, name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.convo(CID).name = name
+	return true
+}
+
+func (m *MemoryStore) GetConvoMtime(CID string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if convo, ok := m.convos[CID]; ok {
+		return convo.mtime
+	}
+	return ""
+}
+
+func (m *MemoryStore) SetConvoMtime(CID string, mtime string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.convo(CID).mtime = mtime
+	return true
+}
+
+func (m *MemoryStore) GetConvoFiles(CID string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if convo, ok := m.convos[CID]; ok {
+		return copyStrings(convo.files)
+	}
+	return make([]string, 0)
+}
+
+func (m *MemoryStore) SetConvoFiles(CID string, files []string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.convo(CID).files = copyStrings(files)
+	return true
+}
+
+func (m *MemoryStore) GetConvoDisabledBots(CID string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if convo, ok := m.convos[CID]; ok {
+		return copyStrings(convo.bots)
+	}
+	return make([]string, 0)
+}
+
+func (m *MemoryStore) SetConvoDisabledBots(CID string, bots []string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.convo(CID).bots = copyStrings(bots)
+	return true
+}
+
+func (m *MemoryStore) DeleteConvo(CID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, existed := m.convos[CID]
+	delete(m.convos, CID)
+	return existed
+}
+
+// MessageStore, rows are kept per conversation like the messages table
+// partitions them
+func (m *MemoryStore) AddMessage(msg MessageStruct) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if msg.MID == "" {
+		return errors.New("message in " + msg.CID + " has no MID")
+	}
+	row := ConvoRowStruct{
+		MID:         msg.MID,
+		F_username:  msg.FromUsername,
+		M_time:      parseTimeString(msg.M_time),
+		Content:     msg.Content,
+		Attachments: msg.Attachments,
+		P_MID:       msg.ParentMID,
+	}
+	rows := m.messages[msg.CID]
+	// primary key is (cid, mid), (cid, f_username, m_time) is unique too
+	for _, e := range rows {
+		if e.MID == row.MID || (e.F_username == row.F_username && e.M_time.Equal(row.M_time)) {
+			return errors.New("duplicate message in " + msg.CID)
+		}
+	}
+	m.messages[msg.CID] = append(rows, row)
+	if i := m.messageIndex(msg.CID, msg.ParentMID); msg.ParentMID != "" && i >= 0 {
+		parent := &m.messages[msg.CID][i]
+		parent.ReplyCount++
+		if parent.LastReply == nil || parent.LastReply.Before(row.M_time) {
+			lastReply := row.M_time
+			parent.LastReply = &lastReply
+		}
+	}
+	return nil
+}
+
+// copy of the conversation rows in parentMID's thread sorted oldest first,
+// with their reactions.  Rows that aren't replies have parentMID ""
+func (m *MemoryStore) sortedMessages(CID string, parentMID string) []ConvoRowStruct {
+	sorted := make([]ConvoRowStruct, 0)
+	for _, row := range m.messages[CID] {
+		if row.P_MID == parentMID {
+			sorted = append(sorted, row)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		// replies page on (m_time, mid), see GetReplies
+		if parentMID != "" && sorted[i].M_time.Equal(sorted[j].M_time) {
+			return sorted[i].MID < sorted[j].MID
+		}
+		return sorted[i].M_time.Before(sorted[j].M_time)
+	})
+	for i, row := range sorted {
+		for _, reaction := range m.reactions[CID+"/"+row.MID] {
+			sorted[i].Reactions = addReaction(sorted[i].Reactions, reaction.username, reaction.emoji)
+		}
+	}
+	return sorted
+}
+
+func (m *MemoryStore) GetMessagesSince(CID string, mtime string) ([]ConvoRowStruct, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	since := parseTimeString(mtime)
+	retMessages := make([]ConvoRowStruct, 0)
+	for _, row := range m.sortedMessages(CID, "") {
+		if row.M_time.After(since) {
+			retMessages = append(retMessages, row)
+		}
+	}
+	return retMessages, nil
+}
+
+func (m *MemoryStore) GetLatestMessages(CID string, limit int) ([]ConvoRowStruct, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sorted := m.sortedMessages(CID, "")
+	if len(sorted) > limit {
+		sorted = sorted[len(sorted)-limit:]
+	}
+	return sorted, nil
+}
+
+func (m *MemoryStore) GetMessagesBefore(CID string, mtime string, limit int) ([]ConvoRowStruct, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sorted := m.sortedMessages(CID, "")
+	before := parseTimeString(mtime)
+	retMessages := make([]ConvoRowStruct, 0)
+	for i := len(sorted) - 1; i >= 0 && len(retMessages) < limit; i-- {
+		if sorted[i].M_time.Before(before) {
+			retMessages = append(retMessages, sorted[i])
+		}
+	}
+	return retMessages, nil
+}
+
+func (m *MemoryStore) GetAllMessages(CID string) ([]ConvoRowStruct, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sortedMessages(CID, ""), nil
+}
+
+func (m *MemoryStore) GetReplies(CID string, parentMID string, mtime string, afterMID string, limit int) ([]ConvoRowStruct, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	after := parseTimeString(mtime)
+	retMessages := make([]ConvoRowStruct, 0)
+	for _, row := range m.sortedMessages(CID, parentMID) {
+		later := row.M_time.After(after) || (afterMID != "" && row.M_time.Equal(after) && row.MID > afterMID)
+		if later && len(retMessages) < limit {
+			retMessages = append(retMessages, row)
+		}
+	}
+	return retMessages, nil
+}
+
+func (m *MemoryStore) GetThreadParticipants(CID string, parentMID string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seen := make(map[string]bool)
+	participants := make([]string, 0)
+	for _, row := range m.messages[CID] {
+		if (row.MID == parentMID || row.P_MID == parentMID) && !row.Deleted && !seen[row.F_username] {
+			seen[row.F_username] = true
+			participants = append(participants, row.F_username)
+		}
+	}
+	return participants, nil
+}
+
+func (m *MemoryStore) SearchMessages(search MessageSearch) ([]SearchResultStruct, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	query := parseSearchQuery(search.Query)
+	after, before := parseTimeString(search.After), parseTimeString(search.Before)
+	results := make([]SearchResultStruct, 0)
+	for _, CID := range search.CIDs {
+		for _, row := range m.messages[CID] {
+			if row.Deleted || (search.From != "" && !strings.EqualFold(row.F_username, search.From)) ||
+				(search.After != "" && row.M_time.Before(after)) || (search.Before != "" && !row.M_time.Before(before)) {
+				continue
+			}
+			if rank := query.rank(row.Content); rank > 0 {
+				results = append(results, SearchResultStruct{CID: CID, MID: row.MID, F_username: row.F_username, M_time: row.M_time, Rank: rank, Content: row.Content})
+			}
+		}
+	}
+	return pageSearchResults(results, search), nil
+}
+
+// index of MID in CID's rows, -1 if it isn't there
+func (m *MemoryStore) messageIndex(CID string, MID string) int {
+	for i, row := range m.messages[CID] {
+		if row.MID == MID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *MemoryStore) GetMessage(CID string, MID string) (ConvoRowStruct, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.messageIndex(CID, MID)
+	if i < 0 {
+		return ConvoRowStruct{}, ErrNoMessage
+	}
+	return m.messages[CID][i], nil
+}
+
+func (m *MemoryStore) EditMessage(CID string, MID string, content string, etime string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.messageIndex(CID, MID)
+	if i < 0 || m.messages[CID][i].Deleted {
+		return ErrNoMessage
+	}
+	edited := parseTimeString(etime)
+	row := m.messages[CID][i]
+	m.edits[CID+"/"+MID] = append(m.edits[CID+"/"+MID], MessageEditStruct{Content: row.Content, E_time: edited})
+	row.Content = content
+	row.E_time = &edited
+	m.messages[CID][i] = row
+	return nil
+}
+
+func (m *MemoryStore) DeleteMessage(CID string, MID string, etime string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.messageIndex(CID, MID)
+	if i < 0 || m.messages[CID][i].Deleted {
+		return ErrNoMessage
+	}
+	deleted := parseTimeString(etime)
+	row := m.messages[CID][i]
+	row.Content = ""
+	row.Attachments = nil
+	row.Deleted = true
+	row.E_time = &deleted
+	m.messages[CID][i] = row
+	delete(m.edits, CID+"/"+MID)
+	delete(m.reactions, CID+"/"+MID)
+	if p := m.messageIndex(CID, row.P_MID); row.P_MID != "" && p >= 0 {
+		// the thread summary only counts the replies that are left
+		parent := &m.messages[CID][p]
+		if parent.ReplyCount > 0 {
+			parent.ReplyCount--
+		}
+		parent.LastReply = nil
+		for _, reply := range m.messages[CID] {
+			if reply.P_MID == row.P_MID && !reply.Deleted && (parent.LastReply == nil || parent.LastReply.Before(reply.M_time)) {
+				lastReply := reply.M_time
+				parent.LastReply = &lastReply
+			}
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetMessageEdits(CID string, MID string) ([]MessageEditStruct, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append(make([]MessageEditStruct, 0), m.edits[CID+"/"+MID]...), nil
+}
+
+func (m *MemoryStore) AddAttachments(CID string, MID string, attachments []MessageAttachment) ([]MessageAttachment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.messageIndex(CID, MID)
+	if i < 0 || m.messages[CID][i].Deleted {
+		return nil, ErrNoMessage
+	}
+	row := &m.messages[CID][i]
+	// a new slice, rows handed out earlier keep theirs
+	row.Attachments = append(append([]MessageAttachment(nil), row.Attachments...), attachments...)
+	return row.Attachments, nil
+}
+
+func (m *MemoryStore) AddReaction(CID string, MID string, username string, emoji string, rtime string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.messageIndex(CID, MID)
+	if i < 0 || m.messages[CID][i].Deleted {
+		return nil
+	}
+	key := CID + "/" + MID
+	for _, reaction := range m.reactions[key] {
+		if reaction.username == username && reaction.emoji == emoji {
+			return nil
+		}
+	}
+	m.reactions[key] = append(m.reactions[key], memoryReaction{username, emoji})
+	return nil
+}
+
+func (m *MemoryStore) RemoveReaction(CID string, MID string, username string, emoji string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := CID + "/" + MID
+	kept := make([]memoryReaction, 0, len(m.reactions[key]))
+	for _, reaction := range m.reactions[key] {
+		if reaction.username != username || reaction.emoji != emoji {
+			kept = append(kept, reaction)
+		}
+	}
+	m.reactions[key] = kept
+	return nil
+}
+
+func (m *MemoryStore) AddScheduledMessage(msg ScheduledMessagesCmdStruct) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	msg.Cmd = ""
+	m.scheduled = append(m.scheduled, msg)
+	return nil
+}
+
+func (m *MemoryStore) RemoveScheduledMessage(msg ScheduledMessagesCmdStruct) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	msgTime := parseTimeString(msg.Time)
+	kept := m.scheduled[:0]
+	for _, e := range m.scheduled {
+		if e.CID == msg.CID && e.Username == msg.Username && e.Content == msg.Content && parseTimeString(e.Time).Equal(msgTime) {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	m.scheduled = kept
+	return nil
+}
+
+func (m *MemoryStore) RemoveAllScheduledMessages(username string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kept := m.scheduled[:0]
+	for _, e := range m.scheduled {
+		if e.Username != username {
+			kept = append(kept, e)
+		}
+	}
+	m.scheduled = kept
+	return nil
+}
+
+func (m *MemoryStore) TakeDueScheduledMessages(t time.Time) ([]ScheduledMessagesCmdStruct, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	due := make([]ScheduledMessagesCmdStruct, 0)
+	kept := make([]ScheduledMessagesCmdStruct, 0, len(m.scheduled))
+	for _, e := range m.scheduled {
+		scheduledTime := parseTimeString(e.Time)
+		if scheduledTime.Before(t) {
+			e.Time = scheduledTime.Format(time.RFC3339Nano)
+			due = append(due, e)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	m.scheduled = kept
+	return due, nil
+}
+
+// EmailStore, mailboxes are keyed by upper case username
+func (m *MemoryStore) CreateMailbox(username string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToUpper(username)
+	if _, ok := m.mailboxes[key]; ok {
+		return errors.New("mailbox for " + username + " already exists")
+	}
+	m.mailboxes[key] = make([]EmailRowStruct, 0)
+	return nil
+}
+
+func (m *MemoryStore) DropMailbox(username string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToUpper(username)
+	if _, ok := m.mailboxes[key]; !ok {
+		return errors.New("mailbox for " + username + " does not exist")
+	}
+	delete(m.mailboxes, key)
+	return nil
+}
+
+func (m *MemoryStore) addEmail(username string, email EmailRowStruct) error {
+	key := strings.ToUpper(username)
+	mailbox, ok := m.mailboxes[key]
+	if !ok {
+		return errors.New("mailbox for " + username + " does not exist")
+	}
+	// primary key is (from_email, recv_time)
+	for _, e := range mailbox {
+		if e.FromEmail == email.FromEmail && e.RecvTime.Equal(email.RecvTime) {
+			return errors.New("duplicate email in mailbox for " + username)
+		}
+	}
+	m.mailboxes[key] = append(mailbox, email)
+	return nil
+}
+
+func (m *MemoryStore) AddEmail(username string, email EmailRowStruct) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.addEmail(username, email)
+}
+
+func (m *MemoryStore) GetEmails(username string, query EmailQuery) ([]EmailRowStruct, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	retEmails := make([]EmailRowStruct, 0)
+	mailbox, ok := m.mailboxes[strings.ToUpper(username)]
+	if !ok {
+		return retEmails, errors.New("mailbox for " + username + " does not exist")
+	}
+	for _, e := range mailbox {
+		if query.matches(e) {
+			retEmails = append(retEmails, e)
+		}
+	}
+	return pageEmails(retEmails, query), nil
+}
+
+func (m *MemoryStore) SetEmailFlag(username string, key EmailKey, flag string, value bool, mtime string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mailbox, ok := m.mailboxes[strings.ToUpper(username)]
+	if !ok {
+		return errors.New("mailbox for " + username + " does not exist")
+	}
+	recvTime := parseTimeString(key.RecvTime)
+	for i, e := range mailbox {
+		if e.FromEmail != key.FromEmail || e.Subject != key.Subject || !e.RecvTime.Equal(recvTime) {
+			continue
+		}
+		switch flag {
+		case EMAIL_FLAG_UNREAD:
+			mailbox[i].Unread = value
+		case EMAIL_FLAG_STARRED:
+			mailbox[i].Starred = value
+		case EMAIL_FLAG_DELETED:
+			mailbox[i].Deleted = value
+		default:
+			return errors.New("unknown email flag " + flag)
+		}
+		mailbox[i].M_time = parseTimeString(mtime)
+	}
+	return nil
+}
+
+func (m *MemoryStore) SaveDraft(username string, email EmailRowStruct) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToUpper(username)
+	mailbox, ok := m.mailboxes[key]
+	if !ok {
+		return errors.New("mailbox for " + username + " does not exist")
+	}
+	// first remove draft that may be there
+	kept := mailbox[:0]
+	for _, e := range mailbox {
+		if !(e.Draft && e.RecvTime.Equal(email.RecvTime)) {
+			kept = append(kept, e)
+		}
+	}
+	m.mailboxes[key] = kept
+	email.Draft = true
+	return m.addEmail(username, email)
+}
+
+func (m *MemoryStore) RemoveDeletedEmails(username string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToUpper(username)
+	mailbox, ok := m.mailboxes[key]
+	if !ok {
+		return errors.New("mailbox for " + username + " does not exist")
+	}
+	kept := mailbox[:0]
+	for _, e := range mailbox {
+		if !e.Deleted {
+			kept = append(kept, e)
+		}
+	}
+	m.mailboxes[key] = kept
+	return nil
+}
+
+// ChangeStore, logs are keyed by upper case username
+func (m *MemoryStore) AppendChange(username string, kind string, event string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToUpper(username)
+	m.changeSeqs[key]++
+	change := ChangeStruct{
+		Seq:    m.changeSeqs[key],
+		Kind:   kind,
+		Event:  []byte(event),
+		C_time: time.Now().UTC(),
+	}
+	log := append(m.changes[key], change)
+	if len(log) > CHANGE_LOG_SIZE {
+		log = append([]ChangeStruct(nil), log[len(log)-CHANGE_LOG_SIZE:]...)
+	}
+	m.changes[key] = log
+	return change.Seq, nil
+}
+
+func (m *MemoryStore) GetChanges(username string, after int64, limit int) ([]ChangeStruct, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	changes := make([]ChangeStruct, 0)
+	for _, change := range m.changes[strings.ToUpper(username)] {
+		if change.Seq > after && len(changes) < limit {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func (m *MemoryStore) LastChange(username string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.changeSeqs[strings.ToUpper(username)], nil
+}
+
+func (m *MemoryStore) DeleteChanges(username string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.changes, strings.ToUpper(username))
+	return nil
+}
+
+// DeviceQueueStore, queues are kept by deviceQueueKey
+func deviceQueueKey(username string, device string) string {
+	return strings.ToUpper(username) + "/" + device
+}
+
+func (m *MemoryStore) EnqueueDeviceEvent(username string, device string, event string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := deviceQueueKey(username, device)
+	m.deviceSeqs[key]++
+	now := time.Now().UTC()
+	queued := DeviceEventStruct{Seq: m.deviceSeqs[key], Event: event, Q_time: now}
+	kept := make([]DeviceEventStruct, 0, len(m.deviceQueue[key])+1)
+	for _, e := range append(m.deviceQueue[key], queued) {
+		if e.Seq > queued.Seq-DEVICE_QUEUE_SIZE && !e.Q_time.Before(now.Add(-DEVICE_QUEUE_RETENTION)) {
+			kept = append(kept, e)
+		}
+	}
+	m.deviceQueue[key] = kept
+	return queued.Seq, nil
+}
+
+func (m *MemoryStore) GetDeviceEvents(username string, device string, after int64, limit int) ([]DeviceEventStruct, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	events := make([]DeviceEventStruct, 0)
+	for _, e := range m.deviceQueue[deviceQueueKey(username, device)] {
+		if e.Seq > after && len(events) < limit {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (m *MemoryStore) LastDeviceEvent(username string, device string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deviceSeqs[deviceQueueKey(username, device)], nil
+}
+
+func (m *MemoryStore) AckDeviceEvents(username string, device string, seq int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := deviceQueueKey(username, device)
+	kept := make([]DeviceEventStruct, 0)
+	for _, e := range m.deviceQueue[key] {
+		if e.Seq > seq {
+			kept = append(kept, e)
+		}
+	}
+	m.deviceQueue[key] = kept
+	return nil
+}
+
+func (m *MemoryStore) DeleteDeviceQueue(username string, device string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := deviceQueueKey(username, device)
+	delete(m.deviceQueue, key)
+	delete(m.deviceSeqs, key)
+	return nil
+}
+
+// EventBus.  Like nats, publishing never blocks: every subscription has its
+// own queue drained into the subscribed channel in order, and a subscriber
+// that falls too far behind loses messages.
+const memorySubscriptionQueueSize = 1024
+
+type memorySubscription struct {
+	store   *MemoryStore
+	subject string
+	queue   chan string
+	done    chan struct{}
+	exited  chan struct{}
+	once    sync.Once
+}
+
+func (m *MemoryStore) Publish(subject string, content string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, sub := range m.subscriptions[subject] {
+		select {
+		case sub.queue <- content:
+		default:
+			ERROR.Println("memory event bus dropping message for slow subscriber on " + subject)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) Subscribe(subject string, ch chan string) (EventSubscription, error) {
+	sub := &memorySubscription{
+		store:   m,
+		subject: subject,
+		queue:   make(chan string, memorySubscriptionQueueSize),
+		done:    make(chan struct{}),
+		exited:  make(chan struct{}),
+	}
+	m.mu.Lock()
+	m.subscriptions[subject] = append(m.subscriptions[subject], sub)
+	m.mu.Unlock()
+	go sub.pump(ch)
+	return sub, nil
+}
+
+func (sub *memorySubscription) pump(ch chan string) {
+	defer close(sub.exited)
+	for {
+		select {
+		case msg := <-sub.queue:
+			select {
+			case ch <- msg:
+			case <-sub.done:
+				return
+			}
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+// Unsubscribe waits for the queue to stop feeding the channel, so the
+// channel can be closed safely afterwards
+func (sub *memorySubscription) Unsubscribe() error {
+	sub.once.Do(func() {
+		m := sub.store
+		m.mu.Lock()
+		subs := m.subscriptions[sub.subject]
+		for i, e := range subs {
+			if e == sub {
+				m.subscriptions[sub.subject] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		if len(m.subscriptions[sub.subject]) == 0 {
+			delete(m.subscriptions, sub.subject)
+		}
+		m.mu.Unlock()
+		close(sub.done)
+		<-sub.exited
+	})
+	return nil
+}
@@ -0,0 +1,134 @@
+package pcDatabase
+
+import (
+	"errors"
+	"github.com/pquerna/ffjson/ffjson"
+	"strings"
+)
+
+// MESSAGE EDITS
+// whoever sent a message can change what it says (EditMessage) or take it
+// back (DeleteMessage), going by the MID SendMessage gave it.  An edit keeps
+// what the message said before (GetMessageEdits), a delete leaves the row
+// with no content and deleted set so clients know to blank it out.  Both are
+// sent to every member's web devices, as MessageEdited and MessageDeleted.
+
+var (
+	// from the MessageStore, there's no message with that MID
+	ErrNoMessage       = errors.New("no such message")
+	ErrMessageNotFound = cmdError(ERR_NOT_FOUND, "there's no message with that ID")
+	ErrNotAuthor       = cmdError(ERR_FORBIDDEN, "only the sender can change a message")
+)
+
+type MessageEditedEvent struct {
+	Cmd     string `json:"cmd"`
+	CID     string `json:"CID"`
+	MID     string `json:"MID"`
+	Content string `json:"content"`
+	E_time  string `json:"e_time"`
+}
+
+type MessageDeletedEvent struct {
+	Cmd    string `json:"cmd"`
+	CID    string `json:"CID"`
+	MID    string `json:"MID"`
+	E_time string `json:"e_time"`
+}
+
+// sends event to the web devices of everyone in CID, it's logged as a
+// change to the messages
+func (db *Database) sendToConvoMembers(CID string, event interface{}) {
+	eventBytes, err := ffjson.Marshal(event)
+	if err != nil {
+		ERROR.Println("error in ffjson.Marshal in sendToConvoMembers:", err)
+		return
+	}
+	for _, m := range ToConvoMemberArray(db.Convos.GetConvoMembers(CID)) {
+		user := db.Users.GetUser(m.Username)
+		db.sendChangeString(user, CHANGE_MESSAGE, string(eventBytes))
+	}
+}
+
+// the message, as long as it's there and the caller sent it
+func (db *Database) authoredMessage(CID string, MID string) (ConvoRowStruct, error) {
+	if db.Messages == nil {
+		return ConvoRowStruct{}, ErrMessagesDown
+	}
+	row, err := db.Messages.GetMessage(CID, MID)
+	if err == ErrNoMessage || (err == nil && row.Deleted) {
+		return row, ErrMessageNotFound
+	} else if err != nil {
+		logPqError("authoredMessage", err)
+		return row, ErrMessagesDown
+	}
+	if strings.ToUpper(row.F_username) != strings.ToUpper(db.Username()) {
+		return row, ErrNotAuthor
+	}
+	return row, nil
+}
+
+// from EditMessage and DeleteMessage in the MessageStore
+func messageChangeError(where string, err error) error {
+	if err == ErrNoMessage {
+		return ErrMessageNotFound // deleted in the meantime
+	}
+	logPqError(where, err)
+	return ErrMessagesDown
+}
+
+func (db *Database) EditMessage(jsondata *MessageCmdStruct) error {
+	if _, err := db.authoredMessage(jsondata.CID, jsondata.MID); err != nil {
+		return err
+	}
+	jsondata.Content = sanitizeMarkup(jsondata.Content)
+	etime := getCurrentUTCISOTimeString()
+	if err := db.Messages.EditMessage(jsondata.CID, jsondata.MID, jsondata.Content, etime); err != nil {
+		return messageChangeError("EditMessage", err)
+	}
+	db.sendToConvoMembers(jsondata.CID, MessageEditedEvent{
+		Cmd:     "MessageEdited",
+		CID:     jsondata.CID,
+		MID:     jsondata.MID,
+		Content: jsondata.Content,
+		E_time:  etime,
+	})
+	return nil
+}
+
+func (db *Database) DeleteMessage(jsondata *MessageCmdStruct) error {
+	if _, err := db.authoredMessage(jsondata.CID, jsondata.MID); err != nil {
+		return err
+	}
+	etime := getCurrentUTCISOTimeString()
+	if err := db.Messages.DeleteMessage(jsondata.CID, jsondata.MID, etime); err != nil {
+		return messageChangeError("DeleteMessage", err)
+	}
+	db.sendToConvoMembers(jsondata.CID, MessageDeletedEvent{
+		Cmd:    "MessageDeleted",
+		CID:    jsondata.CID,
+		MID:    jsondata.MID,
+		E_time: etime,
+	})
+	return nil
+}
+
+// GetMessageEdits is what the message said before each edit, any member of
+// the conversation can see them
+func (db *Database) GetMessageEdits(jsondata *MessageCmdStruct) (*MessageEditsResponse, error) {
+	if db.Messages == nil {
+		return nil, ErrMessagesDown
+	}
+	row, err := db.Messages.GetMessage(jsondata.CID, jsondata.MID)
+	if err == ErrNoMessage || (err == nil && row.Deleted) {
+		return nil, ErrMessageNotFound
+	} else if err != nil {
+		logPqError("GetMessageEdits", err)
+		return nil, ErrMessagesDown
+	}
+	edits, err := db.Messages.GetMessageEdits(jsondata.CID, jsondata.MID)
+	if err != nil {
+		logPqError("GetMessageEdits", err)
+		return nil, ErrMessagesDown
+	}
+	return &MessageEditsResponse{CID: jsondata.CID, MID: jsondata.MID, Edits: edits}, nil
+}
@@ -0,0 +1,81 @@
+package pcDatabase
+
+import (
+	"strings"
+	"testing"
+)
+
+// alice and bob in a conversation with one message from alice, alice is
+// logged in on db
+func sendTestMessage(t *testing.T, db *Database) (CID string, MID string) {
+	createTestUser(t, db, "bob")
+	createTestUser(t, db, "alice")
+	CID = createTestConversation(t, db, "alice", "bob")
+	if env := dispatch(t, db, `{"cmd":"SendMessage","CID":"`+CID+`","t_UIDs":["alice","bob"],"m_time":"2015-06-12T19:20:00.000Z","content":"hi bbo"}`, nil); !env.OK {
+		t.Fatalf("SendMessage returned %+v", env)
+	}
+	expectEvents(t, db, 2)
+	rows, _ := db.Messages.GetAllMessages(CID)
+	if len(rows) != 1 || rows[0].MID == "" {
+		t.Fatalf("conversation has messages %+v", rows)
+	}
+	return CID, rows[0].MID
+}
+
+func TestEditMessage(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	CID, MID := sendTestMessage(t, db)
+
+	if env := dispatch(t, db, `{"cmd":"EditMessage","CID":"`+CID+`","MID":"`+MID+`","content":"hi bob"}`, nil); !env.OK {
+		t.Fatalf("EditMessage returned %+v", env)
+	}
+	for _, event := range expectEvents(t, db, 2) {
+		if !strings.Contains(event, `"MessageEdited"`) || !strings.Contains(event, MID) {
+			t.Errorf("unexpected event %s", event)
+		}
+	}
+	row, _ := db.Messages.GetMessage(CID, MID)
+	if row.Content != "hi bob" || row.E_time == nil {
+		t.Errorf("edited message is %+v", row)
+	}
+	edits := MessageEditsResponse{}
+	dispatch(t, db, `{"cmd":"GetMessageEdits","CID":"`+CID+`","MID":"`+MID+`"}`, &edits)
+	if len(edits.Edits) != 1 || edits.Edits[0].Content != "hi bbo" {
+		t.Errorf("message edits are %+v", edits)
+	}
+
+	// only alice can change it
+	bob := newTestConnection(db)
+	defer bob.Close()
+	dispatch(t, bob, `{"cmd":"ValidateUser","username":"bob","password":"secret","token":"bob-phone"}`, nil)
+	expectError(t, bob, `{"cmd":"EditMessage","CID":"`+CID+`","MID":"`+MID+`","content":"hi alice"}`, ERR_FORBIDDEN)
+	expectError(t, bob, `{"cmd":"DeleteMessage","CID":"`+CID+`","MID":"`+MID+`"}`, ERR_FORBIDDEN)
+	expectError(t, db, `{"cmd":"EditMessage","CID":"`+CID+`","MID":"nope","content":"hi"}`, ERR_NOT_FOUND)
+}
+
+func TestDeleteMessage(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	CID, MID := sendTestMessage(t, db)
+	dispatch(t, db, `{"cmd":"EditMessage","CID":"`+CID+`","MID":"`+MID+`","content":"hi bob"}`, nil)
+	expectEvents(t, db, 2)
+
+	if env := dispatch(t, db, `{"cmd":"DeleteMessage","CID":"`+CID+`","MID":"`+MID+`"}`, nil); !env.OK {
+		t.Fatalf("DeleteMessage returned %+v", env)
+	}
+	for _, event := range expectEvents(t, db, 2) {
+		if !strings.Contains(event, `"MessageDeleted"`) || !strings.Contains(event, MID) {
+			t.Errorf("unexpected event %s", event)
+		}
+	}
+	convoData, _ := db.GetConvoData(&CIDCommandStruct{Cmd: "GetConvoData", CID: CID})
+	if len(convoData.Messages) != 1 || !convoData.Messages[0].Deleted || convoData.Messages[0].Content != "" {
+		t.Errorf("GetConvoData returned messages %+v", convoData.Messages)
+	}
+	if edits, _ := db.Messages.GetMessageEdits(CID, MID); len(edits) != 0 {
+		t.Errorf("the deleted message still has edits %+v", edits)
+	}
+	expectError(t, db, `{"cmd":"EditMessage","CID":"`+CID+`","MID":"`+MID+`","content":"hi again"}`, ERR_NOT_FOUND)
+	expectError(t, db, `{"cmd":"DeleteMessage","CID":"`+CID+`","MID":"`+MID+`"}`, ERR_NOT_FOUND)
+}
@@ -0,0 +1,108 @@
+package pcDatabase
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// moving messages out of the old one table per conversation layout
+// (a table named after each CID) into the messages table.
+//
+// this is done online by the migrate-messages subcommand, never on reads.
+// MigrateLegacyConvoTables copies every old table in one go and can be run
+// again to pick up what servers still running the old code wrote there
+// during a rolling deploy.  With drop set it also drops them, which should
+// only be done once no old servers are left.
+
+// a CID is a uuid, uuid.CleanHyphen format
+const legacyConvoTablePattern = `^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`
+
+// copies a conversation's old table into the messages table, if it still
+// exists.  Message IDs are derived from (cid, f_username, m_time) so copying
+// the same rows twice doesn't duplicate anything.
+func (s *PostgresStore) copyLegacyConvoTable(CID string, drop bool) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	var tableName sql.NullString
+	if err := s.conn.QueryRow(`SELECT to_regclass($1)::text;`, convoTableName(CID)).Scan(&tableName); err != nil {
+		return err
+	}
+	if !tableName.Valid {
+		return nil // already migrated, or never existed
+	}
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return err
+	}
+	if drop {
+		// no more writes to the old table until it's gone
+		if _, err = tx.Exec(`LOCK TABLE ` + convoTableName(CID) + ` IN EXCLUSIVE MODE;`); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	// the old tables are HTML, markup is left false for those and search is
+	// made from the HTML like it is for the other HTML rows
+	CurString := `INSERT INTO ` + POSTGRES_MESSAGES_TABLE + ` (cid, mid, f_username, content, m_time, search) ` +
+		`SELECT $1::varchar, md5($1::varchar || '/' || f_username || '/' || extract(epoch FROM m_time)::text)::uuid::varchar, f_username, content, m_time, to_tsvector(` + searchConfig + `, content) FROM ` + convoTableName(CID) +
+		` ON CONFLICT DO NOTHING;`
+	if _, err = tx.Exec(CurString, CID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if drop {
+		if _, err = tx.Exec(`DROP TABLE ` + convoTableName(CID) + `;`); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// CIDs that still have an old per conversation table
+func (s *PostgresStore) LegacyConvoTables() ([]string, error) {
+	CIDs := make([]string, 0)
+	if s.conn == nil {
+		return CIDs, errNoPostgres
+	}
+	rows, err := s.conn.Query(`SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() AND table_type = 'BASE TABLE' AND table_name ~ $1;`, legacyConvoTablePattern)
+	if err != nil {
+		return CIDs, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var CID string
+		if err := rows.Scan(&CID); err != nil {
+			return CIDs, err
+		}
+		CIDs = append(CIDs, CID)
+	}
+	return CIDs, rows.Err()
+}
+
+// MigrateLegacyConvoTables copies every old per conversation table into the
+// messages table, dropping them afterwards if drop is set.  It's safe to run
+// while the server is up and to run again if it gets interrupted.
+// Returns how many conversations were migrated.
+func (s *PostgresStore) MigrateLegacyConvoTables(drop bool) (int, error) {
+	CIDs, err := s.LegacyConvoTables()
+	if err != nil {
+		return 0, err
+	}
+	migrated := 0
+	failed := 0
+	for _, CID := range CIDs {
+		if err := s.copyLegacyConvoTable(CID, drop); err != nil {
+			logPqError("MigrateLegacyConvoTables "+CID, err)
+			failed++
+			continue
+		}
+		migrated++
+		TRACE.Println("migrated conversation " + CID)
+	}
+	if failed > 0 {
+		return migrated, errors.New("some conversations could not be migrated, run again to retry them")
+	}
+	return migrated, nil
+}
@@ -0,0 +1,171 @@
+package pcDatabase
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// per session, a client can burst COMMAND_BURST commands and then send
+	// COMMAND_RATE a second
+	COMMAND_RATE  = 20
+	COMMAND_BURST = 50
+)
+
+var (
+	ErrNotLoggedIn = cmdError(ERR_NOT_LOGGED_IN, "log in first")
+	ErrRateLimited = cmdError(ERR_RATE_LIMITED, "too many commands, slow down")
+	ErrNotYou      = cmdError(ERR_FORBIDDEN, "you can only do that as yourself")
+	ErrNotMember   = cmdError(ERR_FORBIDDEN, "you aren't in that conversation")
+)
+
+// RequireLogin rejects everything but the public commands until the session
+// has logged in
+func RequireLogin(next Handler) Handler {
+	return func(db *Database, call *Call) (interface{}, error) {
+		if !call.Command.Public && db.Username() == "" {
+			return nil, ErrNotLoggedIn
+		}
+		return next(db, call)
+	}
+}
+
+// BindIdentity makes the logged in user the caller.  The command's Identity
+// fields are set to the session's username, a client that leaves them out
+// gets it filled in and one that names someone else is rejected, so handlers
+// can trust them.  Then Member commands check the caller is in the CID.
+func BindIdentity(next Handler) Handler {
+	return func(db *Database, call *Call) (interface{}, error) {
+		username := db.Username()
+		if username == "" {
+			return next(db, call) // public command before logging in
+		}
+		req := reflect.ValueOf(call.Request).Elem()
+		for _, field := range call.Command.Identity {
+			v := req.FieldByName(field)
+			if strings.TrimSpace(v.String()) != "" && !strings.EqualFold(v.String(), username) {
+				return nil, ErrNotYou
+			}
+			v.SetString(username)
+		}
+		if call.Command.Member && !db.IsConvoMember(req.FieldByName("CID").String(), username) {
+			return nil, ErrNotMember
+		}
+		return next(db, call)
+	}
+}
+
+// LogCommands traces every command with how long it took
+func LogCommands(next Handler) Handler {
+	return func(db *Database, call *Call) (interface{}, error) {
+		startTime := time.Now()
+		resp, err := next(db, call)
+		if err != nil {
+			ERROR.Printf("%s for %q failed after %v: %v", call.Command.Name, db.Username(), time.Since(startTime), err)
+		} else {
+			TRACE.Printf("%s for %q took %v", call.Command.Name, db.Username(), time.Since(startTime))
+		}
+		return resp, err
+	}
+}
+
+// RateLimit gives each session a token bucket of burst commands refilled at
+// rate a second, commands over the limit are rejected without running
+func RateLimit(rate float64, burst int) Middleware {
+	return func(next Handler) Handler {
+		return func(db *Database, call *Call) (interface{}, error) {
+			if db.limiter == nil {
+				db.limiter = newTokenBucket(rate, burst)
+			}
+			if !db.limiter.take(time.Now()) {
+				return nil, ErrRateLimited
+			}
+			return next(db, call)
+		}
+	}
+}
+
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+func (b *tokenBucket) take(now time.Time) bool {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// METRICS
+
+// CommandStats are the totals for one command since startup
+type CommandStats struct {
+	Calls     int64
+	Errors    int64
+	TotalTime time.Duration
+	MaxTime   time.Duration
+}
+
+// Metrics counts calls, errors and time spent per command, shared by every
+// session
+type Metrics struct {
+	mu    sync.Mutex
+	stats map[string]*CommandStats
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{stats: make(map[string]*CommandStats)}
+}
+
+func (m *Metrics) Middleware(next Handler) Handler {
+	return func(db *Database, call *Call) (interface{}, error) {
+		startTime := time.Now()
+		resp, err := next(db, call)
+		elapsed := time.Since(startTime)
+
+		m.mu.Lock()
+		stats, ok := m.stats[call.Command.Name]
+		if !ok {
+			stats = &CommandStats{}
+			m.stats[call.Command.Name] = stats
+		}
+		stats.Calls++
+		if err != nil {
+			stats.Errors++
+		}
+		stats.TotalTime += elapsed
+		if elapsed > stats.MaxTime {
+			stats.MaxTime = elapsed
+		}
+		m.mu.Unlock()
+		return resp, err
+	}
+}
+
+// Snapshot copies the current stats
+func (m *Metrics) Snapshot() map[string]CommandStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make(map[string]CommandStats, len(m.stats))
+	for name, stats := range m.stats {
+		snapshot[name] = *stats
+	}
+	return snapshot
+}
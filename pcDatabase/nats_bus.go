@@ -0,0 +1,53 @@
+package pcDatabase
+
+import (
+	"errors"
+	"github.com/apcera/nats"
+)
+
+// NatsEventBus is the EventBus backed by gnatsd
+type NatsEventBus struct {
+	conn        *nats.Conn
+	encodedconn *nats.EncodedConn
+}
+
+func NewNatsEventBus(conn *nats.Conn, encodedconn *nats.EncodedConn) *NatsEventBus {
+	return &NatsEventBus{conn: conn, encodedconn: encodedconn}
+}
+
+func (b *NatsEventBus) Publish(subject string, content string) error {
+	if b.encodedconn == nil {
+		return nil // don't do anything if nats is nil!
+	}
+	return b.encodedconn.Publish(subject, content)
+}
+
+func (b *NatsEventBus) Subscribe(subject string, ch chan string) (EventSubscription, error) {
+	if b.encodedconn == nil {
+		return nil, errors.New("no nats connection")
+	}
+	sub, err := b.encodedconn.BindRecvChan(subject, ch)
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Flush round trips to gnatsd, so this fails while it's unreachable
+func (b *NatsEventBus) Ping() error {
+	if b.conn == nil || b.conn.IsClosed() {
+		return errors.New("no nats connection")
+	}
+	return b.conn.Flush()
+}
+
+func (b *NatsEventBus) Close() {
+	if b.conn != nil {
+		b.conn.Close()
+		b.conn = nil
+	}
+	if b.encodedconn != nil {
+		b.encodedconn.Close()
+		b.encodedconn = nil
+	}
+}
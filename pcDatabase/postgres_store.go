@@ -0,0 +1,817 @@
+package pcDatabase
+
+import (
+	"crypto/md5"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"pingedchat/config"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	POSTGRES_SCHEDULED_MESSAGES_TABLE = "ScheduledMessagesTable"
+	POSTGRES_MESSAGES_TABLE           = "messages"
+	POSTGRES_MESSAGE_EDITS_TABLE      = "message_edits"
+	POSTGRES_MESSAGE_REACTIONS_TABLE  = "message_reactions"
+	POSTGRES_CHANGES_TABLE            = "user_changes"
+	POSTGRES_CHANGE_SEQS_TABLE        = "user_change_seqs"
+	POSTGRES_DEVICE_EVENTS_TABLE      = "device_events"
+	POSTGRES_DEVICE_SEQS_TABLE        = "device_event_seqs"
+
+	// the columns scanConvoRows reads
+	convoRowColumns = "mid, f_username, content, attachments, markup, m_time, e_time, deleted, p_mid, reply_count, last_reply"
+
+	// only rows that aren't replies, those are in GetReplies
+	notReply = " AND p_mid IS NULL"
+
+	// the text search configuration of the search column, see search.go
+	searchConfig = "'simple'"
+
+	// the words of an email, each mailbox has a GIN index on it
+	emailSearchVector = "to_tsvector(" + searchConfig + ", coalesce(subject, '') || ' ' || coalesce(content, '') || ' ' || from_email || ' ' || to_emails)"
+)
+
+var errNoPostgres = errors.New("no postgres connection")
+
+// PostgresStore is the MessageStore and EmailStore backed by postgres.
+// Messages of every conversation share the messages table, and every user
+// has an email table named "<username>@pinged.email".
+// The tables themselves are created by the migrations package, apart from
+// the email tables which CreateMailbox makes for every new user
+type PostgresStore struct {
+	conn *sql.DB
+}
+
+func NewPostgresStore(conn *sql.DB) *PostgresStore {
+	return &PostgresStore{conn: conn}
+}
+
+// opens a connection pool to the pingedchat database
+func OpenPostgresStore(c config.PostgresConfig) (*PostgresStore, error) {
+	conn, err := sql.Open("postgres", c.ConnectString)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetMaxOpenConns(c.MaxOpenConns)
+	conn.SetMaxIdleConns(c.MaxIdleConns)
+	return NewPostgresStore(conn), nil
+}
+
+func (s *PostgresStore) Close() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+func (s *PostgresStore) Ping() error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	return s.conn.Ping()
+}
+
+func convoTableName(CID string) string {
+	return pq.QuoteIdentifier(CID)
+}
+
+func emailTableName(username string) string {
+	return pq.QuoteIdentifier(username + `@pinged.email`)
+}
+
+// named after a hash of the table, usernames can be long enough to go past
+// postgres' 63 byte identifiers.  The migrations name them the same way
+func mailboxIndexName(username string, suffix string) string {
+	return pq.QuoteIdentifier(fmt.Sprintf("mailbox_%x_%s", md5.Sum([]byte(username+`@pinged.email`)), suffix))
+}
+
+func logPqError(where string, err error) {
+	if pqErr, ok := err.(*pq.Error); ok {
+		ERROR.Println("pq error in "+where+":", pqErr.Code.Name())
+	}
+	ERROR.Println(err)
+}
+
+// conversations
+func (s *PostgresStore) AddMessage(msg MessageStruct) error {
+	if s.conn == nil {
+		return errNoPostgres // can't do anything with no database connection :(
+	}
+	attachments, err := attachmentsColumn(msg.Attachments)
+	if err != nil {
+		return err
+	}
+	if msg.ParentMID == "" {
+		CurString := "INSERT INTO " + POSTGRES_MESSAGES_TABLE + " (cid, mid, f_username, content, attachments, markup, m_time, search) VALUES ($1, $2, $3, $4, $5, true, $6, to_tsvector(" + searchConfig + ", $4))"
+		_, err := s.conn.Exec(CurString, msg.CID, msg.MID, msg.FromUsername, msg.Content, attachments, msg.M_time)
+		return err
+	}
+	// a reply, counted on its thread's message
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return err
+	}
+	CurString := "INSERT INTO " + POSTGRES_MESSAGES_TABLE + " (cid, mid, f_username, content, attachments, markup, m_time, p_mid, search) VALUES ($1, $2, $3, $4, $5, true, $6, $7, to_tsvector(" + searchConfig + ", $4))"
+	if _, err = tx.Exec(CurString, msg.CID, msg.MID, msg.FromUsername, msg.Content, attachments, msg.M_time, msg.ParentMID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	CurString = "UPDATE " + POSTGRES_MESSAGES_TABLE + " SET reply_count = reply_count + 1, last_reply = GREATEST(last_reply, $3::timestamptz) WHERE cid = $1 AND mid = $2"
+	if _, err = tx.Exec(CurString, msg.CID, msg.ParentMID, msg.M_time); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// the attachments column, NULL without any
+func attachmentsColumn(attachments []MessageAttachment) (sql.NullString, error) {
+	if len(attachments) == 0 {
+		return sql.NullString{}, nil
+	}
+	column, err := json.Marshal(attachments)
+	return sql.NullString{String: string(column), Valid: err == nil}, err
+}
+
+// a row's content and attachments, markup is false for the rows from before
+// content was markup, those are HTML
+func storedMarkup(content string, attachments sql.NullString, markup bool) (string, []MessageAttachment) {
+	if !markup {
+		return legacyHTMLToMarkup(content)
+	}
+	var decoded []MessageAttachment
+	if attachments.Valid {
+		if err := json.Unmarshal([]byte(attachments.String), &decoded); err != nil {
+			ERROR.Println("error in json.Unmarshal of a message's attachments:", err)
+		}
+	}
+	return content, decoded
+}
+
+func scanConvoRows(rows *sql.Rows) []ConvoRowStruct {
+	defer rows.Close()
+	retMessages := make([]ConvoRowStruct, 0)
+	for rows.Next() {
+		var SQLMid string
+		var SQLF_username string
+		var SQLContent string
+		var SQLAttachments sql.NullString
+		var SQLMarkup bool
+		var SQLM_time time.Time
+		var SQLE_time *time.Time
+		var SQLDeleted bool
+		var SQLP_mid sql.NullString
+		var SQLReplyCount int
+		var SQLLastReply *time.Time
+		if err := rows.Scan(&SQLMid, &SQLF_username, &SQLContent, &SQLAttachments, &SQLMarkup, &SQLM_time, &SQLE_time, &SQLDeleted, &SQLP_mid, &SQLReplyCount, &SQLLastReply); err != nil {
+			ERROR.Println(err)
+			continue
+		}
+		content, attachments := storedMarkup(SQLContent, SQLAttachments, SQLMarkup)
+		retMessages = append(retMessages, ConvoRowStruct{
+			MID:         SQLMid,
+			F_username:  SQLF_username,
+			Content:     content,
+			Attachments: attachments,
+			M_time:      SQLM_time,
+			E_time:      SQLE_time,
+			Deleted:     SQLDeleted,
+			P_MID:       SQLP_mid.String,
+			ReplyCount:  SQLReplyCount,
+			LastReply:   SQLLastReply,
+		})
+	}
+	return retMessages
+}
+
+func (s *PostgresStore) queryConvoRows(CID string, query string, args ...interface{}) ([]ConvoRowStruct, error) {
+	if s.conn == nil {
+		return make([]ConvoRowStruct, 0), errNoPostgres
+	}
+	rows, err := s.conn.Query(query, append([]interface{}{CID}, args...)...)
+	if err != nil {
+		return make([]ConvoRowStruct, 0), err
+	}
+	retMessages := scanConvoRows(rows)
+	if err := s.addReactionsToRows(CID, retMessages); err != nil {
+		return retMessages, err
+	}
+	return retMessages, nil
+}
+
+// fills in the Reactions of each row
+func (s *PostgresStore) addReactionsToRows(CID string, rows []ConvoRowStruct) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	MIDs := make([]string, len(rows))
+	for i, row := range rows {
+		MIDs[i] = row.MID
+	}
+	reactionRows, err := s.conn.Query(`SELECT mid, username, emoji FROM `+POSTGRES_MESSAGE_REACTIONS_TABLE+` WHERE cid = $1 AND mid = ANY($2) ORDER BY r_time ASC;`, CID, pq.Array(MIDs))
+	if err != nil {
+		return err
+	}
+	defer reactionRows.Close()
+	reactions := make(map[string][]ReactionStruct)
+	for reactionRows.Next() {
+		var MID, username, emoji string
+		if err := reactionRows.Scan(&MID, &username, &emoji); err != nil {
+			return err
+		}
+		reactions[MID] = addReaction(reactions[MID], username, emoji)
+	}
+	for i := range rows {
+		rows[i].Reactions = reactions[rows[i].MID]
+	}
+	return reactionRows.Err()
+}
+
+func (s *PostgresStore) GetMessagesSince(CID string, mtime string) ([]ConvoRowStruct, error) {
+	// VALID:
+	// SELECT mid, f_username, content, m_time FROM messages WHERE cid = '4730d9e6-b719-411a-b179-62f35528c7d5' AND m_time > '2015-06-12T19:16:29.119Z'::timestamptz
+	return s.queryConvoRows(CID, `SELECT `+convoRowColumns+` FROM `+POSTGRES_MESSAGES_TABLE+` WHERE cid = $1 AND m_time > $2::timestamptz`+notReply+` ORDER BY m_time ASC;`, mtime)
+}
+
+func (s *PostgresStore) GetLatestMessages(CID string, limit int) ([]ConvoRowStruct, error) {
+	// Average ms for 20 rows: 497ms
+	// Average ms for 50 rows: 469.25ms // I KNOW, RIGHT???
+	return s.queryConvoRows(CID, `WITH results AS (SELECT `+convoRowColumns+` FROM `+POSTGRES_MESSAGES_TABLE+` WHERE cid = $1`+notReply+` ORDER BY m_time DESC LIMIT $2) SELECT * FROM results ORDER BY m_time ASC;`, limit)
+}
+
+func (s *PostgresStore) GetMessagesBefore(CID string, mtime string, limit int) ([]ConvoRowStruct, error) {
+	return s.queryConvoRows(CID, `SELECT `+convoRowColumns+` FROM `+POSTGRES_MESSAGES_TABLE+` WHERE cid = $1 AND m_time < $2::timestamptz`+notReply+` ORDER BY m_time DESC LIMIT $3;`, mtime, limit)
+}
+
+func (s *PostgresStore) GetAllMessages(CID string) ([]ConvoRowStruct, error) {
+	return s.queryConvoRows(CID, `SELECT `+convoRowColumns+` FROM `+POSTGRES_MESSAGES_TABLE+` WHERE cid = $1`+notReply+` ORDER BY m_time ASC;`)
+}
+
+func (s *PostgresStore) GetReplies(CID string, parentMID string, mtime string, afterMID string, limit int) ([]ConvoRowStruct, error) {
+	return s.queryConvoRows(CID, `SELECT `+convoRowColumns+` FROM `+POSTGRES_MESSAGES_TABLE+` WHERE cid = $1 AND p_mid = $2 AND (m_time, mid) > ($3::timestamptz, $4::varchar) AND ($4::varchar <> '' OR m_time > $3::timestamptz) ORDER BY m_time ASC, mid ASC LIMIT $5;`, parentMID, mtime, afterMID, limit)
+}
+
+func (s *PostgresStore) SearchMessages(search MessageSearch) ([]SearchResultStruct, error) {
+	results := make([]SearchResultStruct, 0)
+	if s.conn == nil {
+		return results, errNoPostgres
+	}
+	rank := `ts_rank_cd(search, q)::real`
+	args := []interface{}{search.Query, pq.Array(search.CIDs)}
+	where := ""
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if search.From != "" {
+		where += ` AND upper(f_username) = upper(` + arg(search.From) + `)`
+	}
+	if search.After != "" {
+		where += ` AND m_time >= ` + arg(search.After) + `::timestamptz`
+	}
+	if search.Before != "" {
+		where += ` AND m_time < ` + arg(search.Before) + `::timestamptz`
+	}
+	if c := search.Cursor; c != nil {
+		// the same order as ORDER BY, so the page starts after the cursor
+		where += ` AND (` + rank + `, m_time, cid, mid) < (` + arg(c.Rank) + `::real, ` + arg(c.M_time) + `::timestamptz, ` + arg(c.CID) + `, ` + arg(c.MID) + `)`
+	}
+	rows, err := s.conn.Query(`SELECT cid, mid, f_username, m_time, content, markup, `+rank+` FROM `+POSTGRES_MESSAGES_TABLE+`, websearch_to_tsquery(`+searchConfig+`, $1) q
+WHERE cid = ANY($2) AND search @@ q AND NOT deleted`+where+`
+ORDER BY `+rank+` DESC, m_time DESC, cid DESC, mid DESC LIMIT `+arg(search.Limit)+`;`, args...)
+	if err != nil {
+		return results, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var result SearchResultStruct
+		var markup bool
+		if err := rows.Scan(&result.CID, &result.MID, &result.F_username, &result.M_time, &result.Content, &markup, &result.Rank); err != nil {
+			return results, err
+		}
+		if !markup {
+			result.Content, _ = legacyHTMLToMarkup(result.Content)
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+func (s *PostgresStore) GetThreadParticipants(CID string, parentMID string) ([]string, error) {
+	participants := make([]string, 0)
+	if s.conn == nil {
+		return participants, errNoPostgres
+	}
+	rows, err := s.conn.Query(`SELECT DISTINCT f_username FROM `+POSTGRES_MESSAGES_TABLE+` WHERE cid = $1 AND (mid = $2 OR p_mid = $2) AND NOT deleted;`, CID, parentMID)
+	if err != nil {
+		return participants, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var username string
+		if err := rows.Scan(&username); err != nil {
+			return participants, err
+		}
+		participants = append(participants, username)
+	}
+	return participants, rows.Err()
+}
+
+func (s *PostgresStore) GetMessage(CID string, MID string) (ConvoRowStruct, error) {
+	rows, err := s.queryConvoRows(CID, `SELECT `+convoRowColumns+` FROM `+POSTGRES_MESSAGES_TABLE+` WHERE cid = $1 AND mid = $2;`, MID)
+	if err != nil {
+		return ConvoRowStruct{}, err
+	}
+	if len(rows) == 0 {
+		return ConvoRowStruct{}, ErrNoMessage
+	}
+	return rows[0], nil
+}
+
+func (s *PostgresStore) EditMessage(CID string, MID string, content string, etime string) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return err
+	}
+	// locked so two edits can't both keep the same old content
+	var oldContent string
+	var markup bool
+	var attachments sql.NullString
+	err = tx.QueryRow(`SELECT content, markup, attachments FROM `+POSTGRES_MESSAGES_TABLE+` WHERE cid = $1 AND mid = $2 AND NOT deleted FOR UPDATE;`, CID, MID).Scan(&oldContent, &markup, &attachments)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return ErrNoMessage
+	} else if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err = tx.Exec(`INSERT INTO `+POSTGRES_MESSAGE_EDITS_TABLE+` (cid, mid, content, markup, e_time) VALUES ($1, $2, $3, $4, $5::timestamptz);`, CID, MID, oldContent, markup, etime); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if !markup {
+		// the HTML's pictures become attachments, the edit only replaces the text
+		_, legacy := legacyHTMLToMarkup(oldContent)
+		if attachments, err = attachmentsColumn(legacy); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if _, err = tx.Exec(`UPDATE `+POSTGRES_MESSAGES_TABLE+` SET content = $3, attachments = $4, markup = true, e_time = $5::timestamptz, search = to_tsvector(`+searchConfig+`, $3) WHERE cid = $1 AND mid = $2;`, CID, MID, content, attachments, etime); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *PostgresStore) DeleteMessage(CID string, MID string, etime string) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return err
+	}
+	var parentMID sql.NullString
+	err = tx.QueryRow(`UPDATE `+POSTGRES_MESSAGES_TABLE+` SET content = '', attachments = NULL, markup = true, deleted = true, e_time = $3::timestamptz, search = NULL WHERE cid = $1 AND mid = $2 AND NOT deleted RETURNING p_mid;`, CID, MID, etime).Scan(&parentMID)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return ErrNoMessage
+		}
+		return err
+	}
+	if parentMID.Valid && parentMID.String != "" {
+		// the thread summary only counts the replies that are left
+		if _, err = tx.Exec(`UPDATE `+POSTGRES_MESSAGES_TABLE+` SET reply_count = GREATEST(reply_count - 1, 0), last_reply = (SELECT max(m_time) FROM `+POSTGRES_MESSAGES_TABLE+` WHERE cid = $1 AND p_mid = $2 AND NOT deleted) WHERE cid = $1 AND mid = $2;`, CID, parentMID.String); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if _, err = tx.Exec(`DELETE FROM `+POSTGRES_MESSAGE_EDITS_TABLE+` WHERE cid = $1 AND mid = $2;`, CID, MID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err = tx.Exec(`DELETE FROM `+POSTGRES_MESSAGE_REACTIONS_TABLE+` WHERE cid = $1 AND mid = $2;`, CID, MID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *PostgresStore) GetMessageEdits(CID string, MID string) ([]MessageEditStruct, error) {
+	edits := make([]MessageEditStruct, 0)
+	if s.conn == nil {
+		return edits, errNoPostgres
+	}
+	rows, err := s.conn.Query(`SELECT content, markup, e_time FROM `+POSTGRES_MESSAGE_EDITS_TABLE+` WHERE cid = $1 AND mid = $2 ORDER BY e_time ASC;`, CID, MID)
+	if err != nil {
+		return edits, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var edit MessageEditStruct
+		var markup bool
+		if err := rows.Scan(&edit.Content, &markup, &edit.E_time); err != nil {
+			return edits, err
+		}
+		if !markup {
+			edit.Content, _ = legacyHTMLToMarkup(edit.Content)
+		}
+		edits = append(edits, edit)
+	}
+	return edits, rows.Err()
+}
+
+func (s *PostgresStore) AddAttachments(CID string, MID string, attachments []MessageAttachment) ([]MessageAttachment, error) {
+	if s.conn == nil {
+		return nil, errNoPostgres
+	}
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+	var content string
+	var column sql.NullString
+	var markup bool
+	err = tx.QueryRow(`SELECT content, attachments, markup FROM `+POSTGRES_MESSAGES_TABLE+` WHERE cid = $1 AND mid = $2 AND NOT deleted FOR UPDATE;`, CID, MID).Scan(&content, &column, &markup)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return nil, ErrNoMessage
+	} else if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	// an HTML row is written back as markup, its attachments are in the HTML
+	content, existing := storedMarkup(content, column, markup)
+	all := append(existing, attachments...)
+	if column, err = attachmentsColumn(all); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if _, err = tx.Exec(`UPDATE `+POSTGRES_MESSAGES_TABLE+` SET content = $3, attachments = $4, markup = true, search = to_tsvector(`+searchConfig+`, $3) WHERE cid = $1 AND mid = $2;`, CID, MID, content, column); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return all, tx.Commit()
+}
+
+func (s *PostgresStore) AddReaction(CID string, MID string, username string, emoji string, rtime string) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	// only while the message is there, DeleteMessage takes its reactions with it
+	_, err := s.conn.Exec(`INSERT INTO `+POSTGRES_MESSAGE_REACTIONS_TABLE+` (cid, mid, username, emoji, r_time) `+
+		`SELECT cid, mid, $3, $4, $5::timestamptz FROM `+POSTGRES_MESSAGES_TABLE+` WHERE cid = $1 AND mid = $2 AND NOT deleted ON CONFLICT DO NOTHING;`,
+		CID, MID, username, emoji, rtime)
+	return err
+}
+
+func (s *PostgresStore) RemoveReaction(CID string, MID string, username string, emoji string) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	_, err := s.conn.Exec(`DELETE FROM `+POSTGRES_MESSAGE_REACTIONS_TABLE+` WHERE cid = $1 AND mid = $2 AND username = $3 AND emoji = $4;`, CID, MID, username, emoji)
+	return err
+}
+
+// scheduled messages
+func (s *PostgresStore) AddScheduledMessage(msg ScheduledMessagesCmdStruct) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	CurString := "INSERT INTO " + POSTGRES_SCHEDULED_MESSAGES_TABLE + " (CID, f_username, content, m_time) VALUES ($1, $2, $3, $4)"
+	_, err := s.conn.Exec(CurString, msg.CID, msg.Username, msg.Content, msg.Time)
+	return err
+}
+
+func (s *PostgresStore) RemoveScheduledMessage(msg ScheduledMessagesCmdStruct) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	CurString := `DELETE FROM ` + POSTGRES_SCHEDULED_MESSAGES_TABLE + ` WHERE CID = $1 AND m_time = $2 AND f_username = $3 AND content = $4;`
+	m_time, _ := time.Parse(time.RFC3339Nano, msg.Time)
+	_, err := s.conn.Exec(CurString, msg.CID, m_time, msg.Username, msg.Content)
+	return err
+}
+
+func (s *PostgresStore) RemoveAllScheduledMessages(username string) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	CurString := `DELETE FROM ` + POSTGRES_SCHEDULED_MESSAGES_TABLE + ` WHERE f_username = $1;`
+	_, err := s.conn.Exec(CurString, username)
+	return err
+}
+
+func (s *PostgresStore) TakeDueScheduledMessages(t time.Time) ([]ScheduledMessagesCmdStruct, error) {
+	due := make([]ScheduledMessagesCmdStruct, 0)
+	if s.conn == nil {
+		return due, errNoPostgres
+	}
+	CurString := `DELETE FROM ` + POSTGRES_SCHEDULED_MESSAGES_TABLE + ` WHERE m_time < $1 RETURNING *;`
+	rows, err := s.conn.Query(CurString, t)
+	if err != nil {
+		return due, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var SQLCID string
+		var SQLF_username string
+		var SQLContent string
+		var SQLM_time time.Time
+		if err := rows.Scan(&SQLCID, &SQLF_username, &SQLContent, &SQLM_time); err != nil {
+			ERROR.Println(err)
+			continue
+		}
+		due = append(due, ScheduledMessagesCmdStruct{
+			CID:      SQLCID,
+			Username: SQLF_username,
+			Content:  SQLContent,
+			Time:     SQLM_time.Format(time.RFC3339Nano),
+		})
+	}
+	return due, nil
+}
+
+// EMAILS
+// email table structure:
+// [   FROM_EMAIL   |   TO_EMAILS    |  RECV_EMAIL  |  SUBJECT  |  CONTENT  | ATTACHMENTS |  STARRED  |  UNREAD  |   SPAM   |  DRAFT  |  DELETED  |  RECV_TIME  |    M_TIME   ]
+// [     varchar    |    varchar     |   varchar    |  varchar  |  varchar  |   varchar   |  boolean  |  boolean |  boolean | boolean |  boolean  | timestamptz |  timestamptz ]
+func (s *PostgresStore) CreateMailbox(username string) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	CurString := "CREATE TABLE " + emailTableName(username) + " (from_email varchar NOT NULL, to_emails varchar NOT NULL, recv_email varchar NOT NULL, subject varchar, content varchar, attachments varchar, starred boolean, unread boolean, spam boolean, draft boolean, deleted boolean, recv_time timestamptz NOT NULL, m_time timestamptz NOT NULL, PRIMARY KEY (from_email, recv_time) );"
+	if _, err := s.conn.Exec(CurString); err != nil {
+		return err
+	}
+	// for GetEmails' paging and searching, the same as the migration makes
+	// for older mailboxes
+	if _, err := s.conn.Exec("CREATE INDEX " + mailboxIndexName(username, "m_time") + " ON " + emailTableName(username) + " (m_time);"); err != nil {
+		return err
+	}
+	_, err := s.conn.Exec("CREATE INDEX " + mailboxIndexName(username, "search") + " ON " + emailTableName(username) + " USING GIN (" + emailSearchVector + ");")
+	return err
+}
+
+func (s *PostgresStore) DropMailbox(username string) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	_, err := s.conn.Exec(`DROP TABLE ` + emailTableName(username) + `;`)
+	return err
+}
+
+func (s *PostgresStore) AddEmail(username string, email EmailRowStruct) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	CurString := "INSERT INTO " + emailTableName(username) + " (from_email, to_emails, recv_email, subject, content, attachments, starred, unread, spam, draft, deleted, recv_time, m_time) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)"
+	_, err := s.conn.Exec(CurString, email.FromEmail, email.ToEmails, email.RecvEmail, email.Subject, email.Content, email.Attachments, email.Starred, email.Unread, email.Spam, email.Draft, email.Deleted, email.RecvTime, email.M_time)
+	return err
+}
+
+func (s *PostgresStore) GetEmails(username string, query EmailQuery) ([]EmailRowStruct, error) {
+	retEmails := make([]EmailRowStruct, 0)
+	if s.conn == nil {
+		return retEmails, errNoPostgres
+	}
+	var args []interface{}
+	where := " WHERE true"
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if query.Since != "" {
+		where += " AND m_time > " + arg(query.Since) + "::timestamptz"
+	}
+	if query.Search != "" {
+		where += " AND " + emailSearchVector + " @@ websearch_to_tsquery(" + searchConfig + ", " + arg(query.Search) + ")"
+	}
+	flags := []struct {
+		column string
+		value  *bool
+	}{{"starred", query.Starred}, {"unread", query.Unread}, {"spam", query.Spam}, {"draft", query.Draft}, {"deleted", query.Deleted}}
+	for _, flag := range flags {
+		if flag.value != nil {
+			// older rows can have the flags null, that's false
+			where += " AND coalesce(" + flag.column + ", false) = " + arg(*flag.value)
+		}
+	}
+	if query.Attachments != nil {
+		where += " AND (coalesce(attachments, '') NOT IN ('', '[]', 'null')) = " + arg(*query.Attachments)
+	}
+	if query.After != "" {
+		where += " AND recv_time >= " + arg(query.After) + "::timestamptz"
+	}
+	if query.Before != "" {
+		where += " AND recv_time < " + arg(query.Before) + "::timestamptz"
+	}
+	if c := query.Cursor; c != nil {
+		where += " AND (m_time, from_email, recv_time) < (" + arg(c.M_time) + "::timestamptz, " + arg(c.FromEmail) + ", " + arg(c.RecvTime) + "::timestamptz)"
+	}
+	limit := ""
+	if query.Limit > 0 {
+		limit = " LIMIT " + arg(query.Limit)
+	}
+	rows, err := s.conn.Query(`SELECT from_email, to_emails, recv_email, subject, content, attachments, starred, unread, spam, draft, deleted, recv_time, m_time FROM `+emailTableName(username)+where+` ORDER BY m_time DESC, from_email DESC, recv_time DESC`+limit+`;`, args...)
+	if err != nil {
+		return retEmails, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var row EmailRowStruct
+		if err := rows.Scan(&row.FromEmail, &row.ToEmails, &row.RecvEmail, &row.Subject, &row.Content, &row.Attachments, &row.Starred, &row.Unread, &row.Spam, &row.Draft, &row.Deleted, &row.RecvTime, &row.M_time); err != nil {
+			ERROR.Println(err)
+			continue
+		}
+		retEmails = append(retEmails, row)
+	}
+	return retEmails, nil
+}
+
+func (s *PostgresStore) SetEmailFlag(username string, key EmailKey, flag string, value bool, mtime string) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	switch flag {
+	case EMAIL_FLAG_UNREAD, EMAIL_FLAG_STARRED, EMAIL_FLAG_DELETED:
+		// flag is used as a column name, so only these
+	default:
+		return errors.New("unknown email flag " + flag)
+	}
+	CurString := "UPDATE " + emailTableName(username) + " SET " + flag + " = $1 , m_time = $2 WHERE from_email = $3 AND subject = $4 AND recv_time = $5"
+	_, err := s.conn.Exec(CurString, value, mtime, key.FromEmail, key.Subject, key.RecvTime)
+	return err
+}
+
+func (s *PostgresStore) SaveDraft(username string, email EmailRowStruct) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	// first remove draft that may be there
+	_, err := s.conn.Exec("DELETE FROM "+emailTableName(username)+" WHERE draft = true AND recv_time = $1", email.RecvTime)
+	if err != nil {
+		logPqError("SaveDraft", err)
+	}
+	email.Draft = true
+	return s.AddEmail(username, email)
+}
+
+func (s *PostgresStore) RemoveDeletedEmails(username string) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	_, err := s.conn.Exec("DELETE FROM " + emailTableName(username) + " WHERE deleted = true")
+	return err
+}
+
+// ChangeStore
+func (s *PostgresStore) AppendChange(username string, kind string, event string) (int64, error) {
+	if s.conn == nil {
+		return 0, errNoPostgres
+	}
+	// one statement, so the seq row stays locked until the change is in
+	var seq int64
+	err := s.conn.QueryRow(`WITH next AS (INSERT INTO `+POSTGRES_CHANGE_SEQS_TABLE+` (username, seq) VALUES ($1, 1)
+		ON CONFLICT (username) DO UPDATE SET seq = `+POSTGRES_CHANGE_SEQS_TABLE+`.seq + 1 RETURNING seq)
+		INSERT INTO `+POSTGRES_CHANGES_TABLE+` (username, seq, kind, event, c_time) SELECT $1, seq, $2, $3, now() FROM next RETURNING seq`,
+		strings.ToUpper(username), kind, event).Scan(&seq)
+	if err != nil {
+		return 0, err
+	}
+	if seq > CHANGE_LOG_SIZE {
+		_, err = s.conn.Exec("DELETE FROM "+POSTGRES_CHANGES_TABLE+" WHERE username = $1 AND seq <= $2", strings.ToUpper(username), seq-CHANGE_LOG_SIZE)
+		if err != nil {
+			logPqError("AppendChange", err) // it's trimmed next time
+		}
+	}
+	return seq, nil
+}
+
+func (s *PostgresStore) GetChanges(username string, after int64, limit int) ([]ChangeStruct, error) {
+	if s.conn == nil {
+		return nil, errNoPostgres
+	}
+	rows, err := s.conn.Query("SELECT seq, kind, event, c_time FROM "+POSTGRES_CHANGES_TABLE+" WHERE username = $1 AND seq > $2 ORDER BY seq LIMIT $3",
+		strings.ToUpper(username), after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	changes := make([]ChangeStruct, 0)
+	for rows.Next() {
+		var change ChangeStruct
+		var event string
+		if err := rows.Scan(&change.Seq, &change.Kind, &event, &change.C_time); err != nil {
+			return nil, err
+		}
+		change.Event = []byte(event)
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+func (s *PostgresStore) LastChange(username string) (int64, error) {
+	if s.conn == nil {
+		return 0, errNoPostgres
+	}
+	var seq int64
+	err := s.conn.QueryRow("SELECT seq FROM "+POSTGRES_CHANGE_SEQS_TABLE+" WHERE username = $1", strings.ToUpper(username)).Scan(&seq)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return seq, err
+}
+
+func (s *PostgresStore) DeleteChanges(username string) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	_, err := s.conn.Exec("DELETE FROM "+POSTGRES_CHANGES_TABLE+" WHERE username = $1", strings.ToUpper(username))
+	return err
+}
+
+// DeviceQueueStore, queues are by (username, device) with username upper case
+func (s *PostgresStore) EnqueueDeviceEvent(username string, device string, event string) (int64, error) {
+	if s.conn == nil {
+		return 0, errNoPostgres
+	}
+	username = strings.ToUpper(username)
+	var seq int64
+	err := s.conn.QueryRow(`WITH next AS (INSERT INTO `+POSTGRES_DEVICE_SEQS_TABLE+` (username, device, seq) VALUES ($1, $2, 1)
+		ON CONFLICT (username, device) DO UPDATE SET seq = `+POSTGRES_DEVICE_SEQS_TABLE+`.seq + 1 RETURNING seq)
+		INSERT INTO `+POSTGRES_DEVICE_EVENTS_TABLE+` (username, device, seq, event, q_time) SELECT $1, $2, seq, $3, now() FROM next RETURNING seq`,
+		username, device, event).Scan(&seq)
+	if err != nil {
+		return 0, err
+	}
+	_, err = s.conn.Exec("DELETE FROM "+POSTGRES_DEVICE_EVENTS_TABLE+" WHERE username = $1 AND device = $2 AND (seq <= $3 OR q_time < $4)",
+		username, device, seq-DEVICE_QUEUE_SIZE, time.Now().Add(-DEVICE_QUEUE_RETENTION))
+	if err != nil {
+		logPqError("EnqueueDeviceEvent", err) // it's trimmed next time
+	}
+	return seq, nil
+}
+
+func (s *PostgresStore) GetDeviceEvents(username string, device string, after int64, limit int) ([]DeviceEventStruct, error) {
+	if s.conn == nil {
+		return nil, errNoPostgres
+	}
+	rows, err := s.conn.Query("SELECT seq, event, q_time FROM "+POSTGRES_DEVICE_EVENTS_TABLE+" WHERE username = $1 AND device = $2 AND seq > $3 ORDER BY seq LIMIT $4",
+		strings.ToUpper(username), device, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	events := make([]DeviceEventStruct, 0)
+	for rows.Next() {
+		var e DeviceEventStruct
+		if err := rows.Scan(&e.Seq, &e.Event, &e.Q_time); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (s *PostgresStore) LastDeviceEvent(username string, device string) (int64, error) {
+	if s.conn == nil {
+		return 0, errNoPostgres
+	}
+	var seq int64
+	err := s.conn.QueryRow("SELECT seq FROM "+POSTGRES_DEVICE_SEQS_TABLE+" WHERE username = $1 AND device = $2", strings.ToUpper(username), device).Scan(&seq)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return seq, err
+}
+
+func (s *PostgresStore) AckDeviceEvents(username string, device string, seq int64) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	_, err := s.conn.Exec("DELETE FROM "+POSTGRES_DEVICE_EVENTS_TABLE+" WHERE username = $1 AND device = $2 AND seq <= $3", strings.ToUpper(username), device, seq)
+	return err
+}
+
+func (s *PostgresStore) DeleteDeviceQueue(username string, device string) error {
+	if s.conn == nil {
+		return errNoPostgres
+	}
+	username = strings.ToUpper(username)
+	if _, err := s.conn.Exec("DELETE FROM "+POSTGRES_DEVICE_EVENTS_TABLE+" WHERE username = $1 AND device = $2", username, device); err != nil {
+		return err
+	}
+	_, err := s.conn.Exec("DELETE FROM "+POSTGRES_DEVICE_SEQS_TABLE+" WHERE username = $1 AND device = $2", username, device)
+	return err
+}
@@ -0,0 +1,269 @@
+package pcDatabase
+
+import (
+	"github.com/pquerna/ffjson/ffjson"
+	"strings"
+	"time"
+)
+
+// PRESENCE
+// every socket a user has open is a session in their record in the active
+// set, with its state (online, away or do-not-disturb) and when it was last
+// seen.  Run starts it once the socket logs in and takes it out when the
+// socket logs out or closes, in between it's written again every
+// PRESENCE_REFRESH, and SetPresence is the client's heartbeat as well as how
+// it goes away or dnd.  A server that dies can't take its sessions out, so a
+// session not seen for PRESENCE_TIMEOUT doesn't count any more and the next
+// logout of that user clears it.
+//
+// what the others see is the user's presence over all their sessions, dnd
+// over online over away, offline with none, and the last time any of them
+// was seen.  When that changes it's sent as a Presence event to the user's
+// friends and everyone in a conversation with them.  A user can hide their
+// last seen (HideLastSeen), then only their state goes out.
+//
+// the presence fields on Database are only touched by the session's own
+// goroutine, commands and the refresh tick both run in Run
+
+const (
+	PRESENCE_ONLINE  = "online"
+	PRESENCE_AWAY    = "away"
+	PRESENCE_DND     = "dnd"
+	PRESENCE_OFFLINE = "offline"
+
+	// how often a session's presence is written again while it's open
+	PRESENCE_REFRESH = 30 * time.Second
+	// how often Run looks for a refresh that's due
+	PRESENCE_CHECK_INTERVAL = 10 * time.Second
+	// a session not seen for this long is gone
+	PRESENCE_TIMEOUT = 75 * time.Second
+	// most usernames GetPresence looks up at once
+	MAX_PRESENCE_LOOKUP = 100
+
+	// a user's record in the active set, Sessions maps the session ID to
+	// {State, Seen}
+	AEROSPIKE_USERS_ACTIVE_SESSIONS_BIN  = "Sessions"
+	AEROSPIKE_USERS_ACTIVE_LAST_SEEN_BIN = "LastSeen"
+)
+
+// the presence of one of a user's sessions, Seen is RFC3339
+type SessionPresence struct {
+	State string
+	Seen  string
+}
+
+type PresenceEvent struct {
+	Cmd string `json:"cmd"`
+	PresenceStruct
+}
+
+// which wins when a user's sessions don't agree
+var presenceRank = map[string]int{PRESENCE_OFFLINE: 0, PRESENCE_AWAY: 1, PRESENCE_ONLINE: 2, PRESENCE_DND: 3}
+
+func presenceToBin(presence SessionPresence) map[interface{}]interface{} {
+	return map[interface{}]interface{}{"State": presence.State, "Seen": presence.Seen}
+}
+
+// the sessions and last seen in a user's record in the active set
+func presenceFromBins(bins map[string]interface{}) (map[string]SessionPresence, string) {
+	sessions := make(map[string]SessionPresence)
+	for ID, value := range binMap(bins[AEROSPIKE_USERS_ACTIVE_SESSIONS_BIN]) {
+		session := binMap(value)
+		sessions[ID.(string)] = SessionPresence{State: binString(session, "State"), Seen: binString(session, "Seen")}
+	}
+	lastSeen, _ := bins[AEROSPIKE_USERS_ACTIVE_LAST_SEEN_BIN].(string)
+	return sessions, lastSeen
+}
+
+func validPresenceState(state string) bool {
+	return state == PRESENCE_ONLINE || state == PRESENCE_AWAY || state == PRESENCE_DND
+}
+
+// the later of two RFC3339 times, a when b isn't one
+func laterTime(a, b string) string {
+	ta, errA := time.Parse(time.RFC3339Nano, a)
+	tb, errB := time.Parse(time.RFC3339Nano, b)
+	if errA != nil || (errB == nil && tb.After(ta)) {
+		return b
+	}
+	return a
+}
+
+// username's presence over all of their sessions that are still alive at now,
+// with their last seen whether they hide it or not
+func (db *Database) userPresence(username string, now time.Time) PresenceStruct {
+	presence := PresenceStruct{Username: username, State: PRESENCE_OFFLINE}
+	sessions, lastSeen, err := db.Users.GetPresence(username)
+	if err != nil {
+		ERROR.Println("error in GetPresence for "+username+":", err)
+		return presence
+	}
+	presence.LastSeen = lastSeen
+	for _, session := range sessions {
+		seen, err := time.Parse(time.RFC3339Nano, session.Seen)
+		if err != nil || now.Sub(seen) > PRESENCE_TIMEOUT {
+			continue
+		}
+		if presenceRank[session.State] > presenceRank[presence.State] {
+			presence.State = session.State
+		}
+		presence.LastSeen = laterTime(presence.LastSeen, session.Seen)
+	}
+	return presence
+}
+
+// the upper case usernames of the user's friends and everyone in a
+// conversation with them, not the user
+func (db *Database) presenceContacts(user UserStruct) map[string]bool {
+	contacts := make(map[string]bool)
+	for _, friend := range user.GetFriendStructs() {
+		contacts[strings.ToUpper(friend.Username)] = true
+	}
+	for _, convo := range user.GetCIDStructs() {
+		for _, member := range ToConvoMemberArray(db.Convos.GetConvoMembers(convo.CID)) {
+			contacts[strings.ToUpper(member.Username)] = true
+		}
+	}
+	delete(contacts, user.UsernameUpper)
+	return contacts
+}
+
+// sends username's presence to their contacts
+func (db *Database) publishPresence(username string, presence PresenceStruct) {
+	user := db.Users.GetUser(strings.ToUpper(username))
+	if user.UsernameUpper == "" {
+		return
+	}
+	presence.Username = user.Username
+	if user.HideLastSeen {
+		presence.LastSeen = ""
+	}
+	eventBytes, err := ffjson.Marshal(PresenceEvent{Cmd: "Presence", PresenceStruct: presence})
+	if err != nil {
+		ERROR.Println("error in ffjson.Marshal in publishPresence:", err)
+		return
+	}
+	for contact := range db.presenceContacts(user) {
+		db.SendStringToWebDevices(db.Users.GetUser(contact).Web, string(eventBytes))
+	}
+}
+
+// writes this session's state for the logged in user, starting the session's
+// presence if it hasn't been, and tells the contacts if the user's presence
+// changed
+func (db *Database) setPresence(state string) error {
+	username := db.Username()
+	if username == "" || db.Users == nil {
+		return nil
+	}
+	if db.presenceUser != "" && db.presenceUser != username {
+		db.endPresence()
+	}
+	now := time.Now()
+	before := db.userPresence(username, now)
+	if db.presenceID == "" {
+		db.presenceID = newMessageID()
+	}
+	seen := now.UTC().Format(time.RFC3339Nano)
+	if err := db.Users.SetSessionPresence(username, db.presenceID, SessionPresence{State: state, Seen: seen}); err != nil {
+		ERROR.Println("error in SetSessionPresence for "+username+":", err)
+		return ErrNotSaved
+	}
+	db.presenceUser, db.presenceState, db.presenceSeen = username, state, now
+	if after := db.userPresence(username, now); after.State != before.State {
+		db.publishPresence(username, after)
+	}
+	return nil
+}
+
+// takes this session out of the user's presence, along with any sessions a
+// dead server left behind
+func (db *Database) endPresence() {
+	username := db.presenceUser
+	if username == "" || db.Users == nil {
+		return
+	}
+	now := time.Now()
+	before := db.userPresence(username, now)
+	if err := db.Users.RemoveSessionPresence(username, db.presenceID, now.UTC().Format(time.RFC3339Nano)); err != nil {
+		ERROR.Println("error in RemoveSessionPresence for "+username+":", err)
+	}
+	if sessions, _, err := db.Users.GetPresence(username); err == nil {
+		for ID, session := range sessions {
+			if seen, err := time.Parse(time.RFC3339Nano, session.Seen); err != nil || now.Sub(seen) > PRESENCE_TIMEOUT {
+				db.Users.RemoveSessionPresence(username, ID, "")
+			}
+		}
+	}
+	db.presenceID, db.presenceUser, db.presenceState = "", "", ""
+	if after := db.userPresence(username, now); after.State != before.State {
+		db.publishPresence(username, after)
+	}
+}
+
+// follows the session logging in, out or in as someone else, Run calls it
+// after every command
+func (db *Database) trackPresence() {
+	if db.presenceUser == db.Username() {
+		return
+	}
+	db.endPresence()
+	db.setPresence(PRESENCE_ONLINE)
+}
+
+// writes the session's presence again if it's due, so it doesn't time out
+func (db *Database) refreshPresence(now time.Time) {
+	if db.presenceUser != "" && now.Sub(db.presenceSeen) >= PRESENCE_REFRESH {
+		db.setPresence(db.presenceState)
+	}
+}
+
+func validatePresenceLookup(req interface{}) error {
+	if n := len(req.(*GetPresenceCmdStruct).Usernames); n > MAX_PRESENCE_LOOKUP {
+		return cmdError(ERR_BAD_REQUEST, "at most %d usernames at once, got %d", MAX_PRESENCE_LOOKUP, n)
+	}
+	return nil
+}
+
+// SetPresence sets this session's state, sending it again is the heartbeat
+func (db *Database) SetPresence(jsondata *PresenceCmdStruct) error {
+	if !validPresenceState(jsondata.State) {
+		return cmdError(ERR_BAD_REQUEST, "state has to be %s, %s or %s", PRESENCE_ONLINE, PRESENCE_AWAY, PRESENCE_DND)
+	}
+	return db.setPresence(jsondata.State)
+}
+
+// GetPresence is the presence of each of Usernames.  Only the caller's
+// friends and conversation members are looked up, anyone else is offline
+func (db *Database) GetPresence(jsondata *GetPresenceCmdStruct) (*PresenceResponse, error) {
+	caller := db.Users.GetUser(strings.ToUpper(jsondata.Username))
+	contacts := db.presenceContacts(caller)
+	now := time.Now()
+	resp := &PresenceResponse{Presence: make([]PresenceStruct, 0, len(jsondata.Usernames))}
+	for _, username := range jsondata.Usernames {
+		upper := strings.ToUpper(username)
+		if !contacts[upper] && upper != caller.UsernameUpper {
+			resp.Presence = append(resp.Presence, PresenceStruct{Username: username, State: PRESENCE_OFFLINE})
+			continue
+		}
+		user := db.Users.GetUser(upper)
+		presence := db.userPresence(user.Username, now)
+		if user.HideLastSeen && upper != caller.UsernameUpper {
+			presence.LastSeen = ""
+		}
+		resp.Presence = append(resp.Presence, presence)
+	}
+	return resp, nil
+}
+
+// HideLastSeen stops (or starts again) sharing when the caller was last online
+func (db *Database) HideLastSeen(jsondata *HideLastSeenCmdStruct) error {
+	_, err := db.updateUser(jsondata.Username, func(user *UserStruct) error {
+		if user.HideLastSeen == jsondata.Hide {
+			return errNoChange
+		}
+		user.HideLastSeen = jsondata.Hide
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,76 @@
+package pcDatabase
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPresence(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "carol")
+	createTestUser(t, db, "bob")
+	createTestUser(t, db, "alice")
+	createTestConversation(t, db, "alice", "bob")
+
+	// logging in puts alice online, bob is in a conversation with alice so
+	// bob hears about it
+	db.trackPresence()
+	if event := expectEvents(t, db, 1)[0]; !strings.Contains(event, `"state":"online"`) || !strings.Contains(event, `"last_seen"`) {
+		t.Errorf("unexpected event %s", event)
+	}
+	dispatch(t, db, `{"cmd":"SetPresence","state":"away"}`, nil)
+	if event := expectEvents(t, db, 1)[0]; !strings.Contains(event, `"state":"away"`) {
+		t.Errorf("unexpected event %s", event)
+	}
+	// a heartbeat changes nothing the others see
+	dispatch(t, db, `{"cmd":"SetPresence","state":"away"}`, nil)
+	select {
+	case event := <-db.nats_receive:
+		t.Errorf("the heartbeat sent %s", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+	expectError(t, db, `{"cmd":"SetPresence","state":"busy"}`, ERR_BAD_REQUEST)
+
+	// bob's phone is online, an old session from a server that died isn't
+	now := time.Now().UTC()
+	db.Users.SetSessionPresence("bob", "old", SessionPresence{State: PRESENCE_DND, Seen: now.Add(-2 * PRESENCE_TIMEOUT).Format(time.RFC3339Nano)})
+	db.Users.SetSessionPresence("bob", "phone", SessionPresence{State: PRESENCE_ONLINE, Seen: now.Format(time.RFC3339Nano)})
+	var resp PresenceResponse
+	dispatch(t, db, `{"cmd":"GetPresence","usernames":["bob","carol","alice"]}`, &resp)
+	want := []string{PRESENCE_ONLINE, PRESENCE_OFFLINE, PRESENCE_AWAY}
+	if len(resp.Presence) != len(want) {
+		t.Fatalf("GetPresence returned %+v", resp.Presence)
+	}
+	for i, presence := range resp.Presence {
+		if presence.State != want[i] {
+			t.Errorf("%s is %s, expected %s", presence.Username, presence.State, want[i])
+		}
+	}
+	if resp.Presence[0].LastSeen == "" || resp.Presence[2].LastSeen == "" {
+		t.Errorf("no last seen in %+v", resp.Presence)
+	}
+	// carol isn't a contact, even if carol were online alice wouldn't see it
+	if resp.Presence[1].LastSeen != "" {
+		t.Errorf("carol's last seen was shared with a stranger")
+	}
+	expectError(t, db, `{"cmd":"GetPresence","usernames":["`+strings.Repeat(`bob","`, MAX_PRESENCE_LOOKUP)+`bob"]}`, ERR_BAD_REQUEST)
+
+	// alice hides last seen, bob only gets the state
+	dispatch(t, db, `{"cmd":"HideLastSeen","hide":true}`, nil)
+	dispatch(t, db, `{"cmd":"SetPresence","state":"dnd"}`, nil)
+	if event := expectEvents(t, db, 1)[0]; !strings.Contains(event, `"state":"dnd"`) || strings.Contains(event, `"last_seen"`) {
+		t.Errorf("unexpected event %s", event)
+	}
+
+	// logging out takes alice offline and clears what a dead server left
+	db.Users.SetSessionPresence("alice", "old", SessionPresence{State: PRESENCE_ONLINE, Seen: now.Add(-2 * PRESENCE_TIMEOUT).Format(time.RFC3339Nano)})
+	db.endPresence()
+	if event := expectEvents(t, db, 1)[0]; !strings.Contains(event, `"state":"offline"`) {
+		t.Errorf("unexpected event %s", event)
+	}
+	if sessions, lastSeen, _ := db.Users.GetPresence("alice"); len(sessions) != 0 || lastSeen == "" {
+		t.Errorf("alice still has %v, last seen %q", sessions, lastSeen)
+	}
+}
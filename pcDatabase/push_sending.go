@@ -4,17 +4,7 @@ import (
 	"github.com/mostafah/mandrill"
 	"github.com/timehop/apns"
 	"pingedchat/pcDatabase/gcm"
-)
-
-const (
-	ANDROID_API_KEY     = "FILL_ME"
-	MANDRILL_API_KEY    = "FILL_ME"
-	TELAPI_TESTING_SID  = "FILL_ME"
-	TELAPI_TESTING_AUTH = "FILL_ME"
-
-	// generate .pem files for Apple certificates
-	APNS_CERT_FILE = "pcDatabase/iosDevelopmentPushCert.pem"
-	APNS_KEY_FILE  = "pcDatabase/iosDevelopmentPushKey-noenc.pem"
+	"strings"
 )
 
 var (
@@ -22,26 +12,22 @@ var (
 	APNSClient apns.Client
 )
 
-func CreatePushTitle(f_username string, content string) string {
-	title := ""
-	// some error checking
-	if len(content) < 4 {
-		title = f_username + " - " + content
-	} else {
-		if content[:2] == "<a" {
-			title = f_username + " sent you a link!"
-		} else if content[:4] == "<img" {
-			title = f_username + " sent you a picture!"
-		} else {
-			// can't slice what's not there
-			if len(content) < 40 {
-				title = f_username + " - " + content // substring 40 characters limit
-			} else {
-				title = f_username + " - " + content[:40] // substring 40 characters limit
-			}
-		}
+// CreatePushTitle is the notification's title, the start of what
+// messageData says or what it has attached
+func CreatePushTitle(messageData MessageStruct) string {
+	nodes := parseMarkup(messageData.Content)
+	var b strings.Builder
+	renderMarkupText(&b, nodes)
+	text := []rune(strings.TrimSpace(b.String()))
+	if len(text) == 0 && len(messageData.Attachments) > 0 {
+		return messageData.FromUsername + " sent you " + attachmentNoun(messageData.Attachments[0]) + "!"
+	} else if len(nodes) == 1 && nodes[0].kind == nodeLink {
+		return messageData.FromUsername + " sent you a link!"
 	}
-	return title
+	if len(text) > PUSH_TITLE_LENGTH {
+		text = text[:PUSH_TITLE_LENGTH]
+	}
+	return messageData.FromUsername + " - " + string(text)
 }
 
 func PushToAndroid(droids []string, messageData MessageStruct) {
@@ -49,10 +35,10 @@ func PushToAndroid(droids []string, messageData MessageStruct) {
 	TRACE.Println(droids)
 	// taken from https://github.com/alexjlockwood/gcm
 	// create message title
-	title := CreatePushTitle(messageData.FromUsername, messageData.Content)
+	title := CreatePushTitle(messageData)
 	data := map[string]interface{}{
-		"message": messageData.Content,
-		// "content":    messageData.Content,
+		"message":    MessageText(messageData),
+		"content":    messageData.Content,
 		"msgcnt":     1, // apparently https://github.com/phonegap-build/PushPlugin wants this
 		"title":      messageData.FromUsername,
 		"CID":        messageData.CID,
@@ -61,7 +47,7 @@ func PushToAndroid(droids []string, messageData MessageStruct) {
 		"cmd":        messageData.Cmd,
 	}
 	msg := gcm.NewMessage(data, title, "4", droids...)
-	sender := &gcm.Sender{ApiKey: ANDROID_API_KEY}
+	sender := &gcm.Sender{ApiKey: conf.Push.AndroidAPIKey}
 	// Send the message and receive the response after at most two retries.
 	response, err := sender.Send(msg, 2)
 	if err != nil {
@@ -75,7 +61,7 @@ func PushToAndroid(droids []string, messageData MessageStruct) {
 func PushToFireos(fires []string, messageData MessageStruct) {
 	for _, fire := range fires {
 		TRACE.Println("fire device " + fire)
-		title := CreatePushTitle(messageData.FromUsername, messageData.Content)
+		title := CreatePushTitle(messageData)
 		msg := new(ADMMessageStruct)
 		msg.Data = make(map[string]string, 6) // we have 6 things to send
 		// "message" is what's displayed in the notification bar
@@ -111,7 +97,12 @@ func PushToIos(IosDevices []string, messageData MessageStruct) {
 		// openssl pkcs12 -nocerts -out key.pem -in key.p12
 		// Remove password from pem file
 		// openssl rsa -in key.pem -out key-noenc.pem
-		APNSClient, err = apns.NewClientWithFiles(apns.SandboxGateway, APNS_CERT_FILE, APNS_KEY_FILE)
+		// generate .pem files for Apple certificates, see the config
+		gateway, feedbackGateway := apns.ProductionGateway, apns.ProductionFeedbackGateway
+		if conf.Push.APNSSandbox {
+			gateway, feedbackGateway = apns.SandboxGateway, apns.SandboxFeedbackGateway
+		}
+		APNSClient, err = apns.NewClientWithFiles(gateway, conf.Push.APNSCertFile, conf.Push.APNSKeyFile)
 		if err != nil {
 			ERROR.Printf("Could not create apns client", err.Error())
 		} else {
@@ -120,7 +111,7 @@ func PushToIos(IosDevices []string, messageData MessageStruct) {
 
 		// start feedback loop
 		go func() {
-			f, err := apns.NewFeedbackWithFiles(apns.SandboxFeedbackGateway, APNS_CERT_FILE, APNS_KEY_FILE)
+			f, err := apns.NewFeedbackWithFiles(feedbackGateway, conf.Push.APNSCertFile, conf.Push.APNSKeyFile)
 			if err != nil {
 				ERROR.Printf("Could not create feedback", err.Error())
 			} else {
@@ -150,7 +141,7 @@ func PushToIos(IosDevices []string, messageData MessageStruct) {
 	// 	"cmd":        "send_message",
 	// }
 
-	title := CreatePushTitle(messageData.FromUsername, messageData.Content)
+	title := CreatePushTitle(messageData)
 
 	for _, iOSDev := range IosDevices {
 		TRACE.Println("iOSDev = " + iOSDev)
@@ -178,7 +169,7 @@ func PushToIos(IosDevices []string, messageData MessageStruct) {
 }
 
 func PushToSMS(phonenum string, messageData MessageStruct, convoName string, toUsername string) {
-	mandrill.Key = MANDRILL_API_KEY
+	mandrill.Key = conf.Mail.MandrillAPIKey
 	// you can test your API key with Ping
 	err := mandrill.Ping()
 	// everything is OK if err is nil
@@ -188,11 +179,11 @@ func PushToSMS(phonenum string, messageData MessageStruct, convoName string, toU
 	}
 	TRACE.Println("PushToSMS, phonenum = " + phonenum)
 	msg := mandrill.NewMessageTo(phonenum, toUsername)
-	msg.HTML = messageData.Content
-	//msg.Text = messageData.Content
+	msg.HTML = MessageHTML(messageData)
+	msg.Text = MessageText(messageData)
 	msg.Subject = convoName
-	msg.FromEmail = messageData.CID + "@txt.pingedchat.com"
-	//msg.FromName = messageData.CID + "@txt.pingedchat.com"
+	msg.FromEmail = messageData.CID + "@" + conf.Mail.TextDomain
+	//msg.FromName = messageData.CID + "@" + conf.Mail.TextDomain
 	res, err := msg.Send(false)
 	if err != nil {
 		ERROR.Println("error in PushToSMS msg.Send(false)")
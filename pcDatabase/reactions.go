@@ -0,0 +1,101 @@
+package pcDatabase
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// REACTIONS
+// members can react to a message with an emoji (AddReaction) and take it
+// back (RemoveReaction).  They're stored next to the message rows and come
+// back with them, grouped by emoji, in GetConvoData, GetMoreConvoMessages and
+// GetAllConvoData.  Changes are only sent to members' web devices as
+// ReactionAdded and ReactionRemoved, phones don't get a push for a reaction.
+
+// longest emoji accepted, in bytes.  Emoji with skin tones and joiners take
+// a few code points
+const MAX_REACTION_LENGTH = 32
+
+type ReactionEvent struct {
+	Cmd      string `json:"cmd"`
+	CID      string `json:"CID"`
+	MID      string `json:"MID"`
+	Username string `json:"Username"`
+	Emoji    string `json:"emoji"`
+}
+
+// adds username under emoji, for building up a row's Reactions
+func addReaction(reactions []ReactionStruct, username string, emoji string) []ReactionStruct {
+	for i, reaction := range reactions {
+		if reaction.Emoji == emoji {
+			reactions[i].Usernames = append(reaction.Usernames, username)
+			return reactions
+		}
+	}
+	return append(reactions, ReactionStruct{Emoji: emoji, Usernames: []string{username}})
+}
+
+// an emoji is short and has nothing to print but itself
+func validateReaction(req interface{}) error {
+	emoji := req.(*ReactionCmdStruct).Emoji
+	if len(emoji) > MAX_REACTION_LENGTH || !utf8.ValidString(emoji) {
+		return cmdError(ERR_BAD_REQUEST, "emoji has to be at most %d bytes of UTF-8", MAX_REACTION_LENGTH)
+	}
+	for _, r := range emoji {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return cmdError(ERR_BAD_REQUEST, "emoji can't have spaces or control characters")
+		}
+	}
+	return nil
+}
+
+// the message has to be there to react to it
+func (db *Database) reactableMessage(CID string, MID string) error {
+	if db.Messages == nil {
+		return ErrMessagesDown
+	}
+	row, err := db.Messages.GetMessage(CID, MID)
+	if err == ErrNoMessage || (err == nil && row.Deleted) {
+		return ErrMessageNotFound
+	} else if err != nil {
+		logPqError("reactableMessage", err)
+		return ErrMessagesDown
+	}
+	return nil
+}
+
+func (db *Database) AddReaction(jsondata *ReactionCmdStruct) error {
+	if err := db.reactableMessage(jsondata.CID, jsondata.MID); err != nil {
+		return err
+	}
+	if err := db.Messages.AddReaction(jsondata.CID, jsondata.MID, db.Username(), jsondata.Emoji, getCurrentUTCISOTimeString()); err != nil {
+		logPqError("AddReaction", err)
+		return ErrMessagesDown
+	}
+	db.sendToConvoMembers(jsondata.CID, ReactionEvent{
+		Cmd:      "ReactionAdded",
+		CID:      jsondata.CID,
+		MID:      jsondata.MID,
+		Username: db.Username(),
+		Emoji:    jsondata.Emoji,
+	})
+	return nil
+}
+
+func (db *Database) RemoveReaction(jsondata *ReactionCmdStruct) error {
+	if err := db.reactableMessage(jsondata.CID, jsondata.MID); err != nil {
+		return err
+	}
+	if err := db.Messages.RemoveReaction(jsondata.CID, jsondata.MID, db.Username(), jsondata.Emoji); err != nil {
+		logPqError("RemoveReaction", err)
+		return ErrMessagesDown
+	}
+	db.sendToConvoMembers(jsondata.CID, ReactionEvent{
+		Cmd:      "ReactionRemoved",
+		CID:      jsondata.CID,
+		MID:      jsondata.MID,
+		Username: db.Username(),
+		Emoji:    jsondata.Emoji,
+	})
+	return nil
+}
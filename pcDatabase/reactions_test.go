@@ -0,0 +1,41 @@
+package pcDatabase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReactions(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	CID, MID := sendTestMessage(t, db)
+	bob := newTestConnection(db)
+	defer bob.Close()
+	dispatch(t, bob, `{"cmd":"ValidateUser","username":"bob","password":"secret","token":"bob-phone"}`, nil)
+
+	for _, conn := range []*Database{db, bob, bob} {
+		if env := dispatch(t, conn, `{"cmd":"AddReaction","CID":"`+CID+`","MID":"`+MID+`","emoji":"👍"}`, nil); !env.OK {
+			t.Fatalf("AddReaction returned %+v", env)
+		}
+	}
+	dispatch(t, bob, `{"cmd":"AddReaction","CID":"`+CID+`","MID":"`+MID+`","emoji":"😂"}`, nil)
+	for _, event := range expectEvents(t, db, 8) {
+		if !strings.Contains(event, `"ReactionAdded"`) {
+			t.Errorf("unexpected event %s", event)
+		}
+	}
+	convoData, _ := db.GetConvoData(&CIDCommandStruct{Cmd: "GetConvoData", CID: CID})
+	reactions := convoData.Messages[0].Reactions
+	if len(reactions) != 2 || reactions[0].Emoji != "👍" || len(reactions[0].Usernames) != 2 || reactions[1].Usernames[0] != "bob" {
+		t.Errorf("message reactions are %+v", reactions)
+	}
+
+	dispatch(t, bob, `{"cmd":"RemoveReaction","CID":"`+CID+`","MID":"`+MID+`","emoji":"👍"}`, nil)
+	expectEvents(t, db, 2)
+	convoData, _ = db.GetConvoData(&CIDCommandStruct{Cmd: "GetConvoData", CID: CID})
+	if reactions := convoData.Messages[0].Reactions; len(reactions) != 2 || len(reactions[0].Usernames) != 1 {
+		t.Errorf("message reactions are %+v after bob took theirs back", reactions)
+	}
+	expectError(t, db, `{"cmd":"AddReaction","CID":"`+CID+`","MID":"`+MID+`","emoji":"not an emoji"}`, ERR_BAD_REQUEST)
+	expectError(t, db, `{"cmd":"AddReaction","CID":"`+CID+`","MID":"nope","emoji":"👍"}`, ERR_NOT_FOUND)
+}
@@ -0,0 +1,103 @@
+package pcDatabase
+
+import (
+	"strings"
+	"time"
+)
+
+// READ RECEIPTS
+// every member's read_time in a conversation is kept up to date by
+// UpdateUserStatus.  GetReadReceipts turns those into who has read a message
+// (or everything up to a time) and how many of the members have.
+//
+// a user can hide their read time (HideReadTime).  It's still saved so their
+// own devices stay in step, but everyone else gets it blank, in the member
+// lists of GetConvoData and the rest as well as in read receipts
+
+// the usernames in members that hide their read time, upper case
+func (db *Database) hiddenReadTimes(members ConvoMemberArray) map[string]bool {
+	hidden := make(map[string]bool)
+	for _, m := range members {
+		if user := db.Users.GetUser(strings.ToUpper(m.Username)); user.HideReadTime {
+			hidden[user.UsernameUpper] = true
+		}
+	}
+	return hidden
+}
+
+// members as viewer sees them, without the read times of the others that
+// hide theirs.  An empty viewer sees nobody's hidden read time
+func (db *Database) membersSeenBy(members ConvoMemberArray, viewer string) ConvoMemberArray {
+	hidden := db.hiddenReadTimes(members)
+	seen := make(ConvoMemberArray, len(members))
+	for i, m := range members {
+		seen[i] = m
+		if hidden[strings.ToUpper(m.Username)] && !strings.EqualFold(m.Username, viewer) {
+			seen[i].ReadTime = ""
+		}
+	}
+	return seen
+}
+
+// GetReadReceipts is who in the conversation has read the message
+func (db *Database) GetReadReceipts(jsondata *ReadReceiptsCmdStruct) (*ReadReceiptsResponse, error) {
+	resp := &ReadReceiptsResponse{CID: jsondata.CID, MID: jsondata.MID, M_time: jsondata.M_time, ReadBy: make([]ReadReceiptStruct, 0)}
+	sender := ""
+	if jsondata.MID != "" || jsondata.M_time == "" {
+		if db.Messages == nil {
+			return nil, ErrMessagesDown
+		}
+		var row ConvoRowStruct
+		var err error
+		if jsondata.MID != "" {
+			row, err = db.Messages.GetMessage(jsondata.CID, jsondata.MID)
+		} else {
+			var latest []ConvoRowStruct
+			if latest, err = db.Messages.GetLatestMessages(jsondata.CID, 1); err == nil && len(latest) == 0 {
+				return resp, nil // nothing to have read
+			} else if err == nil {
+				row = latest[0]
+			}
+		}
+		if err == ErrNoMessage {
+			return nil, ErrMessageNotFound
+		} else if err != nil {
+			logPqError("GetReadReceipts", err)
+			return nil, ErrMessagesDown
+		}
+		resp.MID = row.MID
+		resp.M_time = row.M_time.UTC().Format(time.RFC3339Nano)
+		sender = row.F_username
+	} else if err := checkTimes(jsondata.M_time); err != nil {
+		return nil, err
+	}
+
+	mtime := parseTimeString(resp.M_time)
+	members := ToConvoMemberArray(db.Convos.GetConvoMembers(jsondata.CID))
+	hidden := db.hiddenReadTimes(members)
+	for _, m := range members {
+		if strings.EqualFold(m.Username, sender) {
+			continue
+		}
+		resp.Members++
+		if hidden[strings.ToUpper(m.Username)] && !strings.EqualFold(m.Username, db.Username()) {
+			resp.Hidden++
+		} else if m.ReadTime != "" && !parseTimeString(m.ReadTime).Before(mtime) {
+			resp.Seen++
+			resp.ReadBy = append(resp.ReadBy, ReadReceiptStruct{Username: m.Username, ReadTime: m.ReadTime})
+		}
+	}
+	return resp, nil
+}
+
+// HideReadTime stops (or starts again) sharing the caller's read time
+func (db *Database) HideReadTime(jsondata *HideReadTimeCmdStruct) error {
+	_, err := db.updateUser(jsondata.Username, func(user *UserStruct) error {
+		if user.HideReadTime == jsondata.Hide {
+			return errNoChange
+		}
+		user.HideReadTime = jsondata.Hide
+		return nil
+	})
+	return err
+}
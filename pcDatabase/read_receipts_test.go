@@ -0,0 +1,60 @@
+package pcDatabase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadReceipts(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "carol")
+	CID, MID := sendTestMessage(t, db)
+	db.AddUsersToConversation(&CIDCommandStruct{CID: CID, Members: []string{"carol"}, M_time: "2015-06-12T19:00:00.000Z"})
+	expectEvents(t, db, 3)
+
+	// bob reads alice's message, carol hasn't yet
+	db.UpdateUserStatus(&CmdConvoMember{CID: CID, Username: "bob", NewReadTime: "2015-06-12T19:25:00.000Z"})
+	expectEvents(t, db, 3)
+	receipts := ReadReceiptsResponse{}
+	dispatch(t, db, `{"cmd":"GetReadReceipts","CID":"`+CID+`","MID":"`+MID+`"}`, &receipts)
+	if receipts.Seen != 1 || receipts.Members != 2 || len(receipts.ReadBy) != 1 || receipts.ReadBy[0].Username != "bob" {
+		t.Errorf("receipts for alice's message are %+v", receipts)
+	}
+	// without a MID it's the latest message, the same one
+	latest := ReadReceiptsResponse{}
+	dispatch(t, db, `{"cmd":"GetReadReceipts","CID":"`+CID+`"}`, &latest)
+	if latest.MID != MID || latest.Seen != 1 {
+		t.Errorf("receipts for the latest message are %+v", latest)
+	}
+
+	// once bob hides it nobody else sees bob's read time
+	bob := newTestConnection(db)
+	defer bob.Close()
+	dispatch(t, bob, `{"cmd":"ValidateUser","username":"bob","password":"secret","token":"bob-phone"}`, nil)
+	if env := dispatch(t, bob, `{"cmd":"HideReadTime","hide":true}`, nil); !env.OK {
+		t.Fatalf("HideReadTime returned %+v", env)
+	}
+	receipts = ReadReceiptsResponse{}
+	dispatch(t, db, `{"cmd":"GetReadReceipts","CID":"`+CID+`","M_time":"2015-06-12T19:10:00.000Z"}`, &receipts)
+	if receipts.Seen != 1 || receipts.Hidden != 1 || receipts.Members != 3 || receipts.ReadBy[0].Username != "alice" {
+		t.Errorf("receipts with bob hidden are %+v", receipts)
+	}
+	convoData, _ := db.GetConvoData(&CIDCommandStruct{CID: CID})
+	for _, m := range ToConvoMemberArray(convoData.Members) {
+		if m.Username == "bob" && m.ReadTime != "" {
+			t.Errorf("alice sees bob's read time %q", m.ReadTime)
+		}
+	}
+	db.UpdateUserStatus(&CmdConvoMember{CID: CID, Username: "bob", NewReadTime: "2015-06-12T19:30:00.000Z"})
+	// only bob's own devices get it
+	withReadTime := 0
+	for _, event := range expectEvents(t, db, 3) {
+		if strings.Contains(event, "19:30") {
+			withReadTime++
+		}
+	}
+	if withReadTime != 1 {
+		t.Errorf("bob's read time went out %d times", withReadTime)
+	}
+}
@@ -0,0 +1,121 @@
+package pcDatabase
+
+import (
+	"encoding/json"
+)
+
+// responses sent back to the client as the envelope's data, see router.go
+
+type MatchUsersResponse struct {
+	MatchedUsers []MatchUsersReturnStruct
+}
+
+type InviteResponse struct {
+	FoundUsers []FoundUserStruct
+}
+
+// the email as it was saved to the sender's mailbox
+type SentEmailStruct struct {
+	FromEmail   string
+	ToEmails    []string
+	Subject     string
+	Content     string
+	Attachments []string
+	RecvTime    string
+	M_time      string
+}
+
+type S3PolicyResponse struct {
+	Policy    string `json:"policy"`
+	Signature string `json:"signature"`
+	AWSKey    string `json:"AWS_KEY"`
+}
+
+type SessionResponse struct {
+	SessionToken string `json:"sessionToken"`
+	Expires      string
+}
+
+type SessionsResponse struct {
+	Sessions []SessionInfo
+}
+
+// a session as the user sees it, the device token stays on the server
+type SessionInfo struct {
+	ID      string
+	Created string
+	Expires string
+	Current bool // the session asking
+}
+
+// earlier contents of a message, oldest first
+type MessageEditsResponse struct {
+	CID   string
+	MID   string
+	Edits []MessageEditStruct
+}
+
+// a page of replies, oldest first.  More is set when there are more after
+// the last one
+type ThreadResponse struct {
+	CID     string
+	Parent  ConvoRowStruct
+	Replies []ConvoRowStruct
+	More    bool
+}
+
+// best match first, Cursor is empty on the last page
+type SearchMessagesResponse struct {
+	Query   string
+	Results []SearchResultStruct
+	Cursor  string `json:",omitempty"`
+}
+
+// a page of the change log after the cursor GetChangesSince was given
+type ChangesResponse struct {
+	Changes []ChangeStruct
+	// pass it back for the next page, or after loading everything again
+	Cursor           string
+	More             bool
+	SnapshotRequired bool
+}
+
+type DeviceEventsResponse struct {
+	// the events with their eventSeq, oldest first
+	Events []json.RawMessage
+	// the last eventSeq in Events, ack it or pass it back for the next page
+	Seq  int64
+	More bool
+	// some of the events after the seq asked for are gone, catch up with
+	// GetChangesSince and carry on from Seq
+	Gap bool
+}
+
+type PresenceResponse struct {
+	Presence []PresenceStruct
+}
+
+// who has read up to M_time, "seen by Seen of Members".  The sender of the
+// message isn't counted, and Hidden members don't say either way
+type ReadReceiptsResponse struct {
+	CID     string
+	MID     string `json:",omitempty"`
+	M_time  string
+	ReadBy  []ReadReceiptStruct
+	Seen    int
+	Members int
+	Hidden  int
+}
+
+// every bot, Enabled is whether it's on in the conversation asked about
+type BotsResponse struct {
+	Bots []BotInfo
+}
+
+type BotInfo struct {
+	Name     string
+	Help     string
+	Triggers []string // regular expressions matched against a message's plain text
+	Enabled  bool
+	AlwaysOn bool // can't be turned off
+}
@@ -0,0 +1,607 @@
+package pcDatabase
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// RICH TEXT
+// a message's content is text with a little markup, never HTML:
+//
+//	*bold*  _italic_  ~struck out~  `code`  ```a block of code```
+//	[what the link says](https://where.it/goes), bare http(s) links too
+//
+// a backslash in front of any of *_~`[]\ makes it a plain character, and
+// line breaks are kept.  A marker only counts when it hugs what it marks
+// (*this*, not * this *) and is closed on the same line, anything that
+// doesn't parse is just text.  Links only go to http, https and mailto.
+// Pictures, gifs, files and the weather aren't in the content at all,
+// they're the message's attachments.
+//
+// clients render content themselves.  Where the server has to hand over
+// something else (push notifications, and SMS which goes out as email) it
+// uses MessageText and MessageHTML, which escape everything that isn't
+// markup.
+//
+// messages from before this are HTML.  Those rows are turned into markup
+// when they're read (legacyHTMLToMarkup) and for good by
+// pingedchat migrate-markup, see markup_migration.go
+
+const (
+	ATTACHMENT_IMAGE   = "image"
+	ATTACHMENT_GIF     = "gif"
+	ATTACHMENT_FILE    = "file"
+	ATTACHMENT_WEATHER = "weather" // only from @forecast
+	ATTACHMENT_LINK    = "link"    // only from link previews, see link_previews.go
+
+	// attachments per message
+	MAX_MESSAGE_ATTACHMENTS = 10
+	// characters of a file name or gif search kept
+	MAX_ATTACHMENT_TITLE = 200
+	// characters of the message in a push title
+	PUSH_TITLE_LENGTH = 40
+
+	// the characters a backslash escapes
+	markupSpecial = "*_~`[]\\"
+)
+
+var ErrBadAttachment = cmdError(ERR_BAD_REQUEST, "attachments are images or files with an http or https url, at most %d of them", MAX_MESSAGE_ATTACHMENTS)
+
+// what content parses into
+type markupNode struct {
+	kind     int
+	text     string       // nodeText, nodeCode and nodePre
+	url      string       // nodeLink
+	children []markupNode // nodeBold, nodeItalic, nodeStrike and nodeLink
+}
+
+const (
+	nodeText = iota
+	nodeBreak
+	nodeBold
+	nodeItalic
+	nodeStrike
+	nodeCode
+	nodePre
+	nodeLink
+)
+
+var (
+	markupEmphasis = map[byte]int{'*': nodeBold, '_': nodeItalic, '~': nodeStrike}
+	markupTags     = map[int]string{nodeBold: "strong", nodeItalic: "em", nodeStrike: "s"}
+)
+
+// sanitizeMarkup is content the way it's stored: valid UTF-8, \n for line
+// breaks and no other control characters
+func sanitizeMarkup(content string) string {
+	content = strings.ToValidUTF8(content, "�")
+	content = strings.Replace(content, "\r\n", "\n", -1)
+	return strings.Map(func(r rune) rune {
+		if r == '\r' {
+			return '\n'
+		} else if r != '\n' && r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, content)
+}
+
+// EscapeMarkup makes text that was never meant as markup (an SMS, an old
+// HTML message) come out the same once it's parsed
+func EscapeMarkup(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if strings.IndexByte(markupSpecial, text[i]) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(text[i])
+	}
+	return b.String()
+}
+
+// the schemes a link can have, attachments only get http and https
+func safeURL(raw string, schemes ...string) bool {
+	if raw == "" || strings.IndexFunc(raw, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	for _, s := range schemes {
+		if scheme == s {
+			return scheme == "mailto" || u.Host != ""
+		}
+	}
+	return false
+}
+
+func safeLinkURL(raw string) bool {
+	return safeURL(raw, "http", "https", "mailto")
+}
+
+func safeAttachmentURL(raw string) bool {
+	return safeURL(raw, "http", "https")
+}
+
+// sanitizeAttachments checks the attachments a client sent, they can only
+// be images and files
+func sanitizeAttachments(attachments []MessageAttachment) ([]MessageAttachment, error) {
+	if len(attachments) > MAX_MESSAGE_ATTACHMENTS {
+		return nil, ErrBadAttachment
+	}
+	sanitized := make([]MessageAttachment, 0, len(attachments))
+	for _, a := range attachments {
+		if (a.Type != ATTACHMENT_IMAGE && a.Type != ATTACHMENT_FILE) || !safeAttachmentURL(a.URL) {
+			return nil, ErrBadAttachment
+		}
+		title := []rune(strings.TrimSpace(sanitizeMarkup(a.Title)))
+		if len(title) > MAX_ATTACHMENT_TITLE {
+			title = title[:MAX_ATTACHMENT_TITLE]
+		}
+		sanitized = append(sanitized, MessageAttachment{
+			Type:   a.Type,
+			URL:    a.URL,
+			Title:  strings.Replace(string(title), "\n", " ", -1),
+			Width:  a.Width,
+			Height: a.Height,
+		})
+	}
+	if len(sanitized) == 0 {
+		return nil, nil
+	}
+	return sanitized, nil
+}
+
+// parsing
+
+func parseMarkup(content string) []markupNode {
+	var nodes []markupNode
+	for {
+		start := strings.Index(content, "```")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(content[start+3:], "```")
+		if end < 0 {
+			break
+		}
+		nodes = append(nodes, parseInline(content[:start], true)...)
+		nodes = append(nodes, markupNode{kind: nodePre, text: strings.Trim(content[start+3:start+3+end], "\n")})
+		content = content[start+3+end+3:]
+	}
+	return append(nodes, parseInline(content, true)...)
+}
+
+func isMarkupSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isWordByte(c byte) bool {
+	return c >= 0x80 || c == '_' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// parseInline is everything but code blocks, links is false inside a link
+// so they don't nest
+func parseInline(s string, links bool) []markupNode {
+	var nodes []markupNode
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, markupNode{kind: nodeText, text: text.String()})
+			text.Reset()
+		}
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && strings.IndexByte(markupSpecial, s[i+1]) >= 0 {
+			text.WriteByte(s[i+1])
+			i += 2
+			continue
+		}
+		if c == '\n' {
+			flush()
+			nodes = append(nodes, markupNode{kind: nodeBreak})
+			i++
+			continue
+		}
+		if c == '`' {
+			if end := strings.IndexAny(s[i+1:], "`\n"); end > 0 && s[i+1+end] == '`' {
+				flush()
+				nodes = append(nodes, markupNode{kind: nodeCode, text: s[i+1 : i+1+end]})
+				i += end + 2
+				continue
+			}
+		}
+		if kind, ok := markupEmphasis[c]; ok {
+			if end := closingMarker(s, i); end > 0 {
+				flush()
+				nodes = append(nodes, markupNode{kind: kind, children: parseInline(s[i+1:end], links)})
+				i = end + 1
+				continue
+			}
+		}
+		if c == '[' && links {
+			if label, target, end := markupLink(s, i); end > 0 {
+				flush()
+				nodes = append(nodes, markupNode{kind: nodeLink, url: target, children: parseInline(label, false)})
+				i = end
+				continue
+			}
+		}
+		if (c == 'h' || c == 'H') && links && (i == 0 || isMarkupSpace(s[i-1]) || s[i-1] == '(') {
+			if target, end := bareURL(s, i); end > 0 {
+				flush()
+				nodes = append(nodes, markupNode{kind: nodeLink, url: target, children: []markupNode{{kind: nodeText, text: target}}})
+				i = end
+				continue
+			}
+		}
+		text.WriteByte(c)
+		i++
+	}
+	flush()
+	return nodes
+}
+
+// where the emphasis opened at s[start] closes, or -1
+func closingMarker(s string, start int) int {
+	m := s[start]
+	if start+1 >= len(s) || isMarkupSpace(s[start+1]) || (m == '_' && start > 0 && isWordByte(s[start-1])) {
+		return -1
+	}
+	for j := start + 1; j < len(s); j++ {
+		switch {
+		case s[j] == '\n':
+			return -1
+		case s[j] == '\\':
+			j++ // whatever's escaped doesn't close anything
+		case s[j] == m && j > start+1 && !isMarkupSpace(s[j-1]) && (m != '_' || j+1 == len(s) || !isWordByte(s[j+1])):
+			return j
+		}
+	}
+	return -1
+}
+
+// [label](url) at s[start], end is just past it or 0 when it isn't a link
+func markupLink(s string, start int) (label string, target string, end int) {
+	for j := start + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '\n', '[':
+			return "", "", 0
+		case ']':
+			if j == start+1 || j+1 >= len(s) || s[j+1] != '(' {
+				return "", "", 0
+			}
+			close := strings.IndexAny(s[j+2:], ") \t\n")
+			if close < 0 || s[j+2+close] != ')' {
+				return "", "", 0
+			}
+			target = s[j+2 : j+2+close]
+			if !safeLinkURL(target) {
+				return "", "", 0
+			}
+			return s[start+1 : j], target, j + 3 + close
+		}
+	}
+	return "", "", 0
+}
+
+// the http or https link at s[start] and just past it, or 0.  Punctuation
+// at the end is the sentence's, not the link's
+func bareURL(s string, start int) (string, int) {
+	head := s[start:]
+	if len(head) > len("https://") {
+		head = head[:len("https://")]
+	}
+	if head = strings.ToLower(head); !strings.HasPrefix(head, "http://") && head != "https://" {
+		return "", 0
+	}
+	end := start
+	for end < len(s) && !isMarkupSpace(s[end]) {
+		end++
+	}
+	for end > start {
+		last := s[end-1]
+		if strings.IndexByte(".,!?;:'\"", last) >= 0 || (last == ')' && strings.Count(s[start:end], "(") < strings.Count(s[start:end], ")")) {
+			end--
+			continue
+		}
+		break
+	}
+	// EscapeMarkup puts backslashes in links too
+	target := strings.NewReplacer(`\*`, `*`, `\_`, `_`, `\~`, `~`, "\\`", "`", `\[`, `[`, `\]`, `]`, `\\`, `\`).Replace(s[start:end])
+	if !safeAttachmentURL(target) {
+		return "", 0
+	}
+	return target, end
+}
+
+// rendering
+
+func renderMarkupHTML(b *strings.Builder, nodes []markupNode) {
+	for _, n := range nodes {
+		switch n.kind {
+		case nodeText:
+			b.WriteString(html.EscapeString(n.text))
+		case nodeBreak:
+			b.WriteString("<br>")
+		case nodeCode:
+			b.WriteString("<code>" + html.EscapeString(n.text) + "</code>")
+		case nodePre:
+			b.WriteString("<pre><code>" + html.EscapeString(n.text) + "</code></pre>")
+		case nodeLink:
+			b.WriteString(`<a href="` + html.EscapeString(n.url) + `" rel="nofollow noopener noreferrer" target="_blank">`)
+			renderMarkupHTML(b, n.children)
+			b.WriteString("</a>")
+		default:
+			b.WriteString("<" + markupTags[n.kind] + ">")
+			renderMarkupHTML(b, n.children)
+			b.WriteString("</" + markupTags[n.kind] + ">")
+		}
+	}
+}
+
+func renderMarkupText(b *strings.Builder, nodes []markupNode) {
+	for _, n := range nodes {
+		switch n.kind {
+		case nodeText, nodeCode:
+			b.WriteString(n.text)
+		case nodeBreak:
+			b.WriteString("\n")
+		case nodePre:
+			b.WriteString("\n" + n.text + "\n")
+		case nodeLink:
+			var label strings.Builder
+			renderMarkupText(&label, n.children)
+			b.WriteString(label.String())
+			if label.String() != n.url {
+				b.WriteString(" (" + n.url + ")")
+			}
+		default:
+			renderMarkupText(b, n.children)
+		}
+	}
+}
+
+// MarkupToText is content without the markup
+func MarkupToText(content string) string {
+	var b strings.Builder
+	renderMarkupText(&b, parseMarkup(content))
+	return strings.TrimSpace(b.String())
+}
+
+// MarkupToHTML is content as HTML, everything that isn't markup is escaped
+func MarkupToHTML(content string) string {
+	var b strings.Builder
+	renderMarkupHTML(&b, parseMarkup(content))
+	return b.String()
+}
+
+func attachmentNoun(a MessageAttachment) string {
+	switch a.Type {
+	case ATTACHMENT_IMAGE:
+		return "a picture"
+	case ATTACHMENT_GIF:
+		return "a gif"
+	case ATTACHMENT_WEATHER:
+		return "the weather"
+	case ATTACHMENT_LINK:
+		return "a link"
+	}
+	return "a file"
+}
+
+func weatherText(a MessageAttachment) string {
+	text := a.Summary
+	if a.Temperature != nil {
+		text = strings.TrimSpace(fmt.Sprintf("%.0f° %s", *a.Temperature, text))
+	}
+	return text
+}
+
+// MessageText is the message as plain text, for push notifications and SMS
+func MessageText(msg MessageStruct) string {
+	lines := []string{}
+	if text := MarkupToText(msg.Content); text != "" {
+		lines = append(lines, text)
+	}
+	for _, a := range msg.Attachments {
+		switch {
+		case a.Type == ATTACHMENT_WEATHER:
+			lines = append(lines, weatherText(a))
+		case a.Title != "":
+			lines = append(lines, a.Title+": "+a.URL)
+		default:
+			lines = append(lines, a.URL)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MessageHTML is the message as HTML, for SMS sent as email
+func MessageHTML(msg MessageStruct) string {
+	var b strings.Builder
+	b.WriteString(MarkupToHTML(msg.Content))
+	for _, a := range msg.Attachments {
+		if a.Type != ATTACHMENT_WEATHER && !safeAttachmentURL(a.URL) {
+			continue
+		}
+		b.WriteString("<p>")
+		switch a.Type {
+		case ATTACHMENT_IMAGE, ATTACHMENT_GIF:
+			b.WriteString(`<img src="` + html.EscapeString(a.URL) + `" alt="` + html.EscapeString(a.Title) + `">`)
+		case ATTACHMENT_WEATHER:
+			b.WriteString(html.EscapeString(weatherText(a)))
+		default:
+			title := a.Title
+			if title == "" {
+				title = a.URL
+			}
+			b.WriteString(`<a href="` + html.EscapeString(a.URL) + `" rel="nofollow noopener noreferrer" target="_blank">` + html.EscapeString(title) + `</a>`)
+		}
+		b.WriteString("</p>")
+	}
+	return b.String()
+}
+
+// legacy HTML
+
+var (
+	legacyTagPattern  = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9]*)((?:[^>"']|"[^"]*"|'[^']*')*)>`)
+	legacyAttrPattern = regexp.MustCompile(`([a-zA-Z_:-]+)\s*=\s*("[^"]*"|'[^']*'|[^\s"'>]+)`)
+	legacyBreaks      = regexp.MustCompile(`\n{3,}`)
+
+	// tags dropped with everything in them
+	legacyDroppedTags = map[string]bool{"script": true, "style": true, "iframe": true, "object": true, "head": true, "title": true}
+	// the markup for the tags that have some, links are done separately
+	legacyMarkers = map[string]string{"b": "*", "strong": "*", "i": "_", "em": "_", "s": "~", "strike": "~", "del": "~", "code": "`"}
+	// tags that end a line
+	legacyBlocks = map[string]bool{"p": true, "div": true, "li": true, "tr": true, "h1": true, "h2": true, "h3": true, "blockquote": true}
+)
+
+type legacyOpenTag struct {
+	name string
+	at   int // where its marker starts in the output
+	href string
+}
+
+// legacyHTMLToMarkup is an old HTML message as markup, images come out as
+// attachments and anything else that isn't text is dropped
+func legacyHTMLToMarkup(content string) (string, []MessageAttachment) {
+	var out bytes.Buffer
+	var attachments []MessageAttachment
+	var open []legacyOpenTag
+	skip := "" // inside a dropped tag until it ends
+	for len(content) > 0 {
+		lt := strings.IndexByte(content, '<')
+		if lt < 0 {
+			lt = len(content)
+		}
+		if skip == "" {
+			out.WriteString(EscapeMarkup(html.UnescapeString(content[:lt])))
+		}
+		content = content[lt:]
+		if content == "" {
+			break
+		}
+		if strings.HasPrefix(content, "<!--") {
+			end := strings.Index(content, "-->")
+			if end < 0 {
+				break
+			}
+			content = content[end+3:]
+			continue
+		}
+		match := legacyTagPattern.FindStringSubmatch(content)
+		if match == nil {
+			// not a tag, eg. "<3"
+			if skip == "" {
+				out.WriteByte('<')
+			}
+			content = content[1:]
+			continue
+		}
+		content = content[len(match[0]):]
+		closing, name := match[1] == "/", strings.ToLower(match[2])
+		if skip != "" {
+			if closing && name == skip {
+				skip = ""
+			}
+			continue
+		}
+		attrs := make(map[string]string)
+		for _, attr := range legacyAttrPattern.FindAllStringSubmatch(match[3], -1) {
+			attrs[strings.ToLower(attr[1])] = html.UnescapeString(strings.Trim(attr[2], `"'`))
+		}
+		switch {
+		case legacyDroppedTags[name]:
+			if !closing {
+				skip = name
+			}
+		case name == "br":
+			out.WriteByte('\n')
+		case legacyBlocks[name]:
+			if out.Len() > 0 && !bytes.HasSuffix(out.Bytes(), []byte("\n")) {
+				out.WriteByte('\n')
+			}
+		case name == "img":
+			if src := attrs["src"]; safeAttachmentURL(src) {
+				a := MessageAttachment{Type: ATTACHMENT_IMAGE, URL: src, Title: attrs["alt"]}
+				if u, _ := url.Parse(src); u != nil && strings.HasSuffix(strings.ToLower(u.Host), "giphy.com") {
+					a.Type = ATTACHMENT_GIF
+				}
+				attachments = append(attachments, a)
+			}
+		case name == "a" || legacyMarkers[name] != "":
+			if !closing {
+				open = append(open, legacyOpenTag{name: name, at: out.Len(), href: attrs["href"]})
+				out.WriteString(legacyMarkers[name])
+				continue
+			}
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i].name == name {
+					closeLegacyTag(&out, open[i])
+					open = append(open[:i], open[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	// never closed, their markers would only show up as they are
+	for i := len(open) - 1; i >= 0; i-- {
+		if marker := legacyMarkers[open[i].name]; marker != "" {
+			b := out.Bytes()
+			rest := append([]byte(nil), b[open[i].at+len(marker):]...)
+			out.Truncate(open[i].at)
+			out.Write(rest)
+		}
+	}
+	return strings.TrimSpace(legacyBreaks.ReplaceAllString(out.String(), "\n\n")), attachments
+}
+
+// turns what's been written since tag opened into markup
+func closeLegacyTag(out *bytes.Buffer, tag legacyOpenTag) {
+	marker := legacyMarkers[tag.name]
+	inner := string(out.Bytes()[tag.at+len(marker):])
+	out.Truncate(tag.at)
+	trimmed := strings.TrimSpace(inner)
+	if trimmed == "" {
+		out.WriteString(inner)
+		return
+	}
+	// the spaces go outside the markers, which have to hug the text
+	lead := inner[:strings.Index(inner, trimmed)]
+	trail := inner[len(lead)+len(trimmed):]
+	out.WriteString(lead)
+	switch {
+	case tag.name == "a":
+		href := strings.Replace(tag.href, ")", "%29", -1)
+		if !safeLinkURL(href) || strings.Contains(trimmed, "\n") || trimmed == EscapeMarkup(tag.href) {
+			out.WriteString(trimmed)
+		} else {
+			out.WriteString("[" + trimmed + "](" + href + ")")
+		}
+	case marker == "`":
+		// code has no escapes
+		code := strings.NewReplacer(`\*`, `*`, `\_`, `_`, `\~`, `~`, "\\`", "`", `\[`, `[`, `\]`, `]`, `\\`, `\`).Replace(trimmed)
+		if strings.ContainsAny(code, "`\n") {
+			out.WriteString(trimmed)
+		} else {
+			out.WriteString("`" + code + "`")
+		}
+	case strings.Contains(trimmed, "\n"):
+		out.WriteString(trimmed)
+	default:
+		out.WriteString(marker + trimmed + marker)
+	}
+	out.WriteString(trail)
+}
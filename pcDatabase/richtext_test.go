@@ -0,0 +1,102 @@
+package pcDatabase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkupToHTML(t *testing.T) {
+	for content, want := range map[string]string{
+		`<script>alert(1)</script>`:      `&lt;script&gt;alert(1)&lt;/script&gt;`,
+		`*bold* and _italic_ ~gone~`:     `<strong>bold</strong> and <em>italic</em> <s>gone</s>`,
+		`*bold _and italic_*`:            `<strong>bold <em>and italic</em></strong>`,
+		`snake_case_name, 2 * 3 * 4`:     `snake_case_name, 2 * 3 * 4`,
+		`\*not bold\*`:                   `*not bold*`,
+		"two\nlines":                     `two<br>lines`,
+		"`*code*` and ```\n<b>\n```":     `<code>*code*</code> and <pre><code>&lt;b&gt;</code></pre>`,
+		`[site](https://a.com/?q=1&b=2)`: `<a href="https://a.com/?q=1&amp;b=2" rel="nofollow noopener noreferrer" target="_blank">site</a>`,
+		`[x](javascript:alert(1))`:       `[x](javascript:alert(1))`,
+		`see https://a.com/a_b_c.`:       `see <a href="https://a.com/a_b_c" rel="nofollow noopener noreferrer" target="_blank">https://a.com/a_b_c</a>.`,
+		`"><img src=x onerror=alert(1)>`: `&#34;&gt;&lt;img src=x onerror=alert(1)&gt;`,
+	} {
+		if got := MarkupToHTML(content); got != want {
+			t.Errorf("MarkupToHTML(%q) = %s, expected %s", content, got, want)
+		}
+	}
+}
+
+func TestMessageText(t *testing.T) {
+	temperature := 71.6
+	msg := MessageStruct{
+		FromUsername: "bob",
+		Content:      "*look* at [this](https://a.com)",
+		Attachments: []MessageAttachment{
+			{Type: ATTACHMENT_IMAGE, URL: "https://a.com/cat.jpg", Title: "cat.jpg"},
+			{Type: ATTACHMENT_WEATHER, Temperature: &temperature, Summary: "Clear"},
+		},
+	}
+	if text := MessageText(msg); text != "look at this (https://a.com)\ncat.jpg: https://a.com/cat.jpg\n72° Clear" {
+		t.Errorf("MessageText = %q", text)
+	}
+	if html := MessageHTML(msg); !strings.Contains(html, `<img src="https://a.com/cat.jpg" alt="cat.jpg">`) {
+		t.Errorf("MessageHTML = %s", html)
+	}
+
+	msg.Content = ""
+	if title := CreatePushTitle(msg); title != "bob sent you a picture!" {
+		t.Errorf("title %q", title)
+	}
+	msg.Content = "https://a.com"
+	if title := CreatePushTitle(msg); title != "bob sent you a link!" {
+		t.Errorf("title %q", title)
+	}
+	msg.Content = strings.Repeat("é", PUSH_TITLE_LENGTH+1)
+	if title := CreatePushTitle(msg); title != "bob - "+strings.Repeat("é", PUSH_TITLE_LENGTH) {
+		t.Errorf("title %q", title)
+	}
+}
+
+func TestLegacyHTMLToMarkup(t *testing.T) {
+	for legacy, want := range map[string]string{
+		`look <a href="https://a.com">here</a>`:     `look [here](https://a.com)`,
+		`<a href="https://a.com">https://a.com</a>`: `https://a.com`,
+		`<a href="javascript:alert(1)">click</a>`:   `click`,
+		`hi<script>alert("<b>")</script> there`:     `hi there`,
+		`<b>2*3</b> &lt;3 <3 <i> </i>`:              `*2\*3* <3 <3`,
+		`<div><i>Forecast</i></div><div>72</div>`:   "_Forecast_\n72",
+		`<b>never closed`:                           `never closed`,
+	} {
+		if got, _ := legacyHTMLToMarkup(legacy); got != want {
+			t.Errorf("legacyHTMLToMarkup(%q) = %q, expected %q", legacy, got, want)
+		}
+	}
+
+	// what @giphy used to send
+	content, attachments := legacyHTMLToMarkup(`@giphy cats:<br> <img src="https://media.giphy.com/cats.gif" />`)
+	if content != "@giphy cats:" || len(attachments) != 1 || attachments[0].Type != ATTACHMENT_GIF || attachments[0].URL != "https://media.giphy.com/cats.gif" {
+		t.Errorf("got %q with %+v", content, attachments)
+	}
+	if html := MarkupToHTML(`*2\*3*`); html != `<strong>2*3</strong>` {
+		t.Errorf("converted markup renders as %s", html)
+	}
+}
+
+func TestSendMessageAttachments(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "bob")
+	createTestUser(t, db, "alice")
+	CID := createTestConversation(t, db, "alice", "bob")
+
+	send := `{"cmd":"SendMessage","CID":"` + CID + `","t_UIDs":["alice","bob"],"m_time":"2015-06-12T19:20:00.000Z","content":"cat\r\n\u0007pic",`
+	expectError(t, db, send+`"attachments":[{"type":"image","url":"javascript:alert(1)"}]}`, ERR_BAD_REQUEST)
+	expectError(t, db, send+`"attachments":[{"type":"weather","summary":"sunny"}]}`, ERR_BAD_REQUEST)
+	if env := dispatch(t, db, send+`"attachments":[{"type":"image","url":"https://a.com/cat.jpg"}]}`, nil); !env.OK {
+		t.Fatalf("SendMessage returned %+v", env)
+	}
+	expectEvents(t, db, 2)
+	rows, _ := db.Messages.GetAllMessages(CID)
+	if len(rows) != 1 || rows[0].Content != "cat\npic" || len(rows[0].Attachments) != 1 || rows[0].Attachments[0].URL != "https://a.com/cat.jpg" {
+		t.Errorf("conversation has messages %+v", rows)
+	}
+}
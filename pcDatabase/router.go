@@ -0,0 +1,356 @@
+package pcDatabase
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Command is one command the clients can send over sockjs.  Handler is a
+// method expression like (*Database).GetConvoData taking a pointer to the
+// request struct, and returning either nothing, an error, a pointer to the
+// response struct or the response and an error.  Every command gets an
+// Envelope back, the response goes in its data.
+type Command struct {
+	Name string
+	Doc  string
+	// func(*Database, *Req), func(*Database, *Req) error, func(*Database, *Req) *Resp
+	// or func(*Database, *Req) (*Resp, error)
+	Handler interface{}
+	// request fields (the Go names) that can't be empty
+	Required []string
+	// request fields that name the caller, BindIdentity fills them in from
+	// the session and rejects anyone else
+	Identity []string
+	// the caller has to be a member of the request's CID
+	Member bool
+	// optional, more checks on the decoded request before the handler runs
+	Validate func(req interface{}) error
+	// can be sent before the user logs in
+	Public bool
+
+	handler  reflect.Value
+	request  reflect.Type
+	response reflect.Type // nil if the command has no data
+}
+
+// Call is a decoded command on its way through the middleware
+type Call struct {
+	Command *Command
+	Request interface{} // pointer to the command's request struct
+}
+
+// Handler runs a call, the response is nil when there's no data to send back
+type Handler func(db *Database, call *Call) (interface{}, error)
+
+// Middleware wraps every command, see middleware.go
+type Middleware func(next Handler) Handler
+
+// Router maps command names to their handlers
+type Router struct {
+	commands   map[string]*Command
+	middleware []Middleware
+	handler    Handler // the handlers wrapped in all the middleware
+}
+
+func NewRouter() *Router {
+	r := &Router{commands: make(map[string]*Command)}
+	r.handler = callHandler
+	return r
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Register adds a command, it panics on a handler with the wrong signature
+// since that's a programming error that should never make it past startup
+func (r *Router) Register(cmd Command) {
+	if _, ok := r.commands[cmd.Name]; ok {
+		panic("command " + cmd.Name + " registered twice")
+	}
+	fn := reflect.ValueOf(cmd.Handler)
+	t := fn.Type()
+	if t.Kind() != reflect.Func || t.NumIn() != 2 || t.In(0) != reflect.TypeOf(&Database{}) ||
+		t.In(1).Kind() != reflect.Ptr || t.In(1).Elem().Kind() != reflect.Struct {
+		panic("command " + cmd.Name + " needs a handler like func(*Database, *Request)")
+	}
+	switch {
+	case t.NumOut() == 0:
+	case t.NumOut() == 1 && t.Out(0) == errorType:
+	case t.NumOut() == 1 && t.Out(0).Kind() == reflect.Ptr:
+		cmd.response = t.Out(0).Elem()
+	case t.NumOut() == 2 && t.Out(0).Kind() == reflect.Ptr && t.Out(1) == errorType:
+		cmd.response = t.Out(0).Elem()
+	default:
+		panic("command " + cmd.Name + " must return nothing, error, *Response or (*Response, error)")
+	}
+	cmd.handler = fn
+	cmd.request = t.In(1).Elem()
+	for _, field := range cmd.Required {
+		if _, ok := cmd.request.FieldByName(field); !ok {
+			panic("command " + cmd.Name + " requires " + field + " but " + cmd.request.Name() + " doesn't have it")
+		}
+	}
+	for _, field := range cmd.Identity {
+		if f, ok := cmd.request.FieldByName(field); !ok || f.Type.Kind() != reflect.String {
+			panic("command " + cmd.Name + " takes the caller from " + field + " but " + cmd.request.Name() + " doesn't have it as a string")
+		}
+	}
+	if f, ok := cmd.request.FieldByName("CID"); cmd.Member && (!ok || f.Type.Kind() != reflect.String) {
+		panic("command " + cmd.Name + " checks membership but " + cmd.request.Name() + " has no CID")
+	}
+	r.commands[cmd.Name] = &cmd
+}
+
+// Use adds middleware, the first one added is the outermost
+func (r *Router) Use(middleware ...Middleware) {
+	r.middleware = append(r.middleware, middleware...)
+	r.handler = callHandler
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		r.handler = r.middleware[i](r.handler)
+	}
+}
+
+// Command looks up a registered command, nil if there isn't one
+func (r *Router) Command(name string) *Command {
+	return r.commands[name]
+}
+
+// the innermost Handler, actually calls the command
+func callHandler(db *Database, call *Call) (interface{}, error) {
+	out := call.Command.handler.Call([]reflect.Value{reflect.ValueOf(db), reflect.ValueOf(call.Request)})
+	var err error
+	if len(out) > 0 && out[len(out)-1].Type() == errorType && !out[len(out)-1].IsNil() {
+		err = out[len(out)-1].Interface().(error)
+	}
+	if len(out) == 0 || out[0].Type() == errorType || out[0].IsNil() {
+		return nil, err
+	}
+	return out[0].Interface(), err
+}
+
+// Envelope wraps the answer to every command.  ReqID is whatever the client
+// sent as reqId so it can match answers to requests, OK says if the command
+// worked, then there's either the command's Data or an Error.
+type Envelope struct {
+	Cmd   string        `json:"cmd"`
+	ReqID string        `json:"reqId,omitempty"`
+	OK    bool          `json:"ok"`
+	Data  interface{}   `json:"data,omitempty"`
+	Error *CommandError `json:"error,omitempty"`
+}
+
+// Dispatch decodes msg, runs its command through the middleware and returns
+// the Envelope to send back
+func (r *Router) Dispatch(db *Database, msg string) string {
+	cmdJSON := struct {
+		Cmd   string `json:"cmd"`
+		ReqID string `json:"reqId"`
+	}{}
+	if err := json.Unmarshal([]byte(msg), &cmdJSON); err != nil {
+		return envelopeString(Envelope{Error: cmdError(ERR_BAD_REQUEST, "%v", err)})
+	}
+	env := Envelope{Cmd: cmdJSON.Cmd, ReqID: cmdJSON.ReqID}
+	cmd, ok := r.commands[cmdJSON.Cmd]
+	if !ok {
+		ERROR.Println("Command " + cmdJSON.Cmd + " was not found and will not be executed.")
+		env.Error = cmdError(ERR_UNKNOWN_COMMAND, "unknown command %q", cmdJSON.Cmd)
+		return envelopeString(env)
+	}
+	req := reflect.New(cmd.request).Interface()
+	if err := json.Unmarshal([]byte(msg), req); err != nil {
+		env.Error = cmdError(ERR_BAD_REQUEST, "%v", err)
+		return envelopeString(env)
+	}
+	if err := cmd.validate(req); err != nil {
+		env.Error = toCommandError(err)
+		return envelopeString(env)
+	}
+	resp, err := r.handler(db, &Call{Command: cmd, Request: req})
+	if err != nil {
+		env.Error = toCommandError(err)
+	} else {
+		env.OK = true
+		env.Data = resp
+	}
+	return envelopeString(env)
+}
+
+func envelopeString(env Envelope) string {
+	retString, err := json.Marshal(env)
+	if err != nil {
+		ERROR.Println("error in json.Marshal(env) for " + env.Cmd)
+		ERROR.Println(err)
+		retString, _ = json.Marshal(Envelope{Cmd: env.Cmd, ReqID: env.ReqID, Error: toCommandError(err)})
+	}
+	return string(retString)
+}
+
+// CATALOGUE
+// the catalogue describes every command's request and response, it's what
+// the web and mobile clients are written against.  commands.json in the
+// root of the repo is generated with "pingedchat commands".
+
+type CatalogueInfo struct {
+	Envelope []FieldInfo     `json:"envelope"` // what every answer looks like
+	Errors   []ErrorCodeInfo `json:"errors"`
+	Commands []CommandInfo   `json:"commands"`
+}
+
+type CommandInfo struct {
+	Name     string      `json:"name"`
+	Doc      string      `json:"doc"`
+	Public   bool        `json:"public,omitempty"`
+	Member   bool        `json:"member,omitempty"` // only members of the CID can send it
+	Request  []FieldInfo `json:"request"`
+	Response []FieldInfo `json:"response"` // the envelope's data, null if there's none
+}
+
+type FieldInfo struct {
+	Name     string      `json:"name"`
+	Type     string      `json:"type"` // string, number, boolean, object, or []type for arrays
+	Required bool        `json:"required,omitempty"`
+	Identity bool        `json:"identity,omitempty"` // the caller, can be left out
+	Optional bool        `json:"optional,omitempty"` // left out of responses when empty
+	Fields   []FieldInfo `json:"fields,omitempty"`   // for objects and arrays of objects
+}
+
+// Catalogue describes the envelope, the error codes and every command sorted
+// by name
+func (r *Router) Catalogue() CatalogueInfo {
+	names := make([]string, 0, len(r.commands))
+	for name := range r.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	catalogue := make([]CommandInfo, 0, len(names))
+	for _, name := range names {
+		cmd := r.commands[name]
+		info := CommandInfo{
+			Name:    cmd.Name,
+			Doc:     cmd.Doc,
+			Public:  cmd.Public,
+			Member:  cmd.Member,
+			Request: describeFields(cmd.request),
+		}
+		for i := range info.Request {
+			for _, field := range cmd.Required {
+				if f, _ := cmd.request.FieldByName(field); info.Request[i].Name == jsonName(f) {
+					info.Request[i].Required = true
+				}
+			}
+			for _, field := range cmd.Identity {
+				if f, _ := cmd.request.FieldByName(field); info.Request[i].Name == jsonName(f) {
+					info.Request[i].Identity = true
+				}
+			}
+		}
+		if cmd.response != nil {
+			info.Response = describeFields(cmd.response)
+		}
+		catalogue = append(catalogue, info)
+	}
+	return CatalogueInfo{
+		Envelope: describeFields(reflect.TypeOf(Envelope{})),
+		Errors:   errorCodes,
+		Commands: catalogue,
+	}
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _ := jsonField(f)
+	return name
+}
+
+// jsonField returns the name a struct field has in JSON, "" if it's skipped
+func jsonField(f reflect.StructField) (name string, omitempty bool) {
+	if f.PkgPath != "" {
+		return "", false // unexported
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = f.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+func describeFields(t reflect.Type) []FieldInfo {
+	fields := make([]FieldInfo, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, omitempty := jsonField(f)
+		if name == "" {
+			continue
+		}
+		typeName, subFields := describeType(f.Type)
+		fields = append(fields, FieldInfo{
+			Name:     name,
+			Type:     typeName,
+			Optional: omitempty,
+			Fields:   subFields,
+		})
+	}
+	return fields
+}
+
+func describeType(t reflect.Type) (string, []FieldInfo) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.PkgPath() == "time" && t.Name() == "Time" {
+		return "string", nil // RFC 3339
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string", nil
+	case reflect.Bool:
+		return "boolean", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number", nil
+	case reflect.Slice, reflect.Array:
+		elem, fields := describeType(t.Elem())
+		return "[]" + elem, fields
+	case reflect.Struct:
+		return "object", describeFields(t)
+	}
+	return "object", nil
+}
+
+// VALIDATORS
+
+// checks the required fields, then Validate
+func (cmd *Command) validate(req interface{}) error {
+	v := reflect.ValueOf(req).Elem()
+	for _, field := range cmd.Required {
+		if isEmpty(v.FieldByName(field)) {
+			f, _ := cmd.request.FieldByName(field)
+			return cmdError(ERR_BAD_REQUEST, "%s is required", jsonName(f))
+		}
+	}
+	if cmd.Validate != nil {
+		return cmd.Validate(req)
+	}
+	return nil
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.Interface() == reflect.Zero(v.Type()).Interface()
+}
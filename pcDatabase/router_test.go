@@ -0,0 +1,144 @@
+package pcDatabase
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// the envelope as the client sees it
+type testEnvelope struct {
+	Cmd   string          `json:"cmd"`
+	ReqID string          `json:"reqId"`
+	OK    bool            `json:"ok"`
+	Data  json.RawMessage `json:"data"`
+	Error *CommandError   `json:"error"`
+}
+
+func dispatch(t *testing.T, db *Database, msg string, data interface{}) testEnvelope {
+	env := testEnvelope{}
+	if err := json.Unmarshal([]byte(db.Dispatch(msg)), &env); err != nil {
+		t.Fatal(err)
+	}
+	if data != nil && env.Data != nil {
+		if err := json.Unmarshal(env.Data, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return env
+}
+
+func expectError(t *testing.T, db *Database, msg string, code string) *CommandError {
+	env := dispatch(t, db, msg, nil)
+	if env.OK || env.Error == nil || env.Error.Code != code {
+		t.Errorf("%s returned %+v, expected error %s", msg, env, code)
+		return &CommandError{}
+	}
+	return env.Error
+}
+
+func TestDispatchUnknownCommand(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+
+	env := dispatch(t, db, `{"cmd":"NotACommand","reqId":"r1"}`, nil)
+	if env.Cmd != "NotACommand" || env.ReqID != "r1" || env.OK || env.Error == nil || env.Error.Code != ERR_UNKNOWN_COMMAND {
+		t.Errorf("unknown command returned %+v", env)
+	}
+	expectError(t, db, `not json`, ERR_BAD_REQUEST)
+}
+
+func TestDispatchRequiresLogin(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+
+	expectError(t, db, `{"cmd":"GetUserByUsername","username":"alice"}`, ERR_NOT_LOGGED_IN)
+
+	created := UserStruct{}
+	env := dispatch(t, db, `{"cmd":"CreateUser","reqId":"r2","username":"alice","password":"secret","token":"alice-web"}`, &created)
+	if !env.OK || env.Cmd != "CreateUser" || env.ReqID != "r2" || created.Username != "alice" {
+		t.Fatalf("CreateUser returned %+v with %+v", env, created)
+	}
+	if db.Username() != "alice" || db.Token() != "alice-web" {
+		t.Fatalf("session is logged in as %q with %q", db.Username(), db.Token())
+	}
+
+	user := UserStruct{}
+	env = dispatch(t, db, `{"cmd":"GetUserByUsername","username":"alice"}`, &user)
+	if !env.OK || env.ReqID != "" || user.Username != "alice" {
+		t.Errorf("GetUserByUsername returned %+v with %+v", env, user)
+	}
+}
+
+func TestDispatchValidates(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "alice")
+
+	for msg, expected := range map[string]string{
+		`{"cmd":"GetConvoData"}`:            "CID is required",
+		`{"cmd":"GetConvoData","CID":"  "}`: "CID is required",
+		`{"cmd":"ChangeUserPassword","Username":"alice","OldPassword":"secret","NewPassword":"abc"}`: "NewPassword needs at least 6 characters",
+	} {
+		if err := expectError(t, db, msg, ERR_BAD_REQUEST); err.Message != expected {
+			t.Errorf("%s returned %+v, expected message %q", msg, err, expected)
+		}
+	}
+}
+
+// every failure comes back with a code, not an empty or echoed answer
+func TestDispatchErrorCodes(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+
+	expectError(t, db, `{"cmd":"ValidateUser","username":"alice","password":"secret","token":"t"}`, ERR_INVALID_CREDENTIALS)
+	expectError(t, db, `{"cmd":"GetPasswordResetUser","username":"nobody"}`, ERR_NOT_FOUND)
+	createTestUser(t, db, "alice")
+	expectError(t, db, `{"cmd":"CreateUser","username":"ALICE","password":"secret","token":"t"}`, ERR_CONFLICT)
+	expectError(t, db, `{"cmd":"GetUserByUsername","username":"nobody"}`, ERR_NOT_FOUND)
+	expectError(t, db, `{"cmd":"ChangeUserPassword","Username":"alice","OldPassword":"wrong!","NewPassword":"abcdef"}`, ERR_INVALID_CREDENTIALS)
+
+	// commands without data still answer
+	env := dispatch(t, db, `{"cmd":"ChangeUserPassword","reqId":"r3","Username":"alice","OldPassword":"secret","NewPassword":"abcdef"}`, nil)
+	if !env.OK || env.ReqID != "r3" || env.Error != nil {
+		t.Errorf("ChangeUserPassword returned %+v", env)
+	}
+
+	if !ErrRateLimited.Retry || ErrBadPassword.Retry {
+		t.Error("only some error codes can be retried")
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	start := time.Now()
+	bucket := newTokenBucket(1, 2)
+	if !bucket.take(start) || !bucket.take(start) {
+		t.Fatal("the burst should be allowed")
+	}
+	if bucket.take(start) {
+		t.Error("bucket should be empty after the burst")
+	}
+	if !bucket.take(start.Add(time.Second)) {
+		t.Error("bucket should refill a token a second")
+	}
+}
+
+// commands.json is the contract the clients are written against, it has to
+// change along with the commands
+func TestCatalogueMatchesCommandsJSON(t *testing.T) {
+	committed, err := ioutil.ReadFile("../commands.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected, actual interface{}
+	if err := json.Unmarshal(committed, &expected); err != nil {
+		t.Fatal(err)
+	}
+	current, _ := json.Marshal(Catalogue())
+	json.Unmarshal(current, &actual)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Error("commands.json is out of date, regenerate it with \"pingedchat commands > commands.json\"")
+	}
+}
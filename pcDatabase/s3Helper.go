@@ -2,7 +2,6 @@ package pcDatabase
 
 import (
 	"bytes"
-	aerospike "github.com/aerospike/aerospike-client-go"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -27,9 +26,14 @@ func NowISO8601() string {
 	return t.Format(ISO8601_SECONDS)
 }
 
+// where uploads can be downloaded from, ends with a /
+func S3PublicURL() string {
+	return "https://" + conf.AWS.Bucket + ".s3.amazonaws.com/"
+}
+
 func ConfigureAWS() {
 	// get credentials
-	AWS_CREDENTIALS_FILEPATH, err := filepath.Abs("./aws_credentials")
+	AWS_CREDENTIALS_FILEPATH, err := filepath.Abs(conf.AWS.CredentialsFile)
 	if err != nil {
 		ERROR.Println("error in getting absolute filepath for AWS credentials: ", err)
 	}
@@ -44,8 +48,8 @@ func ConfigureAWS() {
 
 	if AWSConfig == nil {
 		AWSConfig = &aws.Config{
-			Region:           aws.String("us-east-1"),
-			Endpoint:         aws.String("https://FILL_ME.s3.amazonaws.com"), // FILL_ME
+			Region:           aws.String(conf.AWS.Region),
+			Endpoint:         aws.String(strings.TrimSuffix(S3PublicURL(), "/")),
 			S3ForcePathStyle: aws.Bool(true),
 			Credentials:      AWSCreds,
 			LogLevel:         aws.LogLevel(aws.LogDebug), // LogOff, LogDebug
@@ -113,19 +117,16 @@ func (db *Database) UploadToS3(sDec []byte, filename string, user UserStruct) st
 	} else {
 		// add to user used quota
 		TRACE.Println("adding to user.QuotaUsed in kb: ", uint32(fileSize))
-		user.QuotaUsed += uint32(fileSize)
-		// save back
-		QuotaUsedBin := aerospike.NewBin("QuotaUsed", int(user.QuotaUsed))
-		key, err := aerospike.NewKey(AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE, strings.ToUpper(user.UsernameUpper))
-		if err == nil {
-			db.UpdateAerospikeSingleBin(key, QuotaUsedBin)
-		}
+		db.updateUser(user.UsernameUpper, func(user *UserStruct) error {
+			user.QuotaUsed += uint32(fileSize)
+			return nil
+		})
 	}
 
 	// Pretty-print the response data.
 	TRACE.Println("S3 response: ", resp)
 
 	// add to attachments array
-	fullS3FilePath := "https://pingedchat-us1.s3.amazonaws.com/" + s3FilePath
+	fullS3FilePath := S3PublicURL() + s3FilePath
 	return fullS3FilePath
 }
@@ -6,29 +6,31 @@ import (
 	"encoding/base64"
 )
 
-const (
-	AWS_SECRET_ACCESS_KEY = "FILL_ME"
-	AWS_KEY               = "FILL_ME"
-	policy_document       = `{"expiration": "2040-01-01T00:00:00Z",
+func policyDocument() string {
+	return `{"expiration": "2040-01-01T00:00:00Z",
       "conditions": [
-        {"bucket": "FILL_ME"},
+        {"bucket": "` + conf.AWS.Bucket + `"},
         ["starts-with", "$key", "uploads/"],
         {"acl": "public-read"},
         ["starts-with", "$Content-Type", ""],
       ]
     }`
-)
+}
 
 // liberal inspiration from https://github.com/mitchellh/goamz/blob/master/s3/sign.go
 
-func s3Sign() string {
+func s3Sign() *S3PolicyResponse {
 	var b64 = base64.StdEncoding
 
-	b64policy := b64.EncodeToString([]byte(policy_document))
-	hash := hmac.New(sha1.New, []byte(AWS_SECRET_ACCESS_KEY))
+	b64policy := b64.EncodeToString([]byte(policyDocument()))
+	hash := hmac.New(sha1.New, []byte(conf.AWS.SecretAccessKey))
 	hash.Write([]byte(b64policy))
 	signature := make([]byte, b64.EncodedLen(hash.Size()))
 	b64.Encode(signature, hash.Sum(nil))
 
-	return `{ "cmd":"GetS3PolicyData", "policy":"` + string(b64policy) + `","signature":"` + string(signature) + `","AWS_KEY":"` + string(AWS_KEY) + `"}`
+	return &S3PolicyResponse{
+		Policy:    b64policy,
+		Signature: string(signature),
+		AWSKey:    conf.AWS.AccessKeyID,
+	}
 }
@@ -0,0 +1,337 @@
+package pcDatabase
+
+import (
+	"encoding/base64"
+	"github.com/pquerna/ffjson/ffjson"
+	"html"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MESSAGE SEARCH
+// SearchMessages looks through every conversation in the caller's CIDs.  The
+// query is words that all have to be in a message, "quoted phrases" that
+// have to be there in order and -words that mustn't be.  Results come best
+// match first with a snippet of the message, the words that matched wrapped
+// in <mark></mark> and everything else HTML escaped, and a cursor for the
+// next page.
+//
+// In postgres every message's words are kept in the search column, written
+// with the message (AddMessage, EditMessage, DeleteMessage) and matched with
+// websearch_to_tsquery, which takes "or" between words as well.  The words
+// aren't stemmed so the ones highlighted here are the ones that matched
+
+const (
+	SEARCH_PAGE_SIZE = 20
+	// words of the message either side of the first match in the snippet
+	SEARCH_SNIPPET_WORDS = 8
+)
+
+var (
+	ErrEmptySearch = cmdError(ERR_BAD_REQUEST, "the query needs at least one word to look for")
+	ErrBadCursor   = cmdError(ERR_BAD_REQUEST, "that cursor isn't from this search")
+)
+
+// MessageStore.SearchMessages, After and Before are left out when empty and
+// Cursor is nil for the first page
+type MessageSearch struct {
+	CIDs   []string
+	Query  string
+	From   string
+	After  string
+	Before string
+	Cursor *searchCursor
+	Limit  int
+}
+
+// the last result of a page, the next page starts after it
+type searchCursor struct {
+	Rank   float32 `json:"r"`
+	M_time string  `json:"t"`
+	CID    string  `json:"c"`
+	MID    string  `json:"m"`
+}
+
+func encodeSearchCursor(result SearchResultStruct) string {
+	cursor, _ := ffjson.Marshal(searchCursor{
+		Rank:   result.Rank,
+		M_time: result.M_time.UTC().Format(time.RFC3339Nano),
+		CID:    result.CID,
+		MID:    result.MID,
+	})
+	return base64.RawURLEncoding.EncodeToString(cursor)
+}
+
+func decodeSearchCursor(s string) (*searchCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrBadCursor
+	}
+	cursor := &searchCursor{}
+	if err := ffjson.Unmarshal(data, cursor); err != nil || cursor.CID == "" {
+		return nil, ErrBadCursor
+	}
+	return cursor, nil
+}
+
+// the result the cursor was made from, as far as ordering goes
+func (cursor searchCursor) result() SearchResultStruct {
+	return SearchResultStruct{Rank: cursor.Rank, M_time: parseTimeString(cursor.M_time), CID: cursor.CID, MID: cursor.MID}
+}
+
+// whether a comes before b in the results, best rank then newest first
+func searchResultBefore(a SearchResultStruct, b SearchResultStruct) bool {
+	switch {
+	case a.Rank != b.Rank:
+		return a.Rank > b.Rank
+	case !a.M_time.Equal(b.M_time):
+		return a.M_time.After(b.M_time)
+	case a.CID != b.CID:
+		return a.CID > b.CID
+	}
+	return a.MID > b.MID
+}
+
+// a query split up the way websearch_to_tsquery does, lower case
+type searchQuery struct {
+	words   []string
+	phrases [][]string
+	not     []string
+}
+
+// lower case words of s, split on anything that isn't a letter or a number
+func searchWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func parseSearchQuery(query string) searchQuery {
+	q := searchQuery{}
+	for i, part := range strings.Split(query, `"`) {
+		if i%2 == 1 {
+			if phrase := searchWords(part); len(phrase) > 0 {
+				q.phrases = append(q.phrases, phrase)
+			}
+			continue
+		}
+		for _, field := range strings.Fields(part) {
+			if strings.HasPrefix(field, "-") {
+				q.not = append(q.not, searchWords(field)...)
+			} else if strings.ToLower(field) != "or" {
+				q.words = append(q.words, searchWords(field)...)
+			}
+		}
+	}
+	return q
+}
+
+func (q searchQuery) empty() bool {
+	return len(q.words) == 0 && len(q.phrases) == 0
+}
+
+// every word that can match, for highlighting
+func (q searchQuery) matching() map[string]bool {
+	matching := make(map[string]bool)
+	for _, word := range q.words {
+		matching[word] = true
+	}
+	for _, phrase := range q.phrases {
+		for _, word := range phrase {
+			matching[word] = true
+		}
+	}
+	return matching
+}
+
+// how well content matches, 0 if it doesn't.  Used by the MemoryStore, it
+// counts the matching words rather than ranking like postgres does
+func (q searchQuery) rank(content string) float32 {
+	words := searchWords(content)
+	counts := make(map[string]int)
+	for _, word := range words {
+		counts[word]++
+	}
+	for _, word := range q.not {
+		if counts[word] > 0 {
+			return 0
+		}
+	}
+	for _, word := range q.words {
+		if counts[word] == 0 {
+			return 0
+		}
+	}
+	for _, phrase := range q.phrases {
+		if !hasPhrase(words, phrase) {
+			return 0
+		}
+	}
+	rank := 0
+	for word := range q.matching() {
+		rank += counts[word]
+	}
+	return float32(rank)
+}
+
+func hasPhrase(words []string, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		found := true
+		for j, word := range phrase {
+			if words[i+j] != word {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// the part of content around the first matching word, HTML escaped with the
+// matching words in <mark></mark>
+func searchSnippet(content string, matching map[string]bool) string {
+	// where each word of content starts and ends
+	type span struct{ start, end int }
+	var spans []span
+	start := -1
+	for i, r := range content {
+		inWord := unicode.IsLetter(r) || unicode.IsNumber(r)
+		if inWord && start < 0 {
+			start = i
+		} else if !inWord && start >= 0 {
+			spans = append(spans, span{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, span{start, len(content)})
+	}
+	first := 0
+	for i, s := range spans {
+		if matching[strings.ToLower(content[s.start:s.end])] {
+			first = i
+			break
+		}
+	}
+	from, to := first-SEARCH_SNIPPET_WORDS, first+SEARCH_SNIPPET_WORDS
+	if from < 0 {
+		from = 0
+	}
+	if to > len(spans)-1 {
+		to = len(spans) - 1
+	}
+
+	var snippet strings.Builder
+	pos := 0
+	if from > 0 {
+		snippet.WriteString("…")
+		pos = spans[from].start
+	}
+	for _, s := range spans[from : to+1] {
+		snippet.WriteString(html.EscapeString(content[pos:s.start]))
+		word := html.EscapeString(content[s.start:s.end])
+		if matching[strings.ToLower(content[s.start:s.end])] {
+			word = "<mark>" + word + "</mark>"
+		}
+		snippet.WriteString(word)
+		pos = s.end
+	}
+	if len(spans) == 0 || to == len(spans)-1 {
+		snippet.WriteString(html.EscapeString(content[pos:]))
+	} else {
+		snippet.WriteString("…")
+	}
+	return snippet.String()
+}
+
+// sorts results and cuts them down to the page after search.Cursor, for
+// stores that find every match
+func pageSearchResults(results []SearchResultStruct, search MessageSearch) []SearchResultStruct {
+	sort.Slice(results, func(i, j int) bool {
+		return searchResultBefore(results[i], results[j])
+	})
+	page := make([]SearchResultStruct, 0, search.Limit)
+	for _, result := range results {
+		// up to and including the cursor was on earlier pages
+		if search.Cursor != nil && !searchResultBefore(search.Cursor.result(), result) {
+			continue
+		}
+		if len(page) == search.Limit {
+			break
+		}
+		page = append(page, result)
+	}
+	return page
+}
+
+// the times that were given have to be ISO 8601
+func checkTimes(times ...string) error {
+	for _, t := range times {
+		if t != "" && parseTimeString(t).IsZero() {
+			return cmdError(ERR_BAD_REQUEST, "%q isn't an ISO 8601 time", t)
+		}
+	}
+	return nil
+}
+
+// SearchMessages finds messages in the caller's conversations, or just CID
+func (db *Database) SearchMessages(jsondata *SearchCmdStruct) (*SearchMessagesResponse, error) {
+	if db.Messages == nil {
+		return nil, ErrMessagesDown
+	}
+	query := parseSearchQuery(jsondata.Query)
+	if query.empty() {
+		return nil, ErrEmptySearch
+	}
+	search := MessageSearch{
+		Query:  jsondata.Query,
+		From:   jsondata.From,
+		After:  jsondata.After,
+		Before: jsondata.Before,
+		Limit:  SEARCH_PAGE_SIZE + 1, // one more to know if there's another page
+	}
+	if err := checkTimes(jsondata.After, jsondata.Before); err != nil {
+		return nil, err
+	}
+	if jsondata.Cursor != "" {
+		cursor, err := decodeSearchCursor(jsondata.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		search.Cursor = cursor
+	}
+	user := db.Users.GetUser(db.Username())
+	for _, convo := range user.GetCIDStructs() {
+		if jsondata.CID == "" || convo.CID == jsondata.CID {
+			search.CIDs = append(search.CIDs, convo.CID)
+		}
+	}
+	if jsondata.CID != "" && len(search.CIDs) == 0 {
+		return nil, ErrNotMember
+	}
+	resp := &SearchMessagesResponse{Query: jsondata.Query, Results: make([]SearchResultStruct, 0)}
+	if len(search.CIDs) == 0 {
+		return resp, nil
+	}
+
+	results, err := db.Messages.SearchMessages(search)
+	if err != nil {
+		logPqError("SearchMessages", err)
+		return nil, ErrMessagesDown
+	}
+	if len(results) > SEARCH_PAGE_SIZE {
+		results = results[:SEARCH_PAGE_SIZE]
+		resp.Cursor = encodeSearchCursor(results[len(results)-1])
+	}
+	matching := query.matching()
+	for i := range results {
+		results[i].Snippet = searchSnippet(results[i].Content, matching)
+	}
+	resp.Results = results
+	return resp, nil
+}
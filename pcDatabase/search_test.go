@@ -0,0 +1,68 @@
+package pcDatabase
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSearchMessages(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	CID, _ := sendTestMessage(t, db)
+	for i, msg := range []struct{ from, content string }{
+		{"bob", "lunch at <noon>?"},
+		{"alice", "no, lunch tomorrow"},
+		{"bob", "tomorrow lunch then"},
+	} {
+		db.Messages.AddMessage(MessageStruct{CID: CID, MID: newMessageID(), FromUsername: msg.from, Content: msg.content, M_time: fmt.Sprintf("2015-06-13T12:0%d:00.000Z", i)})
+	}
+
+	found := SearchMessagesResponse{}
+	dispatch(t, db, `{"cmd":"SearchMessages","query":"lunch"}`, &found)
+	if len(found.Results) != 3 || found.Cursor != "" {
+		t.Fatalf("SearchMessages returned %+v", found)
+	}
+	if snippet := found.Results[2].Snippet; snippet != "<mark>lunch</mark> at &lt;noon&gt;?" {
+		t.Errorf("the oldest result's snippet is %q", snippet)
+	}
+	found = SearchMessagesResponse{}
+	dispatch(t, db, `{"cmd":"SearchMessages","query":"\"lunch tomorrow\" -no"}`, &found)
+	if len(found.Results) != 0 {
+		t.Errorf("a phrase and a word left out found %+v", found.Results)
+	}
+	found = SearchMessagesResponse{}
+	dispatch(t, db, `{"cmd":"SearchMessages","query":"lunch","from":"bob","before":"2015-06-13T12:02:00.000Z"}`, &found)
+	if len(found.Results) != 1 || found.Results[0].Snippet != "<mark>lunch</mark> at &lt;noon&gt;?" {
+		t.Errorf("bob's lunch before noon found %+v", found.Results)
+	}
+	expectError(t, db, `{"cmd":"SearchMessages","query":"\"\" -lunch"}`, ERR_BAD_REQUEST)
+	expectError(t, db, `{"cmd":"SearchMessages","query":"lunch","CID":"not-a-convo"}`, ERR_FORBIDDEN)
+}
+
+func TestSearchMessagesPages(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	CID, _ := sendTestMessage(t, db)
+	for i := 0; i < SEARCH_PAGE_SIZE+5; i++ {
+		db.Messages.AddMessage(MessageStruct{CID: CID, MID: newMessageID(), FromUsername: "bob", Content: "ping", M_time: fmt.Sprintf("2015-06-13T12:%02d:00.000Z", i)})
+	}
+	seen := make(map[string]bool)
+	cursor := ""
+	for page := 0; page < 3; page++ {
+		found := SearchMessagesResponse{}
+		dispatch(t, db, `{"cmd":"SearchMessages","query":"ping","cursor":"`+cursor+`"}`, &found)
+		for _, result := range found.Results {
+			if seen[result.MID] {
+				t.Errorf("%s is on more than one page", result.MID)
+			}
+			seen[result.MID] = true
+		}
+		if cursor = found.Cursor; cursor == "" {
+			break
+		}
+	}
+	if len(seen) != SEARCH_PAGE_SIZE+5 || cursor != "" {
+		t.Errorf("the pages had %d messages, last cursor %q", len(seen), cursor)
+	}
+	expectError(t, db, `{"cmd":"SearchMessages","query":"ping","cursor":"nope"}`, ERR_BAD_REQUEST)
+}
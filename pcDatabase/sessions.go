@@ -0,0 +1,286 @@
+package pcDatabase
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// SESSIONS
+// every login (CreateUser, ValidateUser) starts a session on the device it
+// came from and hands the client a signed token naming it.  ResumeSession
+// logs back in with the token alone, RefreshSession swaps it for a new one
+// and RevokeSession ends a session, closing the device's connection if it's
+// online.  The sessions live on the user (UserStruct.Sessions) so a token
+// only works while its session is there.
+
+const (
+	// oldest sessions are dropped past this many
+	MAX_SESSIONS = 20
+	// sockjs close status sent to a revoked session
+	SESSION_REVOKED_STATUS = 4001
+)
+
+var ErrBadSession = cmdError(ERR_INVALID_CREDENTIALS, "the session expired or was revoked, log in again")
+
+// one login on one device
+type SessionStruct struct {
+	ID      string
+	Device  string // the web token the session's events are published to
+	Created string
+	Expires string
+}
+
+// published to a revoked session's device, see Run()
+type SessionRevokedEvent struct {
+	Cmd string `json:"cmd"`
+	ID  string
+}
+
+// what's signed into a token
+type sessionClaims struct {
+	Username string `json:"u"`
+	ID       string `json:"s"`
+	Expires  int64  `json:"exp"`
+}
+
+func sessionTTL() time.Duration {
+	return time.Duration(conf.Session.TTLHours) * time.Hour
+}
+
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // no randomness, nothing is safe
+	}
+	return hex.EncodeToString(b)
+}
+
+func sessionSignature(payload string) string {
+	mac := hmac.New(sha256.New, []byte(conf.Session.Secret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// tokens are base64(claims).base64(hmac)
+func signSession(claims sessionClaims) string {
+	claimsBytes, _ := json.Marshal(claims)
+	payload := base64.RawURLEncoding.EncodeToString(claimsBytes)
+	return payload + "." + sessionSignature(payload)
+}
+
+// parseSessionToken checks the signature and expiry, not that the session
+// is still on the user
+func parseSessionToken(token string, now time.Time) (sessionClaims, error) {
+	claims := sessionClaims{}
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 || !hmac.Equal([]byte(sessionSignature(parts[0])), []byte(parts[1])) {
+		return claims, ErrBadSession
+	}
+	claimsBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || json.Unmarshal(claimsBytes, &claims) != nil || now.Unix() >= claims.Expires {
+		return claims, ErrBadSession
+	}
+	return claims, nil
+}
+
+func sessionExpired(session SessionStruct, now time.Time) bool {
+	expires, err := time.Parse(time.RFC3339, session.Expires)
+	return err != nil || !now.Before(expires)
+}
+
+// startSession adds a session for device to the user and returns its ID and
+// token, the caller saves the user
+func (user *UserStruct) startSession(device string, now time.Time) (string, string) {
+	sessions := make([]SessionStruct, 0)
+	for _, session := range user.GetSessionStructs() {
+		if !sessionExpired(session, now) {
+			sessions = append(sessions, session)
+		}
+	}
+	expires := now.Add(sessionTTL())
+	session := SessionStruct{
+		ID:      newSessionID(),
+		Device:  device,
+		Created: now.UTC().Format(time.RFC3339),
+		Expires: expires.UTC().Format(time.RFC3339),
+	}
+	sessions = append(sessions, session)
+	if len(sessions) > MAX_SESSIONS {
+		sessions = sessions[len(sessions)-MAX_SESSIONS:]
+	}
+	user.SaveSessionStructs(sessions)
+	return session.ID, signSession(sessionClaims{Username: user.Username, ID: session.ID, Expires: expires.Unix()})
+}
+
+// revokeSessions drops every session but keep and logs their devices out
+// of Web, send the returned sessions to sendSessionsRevoked once the user is
+// saved
+func revokeSessions(user *UserStruct, keep string) []SessionStruct {
+	kept := make([]SessionStruct, 0)
+	revoked := make([]SessionStruct, 0)
+	for _, session := range user.GetSessionStructs() {
+		if session.ID == keep {
+			kept = append(kept, session)
+		} else {
+			revoked = append(revoked, session)
+			user.Web = removeString(user.Web, session.Device)
+		}
+	}
+	user.SaveSessionStructs(kept)
+	return revoked
+}
+
+// tells each session's device, Run() disconnects it.  Nothing more is
+// queued for the device so its queue goes too
+func (db *Database) sendSessionsRevoked(username string, sessions []SessionStruct) {
+	for _, session := range sessions {
+		db.SendToWebDevices([]string{session.Device}, SessionRevokedEvent{Cmd: "SessionRevoked", ID: session.ID})
+		if db.Queues != nil {
+			if err := db.Queues.DeleteDeviceQueue(username, session.Device); err != nil {
+				ERROR.Println("error in DeleteDeviceQueue:", err)
+			}
+		}
+	}
+}
+
+// revokedSession is the ID in a SessionRevoked event, ok is false for any
+// other message
+func revokedSession(msg string) (string, bool) {
+	if !strings.HasPrefix(msg, `{"cmd":"SessionRevoked"`) {
+		return "", false
+	}
+	event := SessionRevokedEvent{}
+	if err := json.Unmarshal([]byte(msg), &event); err != nil {
+		return "", false
+	}
+	return event.ID, true
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(list []string, s string) []string {
+	kept := make([]string, 0, len(list))
+	for _, e := range list {
+		if e != s {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// COMMANDS
+
+func (db *Database) ResumeSession(jsondata *ResumeSessionCmdStruct) (*UserStruct, error) {
+	now := time.Now()
+	claims, err := parseSessionToken(jsondata.SessionToken, now)
+	if err != nil {
+		return nil, err
+	}
+	var resumed SessionStruct
+	storeduser, err := db.updateUser(claims.Username, func(user *UserStruct) error {
+		for _, session := range user.GetSessionStructs() {
+			if session.ID == claims.ID && !sessionExpired(session, now) {
+				resumed = session
+				// the device is back online
+				user.Web = append(removeString(user.Web, session.Device), session.Device)
+				db.refreshCIDMtimes(user)
+				return nil
+			}
+		}
+		return ErrBadSession
+	})
+	if err == ErrUserNotFound {
+		return nil, ErrBadSession
+	} else if err != nil {
+		return nil, err
+	}
+	db.subscribe(resumed.Device)
+	db.Authenticate(storeduser.Username, resumed.Device, resumed.ID)
+	return &storeduser, nil
+}
+
+// RefreshSession gives the current session a new ID and expiry, the old
+// token stops working
+func (db *Database) RefreshSession(jsondata *EmptyCmdStruct) (*SessionResponse, error) {
+	now := time.Now()
+	ID := newSessionID()
+	expires := now.Add(sessionTTL())
+	storeduser, err := db.updateUser(db.Username(), func(user *UserStruct) error {
+		sessions := user.GetSessionStructs()
+		for i, session := range sessions {
+			if session.ID == db.Session() && !sessionExpired(session, now) {
+				sessions[i].ID = ID
+				sessions[i].Expires = expires.UTC().Format(time.RFC3339)
+				user.SaveSessionStructs(sessions)
+				return nil
+			}
+		}
+		return ErrBadSession
+	})
+	if err != nil {
+		return nil, err
+	}
+	db.Authenticate(db.Username(), db.Token(), ID)
+	return &SessionResponse{
+		SessionToken: signSession(sessionClaims{Username: storeduser.Username, ID: ID, Expires: expires.Unix()}),
+		Expires:      expires.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// RevokeSession ends one of the user's sessions, the current one (logging
+// out) when ID is empty
+func (db *Database) RevokeSession(jsondata *SessionCmdStruct) error {
+	ID := jsondata.ID
+	if ID == "" {
+		ID = db.Session()
+	}
+	var revoked []SessionStruct
+	_, err := db.updateUser(db.Username(), func(user *UserStruct) error {
+		sessions := user.GetSessionStructs()
+		for i, session := range sessions {
+			if session.ID == ID {
+				user.SaveSessionStructs(append(sessions[:i], sessions[i+1:]...))
+				user.Web = removeString(user.Web, session.Device)
+				revoked = []SessionStruct{session}
+				return nil
+			}
+		}
+		return cmdError(ERR_NOT_FOUND, "there's no session with that ID")
+	})
+	if err != nil {
+		return err
+	}
+	db.sendSessionsRevoked(db.Username(), revoked)
+	return nil
+}
+
+func (db *Database) GetSessions(jsondata *EmptyCmdStruct) *SessionsResponse {
+	now := time.Now()
+	storeduser := db.Users.GetUser(strings.ToUpper(db.Username()))
+	resp := &SessionsResponse{Sessions: make([]SessionInfo, 0)}
+	for _, session := range storeduser.GetSessionStructs() {
+		if sessionExpired(session, now) {
+			continue
+		}
+		resp.Sessions = append(resp.Sessions, SessionInfo{
+			ID:      session.ID,
+			Created: session.Created,
+			Expires: session.Expires,
+			Current: session.ID == db.Session(),
+		})
+	}
+	return resp
+}
@@ -0,0 +1,140 @@
+package pcDatabase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// another connection to the same stores, nothing is logged in yet
+func newTestConnection(db *Database) *Database {
+	conn := &Database{}
+	conn.Connect(context.Background(), &Backend{Stores: db.Stores}, nil)
+	return conn
+}
+
+func TestResumeSession(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	alice := createTestUser(t, db, "alice")
+	if alice.SessionToken == "" {
+		t.Fatal("CreateUser didn't hand out a session token")
+	}
+
+	conn := newTestConnection(db)
+	defer conn.Close()
+	user := UserStruct{}
+	env := dispatch(t, conn, `{"cmd":"ResumeSession","sessionToken":"`+alice.SessionToken+`"}`, &user)
+	if !env.OK || user.Username != "alice" || conn.Username() != "alice" || conn.Token() != "alice-web" {
+		t.Fatalf("ResumeSession returned %+v with %+v", env, user)
+	}
+	if conn.Session() != db.Session() {
+		t.Errorf("resumed session %s, expected %s", conn.Session(), db.Session())
+	}
+	if web := db.Users.GetUser("ALICE").Web; len(web) != 1 {
+		t.Errorf("alice's web devices are %v", web)
+	}
+}
+
+func TestResumeSessionRejectsBadTokens(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	alice := createTestUser(t, db, "alice")
+	claims, err := parseSessionToken(alice.SessionToken, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the claims can't be changed without the secret
+	parts := strings.Split(alice.SessionToken, ".")
+	forged := signSession(sessionClaims{Username: "bob", ID: claims.ID, Expires: claims.Expires})
+	expired := signSession(sessionClaims{Username: "alice", ID: claims.ID, Expires: time.Now().Add(-time.Minute).Unix()})
+	for _, token := range []string{
+		"nonsense",
+		strings.Split(forged, ".")[0] + "." + parts[1],
+		parts[0] + "." + parts[1] + "x",
+		expired,
+	} {
+		conn := newTestConnection(db)
+		expectError(t, conn, `{"cmd":"ResumeSession","sessionToken":"`+token+`"}`, ERR_INVALID_CREDENTIALS)
+		if conn.Username() != "" {
+			t.Errorf("%q logged in as %s", token, conn.Username())
+		}
+		conn.Close()
+	}
+}
+
+func TestRefreshSession(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	alice := createTestUser(t, db, "alice")
+
+	refreshed := SessionResponse{}
+	env := dispatch(t, db, `{"cmd":"RefreshSession"}`, &refreshed)
+	if !env.OK || refreshed.SessionToken == "" || refreshed.SessionToken == alice.SessionToken {
+		t.Fatalf("RefreshSession returned %+v with %+v", env, refreshed)
+	}
+
+	conn := newTestConnection(db)
+	defer conn.Close()
+	expectError(t, conn, `{"cmd":"ResumeSession","sessionToken":"`+alice.SessionToken+`"}`, ERR_INVALID_CREDENTIALS)
+	if env := dispatch(t, conn, `{"cmd":"ResumeSession","sessionToken":"`+refreshed.SessionToken+`"}`, nil); !env.OK {
+		t.Errorf("ResumeSession with the refreshed token returned %+v", env)
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "alice")
+
+	// alice logs in on her phone too
+	phone := newTestConnection(db)
+	defer phone.Close()
+	login := UserStruct{}
+	env := dispatch(t, phone, `{"cmd":"ValidateUser","username":"alice","password":"secret","token":"alice-phone"}`, &login)
+	if !env.OK || login.SessionToken == "" {
+		t.Fatalf("ValidateUser returned %+v", env)
+	}
+	sessions := SessionsResponse{}
+	dispatch(t, db, `{"cmd":"GetSessions"}`, &sessions)
+	if len(sessions.Sessions) != 2 || !sessions.Sessions[0].Current || sessions.Sessions[1].Current {
+		t.Fatalf("alice's sessions are %+v", sessions)
+	}
+
+	// the phone was stolen
+	if env := dispatch(t, db, `{"cmd":"RevokeSession","ID":"`+phone.Session()+`"}`, nil); !env.OK {
+		t.Fatalf("RevokeSession returned %+v", env)
+	}
+	if ID, ok := revokedSession(expectEvents(t, phone, 1)[0]); !ok || ID != phone.Session() {
+		t.Errorf("the phone was told %s was revoked", ID)
+	}
+	if web := db.Users.GetUser("ALICE").Web; len(web) != 1 || web[0] != "alice-web" {
+		t.Errorf("alice's web devices are %v", web)
+	}
+	thief := newTestConnection(db)
+	defer thief.Close()
+	expectError(t, thief, `{"cmd":"ResumeSession","sessionToken":"`+login.SessionToken+`"}`, ERR_INVALID_CREDENTIALS)
+	expectError(t, db, `{"cmd":"RevokeSession","ID":"`+phone.Session()+`"}`, ERR_NOT_FOUND)
+}
+
+func TestChangeUserPasswordRevokesOtherSessions(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	alice := createTestUser(t, db, "alice")
+	phone := newTestConnection(db)
+	defer phone.Close()
+	login := UserStruct{}
+	dispatch(t, phone, `{"cmd":"ValidateUser","username":"alice","password":"secret","token":"alice-phone"}`, &login)
+
+	if env := dispatch(t, db, `{"cmd":"ChangeUserPassword","oldPassword":"secret","newPassword":"secret2"}`, nil); !env.OK {
+		t.Fatalf("ChangeUserPassword returned %+v", env)
+	}
+	conn := newTestConnection(db)
+	defer conn.Close()
+	expectError(t, conn, `{"cmd":"ResumeSession","sessionToken":"`+login.SessionToken+`"}`, ERR_INVALID_CREDENTIALS)
+	if env := dispatch(t, conn, `{"cmd":"ResumeSession","sessionToken":"`+alice.SessionToken+`"}`, nil); !env.OK {
+		t.Errorf("the session that changed the password was logged out: %+v", env)
+	}
+}
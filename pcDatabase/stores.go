@@ -0,0 +1,249 @@
+package pcDatabase
+
+import (
+	"context"
+	"time"
+)
+
+// storage interfaces.  Database talks to these instead of holding raw
+// aerospike, postgres and nats connections, so the same handlers can run
+// against the real servers or against the in-memory backend in memory_store.go
+
+// UserFields holds a partial user update, keyed by the bin names used in
+// UserStruct.ToAerospikeBins() ("CIDs", "Web", "QuotaUsed", ...)
+type UserFields map[string]interface{}
+
+type UserStore interface {
+	GetUser(username string) UserStruct
+	// GetUser plus the record's generation, which goes up on every write, 0
+	// when there's no such user
+	GetUserGeneration(username string) (UserStruct, uint32)
+	// writes user only if its record is still at generation (0 creates it),
+	// ErrStaleUser when somebody wrote it in between, see updateUser
+	CheckAndSetUser(user UserStruct, generation uint32) error
+	GetUserByEmail(email string) UserStruct
+	GetUserByPhone(phonenum string) UserStruct
+	SetUser(user UserStruct) bool
+	UpdateUserFields(username string, fields UserFields) bool
+	// changes to one of the user's conversations, done in place on the record
+	// without reading the user first.  ErrNotInConvo when they aren't in CID
+	// sets the conversation's M_time and adds one to its unread count
+	BumpUserConvo(username string, CID string, mtime string) error
+	SetUserConvoUnread(username string, CID string, count int) error
+	SetUserConvoMtime(username string, CID string, mtime string) error
+	DeleteUser(username string) bool
+	// presence, kept apart from the user in the active set, see presence.go.
+	// Sets the state of one of the user's sessions and when it was seen,
+	// which is also the user's last seen
+	SetSessionPresence(username string, session string, presence SessionPresence) error
+	// takes the session out, lastSeen is when it went ("" leaves it as it was)
+	RemoveSessionPresence(username string, session string, lastSeen string) error
+	// the user's sessions by ID and when they were last seen, none and "" for
+	// a user that's never been online
+	GetPresence(username string) (map[string]SessionPresence, string, error)
+}
+
+type ConversationStore interface {
+	GetConvoMembers(CID string) []string
+	SetConvoMembers(CID string, members []string) bool
+	GetConvoName(CID string) string
+	SetConvoName(CID string, name string) bool
+	GetConvoMtime(CID string) string
+	SetConvoMtime(CID string, mtime string) bool
+	GetConvoFiles(CID string) []string
+	SetConvoFiles(CID string, files []string) bool
+	// names of the bots turned off in the conversation, see bots.go
+	GetConvoDisabledBots(CID string) []string
+	SetConvoDisabledBots(CID string, bots []string) bool
+	DeleteConvo(CID string) bool
+}
+
+type MessageStore interface {
+	// msg.MID has to be set, it's unique within the conversation.  A reply
+	// (msg.ParentMID set) bumps its thread message's ReplyCount and LastReply.
+	// Content is markup, rows that were stored as HTML come back as markup too
+	AddMessage(msg MessageStruct) error
+	// the Get*Messages calls leave out replies, see GetReplies
+	// rows newer than mtime, oldest first
+	GetMessagesSince(CID string, mtime string) ([]ConvoRowStruct, error)
+	// the latest limit rows, oldest first
+	GetLatestMessages(CID string, limit int) ([]ConvoRowStruct, error)
+	// up to limit rows older than mtime, newest first
+	GetMessagesBefore(CID string, mtime string, limit int) ([]ConvoRowStruct, error)
+	GetAllMessages(CID string) ([]ConvoRowStruct, error)
+	// up to limit replies to parentMID after (mtime, afterMID), oldest first
+	// and then by MID.  With no afterMID it's the replies newer than mtime
+	GetReplies(CID string, parentMID string, mtime string, afterMID string, limit int) ([]ConvoRowStruct, error)
+	// who sent parentMID or replied to it, leaving out deleted messages
+	GetThreadParticipants(CID string, parentMID string) ([]string, error)
+	// one message, ErrNoMessage if there isn't one with that MID
+	GetMessage(CID string, MID string) (ConvoRowStruct, error)
+	// replaces the content, the old content is kept in the message's edits
+	// and the attachments stay.
+	// ErrNoMessage if it doesn't exist or was deleted
+	EditMessage(CID string, MID string, content string, etime string) error
+	// blanks the content and attachments and marks it deleted, its edits and
+	// reactions are dropped.
+	// ErrNoMessage if it doesn't exist or was already deleted
+	DeleteMessage(CID string, MID string, etime string) error
+	// what the message said before each edit, oldest first
+	GetMessageEdits(CID string, MID string) ([]MessageEditStruct, error)
+	// adds attachments after the message's own, returns all of them.
+	// ErrNoMessage if it doesn't exist or was deleted
+	AddAttachments(CID string, MID string, attachments []MessageAttachment) ([]MessageAttachment, error)
+	// reactions come back with the rows from the Get*Messages calls.  Adding
+	// one that's there already, or to a message that's gone, does nothing
+	AddReaction(CID string, MID string, username string, emoji string, rtime string) error
+	RemoveReaction(CID string, MID string, username string, emoji string) error
+	// up to search.Limit messages matching search in search.CIDs, best match
+	// first, with Content set.  Deleted messages aren't found
+	SearchMessages(search MessageSearch) ([]SearchResultStruct, error)
+	// scheduled messages
+	AddScheduledMessage(msg ScheduledMessagesCmdStruct) error
+	RemoveScheduledMessage(msg ScheduledMessagesCmdStruct) error
+	RemoveAllScheduledMessages(username string) error
+	// removes and returns every scheduled message due before t
+	TakeDueScheduledMessages(t time.Time) ([]ScheduledMessagesCmdStruct, error)
+}
+
+// flags that can be toggled on a stored email with EmailStore.SetEmailFlag
+const (
+	EMAIL_FLAG_UNREAD  = "unread"
+	EMAIL_FLAG_STARRED = "starred"
+	EMAIL_FLAG_DELETED = "deleted"
+)
+
+// identifies a single email in a mailbox, same as the client sends it
+type EmailKey struct {
+	FromEmail string
+	Subject   string
+	RecvTime  string
+}
+
+// what EmailStore.GetEmails returns, everything that's left empty matches
+type EmailQuery struct {
+	Since  string // modified after
+	Search string // see search.go
+	// the email's flags, attachments is whether it has any
+	Starred     *bool
+	Unread      *bool
+	Spam        *bool
+	Draft       *bool
+	Deleted     *bool
+	Attachments *bool
+	After       string // recv_time
+	Before      string
+	Cursor      *emailCursor
+	Limit       int // 0 for no limit
+}
+
+type EmailStore interface {
+	CreateMailbox(username string) error
+	DropMailbox(username string) error
+	AddEmail(username string, email EmailRowStruct) error
+	// emails matching query, most recently modified first
+	GetEmails(username string, query EmailQuery) ([]EmailRowStruct, error)
+	SetEmailFlag(username string, key EmailKey, flag string, value bool, mtime string) error
+	// replaces the draft with the same recv_time, if any
+	SaveDraft(username string, email EmailRowStruct) error
+	RemoveDeletedEmails(username string) error
+}
+
+// every user's change log, see changes.go.  Seqs start at 1 and go up by
+// one per change, usernames are upper case
+type ChangeStore interface {
+	// adds a change to the end of the user's log and drops all but the
+	// latest CHANGE_LOG_SIZE, returns its seq
+	AppendChange(username string, kind string, event string) (int64, error)
+	// up to limit changes after seq, oldest first
+	GetChanges(username string, after int64, limit int) ([]ChangeStruct, error)
+	// seq of the user's latest change, 0 for none
+	LastChange(username string) (int64, error)
+	// empties the log, the seqs carry on from where they were so a cursor
+	// from before never looks current
+	DeleteChanges(username string) error
+}
+
+// every web device's queue of the events sent to it, see device_queue.go.
+// Seqs start at 1 and go up by one per device
+type DeviceQueueStore interface {
+	// adds event to the end of the user's queue on the device and drops the
+	// events older than DEVICE_QUEUE_RETENTION or past DEVICE_QUEUE_SIZE,
+	// returns its seq.  Every user on a device has a queue of their own
+	EnqueueDeviceEvent(username string, device string, event string) (int64, error)
+	// up to limit events after seq, oldest first
+	GetDeviceEvents(username string, device string, after int64, limit int) ([]DeviceEventStruct, error)
+	// seq of the last event queued for the user on the device, 0 for none
+	LastDeviceEvent(username string, device string) (int64, error)
+	// drops the events up to seq, the device has them
+	AckDeviceEvents(username string, device string, seq int64) error
+	// drops the user's queue on the device and its seqs, for a device that's
+	// logged out
+	DeleteDeviceQueue(username string, device string) error
+}
+
+type EventSubscription interface {
+	Unsubscribe() error
+}
+
+// EventBus delivers strings published on a subject (a web device token)
+// to every channel subscribed to that subject
+type EventBus interface {
+	Publish(subject string, content string) error
+	Subscribe(subject string, ch chan string) (EventSubscription, error)
+}
+
+// LinkFetcher looks up what a page says about itself for its link preview,
+// see link_previews.go
+type LinkFetcher interface {
+	// an ATTACHMENT_LINK for rawURL, ErrNoPreview when there's nothing to show
+	FetchPreview(ctx context.Context, rawURL string) (MessageAttachment, error)
+}
+
+// Stores groups every backend a Database needs
+type Stores struct {
+	Users    UserStore
+	Convos   ConversationStore
+	Messages MessageStore
+	Emails   EmailStore
+	Changes  ChangeStore
+	Queues   DeviceQueueStore
+	Events   EventBus
+	// nil turns link previews off
+	Previews LinkFetcher
+}
+
+// closes every store that holds a connection, once each
+func (s Stores) Close() {
+	closed := make(map[interface{}]bool)
+	for _, store := range []interface{}{s.Users, s.Convos, s.Messages, s.Emails, s.Changes, s.Queues, s.Events} {
+		if store == nil || closed[store] {
+			continue
+		}
+		if closer, ok := store.(interface {
+			Close()
+		}); ok {
+			closer.Close()
+		}
+		closed[store] = true
+	}
+}
+
+// pings every store that holds a connection, returning the first error
+func (s Stores) Ping() error {
+	pinged := make(map[interface{}]bool)
+	for _, store := range []interface{}{s.Users, s.Convos, s.Messages, s.Emails, s.Changes, s.Queues, s.Events} {
+		if store == nil || pinged[store] {
+			continue
+		}
+		if pinger, ok := store.(interface {
+			Ping() error
+		}); ok {
+			if err := pinger.Ping(); err != nil {
+				return err
+			}
+		}
+		pinged[store] = true
+	}
+	return nil
+}
@@ -1,13 +1,14 @@
 package pcDatabase
 
 import (
-	// "encoding/json"
+	"encoding/json"
 	aerospike "github.com/aerospike/aerospike-client-go"
 	"github.com/pquerna/ffjson/ffjson"
 	"strings"
 	"time"
 )
 
+// ffjson: skip
 type UserStruct struct {
 	Username               string
 	UsernameUpper          string
@@ -30,8 +31,13 @@ type UserStruct struct {
 	Quota                  uint32
 	QuotaUsed              uint32
 	SecQuests              string // security questions
+	Sessions               string `json:"-"` // JSON marshal, see sessions.go
+	HideReadTime           bool   // the others in a conversation don't see when it was read, see read_receipts.go
+	HideLastSeen           bool   // friends and conversation members only see the state, see presence.go
 	// for json exporting
 	Cmd string `json:"cmd,omitempty"`
+	// handed out by CreateUser and ValidateUser, never stored
+	SessionToken string `json:"sessionToken,omitempty"`
 }
 
 func (user *UserStruct) ToJSONString() string {
@@ -188,6 +194,30 @@ func (user *UserStruct) SaveScheduledMessagesStructs(ScheduledMessages []Schedul
 	}
 }
 
+func (user *UserStruct) GetSessionStructs() []SessionStruct {
+	var Sessions []SessionStruct
+	if user.Sessions == "" {
+		return make([]SessionStruct, 0)
+	}
+	err := json.Unmarshal([]byte(user.Sessions), &Sessions)
+	if err != nil {
+		ERROR.Println("error in json.Unmarshal, user.Sessions = " + user.Sessions)
+		ERROR.Println(err)
+		Sessions = make([]SessionStruct, 0)
+	}
+	return Sessions
+}
+
+func (user *UserStruct) SaveSessionStructs(Sessions []SessionStruct) {
+	SessionsByte, err := json.Marshal(Sessions)
+	if err != nil {
+		ERROR.Println("error in json.Marshal")
+		ERROR.Println(err)
+	} else {
+		user.Sessions = string(SessionsByte)
+	}
+}
+
 func (user *UserStruct) ToAerospikeBins() aerospike.BinMap {
 	bins := aerospike.BinMap{
 		"Username":                         user.Username,
@@ -197,11 +227,6 @@ func (user *UserStruct) ToAerospikeBins() aerospike.BinMap {
 		AEROSPIKE_USERS_USERNAME_PHONE_BIN: user.Phone,
 		"PhoneGateway":                     user.PhoneGateway,
 		"AutoreplyNote":                    user.AutoreplyMessage,
-		"Friends":                          user.Friends,
-		"InPendFriend":                     user.IncomingPendingFriends,
-		"OutPendFriend":                    user.OutgoingPendingFriends,
-		"CIDs":                             user.CIDs,
-		"SchedMessages":                    user.ScheduledMessages,
 		"EmailMtime":                       user.EmailMtime,
 		"Android":                          user.Android,
 		"Fireos":                           user.Fireos,
@@ -210,7 +235,13 @@ func (user *UserStruct) ToAerospikeBins() aerospike.BinMap {
 		"ProfilePic":                       user.ProfilePic,
 		"Quota":                            int(user.Quota),
 		"QuotaUsed":                        int(user.QuotaUsed),
-		"SecQuests":                        user.SecQuests,
+		"Sessions":                         user.Sessions,
+		"HideReadTime":                     boolToBin(user.HideReadTime),
+		"HideLastSeen":                     boolToBin(user.HideLastSeen),
+	}
+	// CIDs, Friends, ... are maps and lists, see user_bins.go
+	for name, value := range user.jsonFieldsToBins() {
+		bins[name] = value
 	}
 	return bins
 }
@@ -256,26 +287,9 @@ func FillUserWithAerospikeBins(recbins aerospike.BinMap) UserStruct {
 	if _, ok := recbins["AutoreplyNote"]; ok {
 		user.AutoreplyMessage = recbins["AutoreplyNote"].(string)
 	}
-	if _, ok := recbins["CIDs"]; ok {
-		user.CIDs = recbins["CIDs"].(string)
-	}
-	if _, ok := recbins["SchedMessages"]; ok {
-		user.ScheduledMessages = recbins["SchedMessages"].(string)
-	}
 	if _, ok := recbins["EmailMtime"]; ok {
 		user.EmailMtime = recbins["EmailMtime"].(string)
 	}
-	if _, ok := recbins["Friends"]; ok {
-		user.Friends = recbins["Friends"].(string)
-	}
-	if _, ok := recbins["InPendFriend"]; ok {
-		// TRACE.Println("ok reading recbins[InPendFriend]")
-		user.IncomingPendingFriends = recbins["InPendFriend"].(string)
-	}
-	if _, ok := recbins["OutPendFriend"]; ok {
-		// TRACE.Println("ok reading recbins[OutPendFriend]")
-		user.OutgoingPendingFriends = recbins["OutPendFriend"].(string)
-	}
 	if _, ok := recbins["ProfilePic"]; ok {
 		user.ProfilePic = recbins["ProfilePic"].(string)
 	}
@@ -285,8 +299,14 @@ func FillUserWithAerospikeBins(recbins aerospike.BinMap) UserStruct {
 	if _, ok := recbins["QuotaUsed"]; ok {
 		user.QuotaUsed = uint32(recbins["QuotaUsed"].(int)) // https://github.com/aerospike/aerospike-client-go/issues/62
 	}
-	if _, ok := recbins["SecQuests"]; ok {
-		user.SecQuests = recbins["SecQuests"].(string)
+	if _, ok := recbins["Sessions"]; ok {
+		user.Sessions = recbins["Sessions"].(string)
+	}
+	if _, ok := recbins["HideReadTime"]; ok {
+		user.HideReadTime = recbins["HideReadTime"].(int) == 1
+	}
+	if _, ok := recbins["HideLastSeen"]; ok {
+		user.HideLastSeen = recbins["HideLastSeen"].(int) == 1
 	}
 	if _, ok := recbins["Android"]; ok {
 		user.Android = InterfaceArrayToStringArray(recbins["Android"].([]interface{}))
@@ -300,6 +320,7 @@ func FillUserWithAerospikeBins(recbins aerospike.BinMap) UserStruct {
 	if _, ok := recbins["Web"]; ok {
 		user.Web = InterfaceArrayToStringArray(recbins["Web"].([]interface{}))
 	}
+	user.fillJSONFieldsFromBins(recbins)
 
 	return user
 }
@@ -368,6 +389,16 @@ type ValidateUserCmdStruct struct {
 	Username string `json:"username"`
 }
 
+type ResumeSessionCmdStruct struct {
+	Cmd          string `json:"cmd"`
+	SessionToken string `json:"sessionToken"`
+}
+
+type SessionCmdStruct struct {
+	Cmd string `json:"cmd"`
+	ID  string `json:"ID"`
+}
+
 type ChangeDeviceStruct struct {
 	Cmd       string `json:"cmd"`
 	Username  string `json:"username"`
@@ -417,6 +448,7 @@ type CIDCommandStruct struct {
 	Messages []string `json:"Messages,omitempty"`
 }
 
+// ffjson: skip
 type ConvoDataStruct struct {
 	Cmd      string           `json:"cmd,omitempty"`
 	CID      string           `json:"CID,omitempty"`
@@ -425,13 +457,17 @@ type ConvoDataStruct struct {
 	Members  []string         `json:"Members,omitempty"`
 	Files    []string         `json:"Files,omitempty"`
 	Messages []ConvoRowStruct `json:"Messages,omitempty"`
+	// bots turned off in the conversation
+	DisabledBots []string `json:"DisabledBots,omitempty"`
 }
 
+// ffjson: skip
 type UserCIDStruct struct {
 	CID           string
 	M_time        string
 	UnreadCount   int
 	AutoreplySent int
+	Muted         int // 1 for no pushes to phones, see MuteConversation
 }
 
 type AutoreplyList struct {
@@ -445,18 +481,40 @@ type UserFriendStruct struct {
 	Message    string
 }
 
+// ffjson: skip
 type ConvoRowStruct struct {
 	// CID        string `json:"CID"`
-	F_username string    `json:"f_username"`
-	M_time     time.Time `json:"m_time"`
-	Content    string    `json:"content"`
+	MID         string              `json:"MID"`
+	F_username  string              `json:"f_username"`
+	M_time      time.Time           `json:"m_time"`
+	Content     string              `json:"content"` // markup, see richtext.go
+	Attachments []MessageAttachment `json:"attachments,omitempty"`
+	E_time      *time.Time          `json:"e_time,omitempty"`  // last edited or deleted
+	Deleted     bool                `json:"deleted,omitempty"` // content is empty once deleted
+	Reactions   []ReactionStruct    `json:"reactions,omitempty"`
+	// replies point at their thread's message, which counts them
+	P_MID      string     `json:"p_MID,omitempty"`
+	ReplyCount int        `json:"reply_count,omitempty"`
+	LastReply  *time.Time `json:"last_reply,omitempty"`
+}
+
+// everyone who reacted to a message with Emoji, in the order they did
+type ReactionStruct struct {
+	Emoji     string   `json:"emoji"`
+	Usernames []string `json:"usernames"`
+}
+
+// what a message said before an edit replaced it at E_time
+type MessageEditStruct struct {
+	Content string    `json:"content"`
+	E_time  time.Time `json:"e_time"`
 }
 
 // convo members
 type ConvoMember struct {
 	Username string `json:"username"`
 	ReadTime string `json:"read_time"`
-	Typing   bool   `json:"typing"`
+	Typing   bool   `json:"typing"` // no longer saved, see typing.go
 }
 
 type ConvoMemberArray []ConvoMember
@@ -511,6 +569,83 @@ func ToConvoMemberArray(arr []string) ConvoMemberArray {
 	return memberArray
 }
 
+// GetReadReceipts, for the message MID or M_time, or the latest message
+// without either
+type ReadReceiptsCmdStruct struct {
+	Cmd    string `json:"cmd,omitempty"`
+	CID    string `json:"CID"`
+	MID    string `json:"MID,omitempty"`
+	M_time string `json:"M_time,omitempty"`
+}
+
+type ReadReceiptStruct struct {
+	Username string `json:"username"`
+	ReadTime string `json:"read_time"`
+}
+
+type HideReadTimeCmdStruct struct {
+	Cmd      string `json:"cmd,omitempty"`
+	Username string `json:"Username"`
+	Hide     bool   `json:"hide"`
+}
+
+type PresenceCmdStruct struct {
+	Cmd      string `json:"cmd,omitempty"`
+	Username string `json:"Username"`
+	State    string `json:"state"`
+}
+
+type GetPresenceCmdStruct struct {
+	Cmd       string   `json:"cmd,omitempty"`
+	Username  string   `json:"Username"`
+	Usernames []string `json:"usernames"`
+}
+
+type PresenceStruct struct {
+	Username string `json:"Username"`
+	State    string `json:"state"`
+	LastSeen string `json:"last_seen,omitempty"`
+}
+
+type HideLastSeenCmdStruct struct {
+	Cmd      string `json:"cmd,omitempty"`
+	Username string `json:"Username"`
+	Hide     bool   `json:"hide"`
+}
+
+type ChangesCmdStruct struct {
+	Cmd      string `json:"cmd,omitempty"`
+	Username string `json:"Username"`
+	Cursor   string `json:"cursor"`
+}
+
+// one change in a user's log, Event is what their web devices were sent
+type ChangeStruct struct {
+	Seq    int64           `json:"seq"`
+	Kind   string          `json:"kind"`
+	Event  json.RawMessage `json:"event"`
+	C_time time.Time       `json:"c_time"`
+}
+
+type DeviceEventsCmdStruct struct {
+	Cmd string `json:"cmd,omitempty"`
+	Seq int64  `json:"seq"`
+}
+
+// one event in a device's queue, as it was sent without its eventSeq
+type DeviceEventStruct struct {
+	Seq    int64
+	Event  string
+	Q_time time.Time
+}
+
+type TypingCmdStruct struct {
+	Cmd      string `json:"cmd,omitempty"`
+	CID      string `json:"CID"`
+	Username string `json:"Username"`
+	Typing   bool   `json:"typing"`
+}
+
 type CmdConvoMember struct {
 	Cmd         string `json:"cmd,omitempty"`
 	CID         string
@@ -527,6 +662,73 @@ type CmdConvoUnreadCount struct {
 	UnreadCount int    `json:"unread_count"`
 }
 
+// EditMessage, DeleteMessage and GetMessageEdits, Content is the new
+// content for EditMessage
+type MessageCmdStruct struct {
+	Cmd     string `json:"cmd,omitempty"`
+	CID     string `json:"CID"`
+	MID     string `json:"MID"`
+	Content string `json:"content,omitempty"`
+}
+
+// AddReaction and RemoveReaction
+type ReactionCmdStruct struct {
+	Cmd      string `json:"cmd,omitempty"`
+	CID      string `json:"CID"`
+	MID      string `json:"MID"`
+	Username string `json:"Username"`
+	Emoji    string `json:"emoji"`
+}
+
+type MuteCmdStruct struct {
+	Cmd      string `json:"cmd,omitempty"`
+	CID      string `json:"CID"`
+	Username string `json:"Username"`
+	Muted    bool   `json:"muted"`
+}
+
+// SetConvoBot, Bot is the bot's name
+type ConvoBotCmdStruct struct {
+	Cmd     string `json:"cmd,omitempty"`
+	CID     string `json:"CID"`
+	Bot     string `json:"bot"`
+	Enabled bool   `json:"enabled"`
+}
+
+// SearchMessages, see search.go for what query can have.  CID, From,
+// After and Before narrow it down, Cursor is from the previous page
+type SearchCmdStruct struct {
+	Cmd    string `json:"cmd,omitempty"`
+	Query  string `json:"query"`
+	CID    string `json:"CID,omitempty"`
+	From   string `json:"from,omitempty"`
+	After  string `json:"after,omitempty"`
+	Before string `json:"before,omitempty"`
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// a message SearchMessages found, Snippet is HTML with the matches marked
+type SearchResultStruct struct {
+	CID        string    `json:"CID"`
+	MID        string    `json:"MID"`
+	F_username string    `json:"f_username"`
+	M_time     time.Time `json:"m_time"`
+	Snippet    string    `json:"snippet"`
+	Rank       float32   `json:"rank"`
+	Content    string    `json:"-"` // what the snippet is made from
+}
+
+// GetThread, replies after M_time or from the start without it
+type ThreadCmdStruct struct {
+	Cmd    string `json:"cmd,omitempty"`
+	CID    string `json:"CID"`
+	MID    string `json:"MID"`
+	M_time string `json:"M_time,omitempty"`
+	// the last reply already loaded, with its M_time, so replies sent at the
+	// same m_time aren't skipped
+	After_MID string `json:"after_MID,omitempty"`
+}
+
 type CmdConvoMtimeCount struct {
 	Cmd      string `json:"cmd,omitempty"`
 	CID      string `json:"CID"`
@@ -603,9 +805,37 @@ type EmailRowStruct struct {
 	M_time      time.Time `json:"m_time"`
 }
 
+// ffjson: skip
 type EmailDataStruct struct {
 	Cmd    string           `json:"cmd,omitempty"`
 	Emails []EmailRowStruct `json:"Messages,omitempty"`
+	Cursor string           `json:"cursor,omitempty"` // for the next page, empty on the last one
+}
+
+// GetAllEmails, emails changed since M_time a page at a time
+type EmailPageCmdStruct struct {
+	Cmd      string `json:"cmd,omitempty"`
+	Username string `json:"Username,omitempty"`
+	M_time   string `json:"M_time"`
+	Cursor   string `json:"cursor,omitempty"`
+}
+
+// SearchEmails, query is the same as SearchMessages' and looks at the
+// subject, content, sender and recipients.  The flags are left out to match
+// either way, After and Before are on recv_time
+type SearchEmailsCmdStruct struct {
+	Cmd         string `json:"cmd,omitempty"`
+	Username    string `json:"Username,omitempty"`
+	Query       string `json:"query,omitempty"`
+	Starred     *bool  `json:"starred,omitempty"`
+	Unread      *bool  `json:"unread,omitempty"`
+	Spam        *bool  `json:"spam,omitempty"`
+	Draft       *bool  `json:"draft,omitempty"`
+	Deleted     *bool  `json:"deleted,omitempty"`
+	Attachments *bool  `json:"attachments,omitempty"`
+	After       string `json:"after,omitempty"`
+	Before      string `json:"before,omitempty"`
+	Cursor      string `json:"cursor,omitempty"`
 }
 
 type SendEmailCmdStruct struct {
@@ -650,13 +880,35 @@ type FriendCmdStruct struct {
 }
 
 // messages
+// ffjson: skip
 type MessageStruct struct {
 	Cmd          string   `json:"cmd,omitempty"`
 	CID          string   `json:"CID,omitempty"`
+	MID          string   `json:"MID,omitempty"` // set by the server
 	FromUsername string   `json:"f_username,omitempty"`
 	ToUIDs       []string `json:"t_UIDs,omitempty"`
 	M_time       string   `json:"m_time,omitempty"`
-	Content      string   `json:"content,omitempty"`
+	Content      string   `json:"content,omitempty"` // markup, see richtext.go
+	ParentMID    string   `json:"p_MID,omitempty"`   // a reply in this message's thread
+	// pictures, gifs, files and the weather
+	Attachments []MessageAttachment `json:"attachments,omitempty"`
+}
+
+// ffjson: skip
+type MessageAttachment struct {
+	Type   string `json:"type"` // ATTACHMENT_IMAGE and so on
+	URL    string `json:"url,omitempty"`
+	Title  string `json:"title,omitempty"` // a file's name, what a gif was found with, a page's title
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+	// a link's preview, from the page's OpenGraph tags or oEmbed
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+	// the weather, Icon is forecast.io's (clear-day, rain...)
+	Icon        string   `json:"icon,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"` // forecast.io's auto units, fahrenheit in the US
+	Summary     string   `json:"summary,omitempty"`
 }
 
 // adm
@@ -681,3 +933,25 @@ type FoundUserStruct struct {
 	Email      string
 	Phone      string
 }
+
+// used by commands that only need the username
+type UsernameCmdStruct struct {
+	Cmd      string `json:"cmd"`
+	Username string `json:"username"`
+}
+
+type EmailCmdStruct struct {
+	Cmd   string `json:"cmd"`
+	Email string `json:"email"`
+}
+
+type ProfilePicCmdStruct struct {
+	Cmd        string `json:"cmd"`
+	Username   string
+	ProfilePic string
+}
+
+// for commands without any arguments
+type EmptyCmdStruct struct {
+	Cmd string `json:"cmd"`
+}
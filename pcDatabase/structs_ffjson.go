@@ -4046,7 +4046,7 @@ done:
 	return nil
 }
 
-func (mj *ConvoDataStruct) MarshalJSON() ([]byte, error) {
+func (mj *ConvoFileListStruct) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -4058,7 +4058,7 @@ func (mj *ConvoDataStruct) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *ConvoDataStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *ConvoFileListStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
@@ -4067,129 +4067,41 @@ func (mj *ConvoDataStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	var obj []byte
 	_ = obj
 	_ = err
-	buf.WriteString(`{ `)
-	if len(mj.Cmd) != 0 {
-		buf.WriteString(`"cmd":`)
-		fflib.WriteJsonString(buf, string(mj.Cmd))
-		buf.WriteByte(',')
-	}
-	if len(mj.CID) != 0 {
-		buf.WriteString(`"CID":`)
-		fflib.WriteJsonString(buf, string(mj.CID))
-		buf.WriteByte(',')
-	}
-	if len(mj.Name) != 0 {
-		buf.WriteString(`"Name":`)
-		fflib.WriteJsonString(buf, string(mj.Name))
-		buf.WriteByte(',')
-	}
-	if len(mj.M_time) != 0 {
-		buf.WriteString(`"M_time":`)
-		fflib.WriteJsonString(buf, string(mj.M_time))
-		buf.WriteByte(',')
-	}
-	if len(mj.Members) != 0 {
-		buf.WriteString(`"Members":`)
-		if mj.Members != nil {
-			buf.WriteString(`[`)
-			for i, v := range mj.Members {
-				if i != 0 {
-					buf.WriteString(`,`)
-				}
-				fflib.WriteJsonString(buf, string(v))
-			}
-			buf.WriteString(`]`)
-		} else {
-			buf.WriteString(`null`)
-		}
-		buf.WriteByte(',')
-	}
-	if len(mj.Files) != 0 {
-		buf.WriteString(`"Files":`)
-		if mj.Files != nil {
-			buf.WriteString(`[`)
-			for i, v := range mj.Files {
-				if i != 0 {
-					buf.WriteString(`,`)
-				}
-				fflib.WriteJsonString(buf, string(v))
-			}
-			buf.WriteString(`]`)
-		} else {
-			buf.WriteString(`null`)
-		}
-		buf.WriteByte(',')
-	}
-	if len(mj.Messages) != 0 {
-		buf.WriteString(`"Messages":`)
-		if mj.Messages != nil {
-			buf.WriteString(`[`)
-			for i, v := range mj.Messages {
-				if i != 0 {
-					buf.WriteString(`,`)
-				}
-
-				{
-
-					err = v.MarshalJSONBuf(buf)
-					if err != nil {
-						return err
-					}
-
-				}
-			}
-			buf.WriteString(`]`)
-		} else {
-			buf.WriteString(`null`)
-		}
-		buf.WriteByte(',')
-	}
-	buf.Rewind(1)
+	buf.WriteString(`{"f_username":`)
+	fflib.WriteJsonString(buf, string(mj.FromUsername))
+	buf.WriteString(`,"fileURL":`)
+	fflib.WriteJsonString(buf, string(mj.FileURL))
+	buf.WriteString(`,"m_time":`)
+	fflib.WriteJsonString(buf, string(mj.M_time))
 	buf.WriteByte('}')
 	return nil
 }
 
 const (
-	ffj_t_ConvoDataStructbase = iota
-	ffj_t_ConvoDataStructno_such_key
-
-	ffj_t_ConvoDataStruct_Cmd
-
-	ffj_t_ConvoDataStruct_CID
-
-	ffj_t_ConvoDataStruct_Name
-
-	ffj_t_ConvoDataStruct_M_time
+	ffj_t_ConvoFileListStructbase = iota
+	ffj_t_ConvoFileListStructno_such_key
 
-	ffj_t_ConvoDataStruct_Members
+	ffj_t_ConvoFileListStruct_FromUsername
 
-	ffj_t_ConvoDataStruct_Files
+	ffj_t_ConvoFileListStruct_FileURL
 
-	ffj_t_ConvoDataStruct_Messages
+	ffj_t_ConvoFileListStruct_M_time
 )
 
-var ffj_key_ConvoDataStruct_Cmd = []byte("cmd")
-
-var ffj_key_ConvoDataStruct_CID = []byte("CID")
-
-var ffj_key_ConvoDataStruct_Name = []byte("Name")
-
-var ffj_key_ConvoDataStruct_M_time = []byte("M_time")
-
-var ffj_key_ConvoDataStruct_Members = []byte("Members")
+var ffj_key_ConvoFileListStruct_FromUsername = []byte("f_username")
 
-var ffj_key_ConvoDataStruct_Files = []byte("Files")
+var ffj_key_ConvoFileListStruct_FileURL = []byte("fileURL")
 
-var ffj_key_ConvoDataStruct_Messages = []byte("Messages")
+var ffj_key_ConvoFileListStruct_M_time = []byte("m_time")
 
-func (uj *ConvoDataStruct) UnmarshalJSON(input []byte) error {
+func (uj *ConvoFileListStruct) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *ConvoDataStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *ConvoFileListStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_ConvoDataStructbase
+	currentKey := ffj_t_ConvoFileListStructbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -4235,107 +4147,54 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_ConvoDataStructno_such_key
+				currentKey = ffj_t_ConvoFileListStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
 				switch kn[0] {
 
-				case 'C':
-
-					if bytes.Equal(ffj_key_ConvoDataStruct_CID, kn) {
-						currentKey = ffj_t_ConvoDataStruct_CID
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'F':
-
-					if bytes.Equal(ffj_key_ConvoDataStruct_Files, kn) {
-						currentKey = ffj_t_ConvoDataStruct_Files
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'M':
-
-					if bytes.Equal(ffj_key_ConvoDataStruct_M_time, kn) {
-						currentKey = ffj_t_ConvoDataStruct_M_time
-						state = fflib.FFParse_want_colon
-						goto mainparse
-
-					} else if bytes.Equal(ffj_key_ConvoDataStruct_Members, kn) {
-						currentKey = ffj_t_ConvoDataStruct_Members
-						state = fflib.FFParse_want_colon
-						goto mainparse
+				case 'f':
 
-					} else if bytes.Equal(ffj_key_ConvoDataStruct_Messages, kn) {
-						currentKey = ffj_t_ConvoDataStruct_Messages
+					if bytes.Equal(ffj_key_ConvoFileListStruct_FromUsername, kn) {
+						currentKey = ffj_t_ConvoFileListStruct_FromUsername
 						state = fflib.FFParse_want_colon
 						goto mainparse
-					}
-
-				case 'N':
 
-					if bytes.Equal(ffj_key_ConvoDataStruct_Name, kn) {
-						currentKey = ffj_t_ConvoDataStruct_Name
+					} else if bytes.Equal(ffj_key_ConvoFileListStruct_FileURL, kn) {
+						currentKey = ffj_t_ConvoFileListStruct_FileURL
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'c':
+				case 'm':
 
-					if bytes.Equal(ffj_key_ConvoDataStruct_Cmd, kn) {
-						currentKey = ffj_t_ConvoDataStruct_Cmd
+					if bytes.Equal(ffj_key_ConvoFileListStruct_M_time, kn) {
+						currentKey = ffj_t_ConvoFileListStruct_M_time
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.EqualFoldRight(ffj_key_ConvoDataStruct_Messages, kn) {
-					currentKey = ffj_t_ConvoDataStruct_Messages
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_ConvoDataStruct_Files, kn) {
-					currentKey = ffj_t_ConvoDataStruct_Files
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_ConvoDataStruct_Members, kn) {
-					currentKey = ffj_t_ConvoDataStruct_Members
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.AsciiEqualFold(ffj_key_ConvoDataStruct_M_time, kn) {
-					currentKey = ffj_t_ConvoDataStruct_M_time
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_ConvoDataStruct_Name, kn) {
-					currentKey = ffj_t_ConvoDataStruct_Name
+				if fflib.AsciiEqualFold(ffj_key_ConvoFileListStruct_M_time, kn) {
+					currentKey = ffj_t_ConvoFileListStruct_M_time
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_ConvoDataStruct_CID, kn) {
-					currentKey = ffj_t_ConvoDataStruct_CID
+				if fflib.SimpleLetterEqualFold(ffj_key_ConvoFileListStruct_FileURL, kn) {
+					currentKey = ffj_t_ConvoFileListStruct_FileURL
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_ConvoDataStruct_Cmd, kn) {
-					currentKey = ffj_t_ConvoDataStruct_Cmd
+				if fflib.EqualFoldRight(ffj_key_ConvoFileListStruct_FromUsername, kn) {
+					currentKey = ffj_t_ConvoFileListStruct_FromUsername
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_ConvoDataStructno_such_key
+				currentKey = ffj_t_ConvoFileListStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -4352,28 +4211,16 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_ConvoDataStruct_Cmd:
-					goto handle_Cmd
-
-				case ffj_t_ConvoDataStruct_CID:
-					goto handle_CID
+				case ffj_t_ConvoFileListStruct_FromUsername:
+					goto handle_FromUsername
 
-				case ffj_t_ConvoDataStruct_Name:
-					goto handle_Name
+				case ffj_t_ConvoFileListStruct_FileURL:
+					goto handle_FileURL
 
-				case ffj_t_ConvoDataStruct_M_time:
+				case ffj_t_ConvoFileListStruct_M_time:
 					goto handle_M_time
 
-				case ffj_t_ConvoDataStruct_Members:
-					goto handle_Members
-
-				case ffj_t_ConvoDataStruct_Files:
-					goto handle_Files
-
-				case ffj_t_ConvoDataStruct_Messages:
-					goto handle_Messages
-
-				case ffj_t_ConvoDataStructno_such_key:
+				case ffj_t_ConvoFileListStructno_such_key:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -4387,9 +4234,9 @@ mainparse:
 		}
 	}
 
-handle_Cmd:
+handle_FromUsername:
 
-	/* handler: uj.Cmd type=string kind=string quoted=false*/
+	/* handler: uj.FromUsername type=string kind=string quoted=false*/
 
 	{
 
@@ -4405,7 +4252,7 @@ handle_Cmd:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Cmd = string(string(outBuf))
+			uj.FromUsername = string(string(outBuf))
 
 		}
 	}
@@ -4413,9 +4260,9 @@ handle_Cmd:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_CID:
+handle_FileURL:
 
-	/* handler: uj.CID type=string kind=string quoted=false*/
+	/* handler: uj.FileURL type=string kind=string quoted=false*/
 
 	{
 
@@ -4431,7 +4278,7 @@ handle_CID:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.CID = string(string(outBuf))
+			uj.FileURL = string(string(outBuf))
 
 		}
 	}
@@ -4439,9 +4286,9 @@ handle_CID:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Name:
+handle_M_time:
 
-	/* handler: uj.Name type=string kind=string quoted=false*/
+	/* handler: uj.M_time type=string kind=string quoted=false*/
 
 	{
 
@@ -4457,7 +4304,7 @@ handle_Name:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Name = string(string(outBuf))
+			uj.M_time = string(string(outBuf))
 
 		}
 	}
@@ -4465,240 +4312,298 @@ handle_Name:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_M_time:
-
-	/* handler: uj.M_time type=string kind=string quoted=false*/
+wantedvalue:
+	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+wrongtokenerror:
+	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
+tokerror:
+	if fs.BigError != nil {
+		return fs.WrapErr(fs.BigError)
+	}
+	err = fs.Error.ToError()
+	if err != nil {
+		return fs.WrapErr(err)
+	}
+	panic("ffjson-generated: unreachable, please report bug.")
+done:
+	return nil
+}
 
-	{
+func (mj *ConvoMember) MarshalJSON() ([]byte, error) {
+	var buf fflib.Buffer
+	if mj == nil {
+		buf.WriteString("null")
+		return buf.Bytes(), nil
+	}
+	err := mj.MarshalJSONBuf(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+func (mj *ConvoMember) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+	if mj == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	var err error
+	var obj []byte
+	_ = obj
+	_ = err
+	buf.WriteString(`{"username":`)
+	fflib.WriteJsonString(buf, string(mj.Username))
+	buf.WriteString(`,"read_time":`)
+	fflib.WriteJsonString(buf, string(mj.ReadTime))
+	if mj.Typing {
+		buf.WriteString(`,"typing":true`)
+	} else {
+		buf.WriteString(`,"typing":false`)
+	}
+	buf.WriteByte('}')
+	return nil
+}
 
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
+const (
+	ffj_t_ConvoMemberbase = iota
+	ffj_t_ConvoMemberno_such_key
 
-		if tok == fflib.FFTok_null {
+	ffj_t_ConvoMember_Username
 
-		} else {
+	ffj_t_ConvoMember_ReadTime
 
-			outBuf := fs.Output.Bytes()
+	ffj_t_ConvoMember_Typing
+)
 
-			uj.M_time = string(string(outBuf))
+var ffj_key_ConvoMember_Username = []byte("username")
 
-		}
-	}
+var ffj_key_ConvoMember_ReadTime = []byte("read_time")
 
-	state = fflib.FFParse_after_value
-	goto mainparse
+var ffj_key_ConvoMember_Typing = []byte("typing")
 
-handle_Members:
+func (uj *ConvoMember) UnmarshalJSON(input []byte) error {
+	fs := fflib.NewFFLexer(input)
+	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
+}
 
-	/* handler: uj.Members type=[]string kind=slice quoted=false*/
+func (uj *ConvoMember) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+	var err error = nil
+	currentKey := ffj_t_ConvoMemberbase
+	_ = currentKey
+	tok := fflib.FFTok_init
+	wantedTok := fflib.FFTok_init
 
-	{
+mainparse:
+	for {
+		tok = fs.Scan()
+		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
+		if tok == fflib.FFTok_error {
+			goto tokerror
+		}
 
-		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
+		switch state {
+
+		case fflib.FFParse_map_start:
+			if tok != fflib.FFTok_left_bracket {
+				wantedTok = fflib.FFTok_left_bracket
+				goto wrongtokenerror
 			}
-		}
+			state = fflib.FFParse_want_key
+			continue
 
-		if tok == fflib.FFTok_null {
-			uj.Members = nil
-		} else {
+		case fflib.FFParse_after_value:
+			if tok == fflib.FFTok_comma {
+				state = fflib.FFParse_want_key
+			} else if tok == fflib.FFTok_right_bracket {
+				goto done
+			} else {
+				wantedTok = fflib.FFTok_comma
+				goto wrongtokenerror
+			}
 
-			uj.Members = make([]string, 0)
+		case fflib.FFParse_want_key:
+			// json {} ended. goto exit. woo.
+			if tok == fflib.FFTok_right_bracket {
+				goto done
+			}
+			if tok != fflib.FFTok_string {
+				wantedTok = fflib.FFTok_string
+				goto wrongtokenerror
+			}
 
-			wantVal := true
+			kn := fs.Output.Bytes()
+			if len(kn) <= 0 {
+				// "" case. hrm.
+				currentKey = ffj_t_ConvoMemberno_such_key
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			} else {
+				switch kn[0] {
 
-			for {
+				case 'r':
 
-				var v string
+					if bytes.Equal(ffj_key_ConvoMember_ReadTime, kn) {
+						currentKey = ffj_t_ConvoMember_ReadTime
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 't':
+
+					if bytes.Equal(ffj_key_ConvoMember_Typing, kn) {
+						currentKey = ffj_t_ConvoMember_Typing
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'u':
+
+					if bytes.Equal(ffj_key_ConvoMember_Username, kn) {
+						currentKey = ffj_t_ConvoMember_Username
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
 
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
 				}
-				if tok == fflib.FFTok_right_brace {
-					break
+
+				if fflib.SimpleLetterEqualFold(ffj_key_ConvoMember_Typing, kn) {
+					currentKey = ffj_t_ConvoMember_Typing
+					state = fflib.FFParse_want_colon
+					goto mainparse
 				}
 
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
+				if fflib.AsciiEqualFold(ffj_key_ConvoMember_ReadTime, kn) {
+					currentKey = ffj_t_ConvoMember_ReadTime
+					state = fflib.FFParse_want_colon
+					goto mainparse
 				}
 
-				/* handler: v type=string kind=string quoted=false*/
+				if fflib.EqualFoldRight(ffj_key_ConvoMember_Username, kn) {
+					currentKey = ffj_t_ConvoMember_Username
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
 
-				{
+				currentKey = ffj_t_ConvoMemberno_such_key
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			}
 
-					{
-						if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-							return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-						}
-					}
+		case fflib.FFParse_want_colon:
+			if tok != fflib.FFTok_colon {
+				wantedTok = fflib.FFTok_colon
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_value
+			continue
+		case fflib.FFParse_want_value:
 
-					if tok == fflib.FFTok_null {
+			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
+				switch currentKey {
 
-					} else {
+				case ffj_t_ConvoMember_Username:
+					goto handle_Username
 
-						outBuf := fs.Output.Bytes()
+				case ffj_t_ConvoMember_ReadTime:
+					goto handle_ReadTime
 
-						v = string(string(outBuf))
+				case ffj_t_ConvoMember_Typing:
+					goto handle_Typing
 
+				case ffj_t_ConvoMemberno_such_key:
+					err = fs.SkipField(tok)
+					if err != nil {
+						return fs.WrapErr(err)
 					}
+					state = fflib.FFParse_after_value
+					goto mainparse
 				}
-
-				uj.Members = append(uj.Members, v)
-				wantVal = false
+			} else {
+				goto wantedvalue
 			}
 		}
 	}
 
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Files:
+handle_Username:
 
-	/* handler: uj.Files type=[]string kind=slice quoted=false*/
+	/* handler: uj.Username type=string kind=string quoted=false*/
 
 	{
 
 		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
 			}
 		}
 
 		if tok == fflib.FFTok_null {
-			uj.Files = nil
-		} else {
 
-			uj.Files = make([]string, 0)
-
-			wantVal := true
+		} else {
 
-			for {
+			outBuf := fs.Output.Bytes()
 
-				var v string
+			uj.Username = string(string(outBuf))
 
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
-				}
-				if tok == fflib.FFTok_right_brace {
-					break
-				}
+		}
+	}
 
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
-				}
+	state = fflib.FFParse_after_value
+	goto mainparse
 
-				/* handler: v type=string kind=string quoted=false*/
+handle_ReadTime:
 
-				{
+	/* handler: uj.ReadTime type=string kind=string quoted=false*/
 
-					{
-						if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-							return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-						}
-					}
+	{
 
-					if tok == fflib.FFTok_null {
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
 
-					} else {
+		if tok == fflib.FFTok_null {
 
-						outBuf := fs.Output.Bytes()
+		} else {
 
-						v = string(string(outBuf))
+			outBuf := fs.Output.Bytes()
 
-					}
-				}
+			uj.ReadTime = string(string(outBuf))
 
-				uj.Files = append(uj.Files, v)
-				wantVal = false
-			}
 		}
 	}
 
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Messages:
+handle_Typing:
 
-	/* handler: uj.Messages type=[]pcDatabase.ConvoRowStruct kind=slice quoted=false*/
+	/* handler: uj.Typing type=bool kind=bool quoted=false*/
 
 	{
-
-		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
-			}
+		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
 		}
+	}
 
+	{
 		if tok == fflib.FFTok_null {
-			uj.Messages = nil
+
 		} else {
+			tmpb := fs.Output.Bytes()
 
-			uj.Messages = make([]ConvoRowStruct, 0)
+			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
 
-			wantVal := true
+				uj.Typing = true
 
-			for {
+			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
 
-				var v ConvoRowStruct
+				uj.Typing = false
 
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
-				}
-				if tok == fflib.FFTok_right_brace {
-					break
-				}
+			} else {
+				err = errors.New("unexpected bytes for true/false value")
+				return fs.WrapErr(err)
+			}
 
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
-				}
-
-				/* handler: v type=pcDatabase.ConvoRowStruct kind=struct quoted=false*/
-
-				{
-					if tok == fflib.FFTok_null {
-
-						state = fflib.FFParse_after_value
-						goto mainparse
-					}
-
-					err = v.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
-					if err != nil {
-						return err
-					}
-					state = fflib.FFParse_after_value
-				}
-
-				uj.Messages = append(uj.Messages, v)
-				wantVal = false
-			}
 		}
 	}
 
@@ -4722,7 +4627,7 @@ done:
 	return nil
 }
 
-func (mj *ConvoFileListStruct) MarshalJSON() ([]byte, error) {
+func (mj *CreateUserCmdStruct) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -4734,7 +4639,7 @@ func (mj *ConvoFileListStruct) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *ConvoFileListStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *CreateUserCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
@@ -4743,41 +4648,65 @@ func (mj *ConvoFileListStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	var obj []byte
 	_ = obj
 	_ = err
-	buf.WriteString(`{"f_username":`)
-	fflib.WriteJsonString(buf, string(mj.FromUsername))
-	buf.WriteString(`,"fileURL":`)
-	fflib.WriteJsonString(buf, string(mj.FileURL))
-	buf.WriteString(`,"m_time":`)
-	fflib.WriteJsonString(buf, string(mj.M_time))
+	buf.WriteString(`{"cmd":`)
+	fflib.WriteJsonString(buf, string(mj.Cmd))
+	buf.WriteString(`,"password":`)
+	fflib.WriteJsonString(buf, string(mj.Password))
+	buf.WriteString(`,"email":`)
+	fflib.WriteJsonString(buf, string(mj.Email))
+	buf.WriteString(`,"phone":`)
+	fflib.WriteJsonString(buf, string(mj.Phone))
+	buf.WriteString(`,"token":`)
+	fflib.WriteJsonString(buf, string(mj.Token))
+	buf.WriteString(`,"username":`)
+	fflib.WriteJsonString(buf, string(mj.Username))
+	buf.WriteString(`,"secQuests":`)
+	fflib.WriteJsonString(buf, string(mj.SecQuests))
 	buf.WriteByte('}')
 	return nil
 }
 
 const (
-	ffj_t_ConvoFileListStructbase = iota
-	ffj_t_ConvoFileListStructno_such_key
+	ffj_t_CreateUserCmdStructbase = iota
+	ffj_t_CreateUserCmdStructno_such_key
 
-	ffj_t_ConvoFileListStruct_FromUsername
+	ffj_t_CreateUserCmdStruct_Cmd
 
-	ffj_t_ConvoFileListStruct_FileURL
+	ffj_t_CreateUserCmdStruct_Password
 
-	ffj_t_ConvoFileListStruct_M_time
+	ffj_t_CreateUserCmdStruct_Email
+
+	ffj_t_CreateUserCmdStruct_Phone
+
+	ffj_t_CreateUserCmdStruct_Token
+
+	ffj_t_CreateUserCmdStruct_Username
+
+	ffj_t_CreateUserCmdStruct_SecQuests
 )
 
-var ffj_key_ConvoFileListStruct_FromUsername = []byte("f_username")
+var ffj_key_CreateUserCmdStruct_Cmd = []byte("cmd")
 
-var ffj_key_ConvoFileListStruct_FileURL = []byte("fileURL")
+var ffj_key_CreateUserCmdStruct_Password = []byte("password")
 
-var ffj_key_ConvoFileListStruct_M_time = []byte("m_time")
+var ffj_key_CreateUserCmdStruct_Email = []byte("email")
 
-func (uj *ConvoFileListStruct) UnmarshalJSON(input []byte) error {
+var ffj_key_CreateUserCmdStruct_Phone = []byte("phone")
+
+var ffj_key_CreateUserCmdStruct_Token = []byte("token")
+
+var ffj_key_CreateUserCmdStruct_Username = []byte("username")
+
+var ffj_key_CreateUserCmdStruct_SecQuests = []byte("secQuests")
+
+func (uj *CreateUserCmdStruct) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *ConvoFileListStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *CreateUserCmdStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_ConvoFileListStructbase
+	currentKey := ffj_t_CreateUserCmdStructbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -4823,54 +4752,110 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_ConvoFileListStructno_such_key
+				currentKey = ffj_t_CreateUserCmdStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
 				switch kn[0] {
 
-				case 'f':
+				case 'c':
 
-					if bytes.Equal(ffj_key_ConvoFileListStruct_FromUsername, kn) {
-						currentKey = ffj_t_ConvoFileListStruct_FromUsername
+					if bytes.Equal(ffj_key_CreateUserCmdStruct_Cmd, kn) {
+						currentKey = ffj_t_CreateUserCmdStruct_Cmd
 						state = fflib.FFParse_want_colon
 						goto mainparse
+					}
 
-					} else if bytes.Equal(ffj_key_ConvoFileListStruct_FileURL, kn) {
-						currentKey = ffj_t_ConvoFileListStruct_FileURL
+				case 'e':
+
+					if bytes.Equal(ffj_key_CreateUserCmdStruct_Email, kn) {
+						currentKey = ffj_t_CreateUserCmdStruct_Email
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'm':
+				case 'p':
 
-					if bytes.Equal(ffj_key_ConvoFileListStruct_M_time, kn) {
-						currentKey = ffj_t_ConvoFileListStruct_M_time
+					if bytes.Equal(ffj_key_CreateUserCmdStruct_Password, kn) {
+						currentKey = ffj_t_CreateUserCmdStruct_Password
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffj_key_CreateUserCmdStruct_Phone, kn) {
+						currentKey = ffj_t_CreateUserCmdStruct_Phone
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 's':
+
+					if bytes.Equal(ffj_key_CreateUserCmdStruct_SecQuests, kn) {
+						currentKey = ffj_t_CreateUserCmdStruct_SecQuests
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 't':
+
+					if bytes.Equal(ffj_key_CreateUserCmdStruct_Token, kn) {
+						currentKey = ffj_t_CreateUserCmdStruct_Token
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'u':
+
+					if bytes.Equal(ffj_key_CreateUserCmdStruct_Username, kn) {
+						currentKey = ffj_t_CreateUserCmdStruct_Username
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.AsciiEqualFold(ffj_key_ConvoFileListStruct_M_time, kn) {
-					currentKey = ffj_t_ConvoFileListStruct_M_time
+				if fflib.EqualFoldRight(ffj_key_CreateUserCmdStruct_SecQuests, kn) {
+					currentKey = ffj_t_CreateUserCmdStruct_SecQuests
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_ConvoFileListStruct_FileURL, kn) {
-					currentKey = ffj_t_ConvoFileListStruct_FileURL
+				if fflib.EqualFoldRight(ffj_key_CreateUserCmdStruct_Username, kn) {
+					currentKey = ffj_t_CreateUserCmdStruct_Username
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_ConvoFileListStruct_FromUsername, kn) {
-					currentKey = ffj_t_ConvoFileListStruct_FromUsername
+				if fflib.EqualFoldRight(ffj_key_CreateUserCmdStruct_Token, kn) {
+					currentKey = ffj_t_CreateUserCmdStruct_Token
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_ConvoFileListStructno_such_key
+				if fflib.SimpleLetterEqualFold(ffj_key_CreateUserCmdStruct_Phone, kn) {
+					currentKey = ffj_t_CreateUserCmdStruct_Phone
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffj_key_CreateUserCmdStruct_Email, kn) {
+					currentKey = ffj_t_CreateUserCmdStruct_Email
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffj_key_CreateUserCmdStruct_Password, kn) {
+					currentKey = ffj_t_CreateUserCmdStruct_Password
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffj_key_CreateUserCmdStruct_Cmd, kn) {
+					currentKey = ffj_t_CreateUserCmdStruct_Cmd
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				currentKey = ffj_t_CreateUserCmdStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -4887,16 +4872,28 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_ConvoFileListStruct_FromUsername:
-					goto handle_FromUsername
+				case ffj_t_CreateUserCmdStruct_Cmd:
+					goto handle_Cmd
 
-				case ffj_t_ConvoFileListStruct_FileURL:
-					goto handle_FileURL
+				case ffj_t_CreateUserCmdStruct_Password:
+					goto handle_Password
 
-				case ffj_t_ConvoFileListStruct_M_time:
-					goto handle_M_time
+				case ffj_t_CreateUserCmdStruct_Email:
+					goto handle_Email
 
-				case ffj_t_ConvoFileListStructno_such_key:
+				case ffj_t_CreateUserCmdStruct_Phone:
+					goto handle_Phone
+
+				case ffj_t_CreateUserCmdStruct_Token:
+					goto handle_Token
+
+				case ffj_t_CreateUserCmdStruct_Username:
+					goto handle_Username
+
+				case ffj_t_CreateUserCmdStruct_SecQuests:
+					goto handle_SecQuests
+
+				case ffj_t_CreateUserCmdStructno_such_key:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -4910,9 +4907,9 @@ mainparse:
 		}
 	}
 
-handle_FromUsername:
+handle_Cmd:
 
-	/* handler: uj.FromUsername type=string kind=string quoted=false*/
+	/* handler: uj.Cmd type=string kind=string quoted=false*/
 
 	{
 
@@ -4928,7 +4925,7 @@ handle_FromUsername:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.FromUsername = string(string(outBuf))
+			uj.Cmd = string(string(outBuf))
 
 		}
 	}
@@ -4936,9 +4933,9 @@ handle_FromUsername:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_FileURL:
+handle_Password:
 
-	/* handler: uj.FileURL type=string kind=string quoted=false*/
+	/* handler: uj.Password type=string kind=string quoted=false*/
 
 	{
 
@@ -4954,7 +4951,7 @@ handle_FileURL:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.FileURL = string(string(outBuf))
+			uj.Password = string(string(outBuf))
 
 		}
 	}
@@ -4962,9 +4959,9 @@ handle_FileURL:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_M_time:
+handle_Email:
 
-	/* handler: uj.M_time type=string kind=string quoted=false*/
+	/* handler: uj.Email type=string kind=string quoted=false*/
 
 	{
 
@@ -4980,7 +4977,7 @@ handle_M_time:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.M_time = string(string(outBuf))
+			uj.Email = string(string(outBuf))
 
 		}
 	}
@@ -4988,220 +4985,35 @@ handle_M_time:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-wantedvalue:
-	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-wrongtokenerror:
-	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
-tokerror:
-	if fs.BigError != nil {
-		return fs.WrapErr(fs.BigError)
-	}
-	err = fs.Error.ToError()
-	if err != nil {
-		return fs.WrapErr(err)
+handle_Phone:
+
+	/* handler: uj.Phone type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			uj.Phone = string(string(outBuf))
+
+		}
 	}
-	panic("ffjson-generated: unreachable, please report bug.")
-done:
-	return nil
-}
 
-func (mj *ConvoMember) MarshalJSON() ([]byte, error) {
-	var buf fflib.Buffer
-	if mj == nil {
-		buf.WriteString("null")
-		return buf.Bytes(), nil
-	}
-	err := mj.MarshalJSONBuf(&buf)
-	if err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
-}
-func (mj *ConvoMember) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
-	if mj == nil {
-		buf.WriteString("null")
-		return nil
-	}
-	var err error
-	var obj []byte
-	_ = obj
-	_ = err
-	buf.WriteString(`{"username":`)
-	fflib.WriteJsonString(buf, string(mj.Username))
-	buf.WriteString(`,"read_time":`)
-	fflib.WriteJsonString(buf, string(mj.ReadTime))
-	if mj.Typing {
-		buf.WriteString(`,"typing":true`)
-	} else {
-		buf.WriteString(`,"typing":false`)
-	}
-	buf.WriteByte('}')
-	return nil
-}
-
-const (
-	ffj_t_ConvoMemberbase = iota
-	ffj_t_ConvoMemberno_such_key
-
-	ffj_t_ConvoMember_Username
-
-	ffj_t_ConvoMember_ReadTime
-
-	ffj_t_ConvoMember_Typing
-)
-
-var ffj_key_ConvoMember_Username = []byte("username")
-
-var ffj_key_ConvoMember_ReadTime = []byte("read_time")
-
-var ffj_key_ConvoMember_Typing = []byte("typing")
-
-func (uj *ConvoMember) UnmarshalJSON(input []byte) error {
-	fs := fflib.NewFFLexer(input)
-	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
-}
-
-func (uj *ConvoMember) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
-	var err error = nil
-	currentKey := ffj_t_ConvoMemberbase
-	_ = currentKey
-	tok := fflib.FFTok_init
-	wantedTok := fflib.FFTok_init
-
-mainparse:
-	for {
-		tok = fs.Scan()
-		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
-		if tok == fflib.FFTok_error {
-			goto tokerror
-		}
-
-		switch state {
-
-		case fflib.FFParse_map_start:
-			if tok != fflib.FFTok_left_bracket {
-				wantedTok = fflib.FFTok_left_bracket
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_key
-			continue
-
-		case fflib.FFParse_after_value:
-			if tok == fflib.FFTok_comma {
-				state = fflib.FFParse_want_key
-			} else if tok == fflib.FFTok_right_bracket {
-				goto done
-			} else {
-				wantedTok = fflib.FFTok_comma
-				goto wrongtokenerror
-			}
-
-		case fflib.FFParse_want_key:
-			// json {} ended. goto exit. woo.
-			if tok == fflib.FFTok_right_bracket {
-				goto done
-			}
-			if tok != fflib.FFTok_string {
-				wantedTok = fflib.FFTok_string
-				goto wrongtokenerror
-			}
-
-			kn := fs.Output.Bytes()
-			if len(kn) <= 0 {
-				// "" case. hrm.
-				currentKey = ffj_t_ConvoMemberno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			} else {
-				switch kn[0] {
-
-				case 'r':
-
-					if bytes.Equal(ffj_key_ConvoMember_ReadTime, kn) {
-						currentKey = ffj_t_ConvoMember_ReadTime
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 't':
-
-					if bytes.Equal(ffj_key_ConvoMember_Typing, kn) {
-						currentKey = ffj_t_ConvoMember_Typing
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'u':
-
-					if bytes.Equal(ffj_key_ConvoMember_Username, kn) {
-						currentKey = ffj_t_ConvoMember_Username
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_ConvoMember_Typing, kn) {
-					currentKey = ffj_t_ConvoMember_Typing
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.AsciiEqualFold(ffj_key_ConvoMember_ReadTime, kn) {
-					currentKey = ffj_t_ConvoMember_ReadTime
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_ConvoMember_Username, kn) {
-					currentKey = ffj_t_ConvoMember_Username
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				currentKey = ffj_t_ConvoMemberno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			}
-
-		case fflib.FFParse_want_colon:
-			if tok != fflib.FFTok_colon {
-				wantedTok = fflib.FFTok_colon
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_value
-			continue
-		case fflib.FFParse_want_value:
-
-			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
-				switch currentKey {
-
-				case ffj_t_ConvoMember_Username:
-					goto handle_Username
-
-				case ffj_t_ConvoMember_ReadTime:
-					goto handle_ReadTime
-
-				case ffj_t_ConvoMember_Typing:
-					goto handle_Typing
-
-				case ffj_t_ConvoMemberno_such_key:
-					err = fs.SkipField(tok)
-					if err != nil {
-						return fs.WrapErr(err)
-					}
-					state = fflib.FFParse_after_value
-					goto mainparse
-				}
-			} else {
-				goto wantedvalue
-			}
-		}
-	}
+	state = fflib.FFParse_after_value
+	goto mainparse
 
-handle_Username:
+handle_Token:
 
-	/* handler: uj.Username type=string kind=string quoted=false*/
+	/* handler: uj.Token type=string kind=string quoted=false*/
 
 	{
 
@@ -5217,7 +5029,7 @@ handle_Username:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Username = string(string(outBuf))
+			uj.Token = string(string(outBuf))
 
 		}
 	}
@@ -5225,9 +5037,9 @@ handle_Username:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_ReadTime:
+handle_Username:
 
-	/* handler: uj.ReadTime type=string kind=string quoted=false*/
+	/* handler: uj.Username type=string kind=string quoted=false*/
 
 	{
 
@@ -5243,7 +5055,7 @@ handle_ReadTime:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.ReadTime = string(string(outBuf))
+			uj.Username = string(string(outBuf))
 
 		}
 	}
@@ -5251,34 +5063,25 @@ handle_ReadTime:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Typing:
+handle_SecQuests:
 
-	/* handler: uj.Typing type=bool kind=bool quoted=false*/
+	/* handler: uj.SecQuests type=string kind=string quoted=false*/
 
 	{
-		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
 		}
-	}
 
-	{
 		if tok == fflib.FFTok_null {
 
 		} else {
-			tmpb := fs.Output.Bytes()
-
-			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
-
-				uj.Typing = true
-
-			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
 
-				uj.Typing = false
+			outBuf := fs.Output.Bytes()
 
-			} else {
-				err = errors.New("unexpected bytes for true/false value")
-				return fs.WrapErr(err)
-			}
+			uj.SecQuests = string(string(outBuf))
 
 		}
 	}
@@ -5303,7 +5106,7 @@ done:
 	return nil
 }
 
-func (mj *ConvoRowStruct) MarshalJSON() ([]byte, error) {
+func (mj *EmailRowStruct) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -5315,7 +5118,7 @@ func (mj *ConvoRowStruct) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *ConvoRowStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *EmailRowStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
@@ -5324,50 +5127,134 @@ func (mj *ConvoRowStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	var obj []byte
 	_ = obj
 	_ = err
-	buf.WriteString(`{"f_username":`)
-	fflib.WriteJsonString(buf, string(mj.F_username))
-	buf.WriteString(`,"m_time":`)
-
-	{
-
-		obj, err = mj.M_time.MarshalJSON()
-		if err != nil {
-			return err
-		}
-		buf.Write(obj)
-
-	}
+	buf.WriteString(`{"from_email":`)
+	fflib.WriteJsonString(buf, string(mj.FromEmail))
+	buf.WriteString(`,"to_emails":`)
+	fflib.WriteJsonString(buf, string(mj.ToEmails))
+	buf.WriteString(`,"recv_email":`)
+	fflib.WriteJsonString(buf, string(mj.RecvEmail))
+	buf.WriteString(`,"subject":`)
+	fflib.WriteJsonString(buf, string(mj.Subject))
 	buf.WriteString(`,"content":`)
 	fflib.WriteJsonString(buf, string(mj.Content))
-	buf.WriteByte('}')
-	return nil
-}
-
+	buf.WriteString(`,"attachments":`)
+	fflib.WriteJsonString(buf, string(mj.Attachments))
+	if mj.Starred {
+		buf.WriteString(`,"starred":true`)
+	} else {
+		buf.WriteString(`,"starred":false`)
+	}
+	if mj.Unread {
+		buf.WriteString(`,"unread":true`)
+	} else {
+		buf.WriteString(`,"unread":false`)
+	}
+	if mj.Spam {
+		buf.WriteString(`,"spam":true`)
+	} else {
+		buf.WriteString(`,"spam":false`)
+	}
+	if mj.Draft {
+		buf.WriteString(`,"draft":true`)
+	} else {
+		buf.WriteString(`,"draft":false`)
+	}
+	if mj.Deleted {
+		buf.WriteString(`,"deleted":true`)
+	} else {
+		buf.WriteString(`,"deleted":false`)
+	}
+	buf.WriteString(`,"recv_time":`)
+
+	{
+
+		obj, err = mj.RecvTime.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(obj)
+
+	}
+	buf.WriteString(`,"m_time":`)
+
+	{
+
+		obj, err = mj.M_time.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(obj)
+
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
 const (
-	ffj_t_ConvoRowStructbase = iota
-	ffj_t_ConvoRowStructno_such_key
+	ffj_t_EmailRowStructbase = iota
+	ffj_t_EmailRowStructno_such_key
+
+	ffj_t_EmailRowStruct_FromEmail
+
+	ffj_t_EmailRowStruct_ToEmails
+
+	ffj_t_EmailRowStruct_RecvEmail
+
+	ffj_t_EmailRowStruct_Subject
+
+	ffj_t_EmailRowStruct_Content
+
+	ffj_t_EmailRowStruct_Attachments
+
+	ffj_t_EmailRowStruct_Starred
+
+	ffj_t_EmailRowStruct_Unread
+
+	ffj_t_EmailRowStruct_Spam
+
+	ffj_t_EmailRowStruct_Draft
 
-	ffj_t_ConvoRowStruct_F_username
+	ffj_t_EmailRowStruct_Deleted
 
-	ffj_t_ConvoRowStruct_M_time
+	ffj_t_EmailRowStruct_RecvTime
 
-	ffj_t_ConvoRowStruct_Content
+	ffj_t_EmailRowStruct_M_time
 )
 
-var ffj_key_ConvoRowStruct_F_username = []byte("f_username")
+var ffj_key_EmailRowStruct_FromEmail = []byte("from_email")
+
+var ffj_key_EmailRowStruct_ToEmails = []byte("to_emails")
+
+var ffj_key_EmailRowStruct_RecvEmail = []byte("recv_email")
+
+var ffj_key_EmailRowStruct_Subject = []byte("subject")
+
+var ffj_key_EmailRowStruct_Content = []byte("content")
+
+var ffj_key_EmailRowStruct_Attachments = []byte("attachments")
+
+var ffj_key_EmailRowStruct_Starred = []byte("starred")
+
+var ffj_key_EmailRowStruct_Unread = []byte("unread")
+
+var ffj_key_EmailRowStruct_Spam = []byte("spam")
+
+var ffj_key_EmailRowStruct_Draft = []byte("draft")
+
+var ffj_key_EmailRowStruct_Deleted = []byte("deleted")
 
-var ffj_key_ConvoRowStruct_M_time = []byte("m_time")
+var ffj_key_EmailRowStruct_RecvTime = []byte("recv_time")
 
-var ffj_key_ConvoRowStruct_Content = []byte("content")
+var ffj_key_EmailRowStruct_M_time = []byte("m_time")
 
-func (uj *ConvoRowStruct) UnmarshalJSON(input []byte) error {
+func (uj *EmailRowStruct) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *ConvoRowStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *EmailRowStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_ConvoRowStructbase
+	currentKey := ffj_t_EmailRowStructbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -5413,57 +5300,185 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_ConvoRowStructno_such_key
+				currentKey = ffj_t_EmailRowStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
 				switch kn[0] {
 
+				case 'a':
+
+					if bytes.Equal(ffj_key_EmailRowStruct_Attachments, kn) {
+						currentKey = ffj_t_EmailRowStruct_Attachments
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
 				case 'c':
 
-					if bytes.Equal(ffj_key_ConvoRowStruct_Content, kn) {
-						currentKey = ffj_t_ConvoRowStruct_Content
+					if bytes.Equal(ffj_key_EmailRowStruct_Content, kn) {
+						currentKey = ffj_t_EmailRowStruct_Content
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'd':
+
+					if bytes.Equal(ffj_key_EmailRowStruct_Draft, kn) {
+						currentKey = ffj_t_EmailRowStruct_Draft
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffj_key_EmailRowStruct_Deleted, kn) {
+						currentKey = ffj_t_EmailRowStruct_Deleted
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'f':
 
-					if bytes.Equal(ffj_key_ConvoRowStruct_F_username, kn) {
-						currentKey = ffj_t_ConvoRowStruct_F_username
+					if bytes.Equal(ffj_key_EmailRowStruct_FromEmail, kn) {
+						currentKey = ffj_t_EmailRowStruct_FromEmail
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'm':
 
-					if bytes.Equal(ffj_key_ConvoRowStruct_M_time, kn) {
-						currentKey = ffj_t_ConvoRowStruct_M_time
+					if bytes.Equal(ffj_key_EmailRowStruct_M_time, kn) {
+						currentKey = ffj_t_EmailRowStruct_M_time
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'r':
+
+					if bytes.Equal(ffj_key_EmailRowStruct_RecvEmail, kn) {
+						currentKey = ffj_t_EmailRowStruct_RecvEmail
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffj_key_EmailRowStruct_RecvTime, kn) {
+						currentKey = ffj_t_EmailRowStruct_RecvTime
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 's':
+
+					if bytes.Equal(ffj_key_EmailRowStruct_Subject, kn) {
+						currentKey = ffj_t_EmailRowStruct_Subject
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffj_key_EmailRowStruct_Starred, kn) {
+						currentKey = ffj_t_EmailRowStruct_Starred
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffj_key_EmailRowStruct_Spam, kn) {
+						currentKey = ffj_t_EmailRowStruct_Spam
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 't':
+
+					if bytes.Equal(ffj_key_EmailRowStruct_ToEmails, kn) {
+						currentKey = ffj_t_EmailRowStruct_ToEmails
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'u':
+
+					if bytes.Equal(ffj_key_EmailRowStruct_Unread, kn) {
+						currentKey = ffj_t_EmailRowStruct_Unread
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_ConvoRowStruct_Content, kn) {
-					currentKey = ffj_t_ConvoRowStruct_Content
+				if fflib.AsciiEqualFold(ffj_key_EmailRowStruct_M_time, kn) {
+					currentKey = ffj_t_EmailRowStruct_M_time
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.AsciiEqualFold(ffj_key_EmailRowStruct_RecvTime, kn) {
+					currentKey = ffj_t_EmailRowStruct_RecvTime
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffj_key_EmailRowStruct_Deleted, kn) {
+					currentKey = ffj_t_EmailRowStruct_Deleted
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffj_key_EmailRowStruct_Draft, kn) {
+					currentKey = ffj_t_EmailRowStruct_Draft
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffj_key_EmailRowStruct_Spam, kn) {
+					currentKey = ffj_t_EmailRowStruct_Spam
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffj_key_EmailRowStruct_Unread, kn) {
+					currentKey = ffj_t_EmailRowStruct_Unread
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffj_key_EmailRowStruct_Starred, kn) {
+					currentKey = ffj_t_EmailRowStruct_Starred
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffj_key_EmailRowStruct_Attachments, kn) {
+					currentKey = ffj_t_EmailRowStruct_Attachments
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffj_key_EmailRowStruct_Content, kn) {
+					currentKey = ffj_t_EmailRowStruct_Content
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffj_key_EmailRowStruct_Subject, kn) {
+					currentKey = ffj_t_EmailRowStruct_Subject
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.AsciiEqualFold(ffj_key_EmailRowStruct_RecvEmail, kn) {
+					currentKey = ffj_t_EmailRowStruct_RecvEmail
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.AsciiEqualFold(ffj_key_ConvoRowStruct_M_time, kn) {
-					currentKey = ffj_t_ConvoRowStruct_M_time
+				if fflib.EqualFoldRight(ffj_key_EmailRowStruct_ToEmails, kn) {
+					currentKey = ffj_t_EmailRowStruct_ToEmails
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_ConvoRowStruct_F_username, kn) {
-					currentKey = ffj_t_ConvoRowStruct_F_username
+				if fflib.AsciiEqualFold(ffj_key_EmailRowStruct_FromEmail, kn) {
+					currentKey = ffj_t_EmailRowStruct_FromEmail
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_ConvoRowStructno_such_key
+				currentKey = ffj_t_EmailRowStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -5480,18 +5495,48 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_ConvoRowStruct_F_username:
-					goto handle_F_username
+				case ffj_t_EmailRowStruct_FromEmail:
+					goto handle_FromEmail
 
-				case ffj_t_ConvoRowStruct_M_time:
-					goto handle_M_time
+				case ffj_t_EmailRowStruct_ToEmails:
+					goto handle_ToEmails
 
-				case ffj_t_ConvoRowStruct_Content:
-					goto handle_Content
+				case ffj_t_EmailRowStruct_RecvEmail:
+					goto handle_RecvEmail
 
-				case ffj_t_ConvoRowStructno_such_key:
-					err = fs.SkipField(tok)
-					if err != nil {
+				case ffj_t_EmailRowStruct_Subject:
+					goto handle_Subject
+
+				case ffj_t_EmailRowStruct_Content:
+					goto handle_Content
+
+				case ffj_t_EmailRowStruct_Attachments:
+					goto handle_Attachments
+
+				case ffj_t_EmailRowStruct_Starred:
+					goto handle_Starred
+
+				case ffj_t_EmailRowStruct_Unread:
+					goto handle_Unread
+
+				case ffj_t_EmailRowStruct_Spam:
+					goto handle_Spam
+
+				case ffj_t_EmailRowStruct_Draft:
+					goto handle_Draft
+
+				case ffj_t_EmailRowStruct_Deleted:
+					goto handle_Deleted
+
+				case ffj_t_EmailRowStruct_RecvTime:
+					goto handle_RecvTime
+
+				case ffj_t_EmailRowStruct_M_time:
+					goto handle_M_time
+
+				case ffj_t_EmailRowStructno_such_key:
+					err = fs.SkipField(tok)
+					if err != nil {
 						return fs.WrapErr(err)
 					}
 					state = fflib.FFParse_after_value
@@ -5503,9 +5548,9 @@ mainparse:
 		}
 	}
 
-handle_F_username:
+handle_FromEmail:
 
-	/* handler: uj.F_username type=string kind=string quoted=false*/
+	/* handler: uj.FromEmail type=string kind=string quoted=false*/
 
 	{
 
@@ -5521,7 +5566,7 @@ handle_F_username:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.F_username = string(string(outBuf))
+			uj.FromEmail = string(string(outBuf))
 
 		}
 	}
@@ -5529,35 +5574,35 @@ handle_F_username:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_M_time:
+handle_ToEmails:
 
-	/* handler: uj.M_time type=time.Time kind=struct quoted=false*/
+	/* handler: uj.ToEmails type=string kind=string quoted=false*/
 
 	{
-		if tok == fflib.FFTok_null {
 
-			state = fflib.FFParse_after_value
-			goto mainparse
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
 		}
 
-		tbuf, err := fs.CaptureField(tok)
-		if err != nil {
-			return fs.WrapErr(err)
-		}
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			uj.ToEmails = string(string(outBuf))
 
-		err = uj.M_time.UnmarshalJSON(tbuf)
-		if err != nil {
-			return fs.WrapErr(err)
 		}
-		state = fflib.FFParse_after_value
 	}
 
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Content:
+handle_RecvEmail:
 
-	/* handler: uj.Content type=string kind=string quoted=false*/
+	/* handler: uj.RecvEmail type=string kind=string quoted=false*/
 
 	{
 
@@ -5573,7 +5618,7 @@ handle_Content:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Content = string(string(outBuf))
+			uj.RecvEmail = string(string(outBuf))
 
 		}
 	}
@@ -5581,322 +5626,112 @@ handle_Content:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-wantedvalue:
-	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-wrongtokenerror:
-	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
-tokerror:
-	if fs.BigError != nil {
-		return fs.WrapErr(fs.BigError)
-	}
-	err = fs.Error.ToError()
-	if err != nil {
-		return fs.WrapErr(err)
-	}
-	panic("ffjson-generated: unreachable, please report bug.")
-done:
-	return nil
-}
-
-func (mj *CreateUserCmdStruct) MarshalJSON() ([]byte, error) {
-	var buf fflib.Buffer
-	if mj == nil {
-		buf.WriteString("null")
-		return buf.Bytes(), nil
-	}
-	err := mj.MarshalJSONBuf(&buf)
-	if err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
-}
-func (mj *CreateUserCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
-	if mj == nil {
-		buf.WriteString("null")
-		return nil
-	}
-	var err error
-	var obj []byte
-	_ = obj
-	_ = err
-	buf.WriteString(`{"cmd":`)
-	fflib.WriteJsonString(buf, string(mj.Cmd))
-	buf.WriteString(`,"password":`)
-	fflib.WriteJsonString(buf, string(mj.Password))
-	buf.WriteString(`,"email":`)
-	fflib.WriteJsonString(buf, string(mj.Email))
-	buf.WriteString(`,"phone":`)
-	fflib.WriteJsonString(buf, string(mj.Phone))
-	buf.WriteString(`,"token":`)
-	fflib.WriteJsonString(buf, string(mj.Token))
-	buf.WriteString(`,"username":`)
-	fflib.WriteJsonString(buf, string(mj.Username))
-	buf.WriteString(`,"secQuests":`)
-	fflib.WriteJsonString(buf, string(mj.SecQuests))
-	buf.WriteByte('}')
-	return nil
-}
+handle_Subject:
 
-const (
-	ffj_t_CreateUserCmdStructbase = iota
-	ffj_t_CreateUserCmdStructno_such_key
+	/* handler: uj.Subject type=string kind=string quoted=false*/
 
-	ffj_t_CreateUserCmdStruct_Cmd
+	{
 
-	ffj_t_CreateUserCmdStruct_Password
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
 
-	ffj_t_CreateUserCmdStruct_Email
+		if tok == fflib.FFTok_null {
 
-	ffj_t_CreateUserCmdStruct_Phone
+		} else {
 
-	ffj_t_CreateUserCmdStruct_Token
+			outBuf := fs.Output.Bytes()
 
-	ffj_t_CreateUserCmdStruct_Username
+			uj.Subject = string(string(outBuf))
 
-	ffj_t_CreateUserCmdStruct_SecQuests
-)
+		}
+	}
 
-var ffj_key_CreateUserCmdStruct_Cmd = []byte("cmd")
+	state = fflib.FFParse_after_value
+	goto mainparse
 
-var ffj_key_CreateUserCmdStruct_Password = []byte("password")
+handle_Content:
 
-var ffj_key_CreateUserCmdStruct_Email = []byte("email")
+	/* handler: uj.Content type=string kind=string quoted=false*/
 
-var ffj_key_CreateUserCmdStruct_Phone = []byte("phone")
+	{
 
-var ffj_key_CreateUserCmdStruct_Token = []byte("token")
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
 
-var ffj_key_CreateUserCmdStruct_Username = []byte("username")
+		if tok == fflib.FFTok_null {
 
-var ffj_key_CreateUserCmdStruct_SecQuests = []byte("secQuests")
+		} else {
 
-func (uj *CreateUserCmdStruct) UnmarshalJSON(input []byte) error {
-	fs := fflib.NewFFLexer(input)
-	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
-}
+			outBuf := fs.Output.Bytes()
 
-func (uj *CreateUserCmdStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
-	var err error = nil
-	currentKey := ffj_t_CreateUserCmdStructbase
-	_ = currentKey
-	tok := fflib.FFTok_init
-	wantedTok := fflib.FFTok_init
+			uj.Content = string(string(outBuf))
 
-mainparse:
-	for {
-		tok = fs.Scan()
-		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
-		if tok == fflib.FFTok_error {
-			goto tokerror
 		}
+	}
 
-		switch state {
+	state = fflib.FFParse_after_value
+	goto mainparse
 
-		case fflib.FFParse_map_start:
-			if tok != fflib.FFTok_left_bracket {
-				wantedTok = fflib.FFTok_left_bracket
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_key
-			continue
+handle_Attachments:
 
-		case fflib.FFParse_after_value:
-			if tok == fflib.FFTok_comma {
-				state = fflib.FFParse_want_key
-			} else if tok == fflib.FFTok_right_bracket {
-				goto done
-			} else {
-				wantedTok = fflib.FFTok_comma
-				goto wrongtokenerror
-			}
+	/* handler: uj.Attachments type=string kind=string quoted=false*/
 
-		case fflib.FFParse_want_key:
-			// json {} ended. goto exit. woo.
-			if tok == fflib.FFTok_right_bracket {
-				goto done
-			}
-			if tok != fflib.FFTok_string {
-				wantedTok = fflib.FFTok_string
-				goto wrongtokenerror
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
 			}
+		}
 
-			kn := fs.Output.Bytes()
-			if len(kn) <= 0 {
-				// "" case. hrm.
-				currentKey = ffj_t_CreateUserCmdStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			} else {
-				switch kn[0] {
+		if tok == fflib.FFTok_null {
 
-				case 'c':
+		} else {
 
-					if bytes.Equal(ffj_key_CreateUserCmdStruct_Cmd, kn) {
-						currentKey = ffj_t_CreateUserCmdStruct_Cmd
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
+			outBuf := fs.Output.Bytes()
 
-				case 'e':
+			uj.Attachments = string(string(outBuf))
 
-					if bytes.Equal(ffj_key_CreateUserCmdStruct_Email, kn) {
-						currentKey = ffj_t_CreateUserCmdStruct_Email
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
+		}
+	}
 
-				case 'p':
+	state = fflib.FFParse_after_value
+	goto mainparse
 
-					if bytes.Equal(ffj_key_CreateUserCmdStruct_Password, kn) {
-						currentKey = ffj_t_CreateUserCmdStruct_Password
-						state = fflib.FFParse_want_colon
-						goto mainparse
+handle_Starred:
 
-					} else if bytes.Equal(ffj_key_CreateUserCmdStruct_Phone, kn) {
-						currentKey = ffj_t_CreateUserCmdStruct_Phone
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 's':
-
-					if bytes.Equal(ffj_key_CreateUserCmdStruct_SecQuests, kn) {
-						currentKey = ffj_t_CreateUserCmdStruct_SecQuests
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 't':
-
-					if bytes.Equal(ffj_key_CreateUserCmdStruct_Token, kn) {
-						currentKey = ffj_t_CreateUserCmdStruct_Token
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'u':
-
-					if bytes.Equal(ffj_key_CreateUserCmdStruct_Username, kn) {
-						currentKey = ffj_t_CreateUserCmdStruct_Username
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				}
-
-				if fflib.EqualFoldRight(ffj_key_CreateUserCmdStruct_SecQuests, kn) {
-					currentKey = ffj_t_CreateUserCmdStruct_SecQuests
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_CreateUserCmdStruct_Username, kn) {
-					currentKey = ffj_t_CreateUserCmdStruct_Username
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_CreateUserCmdStruct_Token, kn) {
-					currentKey = ffj_t_CreateUserCmdStruct_Token
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_CreateUserCmdStruct_Phone, kn) {
-					currentKey = ffj_t_CreateUserCmdStruct_Phone
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_CreateUserCmdStruct_Email, kn) {
-					currentKey = ffj_t_CreateUserCmdStruct_Email
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_CreateUserCmdStruct_Password, kn) {
-					currentKey = ffj_t_CreateUserCmdStruct_Password
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_CreateUserCmdStruct_Cmd, kn) {
-					currentKey = ffj_t_CreateUserCmdStruct_Cmd
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				currentKey = ffj_t_CreateUserCmdStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			}
-
-		case fflib.FFParse_want_colon:
-			if tok != fflib.FFTok_colon {
-				wantedTok = fflib.FFTok_colon
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_value
-			continue
-		case fflib.FFParse_want_value:
-
-			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
-				switch currentKey {
-
-				case ffj_t_CreateUserCmdStruct_Cmd:
-					goto handle_Cmd
-
-				case ffj_t_CreateUserCmdStruct_Password:
-					goto handle_Password
-
-				case ffj_t_CreateUserCmdStruct_Email:
-					goto handle_Email
-
-				case ffj_t_CreateUserCmdStruct_Phone:
-					goto handle_Phone
-
-				case ffj_t_CreateUserCmdStruct_Token:
-					goto handle_Token
-
-				case ffj_t_CreateUserCmdStruct_Username:
-					goto handle_Username
-
-				case ffj_t_CreateUserCmdStruct_SecQuests:
-					goto handle_SecQuests
+	/* handler: uj.Starred type=bool kind=bool quoted=false*/
 
-				case ffj_t_CreateUserCmdStructno_such_key:
-					err = fs.SkipField(tok)
-					if err != nil {
-						return fs.WrapErr(err)
-					}
-					state = fflib.FFParse_after_value
-					goto mainparse
-				}
-			} else {
-				goto wantedvalue
-			}
+	{
+		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
 		}
 	}
 
-handle_Cmd:
-
-	/* handler: uj.Cmd type=string kind=string quoted=false*/
-
 	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
 		if tok == fflib.FFTok_null {
 
 		} else {
+			tmpb := fs.Output.Bytes()
 
-			outBuf := fs.Output.Bytes()
+			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
 
-			uj.Cmd = string(string(outBuf))
+				uj.Starred = true
+
+			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
+
+				uj.Starred = false
+
+			} else {
+				err = errors.New("unexpected bytes for true/false value")
+				return fs.WrapErr(err)
+			}
 
 		}
 	}
@@ -5904,25 +5739,34 @@ handle_Cmd:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Password:
+handle_Unread:
 
-	/* handler: uj.Password type=string kind=string quoted=false*/
+	/* handler: uj.Unread type=bool kind=bool quoted=false*/
 
 	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
+		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
 		}
+	}
 
+	{
 		if tok == fflib.FFTok_null {
 
 		} else {
+			tmpb := fs.Output.Bytes()
 
-			outBuf := fs.Output.Bytes()
+			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
 
-			uj.Password = string(string(outBuf))
+				uj.Unread = true
+
+			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
+
+				uj.Unread = false
+
+			} else {
+				err = errors.New("unexpected bytes for true/false value")
+				return fs.WrapErr(err)
+			}
 
 		}
 	}
@@ -5930,25 +5774,34 @@ handle_Password:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Email:
+handle_Spam:
 
-	/* handler: uj.Email type=string kind=string quoted=false*/
+	/* handler: uj.Spam type=bool kind=bool quoted=false*/
 
 	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
+		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
 		}
+	}
 
+	{
 		if tok == fflib.FFTok_null {
 
 		} else {
+			tmpb := fs.Output.Bytes()
 
-			outBuf := fs.Output.Bytes()
+			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
 
-			uj.Email = string(string(outBuf))
+				uj.Spam = true
+
+			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
+
+				uj.Spam = false
+
+			} else {
+				err = errors.New("unexpected bytes for true/false value")
+				return fs.WrapErr(err)
+			}
 
 		}
 	}
@@ -5956,25 +5809,34 @@ handle_Email:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Phone:
+handle_Draft:
 
-	/* handler: uj.Phone type=string kind=string quoted=false*/
+	/* handler: uj.Draft type=bool kind=bool quoted=false*/
 
 	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
+		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
 		}
+	}
 
+	{
 		if tok == fflib.FFTok_null {
 
 		} else {
+			tmpb := fs.Output.Bytes()
 
-			outBuf := fs.Output.Bytes()
+			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
 
-			uj.Phone = string(string(outBuf))
+				uj.Draft = true
+
+			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
+
+				uj.Draft = false
+
+			} else {
+				err = errors.New("unexpected bytes for true/false value")
+				return fs.WrapErr(err)
+			}
 
 		}
 	}
@@ -5982,2703 +5844,88 @@ handle_Phone:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Token:
+handle_Deleted:
 
-	/* handler: uj.Token type=string kind=string quoted=false*/
+	/* handler: uj.Deleted type=bool kind=bool quoted=false*/
 
 	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
+		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
 		}
+	}
 
+	{
 		if tok == fflib.FFTok_null {
 
 		} else {
+			tmpb := fs.Output.Bytes()
 
-			outBuf := fs.Output.Bytes()
-
-			uj.Token = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
+			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
+
+				uj.Deleted = true
+
+			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
+
+				uj.Deleted = false
 
-handle_Username:
-
-	/* handler: uj.Username type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Username = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_SecQuests:
-
-	/* handler: uj.SecQuests type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.SecQuests = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-wantedvalue:
-	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-wrongtokenerror:
-	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
-tokerror:
-	if fs.BigError != nil {
-		return fs.WrapErr(fs.BigError)
-	}
-	err = fs.Error.ToError()
-	if err != nil {
-		return fs.WrapErr(err)
-	}
-	panic("ffjson-generated: unreachable, please report bug.")
-done:
-	return nil
-}
-
-func (mj *EmailDataStruct) MarshalJSON() ([]byte, error) {
-	var buf fflib.Buffer
-	if mj == nil {
-		buf.WriteString("null")
-		return buf.Bytes(), nil
-	}
-	err := mj.MarshalJSONBuf(&buf)
-	if err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
-}
-func (mj *EmailDataStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
-	if mj == nil {
-		buf.WriteString("null")
-		return nil
-	}
-	var err error
-	var obj []byte
-	_ = obj
-	_ = err
-	buf.WriteString(`{ `)
-	if len(mj.Cmd) != 0 {
-		buf.WriteString(`"cmd":`)
-		fflib.WriteJsonString(buf, string(mj.Cmd))
-		buf.WriteByte(',')
-	}
-	if len(mj.Emails) != 0 {
-		buf.WriteString(`"Messages":`)
-		if mj.Emails != nil {
-			buf.WriteString(`[`)
-			for i, v := range mj.Emails {
-				if i != 0 {
-					buf.WriteString(`,`)
-				}
-
-				{
-
-					err = v.MarshalJSONBuf(buf)
-					if err != nil {
-						return err
-					}
-
-				}
-			}
-			buf.WriteString(`]`)
-		} else {
-			buf.WriteString(`null`)
-		}
-		buf.WriteByte(',')
-	}
-	buf.Rewind(1)
-	buf.WriteByte('}')
-	return nil
-}
-
-const (
-	ffj_t_EmailDataStructbase = iota
-	ffj_t_EmailDataStructno_such_key
-
-	ffj_t_EmailDataStruct_Cmd
-
-	ffj_t_EmailDataStruct_Emails
-)
-
-var ffj_key_EmailDataStruct_Cmd = []byte("cmd")
-
-var ffj_key_EmailDataStruct_Emails = []byte("Messages")
-
-func (uj *EmailDataStruct) UnmarshalJSON(input []byte) error {
-	fs := fflib.NewFFLexer(input)
-	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
-}
-
-func (uj *EmailDataStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
-	var err error = nil
-	currentKey := ffj_t_EmailDataStructbase
-	_ = currentKey
-	tok := fflib.FFTok_init
-	wantedTok := fflib.FFTok_init
-
-mainparse:
-	for {
-		tok = fs.Scan()
-		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
-		if tok == fflib.FFTok_error {
-			goto tokerror
-		}
-
-		switch state {
-
-		case fflib.FFParse_map_start:
-			if tok != fflib.FFTok_left_bracket {
-				wantedTok = fflib.FFTok_left_bracket
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_key
-			continue
-
-		case fflib.FFParse_after_value:
-			if tok == fflib.FFTok_comma {
-				state = fflib.FFParse_want_key
-			} else if tok == fflib.FFTok_right_bracket {
-				goto done
-			} else {
-				wantedTok = fflib.FFTok_comma
-				goto wrongtokenerror
-			}
-
-		case fflib.FFParse_want_key:
-			// json {} ended. goto exit. woo.
-			if tok == fflib.FFTok_right_bracket {
-				goto done
-			}
-			if tok != fflib.FFTok_string {
-				wantedTok = fflib.FFTok_string
-				goto wrongtokenerror
-			}
-
-			kn := fs.Output.Bytes()
-			if len(kn) <= 0 {
-				// "" case. hrm.
-				currentKey = ffj_t_EmailDataStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			} else {
-				switch kn[0] {
-
-				case 'M':
-
-					if bytes.Equal(ffj_key_EmailDataStruct_Emails, kn) {
-						currentKey = ffj_t_EmailDataStruct_Emails
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'c':
-
-					if bytes.Equal(ffj_key_EmailDataStruct_Cmd, kn) {
-						currentKey = ffj_t_EmailDataStruct_Cmd
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				}
-
-				if fflib.EqualFoldRight(ffj_key_EmailDataStruct_Emails, kn) {
-					currentKey = ffj_t_EmailDataStruct_Emails
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_EmailDataStruct_Cmd, kn) {
-					currentKey = ffj_t_EmailDataStruct_Cmd
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				currentKey = ffj_t_EmailDataStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			}
-
-		case fflib.FFParse_want_colon:
-			if tok != fflib.FFTok_colon {
-				wantedTok = fflib.FFTok_colon
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_value
-			continue
-		case fflib.FFParse_want_value:
-
-			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
-				switch currentKey {
-
-				case ffj_t_EmailDataStruct_Cmd:
-					goto handle_Cmd
-
-				case ffj_t_EmailDataStruct_Emails:
-					goto handle_Emails
-
-				case ffj_t_EmailDataStructno_such_key:
-					err = fs.SkipField(tok)
-					if err != nil {
-						return fs.WrapErr(err)
-					}
-					state = fflib.FFParse_after_value
-					goto mainparse
-				}
-			} else {
-				goto wantedvalue
-			}
-		}
-	}
-
-handle_Cmd:
-
-	/* handler: uj.Cmd type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Cmd = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Emails:
-
-	/* handler: uj.Emails type=[]pcDatabase.EmailRowStruct kind=slice quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-			uj.Emails = nil
-		} else {
-
-			uj.Emails = make([]EmailRowStruct, 0)
-
-			wantVal := true
-
-			for {
-
-				var v EmailRowStruct
-
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
-				}
-				if tok == fflib.FFTok_right_brace {
-					break
-				}
-
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
-				}
-
-				/* handler: v type=pcDatabase.EmailRowStruct kind=struct quoted=false*/
-
-				{
-					if tok == fflib.FFTok_null {
-
-						state = fflib.FFParse_after_value
-						goto mainparse
-					}
-
-					err = v.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
-					if err != nil {
-						return err
-					}
-					state = fflib.FFParse_after_value
-				}
-
-				uj.Emails = append(uj.Emails, v)
-				wantVal = false
-			}
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-wantedvalue:
-	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-wrongtokenerror:
-	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
-tokerror:
-	if fs.BigError != nil {
-		return fs.WrapErr(fs.BigError)
-	}
-	err = fs.Error.ToError()
-	if err != nil {
-		return fs.WrapErr(err)
-	}
-	panic("ffjson-generated: unreachable, please report bug.")
-done:
-	return nil
-}
-
-func (mj *EmailRowStruct) MarshalJSON() ([]byte, error) {
-	var buf fflib.Buffer
-	if mj == nil {
-		buf.WriteString("null")
-		return buf.Bytes(), nil
-	}
-	err := mj.MarshalJSONBuf(&buf)
-	if err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
-}
-func (mj *EmailRowStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
-	if mj == nil {
-		buf.WriteString("null")
-		return nil
-	}
-	var err error
-	var obj []byte
-	_ = obj
-	_ = err
-	buf.WriteString(`{"from_email":`)
-	fflib.WriteJsonString(buf, string(mj.FromEmail))
-	buf.WriteString(`,"to_emails":`)
-	fflib.WriteJsonString(buf, string(mj.ToEmails))
-	buf.WriteString(`,"recv_email":`)
-	fflib.WriteJsonString(buf, string(mj.RecvEmail))
-	buf.WriteString(`,"subject":`)
-	fflib.WriteJsonString(buf, string(mj.Subject))
-	buf.WriteString(`,"content":`)
-	fflib.WriteJsonString(buf, string(mj.Content))
-	buf.WriteString(`,"attachments":`)
-	fflib.WriteJsonString(buf, string(mj.Attachments))
-	if mj.Starred {
-		buf.WriteString(`,"starred":true`)
-	} else {
-		buf.WriteString(`,"starred":false`)
-	}
-	if mj.Unread {
-		buf.WriteString(`,"unread":true`)
-	} else {
-		buf.WriteString(`,"unread":false`)
-	}
-	if mj.Spam {
-		buf.WriteString(`,"spam":true`)
-	} else {
-		buf.WriteString(`,"spam":false`)
-	}
-	if mj.Draft {
-		buf.WriteString(`,"draft":true`)
-	} else {
-		buf.WriteString(`,"draft":false`)
-	}
-	if mj.Deleted {
-		buf.WriteString(`,"deleted":true`)
-	} else {
-		buf.WriteString(`,"deleted":false`)
-	}
-	buf.WriteString(`,"recv_time":`)
-
-	{
-
-		obj, err = mj.RecvTime.MarshalJSON()
-		if err != nil {
-			return err
-		}
-		buf.Write(obj)
-
-	}
-	buf.WriteString(`,"m_time":`)
-
-	{
-
-		obj, err = mj.M_time.MarshalJSON()
-		if err != nil {
-			return err
-		}
-		buf.Write(obj)
-
-	}
-	buf.WriteByte('}')
-	return nil
-}
-
-const (
-	ffj_t_EmailRowStructbase = iota
-	ffj_t_EmailRowStructno_such_key
-
-	ffj_t_EmailRowStruct_FromEmail
-
-	ffj_t_EmailRowStruct_ToEmails
-
-	ffj_t_EmailRowStruct_RecvEmail
-
-	ffj_t_EmailRowStruct_Subject
-
-	ffj_t_EmailRowStruct_Content
-
-	ffj_t_EmailRowStruct_Attachments
-
-	ffj_t_EmailRowStruct_Starred
-
-	ffj_t_EmailRowStruct_Unread
-
-	ffj_t_EmailRowStruct_Spam
-
-	ffj_t_EmailRowStruct_Draft
-
-	ffj_t_EmailRowStruct_Deleted
-
-	ffj_t_EmailRowStruct_RecvTime
-
-	ffj_t_EmailRowStruct_M_time
-)
-
-var ffj_key_EmailRowStruct_FromEmail = []byte("from_email")
-
-var ffj_key_EmailRowStruct_ToEmails = []byte("to_emails")
-
-var ffj_key_EmailRowStruct_RecvEmail = []byte("recv_email")
-
-var ffj_key_EmailRowStruct_Subject = []byte("subject")
-
-var ffj_key_EmailRowStruct_Content = []byte("content")
-
-var ffj_key_EmailRowStruct_Attachments = []byte("attachments")
-
-var ffj_key_EmailRowStruct_Starred = []byte("starred")
-
-var ffj_key_EmailRowStruct_Unread = []byte("unread")
-
-var ffj_key_EmailRowStruct_Spam = []byte("spam")
-
-var ffj_key_EmailRowStruct_Draft = []byte("draft")
-
-var ffj_key_EmailRowStruct_Deleted = []byte("deleted")
-
-var ffj_key_EmailRowStruct_RecvTime = []byte("recv_time")
-
-var ffj_key_EmailRowStruct_M_time = []byte("m_time")
-
-func (uj *EmailRowStruct) UnmarshalJSON(input []byte) error {
-	fs := fflib.NewFFLexer(input)
-	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
-}
-
-func (uj *EmailRowStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
-	var err error = nil
-	currentKey := ffj_t_EmailRowStructbase
-	_ = currentKey
-	tok := fflib.FFTok_init
-	wantedTok := fflib.FFTok_init
-
-mainparse:
-	for {
-		tok = fs.Scan()
-		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
-		if tok == fflib.FFTok_error {
-			goto tokerror
-		}
-
-		switch state {
-
-		case fflib.FFParse_map_start:
-			if tok != fflib.FFTok_left_bracket {
-				wantedTok = fflib.FFTok_left_bracket
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_key
-			continue
-
-		case fflib.FFParse_after_value:
-			if tok == fflib.FFTok_comma {
-				state = fflib.FFParse_want_key
-			} else if tok == fflib.FFTok_right_bracket {
-				goto done
-			} else {
-				wantedTok = fflib.FFTok_comma
-				goto wrongtokenerror
-			}
-
-		case fflib.FFParse_want_key:
-			// json {} ended. goto exit. woo.
-			if tok == fflib.FFTok_right_bracket {
-				goto done
-			}
-			if tok != fflib.FFTok_string {
-				wantedTok = fflib.FFTok_string
-				goto wrongtokenerror
-			}
-
-			kn := fs.Output.Bytes()
-			if len(kn) <= 0 {
-				// "" case. hrm.
-				currentKey = ffj_t_EmailRowStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			} else {
-				switch kn[0] {
-
-				case 'a':
-
-					if bytes.Equal(ffj_key_EmailRowStruct_Attachments, kn) {
-						currentKey = ffj_t_EmailRowStruct_Attachments
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'c':
-
-					if bytes.Equal(ffj_key_EmailRowStruct_Content, kn) {
-						currentKey = ffj_t_EmailRowStruct_Content
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'd':
-
-					if bytes.Equal(ffj_key_EmailRowStruct_Draft, kn) {
-						currentKey = ffj_t_EmailRowStruct_Draft
-						state = fflib.FFParse_want_colon
-						goto mainparse
-
-					} else if bytes.Equal(ffj_key_EmailRowStruct_Deleted, kn) {
-						currentKey = ffj_t_EmailRowStruct_Deleted
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'f':
-
-					if bytes.Equal(ffj_key_EmailRowStruct_FromEmail, kn) {
-						currentKey = ffj_t_EmailRowStruct_FromEmail
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'm':
-
-					if bytes.Equal(ffj_key_EmailRowStruct_M_time, kn) {
-						currentKey = ffj_t_EmailRowStruct_M_time
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'r':
-
-					if bytes.Equal(ffj_key_EmailRowStruct_RecvEmail, kn) {
-						currentKey = ffj_t_EmailRowStruct_RecvEmail
-						state = fflib.FFParse_want_colon
-						goto mainparse
-
-					} else if bytes.Equal(ffj_key_EmailRowStruct_RecvTime, kn) {
-						currentKey = ffj_t_EmailRowStruct_RecvTime
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 's':
-
-					if bytes.Equal(ffj_key_EmailRowStruct_Subject, kn) {
-						currentKey = ffj_t_EmailRowStruct_Subject
-						state = fflib.FFParse_want_colon
-						goto mainparse
-
-					} else if bytes.Equal(ffj_key_EmailRowStruct_Starred, kn) {
-						currentKey = ffj_t_EmailRowStruct_Starred
-						state = fflib.FFParse_want_colon
-						goto mainparse
-
-					} else if bytes.Equal(ffj_key_EmailRowStruct_Spam, kn) {
-						currentKey = ffj_t_EmailRowStruct_Spam
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 't':
-
-					if bytes.Equal(ffj_key_EmailRowStruct_ToEmails, kn) {
-						currentKey = ffj_t_EmailRowStruct_ToEmails
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'u':
-
-					if bytes.Equal(ffj_key_EmailRowStruct_Unread, kn) {
-						currentKey = ffj_t_EmailRowStruct_Unread
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				}
-
-				if fflib.AsciiEqualFold(ffj_key_EmailRowStruct_M_time, kn) {
-					currentKey = ffj_t_EmailRowStruct_M_time
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.AsciiEqualFold(ffj_key_EmailRowStruct_RecvTime, kn) {
-					currentKey = ffj_t_EmailRowStruct_RecvTime
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_EmailRowStruct_Deleted, kn) {
-					currentKey = ffj_t_EmailRowStruct_Deleted
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_EmailRowStruct_Draft, kn) {
-					currentKey = ffj_t_EmailRowStruct_Draft
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_EmailRowStruct_Spam, kn) {
-					currentKey = ffj_t_EmailRowStruct_Spam
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_EmailRowStruct_Unread, kn) {
-					currentKey = ffj_t_EmailRowStruct_Unread
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_EmailRowStruct_Starred, kn) {
-					currentKey = ffj_t_EmailRowStruct_Starred
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_EmailRowStruct_Attachments, kn) {
-					currentKey = ffj_t_EmailRowStruct_Attachments
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_EmailRowStruct_Content, kn) {
-					currentKey = ffj_t_EmailRowStruct_Content
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_EmailRowStruct_Subject, kn) {
-					currentKey = ffj_t_EmailRowStruct_Subject
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.AsciiEqualFold(ffj_key_EmailRowStruct_RecvEmail, kn) {
-					currentKey = ffj_t_EmailRowStruct_RecvEmail
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_EmailRowStruct_ToEmails, kn) {
-					currentKey = ffj_t_EmailRowStruct_ToEmails
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.AsciiEqualFold(ffj_key_EmailRowStruct_FromEmail, kn) {
-					currentKey = ffj_t_EmailRowStruct_FromEmail
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				currentKey = ffj_t_EmailRowStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			}
-
-		case fflib.FFParse_want_colon:
-			if tok != fflib.FFTok_colon {
-				wantedTok = fflib.FFTok_colon
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_value
-			continue
-		case fflib.FFParse_want_value:
-
-			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
-				switch currentKey {
-
-				case ffj_t_EmailRowStruct_FromEmail:
-					goto handle_FromEmail
-
-				case ffj_t_EmailRowStruct_ToEmails:
-					goto handle_ToEmails
-
-				case ffj_t_EmailRowStruct_RecvEmail:
-					goto handle_RecvEmail
-
-				case ffj_t_EmailRowStruct_Subject:
-					goto handle_Subject
-
-				case ffj_t_EmailRowStruct_Content:
-					goto handle_Content
-
-				case ffj_t_EmailRowStruct_Attachments:
-					goto handle_Attachments
-
-				case ffj_t_EmailRowStruct_Starred:
-					goto handle_Starred
-
-				case ffj_t_EmailRowStruct_Unread:
-					goto handle_Unread
-
-				case ffj_t_EmailRowStruct_Spam:
-					goto handle_Spam
-
-				case ffj_t_EmailRowStruct_Draft:
-					goto handle_Draft
-
-				case ffj_t_EmailRowStruct_Deleted:
-					goto handle_Deleted
-
-				case ffj_t_EmailRowStruct_RecvTime:
-					goto handle_RecvTime
-
-				case ffj_t_EmailRowStruct_M_time:
-					goto handle_M_time
-
-				case ffj_t_EmailRowStructno_such_key:
-					err = fs.SkipField(tok)
-					if err != nil {
-						return fs.WrapErr(err)
-					}
-					state = fflib.FFParse_after_value
-					goto mainparse
-				}
-			} else {
-				goto wantedvalue
-			}
-		}
-	}
-
-handle_FromEmail:
-
-	/* handler: uj.FromEmail type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.FromEmail = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_ToEmails:
-
-	/* handler: uj.ToEmails type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.ToEmails = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_RecvEmail:
-
-	/* handler: uj.RecvEmail type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.RecvEmail = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Subject:
-
-	/* handler: uj.Subject type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Subject = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Content:
-
-	/* handler: uj.Content type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Content = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Attachments:
-
-	/* handler: uj.Attachments type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Attachments = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Starred:
-
-	/* handler: uj.Starred type=bool kind=bool quoted=false*/
-
-	{
-		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
-		}
-	}
-
-	{
-		if tok == fflib.FFTok_null {
-
-		} else {
-			tmpb := fs.Output.Bytes()
-
-			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
-
-				uj.Starred = true
-
-			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
-
-				uj.Starred = false
-
-			} else {
-				err = errors.New("unexpected bytes for true/false value")
-				return fs.WrapErr(err)
-			}
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Unread:
-
-	/* handler: uj.Unread type=bool kind=bool quoted=false*/
-
-	{
-		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
-		}
-	}
-
-	{
-		if tok == fflib.FFTok_null {
-
-		} else {
-			tmpb := fs.Output.Bytes()
-
-			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
-
-				uj.Unread = true
-
-			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
-
-				uj.Unread = false
-
-			} else {
-				err = errors.New("unexpected bytes for true/false value")
-				return fs.WrapErr(err)
-			}
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Spam:
-
-	/* handler: uj.Spam type=bool kind=bool quoted=false*/
-
-	{
-		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
-		}
-	}
-
-	{
-		if tok == fflib.FFTok_null {
-
-		} else {
-			tmpb := fs.Output.Bytes()
-
-			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
-
-				uj.Spam = true
-
-			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
-
-				uj.Spam = false
-
-			} else {
-				err = errors.New("unexpected bytes for true/false value")
-				return fs.WrapErr(err)
-			}
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Draft:
-
-	/* handler: uj.Draft type=bool kind=bool quoted=false*/
-
-	{
-		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
-		}
-	}
-
-	{
-		if tok == fflib.FFTok_null {
-
-		} else {
-			tmpb := fs.Output.Bytes()
-
-			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
-
-				uj.Draft = true
-
-			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
-
-				uj.Draft = false
-
-			} else {
-				err = errors.New("unexpected bytes for true/false value")
-				return fs.WrapErr(err)
-			}
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Deleted:
-
-	/* handler: uj.Deleted type=bool kind=bool quoted=false*/
-
-	{
-		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
-		}
-	}
-
-	{
-		if tok == fflib.FFTok_null {
-
-		} else {
-			tmpb := fs.Output.Bytes()
-
-			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
-
-				uj.Deleted = true
-
-			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
-
-				uj.Deleted = false
-
-			} else {
-				err = errors.New("unexpected bytes for true/false value")
-				return fs.WrapErr(err)
-			}
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_RecvTime:
-
-	/* handler: uj.RecvTime type=time.Time kind=struct quoted=false*/
-
-	{
-		if tok == fflib.FFTok_null {
-
-			state = fflib.FFParse_after_value
-			goto mainparse
-		}
-
-		tbuf, err := fs.CaptureField(tok)
-		if err != nil {
-			return fs.WrapErr(err)
-		}
-
-		err = uj.RecvTime.UnmarshalJSON(tbuf)
-		if err != nil {
-			return fs.WrapErr(err)
-		}
-		state = fflib.FFParse_after_value
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_M_time:
-
-	/* handler: uj.M_time type=time.Time kind=struct quoted=false*/
-
-	{
-		if tok == fflib.FFTok_null {
-
-			state = fflib.FFParse_after_value
-			goto mainparse
-		}
-
-		tbuf, err := fs.CaptureField(tok)
-		if err != nil {
-			return fs.WrapErr(err)
-		}
-
-		err = uj.M_time.UnmarshalJSON(tbuf)
-		if err != nil {
-			return fs.WrapErr(err)
-		}
-		state = fflib.FFParse_after_value
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-wantedvalue:
-	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-wrongtokenerror:
-	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
-tokerror:
-	if fs.BigError != nil {
-		return fs.WrapErr(fs.BigError)
-	}
-	err = fs.Error.ToError()
-	if err != nil {
-		return fs.WrapErr(err)
-	}
-	panic("ffjson-generated: unreachable, please report bug.")
-done:
-	return nil
-}
-
-func (mj *FoundUserStruct) MarshalJSON() ([]byte, error) {
-	var buf fflib.Buffer
-	if mj == nil {
-		buf.WriteString("null")
-		return buf.Bytes(), nil
-	}
-	err := mj.MarshalJSONBuf(&buf)
-	if err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
-}
-func (mj *FoundUserStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
-	if mj == nil {
-		buf.WriteString("null")
-		return nil
-	}
-	var err error
-	var obj []byte
-	_ = obj
-	_ = err
-	buf.WriteString(`{"Username":`)
-	fflib.WriteJsonString(buf, string(mj.Username))
-	buf.WriteString(`,"ProfilePic":`)
-	fflib.WriteJsonString(buf, string(mj.ProfilePic))
-	buf.WriteString(`,"Email":`)
-	fflib.WriteJsonString(buf, string(mj.Email))
-	buf.WriteString(`,"Phone":`)
-	fflib.WriteJsonString(buf, string(mj.Phone))
-	buf.WriteByte('}')
-	return nil
-}
-
-const (
-	ffj_t_FoundUserStructbase = iota
-	ffj_t_FoundUserStructno_such_key
-
-	ffj_t_FoundUserStruct_Username
-
-	ffj_t_FoundUserStruct_ProfilePic
-
-	ffj_t_FoundUserStruct_Email
-
-	ffj_t_FoundUserStruct_Phone
-)
-
-var ffj_key_FoundUserStruct_Username = []byte("Username")
-
-var ffj_key_FoundUserStruct_ProfilePic = []byte("ProfilePic")
-
-var ffj_key_FoundUserStruct_Email = []byte("Email")
-
-var ffj_key_FoundUserStruct_Phone = []byte("Phone")
-
-func (uj *FoundUserStruct) UnmarshalJSON(input []byte) error {
-	fs := fflib.NewFFLexer(input)
-	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
-}
-
-func (uj *FoundUserStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
-	var err error = nil
-	currentKey := ffj_t_FoundUserStructbase
-	_ = currentKey
-	tok := fflib.FFTok_init
-	wantedTok := fflib.FFTok_init
-
-mainparse:
-	for {
-		tok = fs.Scan()
-		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
-		if tok == fflib.FFTok_error {
-			goto tokerror
-		}
-
-		switch state {
-
-		case fflib.FFParse_map_start:
-			if tok != fflib.FFTok_left_bracket {
-				wantedTok = fflib.FFTok_left_bracket
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_key
-			continue
-
-		case fflib.FFParse_after_value:
-			if tok == fflib.FFTok_comma {
-				state = fflib.FFParse_want_key
-			} else if tok == fflib.FFTok_right_bracket {
-				goto done
-			} else {
-				wantedTok = fflib.FFTok_comma
-				goto wrongtokenerror
-			}
-
-		case fflib.FFParse_want_key:
-			// json {} ended. goto exit. woo.
-			if tok == fflib.FFTok_right_bracket {
-				goto done
-			}
-			if tok != fflib.FFTok_string {
-				wantedTok = fflib.FFTok_string
-				goto wrongtokenerror
-			}
-
-			kn := fs.Output.Bytes()
-			if len(kn) <= 0 {
-				// "" case. hrm.
-				currentKey = ffj_t_FoundUserStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			} else {
-				switch kn[0] {
-
-				case 'E':
-
-					if bytes.Equal(ffj_key_FoundUserStruct_Email, kn) {
-						currentKey = ffj_t_FoundUserStruct_Email
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'P':
-
-					if bytes.Equal(ffj_key_FoundUserStruct_ProfilePic, kn) {
-						currentKey = ffj_t_FoundUserStruct_ProfilePic
-						state = fflib.FFParse_want_colon
-						goto mainparse
-
-					} else if bytes.Equal(ffj_key_FoundUserStruct_Phone, kn) {
-						currentKey = ffj_t_FoundUserStruct_Phone
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'U':
-
-					if bytes.Equal(ffj_key_FoundUserStruct_Username, kn) {
-						currentKey = ffj_t_FoundUserStruct_Username
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_FoundUserStruct_Phone, kn) {
-					currentKey = ffj_t_FoundUserStruct_Phone
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_FoundUserStruct_Email, kn) {
-					currentKey = ffj_t_FoundUserStruct_Email
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_FoundUserStruct_ProfilePic, kn) {
-					currentKey = ffj_t_FoundUserStruct_ProfilePic
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_FoundUserStruct_Username, kn) {
-					currentKey = ffj_t_FoundUserStruct_Username
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				currentKey = ffj_t_FoundUserStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			}
-
-		case fflib.FFParse_want_colon:
-			if tok != fflib.FFTok_colon {
-				wantedTok = fflib.FFTok_colon
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_value
-			continue
-		case fflib.FFParse_want_value:
-
-			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
-				switch currentKey {
-
-				case ffj_t_FoundUserStruct_Username:
-					goto handle_Username
-
-				case ffj_t_FoundUserStruct_ProfilePic:
-					goto handle_ProfilePic
-
-				case ffj_t_FoundUserStruct_Email:
-					goto handle_Email
-
-				case ffj_t_FoundUserStruct_Phone:
-					goto handle_Phone
-
-				case ffj_t_FoundUserStructno_such_key:
-					err = fs.SkipField(tok)
-					if err != nil {
-						return fs.WrapErr(err)
-					}
-					state = fflib.FFParse_after_value
-					goto mainparse
-				}
-			} else {
-				goto wantedvalue
-			}
-		}
-	}
-
-handle_Username:
-
-	/* handler: uj.Username type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Username = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_ProfilePic:
-
-	/* handler: uj.ProfilePic type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.ProfilePic = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Email:
-
-	/* handler: uj.Email type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Email = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Phone:
-
-	/* handler: uj.Phone type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Phone = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-wantedvalue:
-	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-wrongtokenerror:
-	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
-tokerror:
-	if fs.BigError != nil {
-		return fs.WrapErr(fs.BigError)
-	}
-	err = fs.Error.ToError()
-	if err != nil {
-		return fs.WrapErr(err)
-	}
-	panic("ffjson-generated: unreachable, please report bug.")
-done:
-	return nil
-}
-
-func (mj *FriendCmdStruct) MarshalJSON() ([]byte, error) {
-	var buf fflib.Buffer
-	if mj == nil {
-		buf.WriteString("null")
-		return buf.Bytes(), nil
-	}
-	err := mj.MarshalJSONBuf(&buf)
-	if err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
-}
-func (mj *FriendCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
-	if mj == nil {
-		buf.WriteString("null")
-		return nil
-	}
-	var err error
-	var obj []byte
-	_ = obj
-	_ = err
-	buf.WriteString(`{"cmd":`)
-	fflib.WriteJsonString(buf, string(mj.Cmd))
-	buf.WriteString(`,"UID":`)
-	fflib.WriteJsonString(buf, string(mj.UID))
-	buf.WriteString(`,"friend_UID":`)
-	fflib.WriteJsonString(buf, string(mj.FriendUID))
-	buf.WriteString(`,"Message":`)
-	fflib.WriteJsonString(buf, string(mj.Message))
-	buf.WriteByte('}')
-	return nil
-}
-
-const (
-	ffj_t_FriendCmdStructbase = iota
-	ffj_t_FriendCmdStructno_such_key
-
-	ffj_t_FriendCmdStruct_Cmd
-
-	ffj_t_FriendCmdStruct_UID
-
-	ffj_t_FriendCmdStruct_FriendUID
-
-	ffj_t_FriendCmdStruct_Message
-)
-
-var ffj_key_FriendCmdStruct_Cmd = []byte("cmd")
-
-var ffj_key_FriendCmdStruct_UID = []byte("UID")
-
-var ffj_key_FriendCmdStruct_FriendUID = []byte("friend_UID")
-
-var ffj_key_FriendCmdStruct_Message = []byte("Message")
-
-func (uj *FriendCmdStruct) UnmarshalJSON(input []byte) error {
-	fs := fflib.NewFFLexer(input)
-	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
-}
-
-func (uj *FriendCmdStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
-	var err error = nil
-	currentKey := ffj_t_FriendCmdStructbase
-	_ = currentKey
-	tok := fflib.FFTok_init
-	wantedTok := fflib.FFTok_init
-
-mainparse:
-	for {
-		tok = fs.Scan()
-		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
-		if tok == fflib.FFTok_error {
-			goto tokerror
-		}
-
-		switch state {
-
-		case fflib.FFParse_map_start:
-			if tok != fflib.FFTok_left_bracket {
-				wantedTok = fflib.FFTok_left_bracket
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_key
-			continue
-
-		case fflib.FFParse_after_value:
-			if tok == fflib.FFTok_comma {
-				state = fflib.FFParse_want_key
-			} else if tok == fflib.FFTok_right_bracket {
-				goto done
-			} else {
-				wantedTok = fflib.FFTok_comma
-				goto wrongtokenerror
-			}
-
-		case fflib.FFParse_want_key:
-			// json {} ended. goto exit. woo.
-			if tok == fflib.FFTok_right_bracket {
-				goto done
-			}
-			if tok != fflib.FFTok_string {
-				wantedTok = fflib.FFTok_string
-				goto wrongtokenerror
-			}
-
-			kn := fs.Output.Bytes()
-			if len(kn) <= 0 {
-				// "" case. hrm.
-				currentKey = ffj_t_FriendCmdStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			} else {
-				switch kn[0] {
-
-				case 'M':
-
-					if bytes.Equal(ffj_key_FriendCmdStruct_Message, kn) {
-						currentKey = ffj_t_FriendCmdStruct_Message
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'U':
-
-					if bytes.Equal(ffj_key_FriendCmdStruct_UID, kn) {
-						currentKey = ffj_t_FriendCmdStruct_UID
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'c':
-
-					if bytes.Equal(ffj_key_FriendCmdStruct_Cmd, kn) {
-						currentKey = ffj_t_FriendCmdStruct_Cmd
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'f':
-
-					if bytes.Equal(ffj_key_FriendCmdStruct_FriendUID, kn) {
-						currentKey = ffj_t_FriendCmdStruct_FriendUID
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				}
-
-				if fflib.EqualFoldRight(ffj_key_FriendCmdStruct_Message, kn) {
-					currentKey = ffj_t_FriendCmdStruct_Message
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.AsciiEqualFold(ffj_key_FriendCmdStruct_FriendUID, kn) {
-					currentKey = ffj_t_FriendCmdStruct_FriendUID
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_FriendCmdStruct_UID, kn) {
-					currentKey = ffj_t_FriendCmdStruct_UID
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_FriendCmdStruct_Cmd, kn) {
-					currentKey = ffj_t_FriendCmdStruct_Cmd
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				currentKey = ffj_t_FriendCmdStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			}
-
-		case fflib.FFParse_want_colon:
-			if tok != fflib.FFTok_colon {
-				wantedTok = fflib.FFTok_colon
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_value
-			continue
-		case fflib.FFParse_want_value:
-
-			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
-				switch currentKey {
-
-				case ffj_t_FriendCmdStruct_Cmd:
-					goto handle_Cmd
-
-				case ffj_t_FriendCmdStruct_UID:
-					goto handle_UID
-
-				case ffj_t_FriendCmdStruct_FriendUID:
-					goto handle_FriendUID
-
-				case ffj_t_FriendCmdStruct_Message:
-					goto handle_Message
-
-				case ffj_t_FriendCmdStructno_such_key:
-					err = fs.SkipField(tok)
-					if err != nil {
-						return fs.WrapErr(err)
-					}
-					state = fflib.FFParse_after_value
-					goto mainparse
-				}
-			} else {
-				goto wantedvalue
-			}
-		}
-	}
-
-handle_Cmd:
-
-	/* handler: uj.Cmd type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Cmd = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_UID:
-
-	/* handler: uj.UID type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.UID = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_FriendUID:
-
-	/* handler: uj.FriendUID type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.FriendUID = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Message:
-
-	/* handler: uj.Message type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Message = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-wantedvalue:
-	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-wrongtokenerror:
-	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
-tokerror:
-	if fs.BigError != nil {
-		return fs.WrapErr(fs.BigError)
-	}
-	err = fs.Error.ToError()
-	if err != nil {
-		return fs.WrapErr(err)
-	}
-	panic("ffjson-generated: unreachable, please report bug.")
-done:
-	return nil
-}
-
-func (mj *InviteEmailStruct) MarshalJSON() ([]byte, error) {
-	var buf fflib.Buffer
-	if mj == nil {
-		buf.WriteString("null")
-		return buf.Bytes(), nil
-	}
-	err := mj.MarshalJSONBuf(&buf)
-	if err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
-}
-func (mj *InviteEmailStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
-	if mj == nil {
-		buf.WriteString("null")
-		return nil
-	}
-	var err error
-	var obj []byte
-	_ = obj
-	_ = err
-	buf.WriteString(`{"cmd":`)
-	fflib.WriteJsonString(buf, string(mj.Cmd))
-	buf.WriteString(`,"f_username":`)
-	fflib.WriteJsonString(buf, string(mj.FromUsername))
-	buf.WriteString(`,"emails":`)
-	fflib.WriteJsonString(buf, string(mj.Emails))
-	buf.WriteString(`,"phones":`)
-	fflib.WriteJsonString(buf, string(mj.Phones))
-	buf.WriteByte('}')
-	return nil
-}
-
-const (
-	ffj_t_InviteEmailStructbase = iota
-	ffj_t_InviteEmailStructno_such_key
-
-	ffj_t_InviteEmailStruct_Cmd
-
-	ffj_t_InviteEmailStruct_FromUsername
-
-	ffj_t_InviteEmailStruct_Emails
-
-	ffj_t_InviteEmailStruct_Phones
-)
-
-var ffj_key_InviteEmailStruct_Cmd = []byte("cmd")
-
-var ffj_key_InviteEmailStruct_FromUsername = []byte("f_username")
-
-var ffj_key_InviteEmailStruct_Emails = []byte("emails")
-
-var ffj_key_InviteEmailStruct_Phones = []byte("phones")
-
-func (uj *InviteEmailStruct) UnmarshalJSON(input []byte) error {
-	fs := fflib.NewFFLexer(input)
-	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
-}
-
-func (uj *InviteEmailStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
-	var err error = nil
-	currentKey := ffj_t_InviteEmailStructbase
-	_ = currentKey
-	tok := fflib.FFTok_init
-	wantedTok := fflib.FFTok_init
-
-mainparse:
-	for {
-		tok = fs.Scan()
-		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
-		if tok == fflib.FFTok_error {
-			goto tokerror
-		}
-
-		switch state {
-
-		case fflib.FFParse_map_start:
-			if tok != fflib.FFTok_left_bracket {
-				wantedTok = fflib.FFTok_left_bracket
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_key
-			continue
-
-		case fflib.FFParse_after_value:
-			if tok == fflib.FFTok_comma {
-				state = fflib.FFParse_want_key
-			} else if tok == fflib.FFTok_right_bracket {
-				goto done
-			} else {
-				wantedTok = fflib.FFTok_comma
-				goto wrongtokenerror
-			}
-
-		case fflib.FFParse_want_key:
-			// json {} ended. goto exit. woo.
-			if tok == fflib.FFTok_right_bracket {
-				goto done
-			}
-			if tok != fflib.FFTok_string {
-				wantedTok = fflib.FFTok_string
-				goto wrongtokenerror
-			}
-
-			kn := fs.Output.Bytes()
-			if len(kn) <= 0 {
-				// "" case. hrm.
-				currentKey = ffj_t_InviteEmailStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			} else {
-				switch kn[0] {
-
-				case 'c':
-
-					if bytes.Equal(ffj_key_InviteEmailStruct_Cmd, kn) {
-						currentKey = ffj_t_InviteEmailStruct_Cmd
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'e':
-
-					if bytes.Equal(ffj_key_InviteEmailStruct_Emails, kn) {
-						currentKey = ffj_t_InviteEmailStruct_Emails
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'f':
-
-					if bytes.Equal(ffj_key_InviteEmailStruct_FromUsername, kn) {
-						currentKey = ffj_t_InviteEmailStruct_FromUsername
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'p':
-
-					if bytes.Equal(ffj_key_InviteEmailStruct_Phones, kn) {
-						currentKey = ffj_t_InviteEmailStruct_Phones
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				}
-
-				if fflib.EqualFoldRight(ffj_key_InviteEmailStruct_Phones, kn) {
-					currentKey = ffj_t_InviteEmailStruct_Phones
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_InviteEmailStruct_Emails, kn) {
-					currentKey = ffj_t_InviteEmailStruct_Emails
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_InviteEmailStruct_FromUsername, kn) {
-					currentKey = ffj_t_InviteEmailStruct_FromUsername
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_InviteEmailStruct_Cmd, kn) {
-					currentKey = ffj_t_InviteEmailStruct_Cmd
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				currentKey = ffj_t_InviteEmailStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			}
-
-		case fflib.FFParse_want_colon:
-			if tok != fflib.FFTok_colon {
-				wantedTok = fflib.FFTok_colon
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_value
-			continue
-		case fflib.FFParse_want_value:
-
-			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
-				switch currentKey {
-
-				case ffj_t_InviteEmailStruct_Cmd:
-					goto handle_Cmd
-
-				case ffj_t_InviteEmailStruct_FromUsername:
-					goto handle_FromUsername
-
-				case ffj_t_InviteEmailStruct_Emails:
-					goto handle_Emails
-
-				case ffj_t_InviteEmailStruct_Phones:
-					goto handle_Phones
-
-				case ffj_t_InviteEmailStructno_such_key:
-					err = fs.SkipField(tok)
-					if err != nil {
-						return fs.WrapErr(err)
-					}
-					state = fflib.FFParse_after_value
-					goto mainparse
-				}
-			} else {
-				goto wantedvalue
-			}
-		}
-	}
-
-handle_Cmd:
-
-	/* handler: uj.Cmd type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Cmd = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_FromUsername:
-
-	/* handler: uj.FromUsername type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.FromUsername = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Emails:
-
-	/* handler: uj.Emails type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Emails = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Phones:
-
-	/* handler: uj.Phones type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Phones = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-wantedvalue:
-	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-wrongtokenerror:
-	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
-tokerror:
-	if fs.BigError != nil {
-		return fs.WrapErr(fs.BigError)
-	}
-	err = fs.Error.ToError()
-	if err != nil {
-		return fs.WrapErr(err)
-	}
-	panic("ffjson-generated: unreachable, please report bug.")
-done:
-	return nil
-}
-
-func (mj *MatchUsersCmdEmailStruct) MarshalJSON() ([]byte, error) {
-	var buf fflib.Buffer
-	if mj == nil {
-		buf.WriteString("null")
-		return buf.Bytes(), nil
-	}
-	err := mj.MarshalJSONBuf(&buf)
-	if err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
-}
-func (mj *MatchUsersCmdEmailStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
-	if mj == nil {
-		buf.WriteString("null")
-		return nil
-	}
-	var err error
-	var obj []byte
-	_ = obj
-	_ = err
-	buf.WriteString(`{"Name":`)
-	fflib.WriteJsonString(buf, string(mj.Name))
-	buf.WriteString(`,"Email":`)
-	fflib.WriteJsonString(buf, string(mj.Email))
-	buf.WriteByte('}')
-	return nil
-}
-
-const (
-	ffj_t_MatchUsersCmdEmailStructbase = iota
-	ffj_t_MatchUsersCmdEmailStructno_such_key
-
-	ffj_t_MatchUsersCmdEmailStruct_Name
-
-	ffj_t_MatchUsersCmdEmailStruct_Email
-)
-
-var ffj_key_MatchUsersCmdEmailStruct_Name = []byte("Name")
-
-var ffj_key_MatchUsersCmdEmailStruct_Email = []byte("Email")
-
-func (uj *MatchUsersCmdEmailStruct) UnmarshalJSON(input []byte) error {
-	fs := fflib.NewFFLexer(input)
-	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
-}
-
-func (uj *MatchUsersCmdEmailStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
-	var err error = nil
-	currentKey := ffj_t_MatchUsersCmdEmailStructbase
-	_ = currentKey
-	tok := fflib.FFTok_init
-	wantedTok := fflib.FFTok_init
-
-mainparse:
-	for {
-		tok = fs.Scan()
-		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
-		if tok == fflib.FFTok_error {
-			goto tokerror
-		}
-
-		switch state {
-
-		case fflib.FFParse_map_start:
-			if tok != fflib.FFTok_left_bracket {
-				wantedTok = fflib.FFTok_left_bracket
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_key
-			continue
-
-		case fflib.FFParse_after_value:
-			if tok == fflib.FFTok_comma {
-				state = fflib.FFParse_want_key
-			} else if tok == fflib.FFTok_right_bracket {
-				goto done
-			} else {
-				wantedTok = fflib.FFTok_comma
-				goto wrongtokenerror
-			}
-
-		case fflib.FFParse_want_key:
-			// json {} ended. goto exit. woo.
-			if tok == fflib.FFTok_right_bracket {
-				goto done
-			}
-			if tok != fflib.FFTok_string {
-				wantedTok = fflib.FFTok_string
-				goto wrongtokenerror
-			}
-
-			kn := fs.Output.Bytes()
-			if len(kn) <= 0 {
-				// "" case. hrm.
-				currentKey = ffj_t_MatchUsersCmdEmailStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			} else {
-				switch kn[0] {
-
-				case 'E':
-
-					if bytes.Equal(ffj_key_MatchUsersCmdEmailStruct_Email, kn) {
-						currentKey = ffj_t_MatchUsersCmdEmailStruct_Email
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'N':
-
-					if bytes.Equal(ffj_key_MatchUsersCmdEmailStruct_Name, kn) {
-						currentKey = ffj_t_MatchUsersCmdEmailStruct_Name
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersCmdEmailStruct_Email, kn) {
-					currentKey = ffj_t_MatchUsersCmdEmailStruct_Email
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersCmdEmailStruct_Name, kn) {
-					currentKey = ffj_t_MatchUsersCmdEmailStruct_Name
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				currentKey = ffj_t_MatchUsersCmdEmailStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			}
-
-		case fflib.FFParse_want_colon:
-			if tok != fflib.FFTok_colon {
-				wantedTok = fflib.FFTok_colon
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_value
-			continue
-		case fflib.FFParse_want_value:
-
-			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
-				switch currentKey {
-
-				case ffj_t_MatchUsersCmdEmailStruct_Name:
-					goto handle_Name
-
-				case ffj_t_MatchUsersCmdEmailStruct_Email:
-					goto handle_Email
-
-				case ffj_t_MatchUsersCmdEmailStructno_such_key:
-					err = fs.SkipField(tok)
-					if err != nil {
-						return fs.WrapErr(err)
-					}
-					state = fflib.FFParse_after_value
-					goto mainparse
-				}
-			} else {
-				goto wantedvalue
-			}
-		}
-	}
-
-handle_Name:
-
-	/* handler: uj.Name type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Name = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Email:
-
-	/* handler: uj.Email type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Email = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-wantedvalue:
-	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-wrongtokenerror:
-	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
-tokerror:
-	if fs.BigError != nil {
-		return fs.WrapErr(fs.BigError)
-	}
-	err = fs.Error.ToError()
-	if err != nil {
-		return fs.WrapErr(err)
-	}
-	panic("ffjson-generated: unreachable, please report bug.")
-done:
-	return nil
-}
-
-func (mj *MatchUsersCmdPhoneStruct) MarshalJSON() ([]byte, error) {
-	var buf fflib.Buffer
-	if mj == nil {
-		buf.WriteString("null")
-		return buf.Bytes(), nil
-	}
-	err := mj.MarshalJSONBuf(&buf)
-	if err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
-}
-func (mj *MatchUsersCmdPhoneStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
-	if mj == nil {
-		buf.WriteString("null")
-		return nil
-	}
-	var err error
-	var obj []byte
-	_ = obj
-	_ = err
-	buf.WriteString(`{"Name":`)
-	fflib.WriteJsonString(buf, string(mj.Name))
-	buf.WriteString(`,"PhoneNum":`)
-	fflib.WriteJsonString(buf, string(mj.PhoneNum))
-	buf.WriteByte('}')
-	return nil
-}
-
-const (
-	ffj_t_MatchUsersCmdPhoneStructbase = iota
-	ffj_t_MatchUsersCmdPhoneStructno_such_key
-
-	ffj_t_MatchUsersCmdPhoneStruct_Name
-
-	ffj_t_MatchUsersCmdPhoneStruct_PhoneNum
-)
-
-var ffj_key_MatchUsersCmdPhoneStruct_Name = []byte("Name")
-
-var ffj_key_MatchUsersCmdPhoneStruct_PhoneNum = []byte("PhoneNum")
-
-func (uj *MatchUsersCmdPhoneStruct) UnmarshalJSON(input []byte) error {
-	fs := fflib.NewFFLexer(input)
-	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
-}
-
-func (uj *MatchUsersCmdPhoneStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
-	var err error = nil
-	currentKey := ffj_t_MatchUsersCmdPhoneStructbase
-	_ = currentKey
-	tok := fflib.FFTok_init
-	wantedTok := fflib.FFTok_init
-
-mainparse:
-	for {
-		tok = fs.Scan()
-		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
-		if tok == fflib.FFTok_error {
-			goto tokerror
-		}
-
-		switch state {
-
-		case fflib.FFParse_map_start:
-			if tok != fflib.FFTok_left_bracket {
-				wantedTok = fflib.FFTok_left_bracket
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_key
-			continue
-
-		case fflib.FFParse_after_value:
-			if tok == fflib.FFTok_comma {
-				state = fflib.FFParse_want_key
-			} else if tok == fflib.FFTok_right_bracket {
-				goto done
-			} else {
-				wantedTok = fflib.FFTok_comma
-				goto wrongtokenerror
-			}
-
-		case fflib.FFParse_want_key:
-			// json {} ended. goto exit. woo.
-			if tok == fflib.FFTok_right_bracket {
-				goto done
-			}
-			if tok != fflib.FFTok_string {
-				wantedTok = fflib.FFTok_string
-				goto wrongtokenerror
-			}
-
-			kn := fs.Output.Bytes()
-			if len(kn) <= 0 {
-				// "" case. hrm.
-				currentKey = ffj_t_MatchUsersCmdPhoneStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			} else {
-				switch kn[0] {
-
-				case 'N':
-
-					if bytes.Equal(ffj_key_MatchUsersCmdPhoneStruct_Name, kn) {
-						currentKey = ffj_t_MatchUsersCmdPhoneStruct_Name
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'P':
-
-					if bytes.Equal(ffj_key_MatchUsersCmdPhoneStruct_PhoneNum, kn) {
-						currentKey = ffj_t_MatchUsersCmdPhoneStruct_PhoneNum
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersCmdPhoneStruct_PhoneNum, kn) {
-					currentKey = ffj_t_MatchUsersCmdPhoneStruct_PhoneNum
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersCmdPhoneStruct_Name, kn) {
-					currentKey = ffj_t_MatchUsersCmdPhoneStruct_Name
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				currentKey = ffj_t_MatchUsersCmdPhoneStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			}
-
-		case fflib.FFParse_want_colon:
-			if tok != fflib.FFTok_colon {
-				wantedTok = fflib.FFTok_colon
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_value
-			continue
-		case fflib.FFParse_want_value:
-
-			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
-				switch currentKey {
-
-				case ffj_t_MatchUsersCmdPhoneStruct_Name:
-					goto handle_Name
-
-				case ffj_t_MatchUsersCmdPhoneStruct_PhoneNum:
-					goto handle_PhoneNum
-
-				case ffj_t_MatchUsersCmdPhoneStructno_such_key:
-					err = fs.SkipField(tok)
-					if err != nil {
-						return fs.WrapErr(err)
-					}
-					state = fflib.FFParse_after_value
-					goto mainparse
-				}
 			} else {
-				goto wantedvalue
+				err = errors.New("unexpected bytes for true/false value")
+				return fs.WrapErr(err)
 			}
+
 		}
 	}
 
-handle_Name:
-
-	/* handler: uj.Name type=string kind=string quoted=false*/
+	state = fflib.FFParse_after_value
+	goto mainparse
 
-	{
+handle_RecvTime:
 
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
+	/* handler: uj.RecvTime type=time.Time kind=struct quoted=false*/
 
+	{
 		if tok == fflib.FFTok_null {
 
-		} else {
-
-			outBuf := fs.Output.Bytes()
+			state = fflib.FFParse_after_value
+			goto mainparse
+		}
 
-			uj.Name = string(string(outBuf))
+		tbuf, err := fs.CaptureField(tok)
+		if err != nil {
+			return fs.WrapErr(err)
+		}
 
+		err = uj.RecvTime.UnmarshalJSON(tbuf)
+		if err != nil {
+			return fs.WrapErr(err)
 		}
+		state = fflib.FFParse_after_value
 	}
 
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_PhoneNum:
+handle_M_time:
 
-	/* handler: uj.PhoneNum type=string kind=string quoted=false*/
+	/* handler: uj.M_time type=time.Time kind=struct quoted=false*/
 
 	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
 		if tok == fflib.FFTok_null {
 
-		} else {
-
-			outBuf := fs.Output.Bytes()
+			state = fflib.FFParse_after_value
+			goto mainparse
+		}
 
-			uj.PhoneNum = string(string(outBuf))
+		tbuf, err := fs.CaptureField(tok)
+		if err != nil {
+			return fs.WrapErr(err)
+		}
 
+		err = uj.M_time.UnmarshalJSON(tbuf)
+		if err != nil {
+			return fs.WrapErr(err)
 		}
+		state = fflib.FFParse_after_value
 	}
 
 	state = fflib.FFParse_after_value
@@ -8701,7 +5948,7 @@ done:
 	return nil
 }
 
-func (mj *MatchUsersCmdStruct) MarshalJSON() ([]byte, error) {
+func (mj *FoundUserStruct) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -8713,7 +5960,7 @@ func (mj *MatchUsersCmdStruct) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *MatchUsersCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *FoundUserStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
@@ -8722,79 +5969,47 @@ func (mj *MatchUsersCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	var obj []byte
 	_ = obj
 	_ = err
-	buf.WriteString(`{"cmd":`)
-	fflib.WriteJsonString(buf, string(mj.Cmd))
-	buf.WriteString(`,"Phones":`)
-	if mj.Phones != nil {
-		buf.WriteString(`[`)
-		for i, v := range mj.Phones {
-			if i != 0 {
-				buf.WriteString(`,`)
-			}
-
-			{
-
-				err = v.MarshalJSONBuf(buf)
-				if err != nil {
-					return err
-				}
-
-			}
-		}
-		buf.WriteString(`]`)
-	} else {
-		buf.WriteString(`null`)
-	}
-	buf.WriteString(`,"Emails":`)
-	if mj.Emails != nil {
-		buf.WriteString(`[`)
-		for i, v := range mj.Emails {
-			if i != 0 {
-				buf.WriteString(`,`)
-			}
-
-			{
-
-				err = v.MarshalJSONBuf(buf)
-				if err != nil {
-					return err
-				}
-
-			}
-		}
-		buf.WriteString(`]`)
-	} else {
-		buf.WriteString(`null`)
-	}
+	buf.WriteString(`{"Username":`)
+	fflib.WriteJsonString(buf, string(mj.Username))
+	buf.WriteString(`,"ProfilePic":`)
+	fflib.WriteJsonString(buf, string(mj.ProfilePic))
+	buf.WriteString(`,"Email":`)
+	fflib.WriteJsonString(buf, string(mj.Email))
+	buf.WriteString(`,"Phone":`)
+	fflib.WriteJsonString(buf, string(mj.Phone))
 	buf.WriteByte('}')
 	return nil
 }
 
 const (
-	ffj_t_MatchUsersCmdStructbase = iota
-	ffj_t_MatchUsersCmdStructno_such_key
+	ffj_t_FoundUserStructbase = iota
+	ffj_t_FoundUserStructno_such_key
 
-	ffj_t_MatchUsersCmdStruct_Cmd
+	ffj_t_FoundUserStruct_Username
 
-	ffj_t_MatchUsersCmdStruct_Phones
+	ffj_t_FoundUserStruct_ProfilePic
 
-	ffj_t_MatchUsersCmdStruct_Emails
+	ffj_t_FoundUserStruct_Email
+
+	ffj_t_FoundUserStruct_Phone
 )
 
-var ffj_key_MatchUsersCmdStruct_Cmd = []byte("cmd")
+var ffj_key_FoundUserStruct_Username = []byte("Username")
 
-var ffj_key_MatchUsersCmdStruct_Phones = []byte("Phones")
+var ffj_key_FoundUserStruct_ProfilePic = []byte("ProfilePic")
 
-var ffj_key_MatchUsersCmdStruct_Emails = []byte("Emails")
+var ffj_key_FoundUserStruct_Email = []byte("Email")
 
-func (uj *MatchUsersCmdStruct) UnmarshalJSON(input []byte) error {
+var ffj_key_FoundUserStruct_Phone = []byte("Phone")
+
+func (uj *FoundUserStruct) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *MatchUsersCmdStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *FoundUserStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_MatchUsersCmdStructbase
+	currentKey := ffj_t_FoundUserStructbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -8840,7 +6055,7 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_MatchUsersCmdStructno_such_key
+				currentKey = ffj_t_FoundUserStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
@@ -8848,49 +6063,60 @@ mainparse:
 
 				case 'E':
 
-					if bytes.Equal(ffj_key_MatchUsersCmdStruct_Emails, kn) {
-						currentKey = ffj_t_MatchUsersCmdStruct_Emails
+					if bytes.Equal(ffj_key_FoundUserStruct_Email, kn) {
+						currentKey = ffj_t_FoundUserStruct_Email
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'P':
 
-					if bytes.Equal(ffj_key_MatchUsersCmdStruct_Phones, kn) {
-						currentKey = ffj_t_MatchUsersCmdStruct_Phones
+					if bytes.Equal(ffj_key_FoundUserStruct_ProfilePic, kn) {
+						currentKey = ffj_t_FoundUserStruct_ProfilePic
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffj_key_FoundUserStruct_Phone, kn) {
+						currentKey = ffj_t_FoundUserStruct_Phone
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'c':
+				case 'U':
 
-					if bytes.Equal(ffj_key_MatchUsersCmdStruct_Cmd, kn) {
-						currentKey = ffj_t_MatchUsersCmdStruct_Cmd
+					if bytes.Equal(ffj_key_FoundUserStruct_Username, kn) {
+						currentKey = ffj_t_FoundUserStruct_Username
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.EqualFoldRight(ffj_key_MatchUsersCmdStruct_Emails, kn) {
-					currentKey = ffj_t_MatchUsersCmdStruct_Emails
+				if fflib.SimpleLetterEqualFold(ffj_key_FoundUserStruct_Phone, kn) {
+					currentKey = ffj_t_FoundUserStruct_Phone
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_MatchUsersCmdStruct_Phones, kn) {
-					currentKey = ffj_t_MatchUsersCmdStruct_Phones
+				if fflib.SimpleLetterEqualFold(ffj_key_FoundUserStruct_Email, kn) {
+					currentKey = ffj_t_FoundUserStruct_Email
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersCmdStruct_Cmd, kn) {
-					currentKey = ffj_t_MatchUsersCmdStruct_Cmd
+				if fflib.SimpleLetterEqualFold(ffj_key_FoundUserStruct_ProfilePic, kn) {
+					currentKey = ffj_t_FoundUserStruct_ProfilePic
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_MatchUsersCmdStructno_such_key
+				if fflib.EqualFoldRight(ffj_key_FoundUserStruct_Username, kn) {
+					currentKey = ffj_t_FoundUserStruct_Username
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				currentKey = ffj_t_FoundUserStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -8907,16 +6133,19 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_MatchUsersCmdStruct_Cmd:
-					goto handle_Cmd
+				case ffj_t_FoundUserStruct_Username:
+					goto handle_Username
 
-				case ffj_t_MatchUsersCmdStruct_Phones:
-					goto handle_Phones
+				case ffj_t_FoundUserStruct_ProfilePic:
+					goto handle_ProfilePic
 
-				case ffj_t_MatchUsersCmdStruct_Emails:
-					goto handle_Emails
+				case ffj_t_FoundUserStruct_Email:
+					goto handle_Email
 
-				case ffj_t_MatchUsersCmdStructno_such_key:
+				case ffj_t_FoundUserStruct_Phone:
+					goto handle_Phone
+
+				case ffj_t_FoundUserStructno_such_key:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -8930,9 +6159,9 @@ mainparse:
 		}
 	}
 
-handle_Cmd:
+handle_Username:
 
-	/* handler: uj.Cmd type=string kind=string quoted=false*/
+	/* handler: uj.Username type=string kind=string quoted=false*/
 
 	{
 
@@ -8948,7 +6177,7 @@ handle_Cmd:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Cmd = string(string(outBuf))
+			uj.Username = string(string(outBuf))
 
 		}
 	}
@@ -8956,136 +6185,78 @@ handle_Cmd:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Phones:
+handle_ProfilePic:
 
-	/* handler: uj.Phones type=[]pcDatabase.MatchUsersCmdPhoneStruct kind=slice quoted=false*/
+	/* handler: uj.ProfilePic type=string kind=string quoted=false*/
 
 	{
 
 		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
 			}
 		}
 
 		if tok == fflib.FFTok_null {
-			uj.Phones = nil
-		} else {
-
-			uj.Phones = make([]MatchUsersCmdPhoneStruct, 0)
-
-			wantVal := true
-
-			for {
-
-				var v MatchUsersCmdPhoneStruct
-
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
-				}
-				if tok == fflib.FFTok_right_brace {
-					break
-				}
-
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
-				}
 
-				/* handler: v type=pcDatabase.MatchUsersCmdPhoneStruct kind=struct quoted=false*/
-
-				{
-					if tok == fflib.FFTok_null {
-
-						state = fflib.FFParse_after_value
-						goto mainparse
-					}
+		} else {
 
-					err = v.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
-					if err != nil {
-						return err
-					}
-					state = fflib.FFParse_after_value
-				}
+			outBuf := fs.Output.Bytes()
+
+			uj.ProfilePic = string(string(outBuf))
 
-				uj.Phones = append(uj.Phones, v)
-				wantVal = false
-			}
 		}
 	}
 
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Emails:
+handle_Email:
 
-	/* handler: uj.Emails type=[]pcDatabase.MatchUsersCmdEmailStruct kind=slice quoted=false*/
+	/* handler: uj.Email type=string kind=string quoted=false*/
 
 	{
 
 		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
 			}
 		}
 
 		if tok == fflib.FFTok_null {
-			uj.Emails = nil
+
 		} else {
 
-			uj.Emails = make([]MatchUsersCmdEmailStruct, 0)
+			outBuf := fs.Output.Bytes()
 
-			wantVal := true
+			uj.Email = string(string(outBuf))
 
-			for {
+		}
+	}
 
-				var v MatchUsersCmdEmailStruct
+	state = fflib.FFParse_after_value
+	goto mainparse
 
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
-				}
-				if tok == fflib.FFTok_right_brace {
-					break
-				}
+handle_Phone:
 
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
-				}
+	/* handler: uj.Phone type=string kind=string quoted=false*/
 
-				/* handler: v type=pcDatabase.MatchUsersCmdEmailStruct kind=struct quoted=false*/
+	{
 
-				{
-					if tok == fflib.FFTok_null {
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
 
-						state = fflib.FFParse_after_value
-						goto mainparse
-					}
+		if tok == fflib.FFTok_null {
 
-					err = v.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
-					if err != nil {
-						return err
-					}
-					state = fflib.FFParse_after_value
-				}
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			uj.Phone = string(string(outBuf))
 
-				uj.Emails = append(uj.Emails, v)
-				wantVal = false
-			}
 		}
 	}
 
@@ -9109,7 +6280,7 @@ done:
 	return nil
 }
 
-func (mj *MatchUsersReturnStruct) MarshalJSON() ([]byte, error) {
+func (mj *FriendCmdStruct) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -9121,7 +6292,7 @@ func (mj *MatchUsersReturnStruct) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *MatchUsersReturnStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *FriendCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
@@ -9130,53 +6301,47 @@ func (mj *MatchUsersReturnStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error
 	var obj []byte
 	_ = obj
 	_ = err
-	buf.WriteString(`{"Username":`)
-	fflib.WriteJsonString(buf, string(mj.Username))
-	buf.WriteString(`,"DisplayName":`)
-	fflib.WriteJsonString(buf, string(mj.DisplayName))
-	buf.WriteString(`,"ProfilePic":`)
-	fflib.WriteJsonString(buf, string(mj.ProfilePic))
-	buf.WriteString(`,"Phone":`)
-	fflib.WriteJsonString(buf, string(mj.Phone))
-	buf.WriteString(`,"Email":`)
-	fflib.WriteJsonString(buf, string(mj.Email))
+	buf.WriteString(`{"cmd":`)
+	fflib.WriteJsonString(buf, string(mj.Cmd))
+	buf.WriteString(`,"UID":`)
+	fflib.WriteJsonString(buf, string(mj.UID))
+	buf.WriteString(`,"friend_UID":`)
+	fflib.WriteJsonString(buf, string(mj.FriendUID))
+	buf.WriteString(`,"Message":`)
+	fflib.WriteJsonString(buf, string(mj.Message))
 	buf.WriteByte('}')
 	return nil
 }
 
 const (
-	ffj_t_MatchUsersReturnStructbase = iota
-	ffj_t_MatchUsersReturnStructno_such_key
-
-	ffj_t_MatchUsersReturnStruct_Username
+	ffj_t_FriendCmdStructbase = iota
+	ffj_t_FriendCmdStructno_such_key
 
-	ffj_t_MatchUsersReturnStruct_DisplayName
+	ffj_t_FriendCmdStruct_Cmd
 
-	ffj_t_MatchUsersReturnStruct_ProfilePic
+	ffj_t_FriendCmdStruct_UID
 
-	ffj_t_MatchUsersReturnStruct_Phone
+	ffj_t_FriendCmdStruct_FriendUID
 
-	ffj_t_MatchUsersReturnStruct_Email
+	ffj_t_FriendCmdStruct_Message
 )
 
-var ffj_key_MatchUsersReturnStruct_Username = []byte("Username")
-
-var ffj_key_MatchUsersReturnStruct_DisplayName = []byte("DisplayName")
+var ffj_key_FriendCmdStruct_Cmd = []byte("cmd")
 
-var ffj_key_MatchUsersReturnStruct_ProfilePic = []byte("ProfilePic")
+var ffj_key_FriendCmdStruct_UID = []byte("UID")
 
-var ffj_key_MatchUsersReturnStruct_Phone = []byte("Phone")
+var ffj_key_FriendCmdStruct_FriendUID = []byte("friend_UID")
 
-var ffj_key_MatchUsersReturnStruct_Email = []byte("Email")
+var ffj_key_FriendCmdStruct_Message = []byte("Message")
 
-func (uj *MatchUsersReturnStruct) UnmarshalJSON(input []byte) error {
+func (uj *FriendCmdStruct) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *MatchUsersReturnStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *FriendCmdStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_MatchUsersReturnStructbase
+	currentKey := ffj_t_FriendCmdStructbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -9222,82 +6387,71 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_MatchUsersReturnStructno_such_key
+				currentKey = ffj_t_FriendCmdStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
 				switch kn[0] {
 
-				case 'D':
+				case 'M':
 
-					if bytes.Equal(ffj_key_MatchUsersReturnStruct_DisplayName, kn) {
-						currentKey = ffj_t_MatchUsersReturnStruct_DisplayName
+					if bytes.Equal(ffj_key_FriendCmdStruct_Message, kn) {
+						currentKey = ffj_t_FriendCmdStruct_Message
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'E':
+				case 'U':
 
-					if bytes.Equal(ffj_key_MatchUsersReturnStruct_Email, kn) {
-						currentKey = ffj_t_MatchUsersReturnStruct_Email
+					if bytes.Equal(ffj_key_FriendCmdStruct_UID, kn) {
+						currentKey = ffj_t_FriendCmdStruct_UID
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'P':
-
-					if bytes.Equal(ffj_key_MatchUsersReturnStruct_ProfilePic, kn) {
-						currentKey = ffj_t_MatchUsersReturnStruct_ProfilePic
-						state = fflib.FFParse_want_colon
-						goto mainparse
+				case 'c':
 
-					} else if bytes.Equal(ffj_key_MatchUsersReturnStruct_Phone, kn) {
-						currentKey = ffj_t_MatchUsersReturnStruct_Phone
+					if bytes.Equal(ffj_key_FriendCmdStruct_Cmd, kn) {
+						currentKey = ffj_t_FriendCmdStruct_Cmd
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'U':
+				case 'f':
 
-					if bytes.Equal(ffj_key_MatchUsersReturnStruct_Username, kn) {
-						currentKey = ffj_t_MatchUsersReturnStruct_Username
+					if bytes.Equal(ffj_key_FriendCmdStruct_FriendUID, kn) {
+						currentKey = ffj_t_FriendCmdStruct_FriendUID
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersReturnStruct_Email, kn) {
-					currentKey = ffj_t_MatchUsersReturnStruct_Email
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersReturnStruct_Phone, kn) {
-					currentKey = ffj_t_MatchUsersReturnStruct_Phone
+				if fflib.EqualFoldRight(ffj_key_FriendCmdStruct_Message, kn) {
+					currentKey = ffj_t_FriendCmdStruct_Message
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersReturnStruct_ProfilePic, kn) {
-					currentKey = ffj_t_MatchUsersReturnStruct_ProfilePic
+				if fflib.AsciiEqualFold(ffj_key_FriendCmdStruct_FriendUID, kn) {
+					currentKey = ffj_t_FriendCmdStruct_FriendUID
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_MatchUsersReturnStruct_DisplayName, kn) {
-					currentKey = ffj_t_MatchUsersReturnStruct_DisplayName
+				if fflib.SimpleLetterEqualFold(ffj_key_FriendCmdStruct_UID, kn) {
+					currentKey = ffj_t_FriendCmdStruct_UID
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_MatchUsersReturnStruct_Username, kn) {
-					currentKey = ffj_t_MatchUsersReturnStruct_Username
+				if fflib.SimpleLetterEqualFold(ffj_key_FriendCmdStruct_Cmd, kn) {
+					currentKey = ffj_t_FriendCmdStruct_Cmd
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_MatchUsersReturnStructno_such_key
+				currentKey = ffj_t_FriendCmdStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -9314,22 +6468,19 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_MatchUsersReturnStruct_Username:
-					goto handle_Username
-
-				case ffj_t_MatchUsersReturnStruct_DisplayName:
-					goto handle_DisplayName
+				case ffj_t_FriendCmdStruct_Cmd:
+					goto handle_Cmd
 
-				case ffj_t_MatchUsersReturnStruct_ProfilePic:
-					goto handle_ProfilePic
+				case ffj_t_FriendCmdStruct_UID:
+					goto handle_UID
 
-				case ffj_t_MatchUsersReturnStruct_Phone:
-					goto handle_Phone
+				case ffj_t_FriendCmdStruct_FriendUID:
+					goto handle_FriendUID
 
-				case ffj_t_MatchUsersReturnStruct_Email:
-					goto handle_Email
+				case ffj_t_FriendCmdStruct_Message:
+					goto handle_Message
 
-				case ffj_t_MatchUsersReturnStructno_such_key:
+				case ffj_t_FriendCmdStructno_such_key:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -9343,35 +6494,9 @@ mainparse:
 		}
 	}
 
-handle_Username:
-
-	/* handler: uj.Username type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Username = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_DisplayName:
+handle_Cmd:
 
-	/* handler: uj.DisplayName type=string kind=string quoted=false*/
+	/* handler: uj.Cmd type=string kind=string quoted=false*/
 
 	{
 
@@ -9387,7 +6512,7 @@ handle_DisplayName:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.DisplayName = string(string(outBuf))
+			uj.Cmd = string(string(outBuf))
 
 		}
 	}
@@ -9395,9 +6520,9 @@ handle_DisplayName:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_ProfilePic:
+handle_UID:
 
-	/* handler: uj.ProfilePic type=string kind=string quoted=false*/
+	/* handler: uj.UID type=string kind=string quoted=false*/
 
 	{
 
@@ -9413,7 +6538,7 @@ handle_ProfilePic:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.ProfilePic = string(string(outBuf))
+			uj.UID = string(string(outBuf))
 
 		}
 	}
@@ -9421,9 +6546,9 @@ handle_ProfilePic:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Phone:
+handle_FriendUID:
 
-	/* handler: uj.Phone type=string kind=string quoted=false*/
+	/* handler: uj.FriendUID type=string kind=string quoted=false*/
 
 	{
 
@@ -9439,7 +6564,7 @@ handle_Phone:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Phone = string(string(outBuf))
+			uj.FriendUID = string(string(outBuf))
 
 		}
 	}
@@ -9447,9 +6572,9 @@ handle_Phone:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Email:
+handle_Message:
 
-	/* handler: uj.Email type=string kind=string quoted=false*/
+	/* handler: uj.Message type=string kind=string quoted=false*/
 
 	{
 
@@ -9465,7 +6590,7 @@ handle_Email:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Email = string(string(outBuf))
+			uj.Message = string(string(outBuf))
 
 		}
 	}
@@ -9490,7 +6615,7 @@ done:
 	return nil
 }
 
-func (mj *MessageStruct) MarshalJSON() ([]byte, error) {
+func (mj *InviteEmailStruct) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -9502,7 +6627,7 @@ func (mj *MessageStruct) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *MessageStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *InviteEmailStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
@@ -9511,90 +6636,47 @@ func (mj *MessageStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	var obj []byte
 	_ = obj
 	_ = err
-	buf.WriteString(`{ `)
-	if len(mj.Cmd) != 0 {
-		buf.WriteString(`"cmd":`)
-		fflib.WriteJsonString(buf, string(mj.Cmd))
-		buf.WriteByte(',')
-	}
-	if len(mj.CID) != 0 {
-		buf.WriteString(`"CID":`)
-		fflib.WriteJsonString(buf, string(mj.CID))
-		buf.WriteByte(',')
-	}
-	if len(mj.FromUsername) != 0 {
-		buf.WriteString(`"f_username":`)
-		fflib.WriteJsonString(buf, string(mj.FromUsername))
-		buf.WriteByte(',')
-	}
-	if len(mj.ToUIDs) != 0 {
-		buf.WriteString(`"t_UIDs":`)
-		if mj.ToUIDs != nil {
-			buf.WriteString(`[`)
-			for i, v := range mj.ToUIDs {
-				if i != 0 {
-					buf.WriteString(`,`)
-				}
-				fflib.WriteJsonString(buf, string(v))
-			}
-			buf.WriteString(`]`)
-		} else {
-			buf.WriteString(`null`)
-		}
-		buf.WriteByte(',')
-	}
-	if len(mj.M_time) != 0 {
-		buf.WriteString(`"m_time":`)
-		fflib.WriteJsonString(buf, string(mj.M_time))
-		buf.WriteByte(',')
-	}
-	if len(mj.Content) != 0 {
-		buf.WriteString(`"content":`)
-		fflib.WriteJsonString(buf, string(mj.Content))
-		buf.WriteByte(',')
-	}
-	buf.Rewind(1)
+	buf.WriteString(`{"cmd":`)
+	fflib.WriteJsonString(buf, string(mj.Cmd))
+	buf.WriteString(`,"f_username":`)
+	fflib.WriteJsonString(buf, string(mj.FromUsername))
+	buf.WriteString(`,"emails":`)
+	fflib.WriteJsonString(buf, string(mj.Emails))
+	buf.WriteString(`,"phones":`)
+	fflib.WriteJsonString(buf, string(mj.Phones))
 	buf.WriteByte('}')
 	return nil
 }
 
 const (
-	ffj_t_MessageStructbase = iota
-	ffj_t_MessageStructno_such_key
-
-	ffj_t_MessageStruct_Cmd
-
-	ffj_t_MessageStruct_CID
+	ffj_t_InviteEmailStructbase = iota
+	ffj_t_InviteEmailStructno_such_key
 
-	ffj_t_MessageStruct_FromUsername
+	ffj_t_InviteEmailStruct_Cmd
 
-	ffj_t_MessageStruct_ToUIDs
+	ffj_t_InviteEmailStruct_FromUsername
 
-	ffj_t_MessageStruct_M_time
+	ffj_t_InviteEmailStruct_Emails
 
-	ffj_t_MessageStruct_Content
+	ffj_t_InviteEmailStruct_Phones
 )
 
-var ffj_key_MessageStruct_Cmd = []byte("cmd")
-
-var ffj_key_MessageStruct_CID = []byte("CID")
-
-var ffj_key_MessageStruct_FromUsername = []byte("f_username")
+var ffj_key_InviteEmailStruct_Cmd = []byte("cmd")
 
-var ffj_key_MessageStruct_ToUIDs = []byte("t_UIDs")
+var ffj_key_InviteEmailStruct_FromUsername = []byte("f_username")
 
-var ffj_key_MessageStruct_M_time = []byte("m_time")
+var ffj_key_InviteEmailStruct_Emails = []byte("emails")
 
-var ffj_key_MessageStruct_Content = []byte("content")
+var ffj_key_InviteEmailStruct_Phones = []byte("phones")
 
-func (uj *MessageStruct) UnmarshalJSON(input []byte) error {
+func (uj *InviteEmailStruct) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *MessageStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *InviteEmailStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_MessageStructbase
+	currentKey := ffj_t_InviteEmailStructbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -9640,96 +6722,71 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_MessageStructno_such_key
+				currentKey = ffj_t_InviteEmailStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
 				switch kn[0] {
 
-				case 'C':
-
-					if bytes.Equal(ffj_key_MessageStruct_CID, kn) {
-						currentKey = ffj_t_MessageStruct_CID
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
 				case 'c':
 
-					if bytes.Equal(ffj_key_MessageStruct_Cmd, kn) {
-						currentKey = ffj_t_MessageStruct_Cmd
-						state = fflib.FFParse_want_colon
-						goto mainparse
-
-					} else if bytes.Equal(ffj_key_MessageStruct_Content, kn) {
-						currentKey = ffj_t_MessageStruct_Content
+					if bytes.Equal(ffj_key_InviteEmailStruct_Cmd, kn) {
+						currentKey = ffj_t_InviteEmailStruct_Cmd
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'f':
+				case 'e':
 
-					if bytes.Equal(ffj_key_MessageStruct_FromUsername, kn) {
-						currentKey = ffj_t_MessageStruct_FromUsername
+					if bytes.Equal(ffj_key_InviteEmailStruct_Emails, kn) {
+						currentKey = ffj_t_InviteEmailStruct_Emails
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'm':
+				case 'f':
 
-					if bytes.Equal(ffj_key_MessageStruct_M_time, kn) {
-						currentKey = ffj_t_MessageStruct_M_time
+					if bytes.Equal(ffj_key_InviteEmailStruct_FromUsername, kn) {
+						currentKey = ffj_t_InviteEmailStruct_FromUsername
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 't':
+				case 'p':
 
-					if bytes.Equal(ffj_key_MessageStruct_ToUIDs, kn) {
-						currentKey = ffj_t_MessageStruct_ToUIDs
+					if bytes.Equal(ffj_key_InviteEmailStruct_Phones, kn) {
+						currentKey = ffj_t_InviteEmailStruct_Phones
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_MessageStruct_Content, kn) {
-					currentKey = ffj_t_MessageStruct_Content
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.AsciiEqualFold(ffj_key_MessageStruct_M_time, kn) {
-					currentKey = ffj_t_MessageStruct_M_time
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_MessageStruct_ToUIDs, kn) {
-					currentKey = ffj_t_MessageStruct_ToUIDs
+				if fflib.EqualFoldRight(ffj_key_InviteEmailStruct_Phones, kn) {
+					currentKey = ffj_t_InviteEmailStruct_Phones
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_MessageStruct_FromUsername, kn) {
-					currentKey = ffj_t_MessageStruct_FromUsername
+				if fflib.EqualFoldRight(ffj_key_InviteEmailStruct_Emails, kn) {
+					currentKey = ffj_t_InviteEmailStruct_Emails
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_MessageStruct_CID, kn) {
-					currentKey = ffj_t_MessageStruct_CID
+				if fflib.EqualFoldRight(ffj_key_InviteEmailStruct_FromUsername, kn) {
+					currentKey = ffj_t_InviteEmailStruct_FromUsername
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_MessageStruct_Cmd, kn) {
-					currentKey = ffj_t_MessageStruct_Cmd
+				if fflib.SimpleLetterEqualFold(ffj_key_InviteEmailStruct_Cmd, kn) {
+					currentKey = ffj_t_InviteEmailStruct_Cmd
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_MessageStructno_such_key
+				currentKey = ffj_t_InviteEmailStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -9746,25 +6803,19 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_MessageStruct_Cmd:
+				case ffj_t_InviteEmailStruct_Cmd:
 					goto handle_Cmd
 
-				case ffj_t_MessageStruct_CID:
-					goto handle_CID
-
-				case ffj_t_MessageStruct_FromUsername:
+				case ffj_t_InviteEmailStruct_FromUsername:
 					goto handle_FromUsername
 
-				case ffj_t_MessageStruct_ToUIDs:
-					goto handle_ToUIDs
-
-				case ffj_t_MessageStruct_M_time:
-					goto handle_M_time
+				case ffj_t_InviteEmailStruct_Emails:
+					goto handle_Emails
 
-				case ffj_t_MessageStruct_Content:
-					goto handle_Content
+				case ffj_t_InviteEmailStruct_Phones:
+					goto handle_Phones
 
-				case ffj_t_MessageStructno_such_key:
+				case ffj_t_InviteEmailStructno_such_key:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -9804,32 +6855,6 @@ handle_Cmd:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_CID:
-
-	/* handler: uj.CID type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.CID = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
 handle_FromUsername:
 
 	/* handler: uj.FromUsername type=string kind=string quoted=false*/
@@ -9856,82 +6881,9 @@ handle_FromUsername:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_ToUIDs:
-
-	/* handler: uj.ToUIDs type=[]string kind=slice quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-			uj.ToUIDs = nil
-		} else {
-
-			uj.ToUIDs = make([]string, 0)
-
-			wantVal := true
-
-			for {
-
-				var v string
-
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
-				}
-				if tok == fflib.FFTok_right_brace {
-					break
-				}
-
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
-				}
-
-				/* handler: v type=string kind=string quoted=false*/
-
-				{
-
-					{
-						if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-							return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-						}
-					}
-
-					if tok == fflib.FFTok_null {
-
-					} else {
-
-						outBuf := fs.Output.Bytes()
-
-						v = string(string(outBuf))
-
-					}
-				}
-
-				uj.ToUIDs = append(uj.ToUIDs, v)
-				wantVal = false
-			}
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_M_time:
+handle_Emails:
 
-	/* handler: uj.M_time type=string kind=string quoted=false*/
+	/* handler: uj.Emails type=string kind=string quoted=false*/
 
 	{
 
@@ -9947,7 +6899,7 @@ handle_M_time:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.M_time = string(string(outBuf))
+			uj.Emails = string(string(outBuf))
 
 		}
 	}
@@ -9955,9 +6907,9 @@ handle_M_time:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Content:
+handle_Phones:
 
-	/* handler: uj.Content type=string kind=string quoted=false*/
+	/* handler: uj.Phones type=string kind=string quoted=false*/
 
 	{
 
@@ -9973,7 +6925,7 @@ handle_Content:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Content = string(string(outBuf))
+			uj.Phones = string(string(outBuf))
 
 		}
 	}
@@ -9998,7 +6950,7 @@ done:
 	return nil
 }
 
-func (mj *PasswordResetUserStruct) MarshalJSON() ([]byte, error) {
+func (mj *MatchUsersCmdEmailStruct) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -10010,83 +6962,44 @@ func (mj *PasswordResetUserStruct) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *PasswordResetUserStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *MatchUsersCmdEmailStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
 	}
 	var err error
-	var obj []byte
-	_ = obj
-	_ = err
-	buf.WriteString(`{ "cmd":`)
-	fflib.WriteJsonString(buf, string(mj.Cmd))
-	buf.WriteString(`,"Username":`)
-	fflib.WriteJsonString(buf, string(mj.Username))
-	buf.WriteString(`,"Questions":`)
-	if mj.Questions != nil {
-		buf.WriteString(`[`)
-		for i, v := range mj.Questions {
-			if i != 0 {
-				buf.WriteString(`,`)
-			}
-			fflib.WriteJsonString(buf, string(v))
-		}
-		buf.WriteString(`]`)
-	} else {
-		buf.WriteString(`null`)
-	}
-	buf.WriteByte(',')
-	if len(mj.Answers) != 0 {
-		buf.WriteString(`"Answers":`)
-		if mj.Answers != nil {
-			buf.WriteString(`[`)
-			for i, v := range mj.Answers {
-				if i != 0 {
-					buf.WriteString(`,`)
-				}
-				fflib.WriteJsonString(buf, string(v))
-			}
-			buf.WriteString(`]`)
-		} else {
-			buf.WriteString(`null`)
-		}
-		buf.WriteByte(',')
-	}
-	buf.Rewind(1)
+	var obj []byte
+	_ = obj
+	_ = err
+	buf.WriteString(`{"Name":`)
+	fflib.WriteJsonString(buf, string(mj.Name))
+	buf.WriteString(`,"Email":`)
+	fflib.WriteJsonString(buf, string(mj.Email))
 	buf.WriteByte('}')
 	return nil
 }
 
 const (
-	ffj_t_PasswordResetUserStructbase = iota
-	ffj_t_PasswordResetUserStructno_such_key
-
-	ffj_t_PasswordResetUserStruct_Cmd
-
-	ffj_t_PasswordResetUserStruct_Username
+	ffj_t_MatchUsersCmdEmailStructbase = iota
+	ffj_t_MatchUsersCmdEmailStructno_such_key
 
-	ffj_t_PasswordResetUserStruct_Questions
+	ffj_t_MatchUsersCmdEmailStruct_Name
 
-	ffj_t_PasswordResetUserStruct_Answers
+	ffj_t_MatchUsersCmdEmailStruct_Email
 )
 
-var ffj_key_PasswordResetUserStruct_Cmd = []byte("cmd")
-
-var ffj_key_PasswordResetUserStruct_Username = []byte("Username")
-
-var ffj_key_PasswordResetUserStruct_Questions = []byte("Questions")
+var ffj_key_MatchUsersCmdEmailStruct_Name = []byte("Name")
 
-var ffj_key_PasswordResetUserStruct_Answers = []byte("Answers")
+var ffj_key_MatchUsersCmdEmailStruct_Email = []byte("Email")
 
-func (uj *PasswordResetUserStruct) UnmarshalJSON(input []byte) error {
+func (uj *MatchUsersCmdEmailStruct) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *PasswordResetUserStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *MatchUsersCmdEmailStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_PasswordResetUserStructbase
+	currentKey := ffj_t_MatchUsersCmdEmailStructbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -10132,71 +7045,43 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_PasswordResetUserStructno_such_key
+				currentKey = ffj_t_MatchUsersCmdEmailStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
 				switch kn[0] {
 
-				case 'A':
-
-					if bytes.Equal(ffj_key_PasswordResetUserStruct_Answers, kn) {
-						currentKey = ffj_t_PasswordResetUserStruct_Answers
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'Q':
-
-					if bytes.Equal(ffj_key_PasswordResetUserStruct_Questions, kn) {
-						currentKey = ffj_t_PasswordResetUserStruct_Questions
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'U':
+				case 'E':
 
-					if bytes.Equal(ffj_key_PasswordResetUserStruct_Username, kn) {
-						currentKey = ffj_t_PasswordResetUserStruct_Username
+					if bytes.Equal(ffj_key_MatchUsersCmdEmailStruct_Email, kn) {
+						currentKey = ffj_t_MatchUsersCmdEmailStruct_Email
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'c':
+				case 'N':
 
-					if bytes.Equal(ffj_key_PasswordResetUserStruct_Cmd, kn) {
-						currentKey = ffj_t_PasswordResetUserStruct_Cmd
+					if bytes.Equal(ffj_key_MatchUsersCmdEmailStruct_Name, kn) {
+						currentKey = ffj_t_MatchUsersCmdEmailStruct_Name
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.EqualFoldRight(ffj_key_PasswordResetUserStruct_Answers, kn) {
-					currentKey = ffj_t_PasswordResetUserStruct_Answers
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_PasswordResetUserStruct_Questions, kn) {
-					currentKey = ffj_t_PasswordResetUserStruct_Questions
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_PasswordResetUserStruct_Username, kn) {
-					currentKey = ffj_t_PasswordResetUserStruct_Username
+				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersCmdEmailStruct_Email, kn) {
+					currentKey = ffj_t_MatchUsersCmdEmailStruct_Email
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_PasswordResetUserStruct_Cmd, kn) {
-					currentKey = ffj_t_PasswordResetUserStruct_Cmd
+				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersCmdEmailStruct_Name, kn) {
+					currentKey = ffj_t_MatchUsersCmdEmailStruct_Name
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_PasswordResetUserStructno_such_key
+				currentKey = ffj_t_MatchUsersCmdEmailStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -10213,19 +7098,13 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_PasswordResetUserStruct_Cmd:
-					goto handle_Cmd
-
-				case ffj_t_PasswordResetUserStruct_Username:
-					goto handle_Username
-
-				case ffj_t_PasswordResetUserStruct_Questions:
-					goto handle_Questions
+				case ffj_t_MatchUsersCmdEmailStruct_Name:
+					goto handle_Name
 
-				case ffj_t_PasswordResetUserStruct_Answers:
-					goto handle_Answers
+				case ffj_t_MatchUsersCmdEmailStruct_Email:
+					goto handle_Email
 
-				case ffj_t_PasswordResetUserStructno_such_key:
+				case ffj_t_MatchUsersCmdEmailStructno_such_key:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -10239,9 +7118,9 @@ mainparse:
 		}
 	}
 
-handle_Cmd:
+handle_Name:
 
-	/* handler: uj.Cmd type=string kind=string quoted=false*/
+	/* handler: uj.Name type=string kind=string quoted=false*/
 
 	{
 
@@ -10257,7 +7136,7 @@ handle_Cmd:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Cmd = string(string(outBuf))
+			uj.Name = string(string(outBuf))
 
 		}
 	}
@@ -10265,172 +7144,26 @@ handle_Cmd:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Username:
+handle_Email:
 
-	/* handler: uj.Username type=string kind=string quoted=false*/
+	/* handler: uj.Email type=string kind=string quoted=false*/
 
 	{
 
 		{
 			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Username = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Questions:
-
-	/* handler: uj.Questions type=[]string kind=slice quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-			uj.Questions = nil
-		} else {
-
-			uj.Questions = make([]string, 0)
-
-			wantVal := true
-
-			for {
-
-				var v string
-
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
-				}
-				if tok == fflib.FFTok_right_brace {
-					break
-				}
-
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
-				}
-
-				/* handler: v type=string kind=string quoted=false*/
-
-				{
-
-					{
-						if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-							return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-						}
-					}
-
-					if tok == fflib.FFTok_null {
-
-					} else {
-
-						outBuf := fs.Output.Bytes()
-
-						v = string(string(outBuf))
-
-					}
-				}
-
-				uj.Questions = append(uj.Questions, v)
-				wantVal = false
-			}
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Answers:
-
-	/* handler: uj.Answers type=[]string kind=slice quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-			uj.Answers = nil
-		} else {
-
-			uj.Answers = make([]string, 0)
-
-			wantVal := true
-
-			for {
-
-				var v string
-
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
-				}
-				if tok == fflib.FFTok_right_brace {
-					break
-				}
-
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
-				}
-
-				/* handler: v type=string kind=string quoted=false*/
-
-				{
-
-					{
-						if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-							return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-						}
-					}
-
-					if tok == fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
 
-					} else {
+		if tok == fflib.FFTok_null {
 
-						outBuf := fs.Output.Bytes()
+		} else {
 
-						v = string(string(outBuf))
+			outBuf := fs.Output.Bytes()
 
-					}
-				}
+			uj.Email = string(string(outBuf))
 
-				uj.Answers = append(uj.Answers, v)
-				wantVal = false
-			}
 		}
 	}
 
@@ -10454,7 +7187,7 @@ done:
 	return nil
 }
 
-func (mj *QuotaCmdStruct) MarshalJSON() ([]byte, error) {
+func (mj *MatchUsersCmdPhoneStruct) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -10466,7 +7199,7 @@ func (mj *QuotaCmdStruct) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *QuotaCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *MatchUsersCmdPhoneStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
@@ -10475,47 +7208,35 @@ func (mj *QuotaCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	var obj []byte
 	_ = obj
 	_ = err
-	buf.WriteString(`{"cmd":`)
-	fflib.WriteJsonString(buf, string(mj.Cmd))
-	buf.WriteString(`,"Username":`)
-	fflib.WriteJsonString(buf, string(mj.Username))
-	buf.WriteString(`,"Quota":`)
-	fflib.FormatBits2(buf, uint64(mj.Quota), 10, false)
-	buf.WriteString(`,"QuotaUsed":`)
-	fflib.FormatBits2(buf, uint64(mj.QuotaUsed), 10, false)
+	buf.WriteString(`{"Name":`)
+	fflib.WriteJsonString(buf, string(mj.Name))
+	buf.WriteString(`,"PhoneNum":`)
+	fflib.WriteJsonString(buf, string(mj.PhoneNum))
 	buf.WriteByte('}')
 	return nil
 }
 
 const (
-	ffj_t_QuotaCmdStructbase = iota
-	ffj_t_QuotaCmdStructno_such_key
-
-	ffj_t_QuotaCmdStruct_Cmd
-
-	ffj_t_QuotaCmdStruct_Username
+	ffj_t_MatchUsersCmdPhoneStructbase = iota
+	ffj_t_MatchUsersCmdPhoneStructno_such_key
 
-	ffj_t_QuotaCmdStruct_Quota
+	ffj_t_MatchUsersCmdPhoneStruct_Name
 
-	ffj_t_QuotaCmdStruct_QuotaUsed
+	ffj_t_MatchUsersCmdPhoneStruct_PhoneNum
 )
 
-var ffj_key_QuotaCmdStruct_Cmd = []byte("cmd")
-
-var ffj_key_QuotaCmdStruct_Username = []byte("Username")
-
-var ffj_key_QuotaCmdStruct_Quota = []byte("Quota")
+var ffj_key_MatchUsersCmdPhoneStruct_Name = []byte("Name")
 
-var ffj_key_QuotaCmdStruct_QuotaUsed = []byte("QuotaUsed")
+var ffj_key_MatchUsersCmdPhoneStruct_PhoneNum = []byte("PhoneNum")
 
-func (uj *QuotaCmdStruct) UnmarshalJSON(input []byte) error {
+func (uj *MatchUsersCmdPhoneStruct) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *QuotaCmdStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *MatchUsersCmdPhoneStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_QuotaCmdStructbase
+	currentKey := ffj_t_MatchUsersCmdPhoneStructbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -10561,68 +7282,43 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_QuotaCmdStructno_such_key
+				currentKey = ffj_t_MatchUsersCmdPhoneStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
 				switch kn[0] {
 
-				case 'Q':
-
-					if bytes.Equal(ffj_key_QuotaCmdStruct_Quota, kn) {
-						currentKey = ffj_t_QuotaCmdStruct_Quota
-						state = fflib.FFParse_want_colon
-						goto mainparse
-
-					} else if bytes.Equal(ffj_key_QuotaCmdStruct_QuotaUsed, kn) {
-						currentKey = ffj_t_QuotaCmdStruct_QuotaUsed
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'U':
+				case 'N':
 
-					if bytes.Equal(ffj_key_QuotaCmdStruct_Username, kn) {
-						currentKey = ffj_t_QuotaCmdStruct_Username
+					if bytes.Equal(ffj_key_MatchUsersCmdPhoneStruct_Name, kn) {
+						currentKey = ffj_t_MatchUsersCmdPhoneStruct_Name
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'c':
+				case 'P':
 
-					if bytes.Equal(ffj_key_QuotaCmdStruct_Cmd, kn) {
-						currentKey = ffj_t_QuotaCmdStruct_Cmd
+					if bytes.Equal(ffj_key_MatchUsersCmdPhoneStruct_PhoneNum, kn) {
+						currentKey = ffj_t_MatchUsersCmdPhoneStruct_PhoneNum
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.EqualFoldRight(ffj_key_QuotaCmdStruct_QuotaUsed, kn) {
-					currentKey = ffj_t_QuotaCmdStruct_QuotaUsed
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_QuotaCmdStruct_Quota, kn) {
-					currentKey = ffj_t_QuotaCmdStruct_Quota
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_QuotaCmdStruct_Username, kn) {
-					currentKey = ffj_t_QuotaCmdStruct_Username
+				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersCmdPhoneStruct_PhoneNum, kn) {
+					currentKey = ffj_t_MatchUsersCmdPhoneStruct_PhoneNum
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_QuotaCmdStruct_Cmd, kn) {
-					currentKey = ffj_t_QuotaCmdStruct_Cmd
+				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersCmdPhoneStruct_Name, kn) {
+					currentKey = ffj_t_MatchUsersCmdPhoneStruct_Name
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_QuotaCmdStructno_such_key
+				currentKey = ffj_t_MatchUsersCmdPhoneStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -10639,19 +7335,13 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_QuotaCmdStruct_Cmd:
-					goto handle_Cmd
-
-				case ffj_t_QuotaCmdStruct_Username:
-					goto handle_Username
-
-				case ffj_t_QuotaCmdStruct_Quota:
-					goto handle_Quota
+				case ffj_t_MatchUsersCmdPhoneStruct_Name:
+					goto handle_Name
 
-				case ffj_t_QuotaCmdStruct_QuotaUsed:
-					goto handle_QuotaUsed
+				case ffj_t_MatchUsersCmdPhoneStruct_PhoneNum:
+					goto handle_PhoneNum
 
-				case ffj_t_QuotaCmdStructno_such_key:
+				case ffj_t_MatchUsersCmdPhoneStructno_such_key:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -10665,9 +7355,9 @@ mainparse:
 		}
 	}
 
-handle_Cmd:
+handle_Name:
 
-	/* handler: uj.Cmd type=string kind=string quoted=false*/
+	/* handler: uj.Name type=string kind=string quoted=false*/
 
 	{
 
@@ -10683,7 +7373,7 @@ handle_Cmd:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Cmd = string(string(outBuf))
+			uj.Name = string(string(outBuf))
 
 		}
 	}
@@ -10691,9 +7381,9 @@ handle_Cmd:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Username:
+handle_PhoneNum:
 
-	/* handler: uj.Username type=string kind=string quoted=false*/
+	/* handler: uj.PhoneNum type=string kind=string quoted=false*/
 
 	{
 
@@ -10709,67 +7399,7 @@ handle_Username:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Username = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Quota:
-
-	/* handler: uj.Quota type=uint32 kind=uint32 quoted=false*/
-
-	{
-		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for uint32", tok))
-		}
-	}
-
-	{
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			tval, err := fflib.ParseUint(fs.Output.Bytes(), 10, 32)
-
-			if err != nil {
-				return fs.WrapErr(err)
-			}
-
-			uj.Quota = uint32(tval)
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_QuotaUsed:
-
-	/* handler: uj.QuotaUsed type=uint32 kind=uint32 quoted=false*/
-
-	{
-		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for uint32", tok))
-		}
-	}
-
-	{
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			tval, err := fflib.ParseUint(fs.Output.Bytes(), 10, 32)
-
-			if err != nil {
-				return fs.WrapErr(err)
-			}
-
-			uj.QuotaUsed = uint32(tval)
+			uj.PhoneNum = string(string(outBuf))
 
 		}
 	}
@@ -10794,7 +7424,7 @@ done:
 	return nil
 }
 
-func (mj *ScheduledMessagesCmdStruct) MarshalJSON() ([]byte, error) {
+func (mj *MatchUsersCmdStruct) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -10806,7 +7436,7 @@ func (mj *ScheduledMessagesCmdStruct) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *ScheduledMessagesCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *MatchUsersCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
@@ -10817,51 +7447,77 @@ func (mj *ScheduledMessagesCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) e
 	_ = err
 	buf.WriteString(`{"cmd":`)
 	fflib.WriteJsonString(buf, string(mj.Cmd))
-	buf.WriteString(`,"Username":`)
-	fflib.WriteJsonString(buf, string(mj.Username))
-	buf.WriteString(`,"CID":`)
-	fflib.WriteJsonString(buf, string(mj.CID))
-	buf.WriteString(`,"Time":`)
-	fflib.WriteJsonString(buf, string(mj.Time))
-	buf.WriteString(`,"Content":`)
-	fflib.WriteJsonString(buf, string(mj.Content))
-	buf.WriteByte('}')
-	return nil
-}
+	buf.WriteString(`,"Phones":`)
+	if mj.Phones != nil {
+		buf.WriteString(`[`)
+		for i, v := range mj.Phones {
+			if i != 0 {
+				buf.WriteString(`,`)
+			}
 
-const (
-	ffj_t_ScheduledMessagesCmdStructbase = iota
-	ffj_t_ScheduledMessagesCmdStructno_such_key
+			{
 
-	ffj_t_ScheduledMessagesCmdStruct_Cmd
+				err = v.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
 
-	ffj_t_ScheduledMessagesCmdStruct_Username
+			}
+		}
+		buf.WriteString(`]`)
+	} else {
+		buf.WriteString(`null`)
+	}
+	buf.WriteString(`,"Emails":`)
+	if mj.Emails != nil {
+		buf.WriteString(`[`)
+		for i, v := range mj.Emails {
+			if i != 0 {
+				buf.WriteString(`,`)
+			}
 
-	ffj_t_ScheduledMessagesCmdStruct_CID
+			{
+
+				err = v.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+		}
+		buf.WriteString(`]`)
+	} else {
+		buf.WriteString(`null`)
+	}
+	buf.WriteByte('}')
+	return nil
+}
 
-	ffj_t_ScheduledMessagesCmdStruct_Time
+const (
+	ffj_t_MatchUsersCmdStructbase = iota
+	ffj_t_MatchUsersCmdStructno_such_key
 
-	ffj_t_ScheduledMessagesCmdStruct_Content
-)
+	ffj_t_MatchUsersCmdStruct_Cmd
 
-var ffj_key_ScheduledMessagesCmdStruct_Cmd = []byte("cmd")
+	ffj_t_MatchUsersCmdStruct_Phones
 
-var ffj_key_ScheduledMessagesCmdStruct_Username = []byte("Username")
+	ffj_t_MatchUsersCmdStruct_Emails
+)
 
-var ffj_key_ScheduledMessagesCmdStruct_CID = []byte("CID")
+var ffj_key_MatchUsersCmdStruct_Cmd = []byte("cmd")
 
-var ffj_key_ScheduledMessagesCmdStruct_Time = []byte("Time")
+var ffj_key_MatchUsersCmdStruct_Phones = []byte("Phones")
 
-var ffj_key_ScheduledMessagesCmdStruct_Content = []byte("Content")
+var ffj_key_MatchUsersCmdStruct_Emails = []byte("Emails")
 
-func (uj *ScheduledMessagesCmdStruct) UnmarshalJSON(input []byte) error {
+func (uj *MatchUsersCmdStruct) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *ScheduledMessagesCmdStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *MatchUsersCmdStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_ScheduledMessagesCmdStructbase
+	currentKey := ffj_t_MatchUsersCmdStructbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -10907,82 +7563,57 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_ScheduledMessagesCmdStructno_such_key
+				currentKey = ffj_t_MatchUsersCmdStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
 				switch kn[0] {
 
-				case 'C':
-
-					if bytes.Equal(ffj_key_ScheduledMessagesCmdStruct_CID, kn) {
-						currentKey = ffj_t_ScheduledMessagesCmdStruct_CID
-						state = fflib.FFParse_want_colon
-						goto mainparse
-
-					} else if bytes.Equal(ffj_key_ScheduledMessagesCmdStruct_Content, kn) {
-						currentKey = ffj_t_ScheduledMessagesCmdStruct_Content
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'T':
+				case 'E':
 
-					if bytes.Equal(ffj_key_ScheduledMessagesCmdStruct_Time, kn) {
-						currentKey = ffj_t_ScheduledMessagesCmdStruct_Time
+					if bytes.Equal(ffj_key_MatchUsersCmdStruct_Emails, kn) {
+						currentKey = ffj_t_MatchUsersCmdStruct_Emails
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'U':
+				case 'P':
 
-					if bytes.Equal(ffj_key_ScheduledMessagesCmdStruct_Username, kn) {
-						currentKey = ffj_t_ScheduledMessagesCmdStruct_Username
+					if bytes.Equal(ffj_key_MatchUsersCmdStruct_Phones, kn) {
+						currentKey = ffj_t_MatchUsersCmdStruct_Phones
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'c':
 
-					if bytes.Equal(ffj_key_ScheduledMessagesCmdStruct_Cmd, kn) {
-						currentKey = ffj_t_ScheduledMessagesCmdStruct_Cmd
+					if bytes.Equal(ffj_key_MatchUsersCmdStruct_Cmd, kn) {
+						currentKey = ffj_t_MatchUsersCmdStruct_Cmd
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_ScheduledMessagesCmdStruct_Content, kn) {
-					currentKey = ffj_t_ScheduledMessagesCmdStruct_Content
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_ScheduledMessagesCmdStruct_Time, kn) {
-					currentKey = ffj_t_ScheduledMessagesCmdStruct_Time
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_ScheduledMessagesCmdStruct_CID, kn) {
-					currentKey = ffj_t_ScheduledMessagesCmdStruct_CID
+				if fflib.EqualFoldRight(ffj_key_MatchUsersCmdStruct_Emails, kn) {
+					currentKey = ffj_t_MatchUsersCmdStruct_Emails
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_ScheduledMessagesCmdStruct_Username, kn) {
-					currentKey = ffj_t_ScheduledMessagesCmdStruct_Username
+				if fflib.EqualFoldRight(ffj_key_MatchUsersCmdStruct_Phones, kn) {
+					currentKey = ffj_t_MatchUsersCmdStruct_Phones
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_ScheduledMessagesCmdStruct_Cmd, kn) {
-					currentKey = ffj_t_ScheduledMessagesCmdStruct_Cmd
+				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersCmdStruct_Cmd, kn) {
+					currentKey = ffj_t_MatchUsersCmdStruct_Cmd
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_ScheduledMessagesCmdStructno_such_key
+				currentKey = ffj_t_MatchUsersCmdStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -10999,22 +7630,16 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_ScheduledMessagesCmdStruct_Cmd:
+				case ffj_t_MatchUsersCmdStruct_Cmd:
 					goto handle_Cmd
 
-				case ffj_t_ScheduledMessagesCmdStruct_Username:
-					goto handle_Username
-
-				case ffj_t_ScheduledMessagesCmdStruct_CID:
-					goto handle_CID
-
-				case ffj_t_ScheduledMessagesCmdStruct_Time:
-					goto handle_Time
+				case ffj_t_MatchUsersCmdStruct_Phones:
+					goto handle_Phones
 
-				case ffj_t_ScheduledMessagesCmdStruct_Content:
-					goto handle_Content
+				case ffj_t_MatchUsersCmdStruct_Emails:
+					goto handle_Emails
 
-				case ffj_t_ScheduledMessagesCmdStructno_such_key:
+				case ffj_t_MatchUsersCmdStructno_such_key:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -11054,104 +7679,136 @@ handle_Cmd:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Username:
+handle_Phones:
 
-	/* handler: uj.Username type=string kind=string quoted=false*/
+	/* handler: uj.Phones type=[]pcDatabase.MatchUsersCmdPhoneStruct kind=slice quoted=false*/
 
 	{
 
 		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
 			}
 		}
 
 		if tok == fflib.FFTok_null {
-
+			uj.Phones = nil
 		} else {
 
-			outBuf := fs.Output.Bytes()
-
-			uj.Username = string(string(outBuf))
-
-		}
-	}
+			uj.Phones = make([]MatchUsersCmdPhoneStruct, 0)
 
-	state = fflib.FFParse_after_value
-	goto mainparse
+			wantVal := true
 
-handle_CID:
+			for {
 
-	/* handler: uj.CID type=string kind=string quoted=false*/
+				var v MatchUsersCmdPhoneStruct
 
-	{
+				tok = fs.Scan()
+				if tok == fflib.FFTok_error {
+					goto tokerror
+				}
+				if tok == fflib.FFTok_right_brace {
+					break
+				}
 
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
+				if tok == fflib.FFTok_comma {
+					if wantVal == true {
+						// TODO(pquerna): this isn't an ideal error message, this handles
+						// things like [,,,] as an array value.
+						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+					}
+					continue
+				} else {
+					wantVal = true
+				}
 
-		if tok == fflib.FFTok_null {
+				/* handler: v type=pcDatabase.MatchUsersCmdPhoneStruct kind=struct quoted=false*/
 
-		} else {
+				{
+					if tok == fflib.FFTok_null {
 
-			outBuf := fs.Output.Bytes()
+						state = fflib.FFParse_after_value
+						goto mainparse
+					}
 
-			uj.CID = string(string(outBuf))
+					err = v.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+					if err != nil {
+						return err
+					}
+					state = fflib.FFParse_after_value
+				}
 
+				uj.Phones = append(uj.Phones, v)
+				wantVal = false
+			}
 		}
 	}
 
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Time:
+handle_Emails:
 
-	/* handler: uj.Time type=string kind=string quoted=false*/
+	/* handler: uj.Emails type=[]pcDatabase.MatchUsersCmdEmailStruct kind=slice quoted=false*/
 
 	{
 
 		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
 			}
 		}
 
 		if tok == fflib.FFTok_null {
-
+			uj.Emails = nil
 		} else {
 
-			outBuf := fs.Output.Bytes()
-
-			uj.Time = string(string(outBuf))
-
-		}
-	}
+			uj.Emails = make([]MatchUsersCmdEmailStruct, 0)
 
-	state = fflib.FFParse_after_value
-	goto mainparse
+			wantVal := true
 
-handle_Content:
+			for {
 
-	/* handler: uj.Content type=string kind=string quoted=false*/
+				var v MatchUsersCmdEmailStruct
 
-	{
+				tok = fs.Scan()
+				if tok == fflib.FFTok_error {
+					goto tokerror
+				}
+				if tok == fflib.FFTok_right_brace {
+					break
+				}
 
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
+				if tok == fflib.FFTok_comma {
+					if wantVal == true {
+						// TODO(pquerna): this isn't an ideal error message, this handles
+						// things like [,,,] as an array value.
+						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+					}
+					continue
+				} else {
+					wantVal = true
+				}
 
-		if tok == fflib.FFTok_null {
+				/* handler: v type=pcDatabase.MatchUsersCmdEmailStruct kind=struct quoted=false*/
 
-		} else {
+				{
+					if tok == fflib.FFTok_null {
 
-			outBuf := fs.Output.Bytes()
+						state = fflib.FFParse_after_value
+						goto mainparse
+					}
 
-			uj.Content = string(string(outBuf))
+					err = v.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+					if err != nil {
+						return err
+					}
+					state = fflib.FFParse_after_value
+				}
 
+				uj.Emails = append(uj.Emails, v)
+				wantVal = false
+			}
 		}
 	}
 
@@ -11175,7 +7832,7 @@ done:
 	return nil
 }
 
-func (mj *ScheduledMessagesStruct) MarshalJSON() ([]byte, error) {
+func (mj *MatchUsersReturnStruct) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -11187,7 +7844,7 @@ func (mj *ScheduledMessagesStruct) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *ScheduledMessagesStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *MatchUsersReturnStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
@@ -11196,41 +7853,53 @@ func (mj *ScheduledMessagesStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) erro
 	var obj []byte
 	_ = obj
 	_ = err
-	buf.WriteString(`{"CID":`)
-	fflib.WriteJsonString(buf, string(mj.CID))
-	buf.WriteString(`,"Time":`)
-	fflib.WriteJsonString(buf, string(mj.Time))
-	buf.WriteString(`,"Content":`)
-	fflib.WriteJsonString(buf, string(mj.Content))
+	buf.WriteString(`{"Username":`)
+	fflib.WriteJsonString(buf, string(mj.Username))
+	buf.WriteString(`,"DisplayName":`)
+	fflib.WriteJsonString(buf, string(mj.DisplayName))
+	buf.WriteString(`,"ProfilePic":`)
+	fflib.WriteJsonString(buf, string(mj.ProfilePic))
+	buf.WriteString(`,"Phone":`)
+	fflib.WriteJsonString(buf, string(mj.Phone))
+	buf.WriteString(`,"Email":`)
+	fflib.WriteJsonString(buf, string(mj.Email))
 	buf.WriteByte('}')
 	return nil
 }
 
 const (
-	ffj_t_ScheduledMessagesStructbase = iota
-	ffj_t_ScheduledMessagesStructno_such_key
+	ffj_t_MatchUsersReturnStructbase = iota
+	ffj_t_MatchUsersReturnStructno_such_key
+
+	ffj_t_MatchUsersReturnStruct_Username
 
-	ffj_t_ScheduledMessagesStruct_CID
+	ffj_t_MatchUsersReturnStruct_DisplayName
 
-	ffj_t_ScheduledMessagesStruct_Time
+	ffj_t_MatchUsersReturnStruct_ProfilePic
 
-	ffj_t_ScheduledMessagesStruct_Content
+	ffj_t_MatchUsersReturnStruct_Phone
+
+	ffj_t_MatchUsersReturnStruct_Email
 )
 
-var ffj_key_ScheduledMessagesStruct_CID = []byte("CID")
+var ffj_key_MatchUsersReturnStruct_Username = []byte("Username")
 
-var ffj_key_ScheduledMessagesStruct_Time = []byte("Time")
+var ffj_key_MatchUsersReturnStruct_DisplayName = []byte("DisplayName")
 
-var ffj_key_ScheduledMessagesStruct_Content = []byte("Content")
+var ffj_key_MatchUsersReturnStruct_ProfilePic = []byte("ProfilePic")
 
-func (uj *ScheduledMessagesStruct) UnmarshalJSON(input []byte) error {
+var ffj_key_MatchUsersReturnStruct_Phone = []byte("Phone")
+
+var ffj_key_MatchUsersReturnStruct_Email = []byte("Email")
+
+func (uj *MatchUsersReturnStruct) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *ScheduledMessagesStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *MatchUsersReturnStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_ScheduledMessagesStructbase
+	currentKey := ffj_t_MatchUsersReturnStructbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -11276,54 +7945,82 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_ScheduledMessagesStructno_such_key
+				currentKey = ffj_t_MatchUsersReturnStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
 				switch kn[0] {
 
-				case 'C':
+				case 'D':
 
-					if bytes.Equal(ffj_key_ScheduledMessagesStruct_CID, kn) {
-						currentKey = ffj_t_ScheduledMessagesStruct_CID
+					if bytes.Equal(ffj_key_MatchUsersReturnStruct_DisplayName, kn) {
+						currentKey = ffj_t_MatchUsersReturnStruct_DisplayName
 						state = fflib.FFParse_want_colon
 						goto mainparse
+					}
 
-					} else if bytes.Equal(ffj_key_ScheduledMessagesStruct_Content, kn) {
-						currentKey = ffj_t_ScheduledMessagesStruct_Content
+				case 'E':
+
+					if bytes.Equal(ffj_key_MatchUsersReturnStruct_Email, kn) {
+						currentKey = ffj_t_MatchUsersReturnStruct_Email
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'T':
+				case 'P':
 
-					if bytes.Equal(ffj_key_ScheduledMessagesStruct_Time, kn) {
-						currentKey = ffj_t_ScheduledMessagesStruct_Time
+					if bytes.Equal(ffj_key_MatchUsersReturnStruct_ProfilePic, kn) {
+						currentKey = ffj_t_MatchUsersReturnStruct_ProfilePic
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffj_key_MatchUsersReturnStruct_Phone, kn) {
+						currentKey = ffj_t_MatchUsersReturnStruct_Phone
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'U':
+
+					if bytes.Equal(ffj_key_MatchUsersReturnStruct_Username, kn) {
+						currentKey = ffj_t_MatchUsersReturnStruct_Username
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_ScheduledMessagesStruct_Content, kn) {
-					currentKey = ffj_t_ScheduledMessagesStruct_Content
+				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersReturnStruct_Email, kn) {
+					currentKey = ffj_t_MatchUsersReturnStruct_Email
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_ScheduledMessagesStruct_Time, kn) {
-					currentKey = ffj_t_ScheduledMessagesStruct_Time
+				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersReturnStruct_Phone, kn) {
+					currentKey = ffj_t_MatchUsersReturnStruct_Phone
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_ScheduledMessagesStruct_CID, kn) {
-					currentKey = ffj_t_ScheduledMessagesStruct_CID
+				if fflib.SimpleLetterEqualFold(ffj_key_MatchUsersReturnStruct_ProfilePic, kn) {
+					currentKey = ffj_t_MatchUsersReturnStruct_ProfilePic
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_ScheduledMessagesStructno_such_key
+				if fflib.EqualFoldRight(ffj_key_MatchUsersReturnStruct_DisplayName, kn) {
+					currentKey = ffj_t_MatchUsersReturnStruct_DisplayName
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffj_key_MatchUsersReturnStruct_Username, kn) {
+					currentKey = ffj_t_MatchUsersReturnStruct_Username
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				currentKey = ffj_t_MatchUsersReturnStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -11340,16 +8037,22 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_ScheduledMessagesStruct_CID:
-					goto handle_CID
+				case ffj_t_MatchUsersReturnStruct_Username:
+					goto handle_Username
 
-				case ffj_t_ScheduledMessagesStruct_Time:
-					goto handle_Time
+				case ffj_t_MatchUsersReturnStruct_DisplayName:
+					goto handle_DisplayName
 
-				case ffj_t_ScheduledMessagesStruct_Content:
-					goto handle_Content
+				case ffj_t_MatchUsersReturnStruct_ProfilePic:
+					goto handle_ProfilePic
 
-				case ffj_t_ScheduledMessagesStructno_such_key:
+				case ffj_t_MatchUsersReturnStruct_Phone:
+					goto handle_Phone
+
+				case ffj_t_MatchUsersReturnStruct_Email:
+					goto handle_Email
+
+				case ffj_t_MatchUsersReturnStructno_such_key:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -11363,35 +8066,9 @@ mainparse:
 		}
 	}
 
-handle_CID:
-
-	/* handler: uj.CID type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.CID = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Time:
+handle_Username:
 
-	/* handler: uj.Time type=string kind=string quoted=false*/
+	/* handler: uj.Username type=string kind=string quoted=false*/
 
 	{
 
@@ -11407,7 +8084,7 @@ handle_Time:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Time = string(string(outBuf))
+			uj.Username = string(string(outBuf))
 
 		}
 	}
@@ -11415,220 +8092,61 @@ handle_Time:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Content:
+handle_DisplayName:
 
-	/* handler: uj.Content type=string kind=string quoted=false*/
+	/* handler: uj.DisplayName type=string kind=string quoted=false*/
 
 	{
 
 		{
 			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Content = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-wantedvalue:
-	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-wrongtokenerror:
-	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
-tokerror:
-	if fs.BigError != nil {
-		return fs.WrapErr(fs.BigError)
-	}
-	err = fs.Error.ToError()
-	if err != nil {
-		return fs.WrapErr(err)
-	}
-	panic("ffjson-generated: unreachable, please report bug.")
-done:
-	return nil
-}
-
-func (mj *SecurityQuestionStruct) MarshalJSON() ([]byte, error) {
-	var buf fflib.Buffer
-	if mj == nil {
-		buf.WriteString("null")
-		return buf.Bytes(), nil
-	}
-	err := mj.MarshalJSONBuf(&buf)
-	if err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
-}
-func (mj *SecurityQuestionStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
-	if mj == nil {
-		buf.WriteString("null")
-		return nil
-	}
-	var err error
-	var obj []byte
-	_ = obj
-	_ = err
-	buf.WriteString(`{"Question":`)
-	fflib.WriteJsonString(buf, string(mj.Question))
-	buf.WriteString(`,"Answer":`)
-	fflib.WriteJsonString(buf, string(mj.Answer))
-	buf.WriteByte('}')
-	return nil
-}
-
-const (
-	ffj_t_SecurityQuestionStructbase = iota
-	ffj_t_SecurityQuestionStructno_such_key
-
-	ffj_t_SecurityQuestionStruct_Question
-
-	ffj_t_SecurityQuestionStruct_Answer
-)
-
-var ffj_key_SecurityQuestionStruct_Question = []byte("Question")
-
-var ffj_key_SecurityQuestionStruct_Answer = []byte("Answer")
-
-func (uj *SecurityQuestionStruct) UnmarshalJSON(input []byte) error {
-	fs := fflib.NewFFLexer(input)
-	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
-}
-
-func (uj *SecurityQuestionStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
-	var err error = nil
-	currentKey := ffj_t_SecurityQuestionStructbase
-	_ = currentKey
-	tok := fflib.FFTok_init
-	wantedTok := fflib.FFTok_init
-
-mainparse:
-	for {
-		tok = fs.Scan()
-		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
-		if tok == fflib.FFTok_error {
-			goto tokerror
-		}
-
-		switch state {
-
-		case fflib.FFParse_map_start:
-			if tok != fflib.FFTok_left_bracket {
-				wantedTok = fflib.FFTok_left_bracket
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_key
-			continue
-
-		case fflib.FFParse_after_value:
-			if tok == fflib.FFTok_comma {
-				state = fflib.FFParse_want_key
-			} else if tok == fflib.FFTok_right_bracket {
-				goto done
-			} else {
-				wantedTok = fflib.FFTok_comma
-				goto wrongtokenerror
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
 			}
+		}
 
-		case fflib.FFParse_want_key:
-			// json {} ended. goto exit. woo.
-			if tok == fflib.FFTok_right_bracket {
-				goto done
-			}
-			if tok != fflib.FFTok_string {
-				wantedTok = fflib.FFTok_string
-				goto wrongtokenerror
-			}
+		if tok == fflib.FFTok_null {
 
-			kn := fs.Output.Bytes()
-			if len(kn) <= 0 {
-				// "" case. hrm.
-				currentKey = ffj_t_SecurityQuestionStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			} else {
-				switch kn[0] {
+		} else {
 
-				case 'A':
+			outBuf := fs.Output.Bytes()
 
-					if bytes.Equal(ffj_key_SecurityQuestionStruct_Answer, kn) {
-						currentKey = ffj_t_SecurityQuestionStruct_Answer
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
+			uj.DisplayName = string(string(outBuf))
 
-				case 'Q':
+		}
+	}
 
-					if bytes.Equal(ffj_key_SecurityQuestionStruct_Question, kn) {
-						currentKey = ffj_t_SecurityQuestionStruct_Question
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
+	state = fflib.FFParse_after_value
+	goto mainparse
 
-				}
+handle_ProfilePic:
 
-				if fflib.EqualFoldRight(ffj_key_SecurityQuestionStruct_Answer, kn) {
-					currentKey = ffj_t_SecurityQuestionStruct_Answer
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
+	/* handler: uj.ProfilePic type=string kind=string quoted=false*/
 
-				if fflib.EqualFoldRight(ffj_key_SecurityQuestionStruct_Question, kn) {
-					currentKey = ffj_t_SecurityQuestionStruct_Question
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
+	{
 
-				currentKey = ffj_t_SecurityQuestionStructno_such_key
-				state = fflib.FFParse_want_colon
-				goto mainparse
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
 			}
+		}
 
-		case fflib.FFParse_want_colon:
-			if tok != fflib.FFTok_colon {
-				wantedTok = fflib.FFTok_colon
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_value
-			continue
-		case fflib.FFParse_want_value:
+		if tok == fflib.FFTok_null {
 
-			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
-				switch currentKey {
+		} else {
 
-				case ffj_t_SecurityQuestionStruct_Question:
-					goto handle_Question
+			outBuf := fs.Output.Bytes()
 
-				case ffj_t_SecurityQuestionStruct_Answer:
-					goto handle_Answer
+			uj.ProfilePic = string(string(outBuf))
 
-				case ffj_t_SecurityQuestionStructno_such_key:
-					err = fs.SkipField(tok)
-					if err != nil {
-						return fs.WrapErr(err)
-					}
-					state = fflib.FFParse_after_value
-					goto mainparse
-				}
-			} else {
-				goto wantedvalue
-			}
 		}
 	}
 
-handle_Question:
+	state = fflib.FFParse_after_value
+	goto mainparse
 
-	/* handler: uj.Question type=string kind=string quoted=false*/
+handle_Phone:
+
+	/* handler: uj.Phone type=string kind=string quoted=false*/
 
 	{
 
@@ -11644,7 +8162,7 @@ handle_Question:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Question = string(string(outBuf))
+			uj.Phone = string(string(outBuf))
 
 		}
 	}
@@ -11652,9 +8170,9 @@ handle_Question:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Answer:
+handle_Email:
 
-	/* handler: uj.Answer type=string kind=string quoted=false*/
+	/* handler: uj.Email type=string kind=string quoted=false*/
 
 	{
 
@@ -11670,7 +8188,7 @@ handle_Answer:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Answer = string(string(outBuf))
+			uj.Email = string(string(outBuf))
 
 		}
 	}
@@ -11695,7 +8213,7 @@ done:
 	return nil
 }
 
-func (mj *SendEmailAttachment) MarshalJSON() ([]byte, error) {
+func (mj *PasswordResetUserStruct) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -11707,7 +8225,7 @@ func (mj *SendEmailAttachment) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *SendEmailAttachment) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *PasswordResetUserStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
@@ -11716,41 +8234,74 @@ func (mj *SendEmailAttachment) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	var obj []byte
 	_ = obj
 	_ = err
-	buf.WriteString(`{"FileType":`)
-	fflib.WriteJsonString(buf, string(mj.FileType))
-	buf.WriteString(`,"FileName":`)
-	fflib.WriteJsonString(buf, string(mj.FileName))
-	buf.WriteString(`,"Binary":`)
-	fflib.WriteJsonString(buf, string(mj.Binary))
+	buf.WriteString(`{ "cmd":`)
+	fflib.WriteJsonString(buf, string(mj.Cmd))
+	buf.WriteString(`,"Username":`)
+	fflib.WriteJsonString(buf, string(mj.Username))
+	buf.WriteString(`,"Questions":`)
+	if mj.Questions != nil {
+		buf.WriteString(`[`)
+		for i, v := range mj.Questions {
+			if i != 0 {
+				buf.WriteString(`,`)
+			}
+			fflib.WriteJsonString(buf, string(v))
+		}
+		buf.WriteString(`]`)
+	} else {
+		buf.WriteString(`null`)
+	}
+	buf.WriteByte(',')
+	if len(mj.Answers) != 0 {
+		buf.WriteString(`"Answers":`)
+		if mj.Answers != nil {
+			buf.WriteString(`[`)
+			for i, v := range mj.Answers {
+				if i != 0 {
+					buf.WriteString(`,`)
+				}
+				fflib.WriteJsonString(buf, string(v))
+			}
+			buf.WriteString(`]`)
+		} else {
+			buf.WriteString(`null`)
+		}
+		buf.WriteByte(',')
+	}
+	buf.Rewind(1)
 	buf.WriteByte('}')
 	return nil
 }
 
 const (
-	ffj_t_SendEmailAttachmentbase = iota
-	ffj_t_SendEmailAttachmentno_such_key
+	ffj_t_PasswordResetUserStructbase = iota
+	ffj_t_PasswordResetUserStructno_such_key
 
-	ffj_t_SendEmailAttachment_FileType
+	ffj_t_PasswordResetUserStruct_Cmd
 
-	ffj_t_SendEmailAttachment_FileName
+	ffj_t_PasswordResetUserStruct_Username
 
-	ffj_t_SendEmailAttachment_Binary
+	ffj_t_PasswordResetUserStruct_Questions
+
+	ffj_t_PasswordResetUserStruct_Answers
 )
 
-var ffj_key_SendEmailAttachment_FileType = []byte("FileType")
+var ffj_key_PasswordResetUserStruct_Cmd = []byte("cmd")
 
-var ffj_key_SendEmailAttachment_FileName = []byte("FileName")
+var ffj_key_PasswordResetUserStruct_Username = []byte("Username")
 
-var ffj_key_SendEmailAttachment_Binary = []byte("Binary")
+var ffj_key_PasswordResetUserStruct_Questions = []byte("Questions")
 
-func (uj *SendEmailAttachment) UnmarshalJSON(input []byte) error {
+var ffj_key_PasswordResetUserStruct_Answers = []byte("Answers")
+
+func (uj *PasswordResetUserStruct) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *SendEmailAttachment) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *PasswordResetUserStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_SendEmailAttachmentbase
+	currentKey := ffj_t_PasswordResetUserStructbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -11796,54 +8347,71 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_SendEmailAttachmentno_such_key
+				currentKey = ffj_t_PasswordResetUserStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
 				switch kn[0] {
 
-				case 'B':
+				case 'A':
 
-					if bytes.Equal(ffj_key_SendEmailAttachment_Binary, kn) {
-						currentKey = ffj_t_SendEmailAttachment_Binary
+					if bytes.Equal(ffj_key_PasswordResetUserStruct_Answers, kn) {
+						currentKey = ffj_t_PasswordResetUserStruct_Answers
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'F':
+				case 'Q':
 
-					if bytes.Equal(ffj_key_SendEmailAttachment_FileType, kn) {
-						currentKey = ffj_t_SendEmailAttachment_FileType
+					if bytes.Equal(ffj_key_PasswordResetUserStruct_Questions, kn) {
+						currentKey = ffj_t_PasswordResetUserStruct_Questions
 						state = fflib.FFParse_want_colon
 						goto mainparse
+					}
 
-					} else if bytes.Equal(ffj_key_SendEmailAttachment_FileName, kn) {
-						currentKey = ffj_t_SendEmailAttachment_FileName
+				case 'U':
+
+					if bytes.Equal(ffj_key_PasswordResetUserStruct_Username, kn) {
+						currentKey = ffj_t_PasswordResetUserStruct_Username
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'c':
+
+					if bytes.Equal(ffj_key_PasswordResetUserStruct_Cmd, kn) {
+						currentKey = ffj_t_PasswordResetUserStruct_Cmd
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_SendEmailAttachment_Binary, kn) {
-					currentKey = ffj_t_SendEmailAttachment_Binary
+				if fflib.EqualFoldRight(ffj_key_PasswordResetUserStruct_Answers, kn) {
+					currentKey = ffj_t_PasswordResetUserStruct_Answers
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_SendEmailAttachment_FileName, kn) {
-					currentKey = ffj_t_SendEmailAttachment_FileName
+				if fflib.EqualFoldRight(ffj_key_PasswordResetUserStruct_Questions, kn) {
+					currentKey = ffj_t_PasswordResetUserStruct_Questions
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_SendEmailAttachment_FileType, kn) {
-					currentKey = ffj_t_SendEmailAttachment_FileType
+				if fflib.EqualFoldRight(ffj_key_PasswordResetUserStruct_Username, kn) {
+					currentKey = ffj_t_PasswordResetUserStruct_Username
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_SendEmailAttachmentno_such_key
+				if fflib.SimpleLetterEqualFold(ffj_key_PasswordResetUserStruct_Cmd, kn) {
+					currentKey = ffj_t_PasswordResetUserStruct_Cmd
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				currentKey = ffj_t_PasswordResetUserStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -11860,16 +8428,19 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_SendEmailAttachment_FileType:
-					goto handle_FileType
+				case ffj_t_PasswordResetUserStruct_Cmd:
+					goto handle_Cmd
 
-				case ffj_t_SendEmailAttachment_FileName:
-					goto handle_FileName
+				case ffj_t_PasswordResetUserStruct_Username:
+					goto handle_Username
 
-				case ffj_t_SendEmailAttachment_Binary:
-					goto handle_Binary
+				case ffj_t_PasswordResetUserStruct_Questions:
+					goto handle_Questions
 
-				case ffj_t_SendEmailAttachmentno_such_key:
+				case ffj_t_PasswordResetUserStruct_Answers:
+					goto handle_Answers
+
+				case ffj_t_PasswordResetUserStructno_such_key:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -11883,9 +8454,35 @@ mainparse:
 		}
 	}
 
-handle_FileType:
+handle_Cmd:
+
+	/* handler: uj.Cmd type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			uj.Cmd = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
 
-	/* handler: uj.FileType type=string kind=string quoted=false*/
+handle_Username:
+
+	/* handler: uj.Username type=string kind=string quoted=false*/
 
 	{
 
@@ -11901,7 +8498,7 @@ handle_FileType:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.FileType = string(string(outBuf))
+			uj.Username = string(string(outBuf))
 
 		}
 	}
@@ -11909,52 +8506,146 @@ handle_FileType:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_FileName:
+handle_Questions:
 
-	/* handler: uj.FileName type=string kind=string quoted=false*/
+	/* handler: uj.Questions type=[]string kind=slice quoted=false*/
 
 	{
 
 		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
 			}
 		}
 
 		if tok == fflib.FFTok_null {
-
+			uj.Questions = nil
 		} else {
 
-			outBuf := fs.Output.Bytes()
+			uj.Questions = make([]string, 0)
 
-			uj.FileName = string(string(outBuf))
+			wantVal := true
+
+			for {
+
+				var v string
+
+				tok = fs.Scan()
+				if tok == fflib.FFTok_error {
+					goto tokerror
+				}
+				if tok == fflib.FFTok_right_brace {
+					break
+				}
+
+				if tok == fflib.FFTok_comma {
+					if wantVal == true {
+						// TODO(pquerna): this isn't an ideal error message, this handles
+						// things like [,,,] as an array value.
+						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+					}
+					continue
+				} else {
+					wantVal = true
+				}
+
+				/* handler: v type=string kind=string quoted=false*/
+
+				{
+
+					{
+						if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+							return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+						}
+					}
 
+					if tok == fflib.FFTok_null {
+
+					} else {
+
+						outBuf := fs.Output.Bytes()
+
+						v = string(string(outBuf))
+
+					}
+				}
+
+				uj.Questions = append(uj.Questions, v)
+				wantVal = false
+			}
 		}
 	}
 
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Binary:
+handle_Answers:
 
-	/* handler: uj.Binary type=string kind=string quoted=false*/
+	/* handler: uj.Answers type=[]string kind=slice quoted=false*/
 
 	{
 
 		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
 			}
 		}
 
 		if tok == fflib.FFTok_null {
-
+			uj.Answers = nil
 		} else {
 
-			outBuf := fs.Output.Bytes()
+			uj.Answers = make([]string, 0)
 
-			uj.Binary = string(string(outBuf))
+			wantVal := true
+
+			for {
+
+				var v string
+
+				tok = fs.Scan()
+				if tok == fflib.FFTok_error {
+					goto tokerror
+				}
+				if tok == fflib.FFTok_right_brace {
+					break
+				}
+
+				if tok == fflib.FFTok_comma {
+					if wantVal == true {
+						// TODO(pquerna): this isn't an ideal error message, this handles
+						// things like [,,,] as an array value.
+						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+					}
+					continue
+				} else {
+					wantVal = true
+				}
+
+				/* handler: v type=string kind=string quoted=false*/
+
+				{
+
+					{
+						if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+							return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+						}
+					}
+
+					if tok == fflib.FFTok_null {
+
+					} else {
+
+						outBuf := fs.Output.Bytes()
+
+						v = string(string(outBuf))
+
+					}
+				}
 
+				uj.Answers = append(uj.Answers, v)
+				wantVal = false
+			}
 		}
 	}
 
@@ -11978,7 +8669,7 @@ done:
 	return nil
 }
 
-func (mj *SendEmailCmdStruct) MarshalJSON() ([]byte, error) {
+func (mj *QuotaCmdStruct) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -11990,7 +8681,7 @@ func (mj *SendEmailCmdStruct) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *SendEmailCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *QuotaCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
@@ -12001,93 +8692,45 @@ func (mj *SendEmailCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	_ = err
 	buf.WriteString(`{"cmd":`)
 	fflib.WriteJsonString(buf, string(mj.Cmd))
-	buf.WriteString(`,"FromEmail":`)
-	fflib.WriteJsonString(buf, string(mj.FromEmail))
-	buf.WriteString(`,"ToEmails":`)
-	if mj.ToEmails != nil {
-		buf.WriteString(`[`)
-		for i, v := range mj.ToEmails {
-			if i != 0 {
-				buf.WriteString(`,`)
-			}
-			fflib.WriteJsonString(buf, string(v))
-		}
-		buf.WriteString(`]`)
-	} else {
-		buf.WriteString(`null`)
-	}
-	buf.WriteString(`,"Subject":`)
-	fflib.WriteJsonString(buf, string(mj.Subject))
-	buf.WriteString(`,"Content":`)
-	fflib.WriteJsonString(buf, string(mj.Content))
-	buf.WriteString(`,"Attachments":`)
-	if mj.Attachments != nil {
-		buf.WriteString(`[`)
-		for i, v := range mj.Attachments {
-			if i != 0 {
-				buf.WriteString(`,`)
-			}
-
-			{
-
-				err = v.MarshalJSONBuf(buf)
-				if err != nil {
-					return err
-				}
-
-			}
-		}
-		buf.WriteString(`]`)
-	} else {
-		buf.WriteString(`null`)
-	}
-	buf.WriteString(`,"M_time":`)
-	fflib.WriteJsonString(buf, string(mj.M_time))
+	buf.WriteString(`,"Username":`)
+	fflib.WriteJsonString(buf, string(mj.Username))
+	buf.WriteString(`,"Quota":`)
+	fflib.FormatBits2(buf, uint64(mj.Quota), 10, false)
+	buf.WriteString(`,"QuotaUsed":`)
+	fflib.FormatBits2(buf, uint64(mj.QuotaUsed), 10, false)
 	buf.WriteByte('}')
 	return nil
 }
 
 const (
-	ffj_t_SendEmailCmdStructbase = iota
-	ffj_t_SendEmailCmdStructno_such_key
-
-	ffj_t_SendEmailCmdStruct_Cmd
-
-	ffj_t_SendEmailCmdStruct_FromEmail
-
-	ffj_t_SendEmailCmdStruct_ToEmails
+	ffj_t_QuotaCmdStructbase = iota
+	ffj_t_QuotaCmdStructno_such_key
 
-	ffj_t_SendEmailCmdStruct_Subject
+	ffj_t_QuotaCmdStruct_Cmd
 
-	ffj_t_SendEmailCmdStruct_Content
+	ffj_t_QuotaCmdStruct_Username
 
-	ffj_t_SendEmailCmdStruct_Attachments
+	ffj_t_QuotaCmdStruct_Quota
 
-	ffj_t_SendEmailCmdStruct_M_time
+	ffj_t_QuotaCmdStruct_QuotaUsed
 )
 
-var ffj_key_SendEmailCmdStruct_Cmd = []byte("cmd")
-
-var ffj_key_SendEmailCmdStruct_FromEmail = []byte("FromEmail")
-
-var ffj_key_SendEmailCmdStruct_ToEmails = []byte("ToEmails")
-
-var ffj_key_SendEmailCmdStruct_Subject = []byte("Subject")
+var ffj_key_QuotaCmdStruct_Cmd = []byte("cmd")
 
-var ffj_key_SendEmailCmdStruct_Content = []byte("Content")
+var ffj_key_QuotaCmdStruct_Username = []byte("Username")
 
-var ffj_key_SendEmailCmdStruct_Attachments = []byte("Attachments")
+var ffj_key_QuotaCmdStruct_Quota = []byte("Quota")
 
-var ffj_key_SendEmailCmdStruct_M_time = []byte("M_time")
+var ffj_key_QuotaCmdStruct_QuotaUsed = []byte("QuotaUsed")
 
-func (uj *SendEmailCmdStruct) UnmarshalJSON(input []byte) error {
+func (uj *QuotaCmdStruct) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *SendEmailCmdStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *QuotaCmdStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_SendEmailCmdStructbase
+	currentKey := ffj_t_QuotaCmdStructbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -12133,113 +8776,68 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_SendEmailCmdStructno_such_key
+				currentKey = ffj_t_QuotaCmdStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
 				switch kn[0] {
 
-				case 'A':
-
-					if bytes.Equal(ffj_key_SendEmailCmdStruct_Attachments, kn) {
-						currentKey = ffj_t_SendEmailCmdStruct_Attachments
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'C':
-
-					if bytes.Equal(ffj_key_SendEmailCmdStruct_Content, kn) {
-						currentKey = ffj_t_SendEmailCmdStruct_Content
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'F':
-
-					if bytes.Equal(ffj_key_SendEmailCmdStruct_FromEmail, kn) {
-						currentKey = ffj_t_SendEmailCmdStruct_FromEmail
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'M':
+				case 'Q':
 
-					if bytes.Equal(ffj_key_SendEmailCmdStruct_M_time, kn) {
-						currentKey = ffj_t_SendEmailCmdStruct_M_time
+					if bytes.Equal(ffj_key_QuotaCmdStruct_Quota, kn) {
+						currentKey = ffj_t_QuotaCmdStruct_Quota
 						state = fflib.FFParse_want_colon
 						goto mainparse
-					}
-
-				case 'S':
 
-					if bytes.Equal(ffj_key_SendEmailCmdStruct_Subject, kn) {
-						currentKey = ffj_t_SendEmailCmdStruct_Subject
+					} else if bytes.Equal(ffj_key_QuotaCmdStruct_QuotaUsed, kn) {
+						currentKey = ffj_t_QuotaCmdStruct_QuotaUsed
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'T':
+				case 'U':
 
-					if bytes.Equal(ffj_key_SendEmailCmdStruct_ToEmails, kn) {
-						currentKey = ffj_t_SendEmailCmdStruct_ToEmails
+					if bytes.Equal(ffj_key_QuotaCmdStruct_Username, kn) {
+						currentKey = ffj_t_QuotaCmdStruct_Username
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'c':
 
-					if bytes.Equal(ffj_key_SendEmailCmdStruct_Cmd, kn) {
-						currentKey = ffj_t_SendEmailCmdStruct_Cmd
+					if bytes.Equal(ffj_key_QuotaCmdStruct_Cmd, kn) {
+						currentKey = ffj_t_QuotaCmdStruct_Cmd
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.AsciiEqualFold(ffj_key_SendEmailCmdStruct_M_time, kn) {
-					currentKey = ffj_t_SendEmailCmdStruct_M_time
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_SendEmailCmdStruct_Attachments, kn) {
-					currentKey = ffj_t_SendEmailCmdStruct_Attachments
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_SendEmailCmdStruct_Content, kn) {
-					currentKey = ffj_t_SendEmailCmdStruct_Content
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_SendEmailCmdStruct_Subject, kn) {
-					currentKey = ffj_t_SendEmailCmdStruct_Subject
+				if fflib.EqualFoldRight(ffj_key_QuotaCmdStruct_QuotaUsed, kn) {
+					currentKey = ffj_t_QuotaCmdStruct_QuotaUsed
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_SendEmailCmdStruct_ToEmails, kn) {
-					currentKey = ffj_t_SendEmailCmdStruct_ToEmails
+				if fflib.SimpleLetterEqualFold(ffj_key_QuotaCmdStruct_Quota, kn) {
+					currentKey = ffj_t_QuotaCmdStruct_Quota
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_SendEmailCmdStruct_FromEmail, kn) {
-					currentKey = ffj_t_SendEmailCmdStruct_FromEmail
+				if fflib.EqualFoldRight(ffj_key_QuotaCmdStruct_Username, kn) {
+					currentKey = ffj_t_QuotaCmdStruct_Username
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_SendEmailCmdStruct_Cmd, kn) {
-					currentKey = ffj_t_SendEmailCmdStruct_Cmd
+				if fflib.SimpleLetterEqualFold(ffj_key_QuotaCmdStruct_Cmd, kn) {
+					currentKey = ffj_t_QuotaCmdStruct_Cmd
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_SendEmailCmdStructno_such_key
+				currentKey = ffj_t_QuotaCmdStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -12256,28 +8854,19 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_SendEmailCmdStruct_Cmd:
+				case ffj_t_QuotaCmdStruct_Cmd:
 					goto handle_Cmd
 
-				case ffj_t_SendEmailCmdStruct_FromEmail:
-					goto handle_FromEmail
-
-				case ffj_t_SendEmailCmdStruct_ToEmails:
-					goto handle_ToEmails
-
-				case ffj_t_SendEmailCmdStruct_Subject:
-					goto handle_Subject
-
-				case ffj_t_SendEmailCmdStruct_Content:
-					goto handle_Content
+				case ffj_t_QuotaCmdStruct_Username:
+					goto handle_Username
 
-				case ffj_t_SendEmailCmdStruct_Attachments:
-					goto handle_Attachments
+				case ffj_t_QuotaCmdStruct_Quota:
+					goto handle_Quota
 
-				case ffj_t_SendEmailCmdStruct_M_time:
-					goto handle_M_time
+				case ffj_t_QuotaCmdStruct_QuotaUsed:
+					goto handle_QuotaUsed
 
-				case ffj_t_SendEmailCmdStructno_such_key:
+				case ffj_t_QuotaCmdStructno_such_key:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -12317,134 +8906,9 @@ handle_Cmd:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_FromEmail:
-
-	/* handler: uj.FromEmail type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.FromEmail = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_ToEmails:
-
-	/* handler: uj.ToEmails type=[]string kind=slice quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-			uj.ToEmails = nil
-		} else {
-
-			uj.ToEmails = make([]string, 0)
-
-			wantVal := true
-
-			for {
-
-				var v string
-
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
-				}
-				if tok == fflib.FFTok_right_brace {
-					break
-				}
-
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
-				}
-
-				/* handler: v type=string kind=string quoted=false*/
-
-				{
-
-					{
-						if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-							return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-						}
-					}
-
-					if tok == fflib.FFTok_null {
-
-					} else {
-
-						outBuf := fs.Output.Bytes()
-
-						v = string(string(outBuf))
-
-					}
-				}
-
-				uj.ToEmails = append(uj.ToEmails, v)
-				wantVal = false
-			}
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Subject:
-
-	/* handler: uj.Subject type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Subject = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Content:
+handle_Username:
 
-	/* handler: uj.Content type=string kind=string quoted=false*/
+	/* handler: uj.Username type=string kind=string quoted=false*/
 
 	{
 
@@ -12460,7 +8924,7 @@ handle_Content:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Content = string(string(outBuf))
+			uj.Username = string(string(outBuf))
 
 		}
 	}
@@ -12468,93 +8932,59 @@ handle_Content:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Attachments:
-
-	/* handler: uj.Attachments type=[]pcDatabase.SendEmailAttachment kind=slice quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-			uj.Attachments = nil
-		} else {
-
-			uj.Attachments = make([]SendEmailAttachment, 0)
-
-			wantVal := true
-
-			for {
-
-				var v SendEmailAttachment
+handle_Quota:
 
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
-				}
-				if tok == fflib.FFTok_right_brace {
-					break
-				}
+	/* handler: uj.Quota type=uint32 kind=uint32 quoted=false*/
 
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
-				}
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for uint32", tok))
+		}
+	}
 
-				/* handler: v type=pcDatabase.SendEmailAttachment kind=struct quoted=false*/
+	{
 
-				{
-					if tok == fflib.FFTok_null {
+		if tok == fflib.FFTok_null {
 
-						state = fflib.FFParse_after_value
-						goto mainparse
-					}
+		} else {
 
-					err = v.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
-					if err != nil {
-						return err
-					}
-					state = fflib.FFParse_after_value
-				}
+			tval, err := fflib.ParseUint(fs.Output.Bytes(), 10, 32)
 
-				uj.Attachments = append(uj.Attachments, v)
-				wantVal = false
+			if err != nil {
+				return fs.WrapErr(err)
 			}
+
+			uj.Quota = uint32(tval)
+
 		}
 	}
 
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_M_time:
+handle_QuotaUsed:
 
-	/* handler: uj.M_time type=string kind=string quoted=false*/
+	/* handler: uj.QuotaUsed type=uint32 kind=uint32 quoted=false*/
 
 	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for uint32", tok))
 		}
+	}
+
+	{
 
 		if tok == fflib.FFTok_null {
 
 		} else {
 
-			outBuf := fs.Output.Bytes()
+			tval, err := fflib.ParseUint(fs.Output.Bytes(), 10, 32)
 
-			uj.M_time = string(string(outBuf))
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			uj.QuotaUsed = uint32(tval)
 
 		}
 	}
@@ -12579,7 +9009,7 @@ done:
 	return nil
 }
 
-func (mj *UpdateEmailCmdStruct) MarshalJSON() ([]byte, error) {
+func (mj *ScheduledMessagesCmdStruct) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -12591,7 +9021,7 @@ func (mj *UpdateEmailCmdStruct) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *UpdateEmailCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *ScheduledMessagesCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
@@ -12604,139 +9034,49 @@ func (mj *UpdateEmailCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	fflib.WriteJsonString(buf, string(mj.Cmd))
 	buf.WriteString(`,"Username":`)
 	fflib.WriteJsonString(buf, string(mj.Username))
-	buf.WriteString(`,"FromEmail":`)
-	fflib.WriteJsonString(buf, string(mj.FromEmail))
-	buf.WriteString(`,"ToEmails":`)
-	if mj.ToEmails != nil {
-		buf.WriteString(`[`)
-		for i, v := range mj.ToEmails {
-			if i != 0 {
-				buf.WriteString(`,`)
-			}
-			fflib.WriteJsonString(buf, string(v))
-		}
-		buf.WriteString(`]`)
-	} else {
-		buf.WriteString(`null`)
-	}
-	buf.WriteString(`,"Subject":`)
-	fflib.WriteJsonString(buf, string(mj.Subject))
-	buf.WriteString(`,"Attachments":`)
-	if mj.Attachments != nil {
-		buf.WriteString(`[`)
-		for i, v := range mj.Attachments {
-			if i != 0 {
-				buf.WriteString(`,`)
-			}
-
-			{
-
-				err = v.MarshalJSONBuf(buf)
-				if err != nil {
-					return err
-				}
-
-			}
-		}
-		buf.WriteString(`]`)
-	} else {
-		buf.WriteString(`null`)
-	}
+	buf.WriteString(`,"CID":`)
+	fflib.WriteJsonString(buf, string(mj.CID))
+	buf.WriteString(`,"Time":`)
+	fflib.WriteJsonString(buf, string(mj.Time))
 	buf.WriteString(`,"Content":`)
 	fflib.WriteJsonString(buf, string(mj.Content))
-	buf.WriteString(`,"RecvTime":`)
-	fflib.WriteJsonString(buf, string(mj.RecvTime))
-	buf.WriteString(`,"EmailMtime":`)
-	fflib.WriteJsonString(buf, string(mj.EmailMtime))
-	if mj.Unread {
-		buf.WriteString(`,"Unread":true`)
-	} else {
-		buf.WriteString(`,"Unread":false`)
-	}
-	if mj.Spam {
-		buf.WriteString(`,"Spam":true`)
-	} else {
-		buf.WriteString(`,"Spam":false`)
-	}
-	if mj.Starred {
-		buf.WriteString(`,"Starred":true`)
-	} else {
-		buf.WriteString(`,"Starred":false`)
-	}
-	if mj.Deleted {
-		buf.WriteString(`,"Deleted":true`)
-	} else {
-		buf.WriteString(`,"Deleted":false`)
-	}
 	buf.WriteByte('}')
 	return nil
 }
 
 const (
-	ffj_t_UpdateEmailCmdStructbase = iota
-	ffj_t_UpdateEmailCmdStructno_such_key
-
-	ffj_t_UpdateEmailCmdStruct_Cmd
-
-	ffj_t_UpdateEmailCmdStruct_Username
-
-	ffj_t_UpdateEmailCmdStruct_FromEmail
-
-	ffj_t_UpdateEmailCmdStruct_ToEmails
-
-	ffj_t_UpdateEmailCmdStruct_Subject
-
-	ffj_t_UpdateEmailCmdStruct_Attachments
-
-	ffj_t_UpdateEmailCmdStruct_Content
-
-	ffj_t_UpdateEmailCmdStruct_RecvTime
+	ffj_t_ScheduledMessagesCmdStructbase = iota
+	ffj_t_ScheduledMessagesCmdStructno_such_key
 
-	ffj_t_UpdateEmailCmdStruct_EmailMtime
+	ffj_t_ScheduledMessagesCmdStruct_Cmd
 
-	ffj_t_UpdateEmailCmdStruct_Unread
+	ffj_t_ScheduledMessagesCmdStruct_Username
 
-	ffj_t_UpdateEmailCmdStruct_Spam
+	ffj_t_ScheduledMessagesCmdStruct_CID
 
-	ffj_t_UpdateEmailCmdStruct_Starred
+	ffj_t_ScheduledMessagesCmdStruct_Time
 
-	ffj_t_UpdateEmailCmdStruct_Deleted
+	ffj_t_ScheduledMessagesCmdStruct_Content
 )
 
-var ffj_key_UpdateEmailCmdStruct_Cmd = []byte("cmd")
-
-var ffj_key_UpdateEmailCmdStruct_Username = []byte("Username")
-
-var ffj_key_UpdateEmailCmdStruct_FromEmail = []byte("FromEmail")
-
-var ffj_key_UpdateEmailCmdStruct_ToEmails = []byte("ToEmails")
-
-var ffj_key_UpdateEmailCmdStruct_Subject = []byte("Subject")
-
-var ffj_key_UpdateEmailCmdStruct_Attachments = []byte("Attachments")
-
-var ffj_key_UpdateEmailCmdStruct_Content = []byte("Content")
-
-var ffj_key_UpdateEmailCmdStruct_RecvTime = []byte("RecvTime")
-
-var ffj_key_UpdateEmailCmdStruct_EmailMtime = []byte("EmailMtime")
+var ffj_key_ScheduledMessagesCmdStruct_Cmd = []byte("cmd")
 
-var ffj_key_UpdateEmailCmdStruct_Unread = []byte("Unread")
+var ffj_key_ScheduledMessagesCmdStruct_Username = []byte("Username")
 
-var ffj_key_UpdateEmailCmdStruct_Spam = []byte("Spam")
+var ffj_key_ScheduledMessagesCmdStruct_CID = []byte("CID")
 
-var ffj_key_UpdateEmailCmdStruct_Starred = []byte("Starred")
+var ffj_key_ScheduledMessagesCmdStruct_Time = []byte("Time")
 
-var ffj_key_UpdateEmailCmdStruct_Deleted = []byte("Deleted")
+var ffj_key_ScheduledMessagesCmdStruct_Content = []byte("Content")
 
-func (uj *UpdateEmailCmdStruct) UnmarshalJSON(input []byte) error {
+func (uj *ScheduledMessagesCmdStruct) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *UpdateEmailCmdStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *ScheduledMessagesCmdStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_UpdateEmailCmdStructbase
+	currentKey := ffj_t_ScheduledMessagesCmdStructbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -12782,188 +9122,82 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_UpdateEmailCmdStructno_such_key
+				currentKey = ffj_t_ScheduledMessagesCmdStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
 				switch kn[0] {
 
-				case 'A':
-
-					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Attachments, kn) {
-						currentKey = ffj_t_UpdateEmailCmdStruct_Attachments
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
 				case 'C':
 
-					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Content, kn) {
-						currentKey = ffj_t_UpdateEmailCmdStruct_Content
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'D':
-
-					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Deleted, kn) {
-						currentKey = ffj_t_UpdateEmailCmdStruct_Deleted
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'E':
-
-					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_EmailMtime, kn) {
-						currentKey = ffj_t_UpdateEmailCmdStruct_EmailMtime
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'F':
-
-					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_FromEmail, kn) {
-						currentKey = ffj_t_UpdateEmailCmdStruct_FromEmail
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'R':
-
-					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_RecvTime, kn) {
-						currentKey = ffj_t_UpdateEmailCmdStruct_RecvTime
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'S':
-
-					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Subject, kn) {
-						currentKey = ffj_t_UpdateEmailCmdStruct_Subject
-						state = fflib.FFParse_want_colon
-						goto mainparse
-
-					} else if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Spam, kn) {
-						currentKey = ffj_t_UpdateEmailCmdStruct_Spam
+					if bytes.Equal(ffj_key_ScheduledMessagesCmdStruct_CID, kn) {
+						currentKey = ffj_t_ScheduledMessagesCmdStruct_CID
 						state = fflib.FFParse_want_colon
 						goto mainparse
 
-					} else if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Starred, kn) {
-						currentKey = ffj_t_UpdateEmailCmdStruct_Starred
+					} else if bytes.Equal(ffj_key_ScheduledMessagesCmdStruct_Content, kn) {
+						currentKey = ffj_t_ScheduledMessagesCmdStruct_Content
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'T':
 
-					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_ToEmails, kn) {
-						currentKey = ffj_t_UpdateEmailCmdStruct_ToEmails
+					if bytes.Equal(ffj_key_ScheduledMessagesCmdStruct_Time, kn) {
+						currentKey = ffj_t_ScheduledMessagesCmdStruct_Time
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'U':
 
-					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Username, kn) {
-						currentKey = ffj_t_UpdateEmailCmdStruct_Username
-						state = fflib.FFParse_want_colon
-						goto mainparse
-
-					} else if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Unread, kn) {
-						currentKey = ffj_t_UpdateEmailCmdStruct_Unread
+					if bytes.Equal(ffj_key_ScheduledMessagesCmdStruct_Username, kn) {
+						currentKey = ffj_t_ScheduledMessagesCmdStruct_Username
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'c':
 
-					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Cmd, kn) {
-						currentKey = ffj_t_UpdateEmailCmdStruct_Cmd
+					if bytes.Equal(ffj_key_ScheduledMessagesCmdStruct_Cmd, kn) {
+						currentKey = ffj_t_ScheduledMessagesCmdStruct_Cmd
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_UpdateEmailCmdStruct_Deleted, kn) {
-					currentKey = ffj_t_UpdateEmailCmdStruct_Deleted
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_UpdateEmailCmdStruct_Starred, kn) {
-					currentKey = ffj_t_UpdateEmailCmdStruct_Starred
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_UpdateEmailCmdStruct_Spam, kn) {
-					currentKey = ffj_t_UpdateEmailCmdStruct_Spam
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_UpdateEmailCmdStruct_Unread, kn) {
-					currentKey = ffj_t_UpdateEmailCmdStruct_Unread
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_UpdateEmailCmdStruct_EmailMtime, kn) {
-					currentKey = ffj_t_UpdateEmailCmdStruct_EmailMtime
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_UpdateEmailCmdStruct_RecvTime, kn) {
-					currentKey = ffj_t_UpdateEmailCmdStruct_RecvTime
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_UpdateEmailCmdStruct_Content, kn) {
-					currentKey = ffj_t_UpdateEmailCmdStruct_Content
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_UpdateEmailCmdStruct_Attachments, kn) {
-					currentKey = ffj_t_UpdateEmailCmdStruct_Attachments
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_UpdateEmailCmdStruct_Subject, kn) {
-					currentKey = ffj_t_UpdateEmailCmdStruct_Subject
+				if fflib.SimpleLetterEqualFold(ffj_key_ScheduledMessagesCmdStruct_Content, kn) {
+					currentKey = ffj_t_ScheduledMessagesCmdStruct_Content
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_UpdateEmailCmdStruct_ToEmails, kn) {
-					currentKey = ffj_t_UpdateEmailCmdStruct_ToEmails
+				if fflib.SimpleLetterEqualFold(ffj_key_ScheduledMessagesCmdStruct_Time, kn) {
+					currentKey = ffj_t_ScheduledMessagesCmdStruct_Time
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_UpdateEmailCmdStruct_FromEmail, kn) {
-					currentKey = ffj_t_UpdateEmailCmdStruct_FromEmail
+				if fflib.SimpleLetterEqualFold(ffj_key_ScheduledMessagesCmdStruct_CID, kn) {
+					currentKey = ffj_t_ScheduledMessagesCmdStruct_CID
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_UpdateEmailCmdStruct_Username, kn) {
-					currentKey = ffj_t_UpdateEmailCmdStruct_Username
+				if fflib.EqualFoldRight(ffj_key_ScheduledMessagesCmdStruct_Username, kn) {
+					currentKey = ffj_t_ScheduledMessagesCmdStruct_Username
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_UpdateEmailCmdStruct_Cmd, kn) {
-					currentKey = ffj_t_UpdateEmailCmdStruct_Cmd
+				if fflib.SimpleLetterEqualFold(ffj_key_ScheduledMessagesCmdStruct_Cmd, kn) {
+					currentKey = ffj_t_ScheduledMessagesCmdStruct_Cmd
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_UpdateEmailCmdStructno_such_key
+				currentKey = ffj_t_ScheduledMessagesCmdStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -12980,46 +9214,22 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_UpdateEmailCmdStruct_Cmd:
+				case ffj_t_ScheduledMessagesCmdStruct_Cmd:
 					goto handle_Cmd
 
-				case ffj_t_UpdateEmailCmdStruct_Username:
+				case ffj_t_ScheduledMessagesCmdStruct_Username:
 					goto handle_Username
 
-				case ffj_t_UpdateEmailCmdStruct_FromEmail:
-					goto handle_FromEmail
-
-				case ffj_t_UpdateEmailCmdStruct_ToEmails:
-					goto handle_ToEmails
-
-				case ffj_t_UpdateEmailCmdStruct_Subject:
-					goto handle_Subject
+				case ffj_t_ScheduledMessagesCmdStruct_CID:
+					goto handle_CID
 
-				case ffj_t_UpdateEmailCmdStruct_Attachments:
-					goto handle_Attachments
+				case ffj_t_ScheduledMessagesCmdStruct_Time:
+					goto handle_Time
 
-				case ffj_t_UpdateEmailCmdStruct_Content:
+				case ffj_t_ScheduledMessagesCmdStruct_Content:
 					goto handle_Content
 
-				case ffj_t_UpdateEmailCmdStruct_RecvTime:
-					goto handle_RecvTime
-
-				case ffj_t_UpdateEmailCmdStruct_EmailMtime:
-					goto handle_EmailMtime
-
-				case ffj_t_UpdateEmailCmdStruct_Unread:
-					goto handle_Unread
-
-				case ffj_t_UpdateEmailCmdStruct_Spam:
-					goto handle_Spam
-
-				case ffj_t_UpdateEmailCmdStruct_Starred:
-					goto handle_Starred
-
-				case ffj_t_UpdateEmailCmdStruct_Deleted:
-					goto handle_Deleted
-
-				case ffj_t_UpdateEmailCmdStructno_such_key:
+				case ffj_t_ScheduledMessagesCmdStructno_such_key:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -13085,9 +9295,9 @@ handle_Username:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_FromEmail:
+handle_CID:
 
-	/* handler: uj.FromEmail type=string kind=string quoted=false*/
+	/* handler: uj.CID type=string kind=string quoted=false*/
 
 	{
 
@@ -13103,90 +9313,274 @@ handle_FromEmail:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.FromEmail = string(string(outBuf))
+			uj.CID = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Time:
+
+	/* handler: uj.Time type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			uj.Time = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Content:
+
+	/* handler: uj.Content type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			uj.Content = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+wantedvalue:
+	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+wrongtokenerror:
+	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
+tokerror:
+	if fs.BigError != nil {
+		return fs.WrapErr(fs.BigError)
+	}
+	err = fs.Error.ToError()
+	if err != nil {
+		return fs.WrapErr(err)
+	}
+	panic("ffjson-generated: unreachable, please report bug.")
+done:
+	return nil
+}
+
+func (mj *ScheduledMessagesStruct) MarshalJSON() ([]byte, error) {
+	var buf fflib.Buffer
+	if mj == nil {
+		buf.WriteString("null")
+		return buf.Bytes(), nil
+	}
+	err := mj.MarshalJSONBuf(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+func (mj *ScheduledMessagesStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+	if mj == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	var err error
+	var obj []byte
+	_ = obj
+	_ = err
+	buf.WriteString(`{"CID":`)
+	fflib.WriteJsonString(buf, string(mj.CID))
+	buf.WriteString(`,"Time":`)
+	fflib.WriteJsonString(buf, string(mj.Time))
+	buf.WriteString(`,"Content":`)
+	fflib.WriteJsonString(buf, string(mj.Content))
+	buf.WriteByte('}')
+	return nil
+}
+
+const (
+	ffj_t_ScheduledMessagesStructbase = iota
+	ffj_t_ScheduledMessagesStructno_such_key
+
+	ffj_t_ScheduledMessagesStruct_CID
+
+	ffj_t_ScheduledMessagesStruct_Time
+
+	ffj_t_ScheduledMessagesStruct_Content
+)
+
+var ffj_key_ScheduledMessagesStruct_CID = []byte("CID")
+
+var ffj_key_ScheduledMessagesStruct_Time = []byte("Time")
+
+var ffj_key_ScheduledMessagesStruct_Content = []byte("Content")
+
+func (uj *ScheduledMessagesStruct) UnmarshalJSON(input []byte) error {
+	fs := fflib.NewFFLexer(input)
+	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
+}
+
+func (uj *ScheduledMessagesStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+	var err error = nil
+	currentKey := ffj_t_ScheduledMessagesStructbase
+	_ = currentKey
+	tok := fflib.FFTok_init
+	wantedTok := fflib.FFTok_init
 
+mainparse:
+	for {
+		tok = fs.Scan()
+		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
+		if tok == fflib.FFTok_error {
+			goto tokerror
 		}
-	}
 
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_ToEmails:
+		switch state {
 
-	/* handler: uj.ToEmails type=[]string kind=slice quoted=false*/
+		case fflib.FFParse_map_start:
+			if tok != fflib.FFTok_left_bracket {
+				wantedTok = fflib.FFTok_left_bracket
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_key
+			continue
 
-	{
+		case fflib.FFParse_after_value:
+			if tok == fflib.FFTok_comma {
+				state = fflib.FFParse_want_key
+			} else if tok == fflib.FFTok_right_bracket {
+				goto done
+			} else {
+				wantedTok = fflib.FFTok_comma
+				goto wrongtokenerror
+			}
 
-		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
+		case fflib.FFParse_want_key:
+			// json {} ended. goto exit. woo.
+			if tok == fflib.FFTok_right_bracket {
+				goto done
+			}
+			if tok != fflib.FFTok_string {
+				wantedTok = fflib.FFTok_string
+				goto wrongtokenerror
 			}
-		}
 
-		if tok == fflib.FFTok_null {
-			uj.ToEmails = nil
-		} else {
+			kn := fs.Output.Bytes()
+			if len(kn) <= 0 {
+				// "" case. hrm.
+				currentKey = ffj_t_ScheduledMessagesStructno_such_key
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			} else {
+				switch kn[0] {
 
-			uj.ToEmails = make([]string, 0)
+				case 'C':
 
-			wantVal := true
+					if bytes.Equal(ffj_key_ScheduledMessagesStruct_CID, kn) {
+						currentKey = ffj_t_ScheduledMessagesStruct_CID
+						state = fflib.FFParse_want_colon
+						goto mainparse
 
-			for {
+					} else if bytes.Equal(ffj_key_ScheduledMessagesStruct_Content, kn) {
+						currentKey = ffj_t_ScheduledMessagesStruct_Content
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
 
-				var v string
+				case 'T':
+
+					if bytes.Equal(ffj_key_ScheduledMessagesStruct_Time, kn) {
+						currentKey = ffj_t_ScheduledMessagesStruct_Time
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
 
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
 				}
-				if tok == fflib.FFTok_right_brace {
-					break
+
+				if fflib.SimpleLetterEqualFold(ffj_key_ScheduledMessagesStruct_Content, kn) {
+					currentKey = ffj_t_ScheduledMessagesStruct_Content
+					state = fflib.FFParse_want_colon
+					goto mainparse
 				}
 
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
+				if fflib.SimpleLetterEqualFold(ffj_key_ScheduledMessagesStruct_Time, kn) {
+					currentKey = ffj_t_ScheduledMessagesStruct_Time
+					state = fflib.FFParse_want_colon
+					goto mainparse
 				}
 
-				/* handler: v type=string kind=string quoted=false*/
+				if fflib.SimpleLetterEqualFold(ffj_key_ScheduledMessagesStruct_CID, kn) {
+					currentKey = ffj_t_ScheduledMessagesStruct_CID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
 
-				{
+				currentKey = ffj_t_ScheduledMessagesStructno_such_key
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			}
 
-					{
-						if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-							return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-						}
-					}
+		case fflib.FFParse_want_colon:
+			if tok != fflib.FFTok_colon {
+				wantedTok = fflib.FFTok_colon
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_value
+			continue
+		case fflib.FFParse_want_value:
 
-					if tok == fflib.FFTok_null {
+			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
+				switch currentKey {
 
-					} else {
+				case ffj_t_ScheduledMessagesStruct_CID:
+					goto handle_CID
 
-						outBuf := fs.Output.Bytes()
+				case ffj_t_ScheduledMessagesStruct_Time:
+					goto handle_Time
 
-						v = string(string(outBuf))
+				case ffj_t_ScheduledMessagesStruct_Content:
+					goto handle_Content
 
+				case ffj_t_ScheduledMessagesStructno_such_key:
+					err = fs.SkipField(tok)
+					if err != nil {
+						return fs.WrapErr(err)
 					}
+					state = fflib.FFParse_after_value
+					goto mainparse
 				}
-
-				uj.ToEmails = append(uj.ToEmails, v)
-				wantVal = false
+			} else {
+				goto wantedvalue
 			}
 		}
 	}
 
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Subject:
+handle_CID:
 
-	/* handler: uj.Subject type=string kind=string quoted=false*/
+	/* handler: uj.CID type=string kind=string quoted=false*/
 
 	{
 
@@ -13202,7 +9596,7 @@ handle_Subject:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Subject = string(string(outBuf))
+			uj.CID = string(string(outBuf))
 
 		}
 	}
@@ -13210,68 +9604,26 @@ handle_Subject:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Attachments:
+handle_Time:
 
-	/* handler: uj.Attachments type=[]pcDatabase.SendEmailAttachment kind=slice quoted=false*/
+	/* handler: uj.Time type=string kind=string quoted=false*/
 
 	{
 
 		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
 			}
 		}
 
 		if tok == fflib.FFTok_null {
-			uj.Attachments = nil
-		} else {
-
-			uj.Attachments = make([]SendEmailAttachment, 0)
-
-			wantVal := true
-
-			for {
-
-				var v SendEmailAttachment
-
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
-				}
-				if tok == fflib.FFTok_right_brace {
-					break
-				}
-
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
-				}
-
-				/* handler: v type=pcDatabase.SendEmailAttachment kind=struct quoted=false*/
 
-				{
-					if tok == fflib.FFTok_null {
+		} else {
 
-						state = fflib.FFParse_after_value
-						goto mainparse
-					}
+			outBuf := fs.Output.Bytes()
 
-					err = v.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
-					if err != nil {
-						return err
-					}
-					state = fflib.FFParse_after_value
-				}
+			uj.Time = string(string(outBuf))
 
-				uj.Attachments = append(uj.Attachments, v)
-				wantVal = false
-			}
 		}
 	}
 
@@ -13304,156 +9656,210 @@ handle_Content:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_RecvTime:
+wantedvalue:
+	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+wrongtokenerror:
+	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
+tokerror:
+	if fs.BigError != nil {
+		return fs.WrapErr(fs.BigError)
+	}
+	err = fs.Error.ToError()
+	if err != nil {
+		return fs.WrapErr(err)
+	}
+	panic("ffjson-generated: unreachable, please report bug.")
+done:
+	return nil
+}
+
+func (mj *SecurityQuestionStruct) MarshalJSON() ([]byte, error) {
+	var buf fflib.Buffer
+	if mj == nil {
+		buf.WriteString("null")
+		return buf.Bytes(), nil
+	}
+	err := mj.MarshalJSONBuf(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+func (mj *SecurityQuestionStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+	if mj == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	var err error
+	var obj []byte
+	_ = obj
+	_ = err
+	buf.WriteString(`{"Question":`)
+	fflib.WriteJsonString(buf, string(mj.Question))
+	buf.WriteString(`,"Answer":`)
+	fflib.WriteJsonString(buf, string(mj.Answer))
+	buf.WriteByte('}')
+	return nil
+}
 
-	/* handler: uj.RecvTime type=string kind=string quoted=false*/
+const (
+	ffj_t_SecurityQuestionStructbase = iota
+	ffj_t_SecurityQuestionStructno_such_key
 
-	{
+	ffj_t_SecurityQuestionStruct_Question
 
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
+	ffj_t_SecurityQuestionStruct_Answer
+)
 
-		if tok == fflib.FFTok_null {
+var ffj_key_SecurityQuestionStruct_Question = []byte("Question")
 
-		} else {
+var ffj_key_SecurityQuestionStruct_Answer = []byte("Answer")
 
-			outBuf := fs.Output.Bytes()
+func (uj *SecurityQuestionStruct) UnmarshalJSON(input []byte) error {
+	fs := fflib.NewFFLexer(input)
+	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
+}
 
-			uj.RecvTime = string(string(outBuf))
+func (uj *SecurityQuestionStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+	var err error = nil
+	currentKey := ffj_t_SecurityQuestionStructbase
+	_ = currentKey
+	tok := fflib.FFTok_init
+	wantedTok := fflib.FFTok_init
 
+mainparse:
+	for {
+		tok = fs.Scan()
+		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
+		if tok == fflib.FFTok_error {
+			goto tokerror
 		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_EmailMtime:
-
-	/* handler: uj.EmailMtime type=string kind=string quoted=false*/
 
-	{
+		switch state {
 
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+		case fflib.FFParse_map_start:
+			if tok != fflib.FFTok_left_bracket {
+				wantedTok = fflib.FFTok_left_bracket
+				goto wrongtokenerror
 			}
-		}
+			state = fflib.FFParse_want_key
+			continue
 
-		if tok == fflib.FFTok_null {
+		case fflib.FFParse_after_value:
+			if tok == fflib.FFTok_comma {
+				state = fflib.FFParse_want_key
+			} else if tok == fflib.FFTok_right_bracket {
+				goto done
+			} else {
+				wantedTok = fflib.FFTok_comma
+				goto wrongtokenerror
+			}
 
-		} else {
+		case fflib.FFParse_want_key:
+			// json {} ended. goto exit. woo.
+			if tok == fflib.FFTok_right_bracket {
+				goto done
+			}
+			if tok != fflib.FFTok_string {
+				wantedTok = fflib.FFTok_string
+				goto wrongtokenerror
+			}
 
-			outBuf := fs.Output.Bytes()
+			kn := fs.Output.Bytes()
+			if len(kn) <= 0 {
+				// "" case. hrm.
+				currentKey = ffj_t_SecurityQuestionStructno_such_key
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			} else {
+				switch kn[0] {
 
-			uj.EmailMtime = string(string(outBuf))
+				case 'A':
 
-		}
-	}
+					if bytes.Equal(ffj_key_SecurityQuestionStruct_Answer, kn) {
+						currentKey = ffj_t_SecurityQuestionStruct_Answer
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
 
-	state = fflib.FFParse_after_value
-	goto mainparse
+				case 'Q':
 
-handle_Unread:
+					if bytes.Equal(ffj_key_SecurityQuestionStruct_Question, kn) {
+						currentKey = ffj_t_SecurityQuestionStruct_Question
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
 
-	/* handler: uj.Unread type=bool kind=bool quoted=false*/
+				}
 
-	{
-		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
-		}
-	}
+				if fflib.EqualFoldRight(ffj_key_SecurityQuestionStruct_Answer, kn) {
+					currentKey = ffj_t_SecurityQuestionStruct_Answer
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
 
-	{
-		if tok == fflib.FFTok_null {
+				if fflib.EqualFoldRight(ffj_key_SecurityQuestionStruct_Question, kn) {
+					currentKey = ffj_t_SecurityQuestionStruct_Question
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
 
-		} else {
-			tmpb := fs.Output.Bytes()
+				currentKey = ffj_t_SecurityQuestionStructno_such_key
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			}
 
-			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
+		case fflib.FFParse_want_colon:
+			if tok != fflib.FFTok_colon {
+				wantedTok = fflib.FFTok_colon
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_value
+			continue
+		case fflib.FFParse_want_value:
 
-				uj.Unread = true
+			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
+				switch currentKey {
 
-			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
+				case ffj_t_SecurityQuestionStruct_Question:
+					goto handle_Question
 
-				uj.Unread = false
+				case ffj_t_SecurityQuestionStruct_Answer:
+					goto handle_Answer
 
+				case ffj_t_SecurityQuestionStructno_such_key:
+					err = fs.SkipField(tok)
+					if err != nil {
+						return fs.WrapErr(err)
+					}
+					state = fflib.FFParse_after_value
+					goto mainparse
+				}
 			} else {
-				err = errors.New("unexpected bytes for true/false value")
-				return fs.WrapErr(err)
+				goto wantedvalue
 			}
-
 		}
 	}
 
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Spam:
-
-	/* handler: uj.Spam type=bool kind=bool quoted=false*/
+handle_Question:
 
-	{
-		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
-		}
-	}
+	/* handler: uj.Question type=string kind=string quoted=false*/
 
 	{
-		if tok == fflib.FFTok_null {
-
-		} else {
-			tmpb := fs.Output.Bytes()
-
-			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
 
-				uj.Spam = true
-
-			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
-
-				uj.Spam = false
-
-			} else {
-				err = errors.New("unexpected bytes for true/false value")
-				return fs.WrapErr(err)
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
 			}
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Starred:
-
-	/* handler: uj.Starred type=bool kind=bool quoted=false*/
-
-	{
-		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
 		}
-	}
 
-	{
 		if tok == fflib.FFTok_null {
 
 		} else {
-			tmpb := fs.Output.Bytes()
-
-			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
-
-				uj.Starred = true
-
-			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
 
-				uj.Starred = false
+			outBuf := fs.Output.Bytes()
 
-			} else {
-				err = errors.New("unexpected bytes for true/false value")
-				return fs.WrapErr(err)
-			}
+			uj.Question = string(string(outBuf))
 
 		}
 	}
@@ -13461,34 +9867,25 @@ handle_Starred:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Deleted:
+handle_Answer:
 
-	/* handler: uj.Deleted type=bool kind=bool quoted=false*/
+	/* handler: uj.Answer type=string kind=string quoted=false*/
 
 	{
-		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
 		}
-	}
 
-	{
 		if tok == fflib.FFTok_null {
 
 		} else {
-			tmpb := fs.Output.Bytes()
 
-			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
-
-				uj.Deleted = true
-
-			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
-
-				uj.Deleted = false
+			outBuf := fs.Output.Bytes()
 
-			} else {
-				err = errors.New("unexpected bytes for true/false value")
-				return fs.WrapErr(err)
-			}
+			uj.Answer = string(string(outBuf))
 
 		}
 	}
@@ -13513,7 +9910,7 @@ done:
 	return nil
 }
 
-func (mj *UserCIDStruct) MarshalJSON() ([]byte, error) {
+func (mj *SendEmailAttachment) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -13525,7 +9922,7 @@ func (mj *UserCIDStruct) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *UserCIDStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *SendEmailAttachment) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
@@ -13534,47 +9931,41 @@ func (mj *UserCIDStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	var obj []byte
 	_ = obj
 	_ = err
-	buf.WriteString(`{"CID":`)
-	fflib.WriteJsonString(buf, string(mj.CID))
-	buf.WriteString(`,"M_time":`)
-	fflib.WriteJsonString(buf, string(mj.M_time))
-	buf.WriteString(`,"UnreadCount":`)
-	fflib.FormatBits2(buf, uint64(mj.UnreadCount), 10, mj.UnreadCount < 0)
-	buf.WriteString(`,"AutoreplySent":`)
-	fflib.FormatBits2(buf, uint64(mj.AutoreplySent), 10, mj.AutoreplySent < 0)
+	buf.WriteString(`{"FileType":`)
+	fflib.WriteJsonString(buf, string(mj.FileType))
+	buf.WriteString(`,"FileName":`)
+	fflib.WriteJsonString(buf, string(mj.FileName))
+	buf.WriteString(`,"Binary":`)
+	fflib.WriteJsonString(buf, string(mj.Binary))
 	buf.WriteByte('}')
 	return nil
 }
 
 const (
-	ffj_t_UserCIDStructbase = iota
-	ffj_t_UserCIDStructno_such_key
-
-	ffj_t_UserCIDStruct_CID
+	ffj_t_SendEmailAttachmentbase = iota
+	ffj_t_SendEmailAttachmentno_such_key
 
-	ffj_t_UserCIDStruct_M_time
+	ffj_t_SendEmailAttachment_FileType
 
-	ffj_t_UserCIDStruct_UnreadCount
+	ffj_t_SendEmailAttachment_FileName
 
-	ffj_t_UserCIDStruct_AutoreplySent
+	ffj_t_SendEmailAttachment_Binary
 )
 
-var ffj_key_UserCIDStruct_CID = []byte("CID")
-
-var ffj_key_UserCIDStruct_M_time = []byte("M_time")
+var ffj_key_SendEmailAttachment_FileType = []byte("FileType")
 
-var ffj_key_UserCIDStruct_UnreadCount = []byte("UnreadCount")
+var ffj_key_SendEmailAttachment_FileName = []byte("FileName")
 
-var ffj_key_UserCIDStruct_AutoreplySent = []byte("AutoreplySent")
+var ffj_key_SendEmailAttachment_Binary = []byte("Binary")
 
-func (uj *UserCIDStruct) UnmarshalJSON(input []byte) error {
+func (uj *SendEmailAttachment) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *UserCIDStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *SendEmailAttachment) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_UserCIDStructbase
+	currentKey := ffj_t_SendEmailAttachmentbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -13620,71 +10011,54 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_UserCIDStructno_such_key
+				currentKey = ffj_t_SendEmailAttachmentno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
 				switch kn[0] {
 
-				case 'A':
-
-					if bytes.Equal(ffj_key_UserCIDStruct_AutoreplySent, kn) {
-						currentKey = ffj_t_UserCIDStruct_AutoreplySent
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				case 'C':
+				case 'B':
 
-					if bytes.Equal(ffj_key_UserCIDStruct_CID, kn) {
-						currentKey = ffj_t_UserCIDStruct_CID
+					if bytes.Equal(ffj_key_SendEmailAttachment_Binary, kn) {
+						currentKey = ffj_t_SendEmailAttachment_Binary
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'M':
+				case 'F':
 
-					if bytes.Equal(ffj_key_UserCIDStruct_M_time, kn) {
-						currentKey = ffj_t_UserCIDStruct_M_time
+					if bytes.Equal(ffj_key_SendEmailAttachment_FileType, kn) {
+						currentKey = ffj_t_SendEmailAttachment_FileType
 						state = fflib.FFParse_want_colon
 						goto mainparse
-					}
-
-				case 'U':
 
-					if bytes.Equal(ffj_key_UserCIDStruct_UnreadCount, kn) {
-						currentKey = ffj_t_UserCIDStruct_UnreadCount
+					} else if bytes.Equal(ffj_key_SendEmailAttachment_FileName, kn) {
+						currentKey = ffj_t_SendEmailAttachment_FileName
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.EqualFoldRight(ffj_key_UserCIDStruct_AutoreplySent, kn) {
-					currentKey = ffj_t_UserCIDStruct_AutoreplySent
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_UserCIDStruct_UnreadCount, kn) {
-					currentKey = ffj_t_UserCIDStruct_UnreadCount
+				if fflib.SimpleLetterEqualFold(ffj_key_SendEmailAttachment_Binary, kn) {
+					currentKey = ffj_t_SendEmailAttachment_Binary
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.AsciiEqualFold(ffj_key_UserCIDStruct_M_time, kn) {
-					currentKey = ffj_t_UserCIDStruct_M_time
+				if fflib.SimpleLetterEqualFold(ffj_key_SendEmailAttachment_FileName, kn) {
+					currentKey = ffj_t_SendEmailAttachment_FileName
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_UserCIDStruct_CID, kn) {
-					currentKey = ffj_t_UserCIDStruct_CID
+				if fflib.SimpleLetterEqualFold(ffj_key_SendEmailAttachment_FileType, kn) {
+					currentKey = ffj_t_SendEmailAttachment_FileType
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_UserCIDStructno_such_key
+				currentKey = ffj_t_SendEmailAttachmentno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -13701,19 +10075,16 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_UserCIDStruct_CID:
-					goto handle_CID
-
-				case ffj_t_UserCIDStruct_M_time:
-					goto handle_M_time
+				case ffj_t_SendEmailAttachment_FileType:
+					goto handle_FileType
 
-				case ffj_t_UserCIDStruct_UnreadCount:
-					goto handle_UnreadCount
+				case ffj_t_SendEmailAttachment_FileName:
+					goto handle_FileName
 
-				case ffj_t_UserCIDStruct_AutoreplySent:
-					goto handle_AutoreplySent
+				case ffj_t_SendEmailAttachment_Binary:
+					goto handle_Binary
 
-				case ffj_t_UserCIDStructno_such_key:
+				case ffj_t_SendEmailAttachmentno_such_key:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -13727,9 +10098,9 @@ mainparse:
 		}
 	}
 
-handle_CID:
+handle_FileType:
 
-	/* handler: uj.CID type=string kind=string quoted=false*/
+	/* handler: uj.FileType type=string kind=string quoted=false*/
 
 	{
 
@@ -13745,7 +10116,7 @@ handle_CID:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.CID = string(string(outBuf))
+			uj.FileType = string(string(outBuf))
 
 		}
 	}
@@ -13753,9 +10124,9 @@ handle_CID:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_M_time:
+handle_FileName:
 
-	/* handler: uj.M_time type=string kind=string quoted=false*/
+	/* handler: uj.FileName type=string kind=string quoted=false*/
 
 	{
 
@@ -13771,7 +10142,7 @@ handle_M_time:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.M_time = string(string(outBuf))
+			uj.FileName = string(string(outBuf))
 
 		}
 	}
@@ -13779,59 +10150,25 @@ handle_M_time:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_UnreadCount:
-
-	/* handler: uj.UnreadCount type=int kind=int quoted=false*/
+handle_Binary:
 
-	{
-		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
-		}
-	}
+	/* handler: uj.Binary type=string kind=string quoted=false*/
 
 	{
 
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
-
-			if err != nil {
-				return fs.WrapErr(err)
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
 			}
-
-			uj.UnreadCount = int(tval)
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_AutoreplySent:
-
-	/* handler: uj.AutoreplySent type=int kind=int quoted=false*/
-
-	{
-		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
 		}
-	}
-
-	{
 
 		if tok == fflib.FFTok_null {
 
 		} else {
 
-			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
-
-			if err != nil {
-				return fs.WrapErr(err)
-			}
+			outBuf := fs.Output.Bytes()
 
-			uj.AutoreplySent = int(tval)
+			uj.Binary = string(string(outBuf))
 
 		}
 	}
@@ -13856,7 +10193,7 @@ done:
 	return nil
 }
 
-func (mj *UserFriendStruct) MarshalJSON() ([]byte, error) {
+func (mj *SendEmailCmdStruct) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -13868,7 +10205,7 @@ func (mj *UserFriendStruct) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *UserFriendStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *SendEmailCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
@@ -13877,41 +10214,95 @@ func (mj *UserFriendStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	var obj []byte
 	_ = obj
 	_ = err
-	buf.WriteString(`{"Username":`)
-	fflib.WriteJsonString(buf, string(mj.Username))
-	buf.WriteString(`,"ProfilePic":`)
-	fflib.WriteJsonString(buf, string(mj.ProfilePic))
-	buf.WriteString(`,"Message":`)
-	fflib.WriteJsonString(buf, string(mj.Message))
+	buf.WriteString(`{"cmd":`)
+	fflib.WriteJsonString(buf, string(mj.Cmd))
+	buf.WriteString(`,"FromEmail":`)
+	fflib.WriteJsonString(buf, string(mj.FromEmail))
+	buf.WriteString(`,"ToEmails":`)
+	if mj.ToEmails != nil {
+		buf.WriteString(`[`)
+		for i, v := range mj.ToEmails {
+			if i != 0 {
+				buf.WriteString(`,`)
+			}
+			fflib.WriteJsonString(buf, string(v))
+		}
+		buf.WriteString(`]`)
+	} else {
+		buf.WriteString(`null`)
+	}
+	buf.WriteString(`,"Subject":`)
+	fflib.WriteJsonString(buf, string(mj.Subject))
+	buf.WriteString(`,"Content":`)
+	fflib.WriteJsonString(buf, string(mj.Content))
+	buf.WriteString(`,"Attachments":`)
+	if mj.Attachments != nil {
+		buf.WriteString(`[`)
+		for i, v := range mj.Attachments {
+			if i != 0 {
+				buf.WriteString(`,`)
+			}
+
+			{
+
+				err = v.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+		}
+		buf.WriteString(`]`)
+	} else {
+		buf.WriteString(`null`)
+	}
+	buf.WriteString(`,"M_time":`)
+	fflib.WriteJsonString(buf, string(mj.M_time))
 	buf.WriteByte('}')
 	return nil
 }
 
 const (
-	ffj_t_UserFriendStructbase = iota
-	ffj_t_UserFriendStructno_such_key
+	ffj_t_SendEmailCmdStructbase = iota
+	ffj_t_SendEmailCmdStructno_such_key
 
-	ffj_t_UserFriendStruct_Username
+	ffj_t_SendEmailCmdStruct_Cmd
 
-	ffj_t_UserFriendStruct_ProfilePic
+	ffj_t_SendEmailCmdStruct_FromEmail
 
-	ffj_t_UserFriendStruct_Message
+	ffj_t_SendEmailCmdStruct_ToEmails
+
+	ffj_t_SendEmailCmdStruct_Subject
+
+	ffj_t_SendEmailCmdStruct_Content
+
+	ffj_t_SendEmailCmdStruct_Attachments
+
+	ffj_t_SendEmailCmdStruct_M_time
 )
 
-var ffj_key_UserFriendStruct_Username = []byte("Username")
+var ffj_key_SendEmailCmdStruct_Cmd = []byte("cmd")
 
-var ffj_key_UserFriendStruct_ProfilePic = []byte("ProfilePic")
+var ffj_key_SendEmailCmdStruct_FromEmail = []byte("FromEmail")
 
-var ffj_key_UserFriendStruct_Message = []byte("Message")
+var ffj_key_SendEmailCmdStruct_ToEmails = []byte("ToEmails")
 
-func (uj *UserFriendStruct) UnmarshalJSON(input []byte) error {
+var ffj_key_SendEmailCmdStruct_Subject = []byte("Subject")
+
+var ffj_key_SendEmailCmdStruct_Content = []byte("Content")
+
+var ffj_key_SendEmailCmdStruct_Attachments = []byte("Attachments")
+
+var ffj_key_SendEmailCmdStruct_M_time = []byte("M_time")
+
+func (uj *SendEmailCmdStruct) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *UserFriendStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *SendEmailCmdStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_UserFriendStructbase
+	currentKey := ffj_t_SendEmailCmdStructbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -13957,57 +10348,113 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_UserFriendStructno_such_key
+				currentKey = ffj_t_SendEmailCmdStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
 				switch kn[0] {
 
+				case 'A':
+
+					if bytes.Equal(ffj_key_SendEmailCmdStruct_Attachments, kn) {
+						currentKey = ffj_t_SendEmailCmdStruct_Attachments
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'C':
+
+					if bytes.Equal(ffj_key_SendEmailCmdStruct_Content, kn) {
+						currentKey = ffj_t_SendEmailCmdStruct_Content
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'F':
+
+					if bytes.Equal(ffj_key_SendEmailCmdStruct_FromEmail, kn) {
+						currentKey = ffj_t_SendEmailCmdStruct_FromEmail
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
 				case 'M':
 
-					if bytes.Equal(ffj_key_UserFriendStruct_Message, kn) {
-						currentKey = ffj_t_UserFriendStruct_Message
+					if bytes.Equal(ffj_key_SendEmailCmdStruct_M_time, kn) {
+						currentKey = ffj_t_SendEmailCmdStruct_M_time
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'P':
+				case 'S':
 
-					if bytes.Equal(ffj_key_UserFriendStruct_ProfilePic, kn) {
-						currentKey = ffj_t_UserFriendStruct_ProfilePic
+					if bytes.Equal(ffj_key_SendEmailCmdStruct_Subject, kn) {
+						currentKey = ffj_t_SendEmailCmdStruct_Subject
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'U':
+				case 'T':
 
-					if bytes.Equal(ffj_key_UserFriendStruct_Username, kn) {
-						currentKey = ffj_t_UserFriendStruct_Username
+					if bytes.Equal(ffj_key_SendEmailCmdStruct_ToEmails, kn) {
+						currentKey = ffj_t_SendEmailCmdStruct_ToEmails
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'c':
+
+					if bytes.Equal(ffj_key_SendEmailCmdStruct_Cmd, kn) {
+						currentKey = ffj_t_SendEmailCmdStruct_Cmd
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.EqualFoldRight(ffj_key_UserFriendStruct_Message, kn) {
-					currentKey = ffj_t_UserFriendStruct_Message
+				if fflib.AsciiEqualFold(ffj_key_SendEmailCmdStruct_M_time, kn) {
+					currentKey = ffj_t_SendEmailCmdStruct_M_time
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_UserFriendStruct_ProfilePic, kn) {
-					currentKey = ffj_t_UserFriendStruct_ProfilePic
+				if fflib.EqualFoldRight(ffj_key_SendEmailCmdStruct_Attachments, kn) {
+					currentKey = ffj_t_SendEmailCmdStruct_Attachments
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_UserFriendStruct_Username, kn) {
-					currentKey = ffj_t_UserFriendStruct_Username
+				if fflib.SimpleLetterEqualFold(ffj_key_SendEmailCmdStruct_Content, kn) {
+					currentKey = ffj_t_SendEmailCmdStruct_Content
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_UserFriendStructno_such_key
+				if fflib.EqualFoldRight(ffj_key_SendEmailCmdStruct_Subject, kn) {
+					currentKey = ffj_t_SendEmailCmdStruct_Subject
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffj_key_SendEmailCmdStruct_ToEmails, kn) {
+					currentKey = ffj_t_SendEmailCmdStruct_ToEmails
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffj_key_SendEmailCmdStruct_FromEmail, kn) {
+					currentKey = ffj_t_SendEmailCmdStruct_FromEmail
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffj_key_SendEmailCmdStruct_Cmd, kn) {
+					currentKey = ffj_t_SendEmailCmdStruct_Cmd
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				currentKey = ffj_t_SendEmailCmdStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -14024,16 +10471,28 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_UserFriendStruct_Username:
-					goto handle_Username
+				case ffj_t_SendEmailCmdStruct_Cmd:
+					goto handle_Cmd
 
-				case ffj_t_UserFriendStruct_ProfilePic:
-					goto handle_ProfilePic
+				case ffj_t_SendEmailCmdStruct_FromEmail:
+					goto handle_FromEmail
 
-				case ffj_t_UserFriendStruct_Message:
-					goto handle_Message
+				case ffj_t_SendEmailCmdStruct_ToEmails:
+					goto handle_ToEmails
 
-				case ffj_t_UserFriendStructno_such_key:
+				case ffj_t_SendEmailCmdStruct_Subject:
+					goto handle_Subject
+
+				case ffj_t_SendEmailCmdStruct_Content:
+					goto handle_Content
+
+				case ffj_t_SendEmailCmdStruct_Attachments:
+					goto handle_Attachments
+
+				case ffj_t_SendEmailCmdStruct_M_time:
+					goto handle_M_time
+
+				case ffj_t_SendEmailCmdStructno_such_key:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -14047,9 +10506,9 @@ mainparse:
 		}
 	}
 
-handle_Username:
+handle_Cmd:
 
-	/* handler: uj.Username type=string kind=string quoted=false*/
+	/* handler: uj.Cmd type=string kind=string quoted=false*/
 
 	{
 
@@ -14065,7 +10524,7 @@ handle_Username:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Username = string(string(outBuf))
+			uj.Cmd = string(string(outBuf))
 
 		}
 	}
@@ -14073,9 +10532,134 @@ handle_Username:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_ProfilePic:
+handle_FromEmail:
 
-	/* handler: uj.ProfilePic type=string kind=string quoted=false*/
+	/* handler: uj.FromEmail type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			uj.FromEmail = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_ToEmails:
+
+	/* handler: uj.ToEmails type=[]string kind=slice quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+			uj.ToEmails = nil
+		} else {
+
+			uj.ToEmails = make([]string, 0)
+
+			wantVal := true
+
+			for {
+
+				var v string
+
+				tok = fs.Scan()
+				if tok == fflib.FFTok_error {
+					goto tokerror
+				}
+				if tok == fflib.FFTok_right_brace {
+					break
+				}
+
+				if tok == fflib.FFTok_comma {
+					if wantVal == true {
+						// TODO(pquerna): this isn't an ideal error message, this handles
+						// things like [,,,] as an array value.
+						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+					}
+					continue
+				} else {
+					wantVal = true
+				}
+
+				/* handler: v type=string kind=string quoted=false*/
+
+				{
+
+					{
+						if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+							return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+						}
+					}
+
+					if tok == fflib.FFTok_null {
+
+					} else {
+
+						outBuf := fs.Output.Bytes()
+
+						v = string(string(outBuf))
+
+					}
+				}
+
+				uj.ToEmails = append(uj.ToEmails, v)
+				wantVal = false
+			}
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Subject:
+
+	/* handler: uj.Subject type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			uj.Subject = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Content:
+
+	/* handler: uj.Content type=string kind=string quoted=false*/
 
 	{
 
@@ -14091,17 +10675,85 @@ handle_ProfilePic:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.ProfilePic = string(string(outBuf))
+			uj.Content = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Attachments:
+
+	/* handler: uj.Attachments type=[]pcDatabase.SendEmailAttachment kind=slice quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+			uj.Attachments = nil
+		} else {
+
+			uj.Attachments = make([]SendEmailAttachment, 0)
+
+			wantVal := true
+
+			for {
+
+				var v SendEmailAttachment
+
+				tok = fs.Scan()
+				if tok == fflib.FFTok_error {
+					goto tokerror
+				}
+				if tok == fflib.FFTok_right_brace {
+					break
+				}
+
+				if tok == fflib.FFTok_comma {
+					if wantVal == true {
+						// TODO(pquerna): this isn't an ideal error message, this handles
+						// things like [,,,] as an array value.
+						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+					}
+					continue
+				} else {
+					wantVal = true
+				}
+
+				/* handler: v type=pcDatabase.SendEmailAttachment kind=struct quoted=false*/
+
+				{
+					if tok == fflib.FFTok_null {
+
+						state = fflib.FFParse_after_value
+						goto mainparse
+					}
+
+					err = v.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+					if err != nil {
+						return err
+					}
+					state = fflib.FFParse_after_value
+				}
 
+				uj.Attachments = append(uj.Attachments, v)
+				wantVal = false
+			}
 		}
 	}
 
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Message:
+handle_M_time:
 
-	/* handler: uj.Message type=string kind=string quoted=false*/
+	/* handler: uj.M_time type=string kind=string quoted=false*/
 
 	{
 
@@ -14117,7 +10769,7 @@ handle_Message:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Message = string(string(outBuf))
+			uj.M_time = string(string(outBuf))
 
 		}
 	}
@@ -14142,7 +10794,7 @@ done:
 	return nil
 }
 
-func (mj *UserStruct) MarshalJSON() ([]byte, error) {
+func (mj *UpdateEmailCmdStruct) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if mj == nil {
 		buf.WriteString("null")
@@ -14154,7 +10806,7 @@ func (mj *UserStruct) MarshalJSON() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
-func (mj *UserStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (mj *UpdateEmailCmdStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if mj == nil {
 		buf.WriteString("null")
 		return nil
@@ -14163,36 +10815,16 @@ func (mj *UserStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	var obj []byte
 	_ = obj
 	_ = err
-	buf.WriteString(`{ "Username":`)
+	buf.WriteString(`{"cmd":`)
+	fflib.WriteJsonString(buf, string(mj.Cmd))
+	buf.WriteString(`,"Username":`)
 	fflib.WriteJsonString(buf, string(mj.Username))
-	buf.WriteString(`,"UsernameUpper":`)
-	fflib.WriteJsonString(buf, string(mj.UsernameUpper))
-	buf.WriteString(`,"Password":`)
-	fflib.WriteJsonString(buf, string(mj.Password))
-	buf.WriteString(`,"Email":`)
-	fflib.WriteJsonString(buf, string(mj.Email))
-	buf.WriteString(`,"Phone":`)
-	fflib.WriteJsonString(buf, string(mj.Phone))
-	buf.WriteString(`,"PhoneGateway":`)
-	fflib.WriteJsonString(buf, string(mj.PhoneGateway))
-	buf.WriteString(`,"AutoreplyMessage":`)
-	fflib.WriteJsonString(buf, string(mj.AutoreplyMessage))
-	buf.WriteString(`,"Friends":`)
-	fflib.WriteJsonString(buf, string(mj.Friends))
-	buf.WriteString(`,"IncomingPendingFriends":`)
-	fflib.WriteJsonString(buf, string(mj.IncomingPendingFriends))
-	buf.WriteString(`,"OutgoingPendingFriends":`)
-	fflib.WriteJsonString(buf, string(mj.OutgoingPendingFriends))
-	buf.WriteString(`,"CIDs":`)
-	fflib.WriteJsonString(buf, string(mj.CIDs))
-	buf.WriteString(`,"ScheduledMessages":`)
-	fflib.WriteJsonString(buf, string(mj.ScheduledMessages))
-	buf.WriteString(`,"EmailMtime":`)
-	fflib.WriteJsonString(buf, string(mj.EmailMtime))
-	buf.WriteString(`,"Android":`)
-	if mj.Android != nil {
+	buf.WriteString(`,"FromEmail":`)
+	fflib.WriteJsonString(buf, string(mj.FromEmail))
+	buf.WriteString(`,"ToEmails":`)
+	if mj.ToEmails != nil {
 		buf.WriteString(`[`)
-		for i, v := range mj.Android {
+		for i, v := range mj.ToEmails {
 			if i != 0 {
 				buf.WriteString(`,`)
 			}
@@ -14202,165 +10834,124 @@ func (mj *UserStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	} else {
 		buf.WriteString(`null`)
 	}
-	buf.WriteString(`,"Fireos":`)
-	if mj.Fireos != nil {
+	buf.WriteString(`,"Subject":`)
+	fflib.WriteJsonString(buf, string(mj.Subject))
+	buf.WriteString(`,"Attachments":`)
+	if mj.Attachments != nil {
 		buf.WriteString(`[`)
-		for i, v := range mj.Fireos {
+		for i, v := range mj.Attachments {
 			if i != 0 {
 				buf.WriteString(`,`)
 			}
-			fflib.WriteJsonString(buf, string(v))
+
+			{
+
+				err = v.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
 		}
 		buf.WriteString(`]`)
 	} else {
 		buf.WriteString(`null`)
 	}
-	buf.WriteString(`,"Ios":`)
-	if mj.Ios != nil {
-		buf.WriteString(`[`)
-		for i, v := range mj.Ios {
-			if i != 0 {
-				buf.WriteString(`,`)
-			}
-			fflib.WriteJsonString(buf, string(v))
-		}
-		buf.WriteString(`]`)
+	buf.WriteString(`,"Content":`)
+	fflib.WriteJsonString(buf, string(mj.Content))
+	buf.WriteString(`,"RecvTime":`)
+	fflib.WriteJsonString(buf, string(mj.RecvTime))
+	buf.WriteString(`,"EmailMtime":`)
+	fflib.WriteJsonString(buf, string(mj.EmailMtime))
+	if mj.Unread {
+		buf.WriteString(`,"Unread":true`)
 	} else {
-		buf.WriteString(`null`)
+		buf.WriteString(`,"Unread":false`)
 	}
-	buf.WriteString(`,"Web":`)
-	if mj.Web != nil {
-		buf.WriteString(`[`)
-		for i, v := range mj.Web {
-			if i != 0 {
-				buf.WriteString(`,`)
-			}
-			fflib.WriteJsonString(buf, string(v))
-		}
-		buf.WriteString(`]`)
+	if mj.Spam {
+		buf.WriteString(`,"Spam":true`)
 	} else {
-		buf.WriteString(`null`)
+		buf.WriteString(`,"Spam":false`)
 	}
-	buf.WriteString(`,"ProfilePic":`)
-	fflib.WriteJsonString(buf, string(mj.ProfilePic))
-	buf.WriteString(`,"Quota":`)
-	fflib.FormatBits2(buf, uint64(mj.Quota), 10, false)
-	buf.WriteString(`,"QuotaUsed":`)
-	fflib.FormatBits2(buf, uint64(mj.QuotaUsed), 10, false)
-	buf.WriteString(`,"SecQuests":`)
-	fflib.WriteJsonString(buf, string(mj.SecQuests))
-	buf.WriteByte(',')
-	if len(mj.Cmd) != 0 {
-		buf.WriteString(`"cmd":`)
-		fflib.WriteJsonString(buf, string(mj.Cmd))
-		buf.WriteByte(',')
+	if mj.Starred {
+		buf.WriteString(`,"Starred":true`)
+	} else {
+		buf.WriteString(`,"Starred":false`)
+	}
+	if mj.Deleted {
+		buf.WriteString(`,"Deleted":true`)
+	} else {
+		buf.WriteString(`,"Deleted":false`)
 	}
-	buf.Rewind(1)
 	buf.WriteByte('}')
 	return nil
 }
 
 const (
-	ffj_t_UserStructbase = iota
-	ffj_t_UserStructno_such_key
-
-	ffj_t_UserStruct_Username
-
-	ffj_t_UserStruct_UsernameUpper
-
-	ffj_t_UserStruct_Password
-
-	ffj_t_UserStruct_Email
-
-	ffj_t_UserStruct_Phone
-
-	ffj_t_UserStruct_PhoneGateway
-
-	ffj_t_UserStruct_AutoreplyMessage
-
-	ffj_t_UserStruct_Friends
-
-	ffj_t_UserStruct_IncomingPendingFriends
+	ffj_t_UpdateEmailCmdStructbase = iota
+	ffj_t_UpdateEmailCmdStructno_such_key
 
-	ffj_t_UserStruct_OutgoingPendingFriends
+	ffj_t_UpdateEmailCmdStruct_Cmd
 
-	ffj_t_UserStruct_CIDs
+	ffj_t_UpdateEmailCmdStruct_Username
 
-	ffj_t_UserStruct_ScheduledMessages
+	ffj_t_UpdateEmailCmdStruct_FromEmail
 
-	ffj_t_UserStruct_EmailMtime
+	ffj_t_UpdateEmailCmdStruct_ToEmails
 
-	ffj_t_UserStruct_Android
+	ffj_t_UpdateEmailCmdStruct_Subject
 
-	ffj_t_UserStruct_Fireos
+	ffj_t_UpdateEmailCmdStruct_Attachments
 
-	ffj_t_UserStruct_Ios
+	ffj_t_UpdateEmailCmdStruct_Content
 
-	ffj_t_UserStruct_Web
+	ffj_t_UpdateEmailCmdStruct_RecvTime
 
-	ffj_t_UserStruct_ProfilePic
+	ffj_t_UpdateEmailCmdStruct_EmailMtime
 
-	ffj_t_UserStruct_Quota
+	ffj_t_UpdateEmailCmdStruct_Unread
 
-	ffj_t_UserStruct_QuotaUsed
+	ffj_t_UpdateEmailCmdStruct_Spam
 
-	ffj_t_UserStruct_SecQuests
+	ffj_t_UpdateEmailCmdStruct_Starred
 
-	ffj_t_UserStruct_Cmd
+	ffj_t_UpdateEmailCmdStruct_Deleted
 )
 
-var ffj_key_UserStruct_Username = []byte("Username")
-
-var ffj_key_UserStruct_UsernameUpper = []byte("UsernameUpper")
-
-var ffj_key_UserStruct_Password = []byte("Password")
-
-var ffj_key_UserStruct_Email = []byte("Email")
-
-var ffj_key_UserStruct_Phone = []byte("Phone")
-
-var ffj_key_UserStruct_PhoneGateway = []byte("PhoneGateway")
-
-var ffj_key_UserStruct_AutoreplyMessage = []byte("AutoreplyMessage")
-
-var ffj_key_UserStruct_Friends = []byte("Friends")
-
-var ffj_key_UserStruct_IncomingPendingFriends = []byte("IncomingPendingFriends")
-
-var ffj_key_UserStruct_OutgoingPendingFriends = []byte("OutgoingPendingFriends")
+var ffj_key_UpdateEmailCmdStruct_Cmd = []byte("cmd")
 
-var ffj_key_UserStruct_CIDs = []byte("CIDs")
+var ffj_key_UpdateEmailCmdStruct_Username = []byte("Username")
 
-var ffj_key_UserStruct_ScheduledMessages = []byte("ScheduledMessages")
+var ffj_key_UpdateEmailCmdStruct_FromEmail = []byte("FromEmail")
 
-var ffj_key_UserStruct_EmailMtime = []byte("EmailMtime")
+var ffj_key_UpdateEmailCmdStruct_ToEmails = []byte("ToEmails")
 
-var ffj_key_UserStruct_Android = []byte("Android")
+var ffj_key_UpdateEmailCmdStruct_Subject = []byte("Subject")
 
-var ffj_key_UserStruct_Fireos = []byte("Fireos")
+var ffj_key_UpdateEmailCmdStruct_Attachments = []byte("Attachments")
 
-var ffj_key_UserStruct_Ios = []byte("Ios")
+var ffj_key_UpdateEmailCmdStruct_Content = []byte("Content")
 
-var ffj_key_UserStruct_Web = []byte("Web")
+var ffj_key_UpdateEmailCmdStruct_RecvTime = []byte("RecvTime")
 
-var ffj_key_UserStruct_ProfilePic = []byte("ProfilePic")
+var ffj_key_UpdateEmailCmdStruct_EmailMtime = []byte("EmailMtime")
 
-var ffj_key_UserStruct_Quota = []byte("Quota")
+var ffj_key_UpdateEmailCmdStruct_Unread = []byte("Unread")
 
-var ffj_key_UserStruct_QuotaUsed = []byte("QuotaUsed")
+var ffj_key_UpdateEmailCmdStruct_Spam = []byte("Spam")
 
-var ffj_key_UserStruct_SecQuests = []byte("SecQuests")
+var ffj_key_UpdateEmailCmdStruct_Starred = []byte("Starred")
 
-var ffj_key_UserStruct_Cmd = []byte("cmd")
+var ffj_key_UpdateEmailCmdStruct_Deleted = []byte("Deleted")
 
-func (uj *UserStruct) UnmarshalJSON(input []byte) error {
+func (uj *UpdateEmailCmdStruct) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *UserStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (uj *UpdateEmailCmdStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error = nil
-	currentKey := ffj_t_UserStructbase
+	currentKey := ffj_t_UpdateEmailCmdStructbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -14406,7 +10997,7 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_UserStructno_such_key
+				currentKey = ffj_t_UpdateEmailCmdStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
@@ -14414,285 +11005,180 @@ mainparse:
 
 				case 'A':
 
-					if bytes.Equal(ffj_key_UserStruct_AutoreplyMessage, kn) {
-						currentKey = ffj_t_UserStruct_AutoreplyMessage
-						state = fflib.FFParse_want_colon
-						goto mainparse
-
-					} else if bytes.Equal(ffj_key_UserStruct_Android, kn) {
-						currentKey = ffj_t_UserStruct_Android
+					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Attachments, kn) {
+						currentKey = ffj_t_UpdateEmailCmdStruct_Attachments
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'C':
 
-					if bytes.Equal(ffj_key_UserStruct_CIDs, kn) {
-						currentKey = ffj_t_UserStruct_CIDs
+					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Content, kn) {
+						currentKey = ffj_t_UpdateEmailCmdStruct_Content
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'E':
-
-					if bytes.Equal(ffj_key_UserStruct_Email, kn) {
-						currentKey = ffj_t_UserStruct_Email
-						state = fflib.FFParse_want_colon
-						goto mainparse
+				case 'D':
 
-					} else if bytes.Equal(ffj_key_UserStruct_EmailMtime, kn) {
-						currentKey = ffj_t_UserStruct_EmailMtime
+					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Deleted, kn) {
+						currentKey = ffj_t_UpdateEmailCmdStruct_Deleted
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'F':
-
-					if bytes.Equal(ffj_key_UserStruct_Friends, kn) {
-						currentKey = ffj_t_UserStruct_Friends
-						state = fflib.FFParse_want_colon
-						goto mainparse
+				case 'E':
 
-					} else if bytes.Equal(ffj_key_UserStruct_Fireos, kn) {
-						currentKey = ffj_t_UserStruct_Fireos
+					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_EmailMtime, kn) {
+						currentKey = ffj_t_UpdateEmailCmdStruct_EmailMtime
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'I':
-
-					if bytes.Equal(ffj_key_UserStruct_IncomingPendingFriends, kn) {
-						currentKey = ffj_t_UserStruct_IncomingPendingFriends
-						state = fflib.FFParse_want_colon
-						goto mainparse
+				case 'F':
 
-					} else if bytes.Equal(ffj_key_UserStruct_Ios, kn) {
-						currentKey = ffj_t_UserStruct_Ios
+					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_FromEmail, kn) {
+						currentKey = ffj_t_UpdateEmailCmdStruct_FromEmail
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'O':
+				case 'R':
 
-					if bytes.Equal(ffj_key_UserStruct_OutgoingPendingFriends, kn) {
-						currentKey = ffj_t_UserStruct_OutgoingPendingFriends
+					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_RecvTime, kn) {
+						currentKey = ffj_t_UpdateEmailCmdStruct_RecvTime
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'P':
-
-					if bytes.Equal(ffj_key_UserStruct_Password, kn) {
-						currentKey = ffj_t_UserStruct_Password
-						state = fflib.FFParse_want_colon
-						goto mainparse
-
-					} else if bytes.Equal(ffj_key_UserStruct_Phone, kn) {
-						currentKey = ffj_t_UserStruct_Phone
-						state = fflib.FFParse_want_colon
-						goto mainparse
-
-					} else if bytes.Equal(ffj_key_UserStruct_PhoneGateway, kn) {
-						currentKey = ffj_t_UserStruct_PhoneGateway
-						state = fflib.FFParse_want_colon
-						goto mainparse
+				case 'S':
 
-					} else if bytes.Equal(ffj_key_UserStruct_ProfilePic, kn) {
-						currentKey = ffj_t_UserStruct_ProfilePic
+					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Subject, kn) {
+						currentKey = ffj_t_UpdateEmailCmdStruct_Subject
 						state = fflib.FFParse_want_colon
 						goto mainparse
-					}
 
-				case 'Q':
-
-					if bytes.Equal(ffj_key_UserStruct_Quota, kn) {
-						currentKey = ffj_t_UserStruct_Quota
+					} else if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Spam, kn) {
+						currentKey = ffj_t_UpdateEmailCmdStruct_Spam
 						state = fflib.FFParse_want_colon
 						goto mainparse
 
-					} else if bytes.Equal(ffj_key_UserStruct_QuotaUsed, kn) {
-						currentKey = ffj_t_UserStruct_QuotaUsed
+					} else if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Starred, kn) {
+						currentKey = ffj_t_UpdateEmailCmdStruct_Starred
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				case 'S':
-
-					if bytes.Equal(ffj_key_UserStruct_ScheduledMessages, kn) {
-						currentKey = ffj_t_UserStruct_ScheduledMessages
-						state = fflib.FFParse_want_colon
-						goto mainparse
+				case 'T':
 
-					} else if bytes.Equal(ffj_key_UserStruct_SecQuests, kn) {
-						currentKey = ffj_t_UserStruct_SecQuests
+					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_ToEmails, kn) {
+						currentKey = ffj_t_UpdateEmailCmdStruct_ToEmails
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'U':
 
-					if bytes.Equal(ffj_key_UserStruct_Username, kn) {
-						currentKey = ffj_t_UserStruct_Username
-						state = fflib.FFParse_want_colon
-						goto mainparse
-
-					} else if bytes.Equal(ffj_key_UserStruct_UsernameUpper, kn) {
-						currentKey = ffj_t_UserStruct_UsernameUpper
+					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Username, kn) {
+						currentKey = ffj_t_UpdateEmailCmdStruct_Username
 						state = fflib.FFParse_want_colon
 						goto mainparse
-					}
-
-				case 'W':
 
-					if bytes.Equal(ffj_key_UserStruct_Web, kn) {
-						currentKey = ffj_t_UserStruct_Web
+					} else if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Unread, kn) {
+						currentKey = ffj_t_UpdateEmailCmdStruct_Unread
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'c':
 
-					if bytes.Equal(ffj_key_UserStruct_Cmd, kn) {
-						currentKey = ffj_t_UserStruct_Cmd
-						state = fflib.FFParse_want_colon
-						goto mainparse
-					}
-
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_UserStruct_Cmd, kn) {
-					currentKey = ffj_t_UserStruct_Cmd
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_UserStruct_SecQuests, kn) {
-					currentKey = ffj_t_UserStruct_SecQuests
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_UserStruct_QuotaUsed, kn) {
-					currentKey = ffj_t_UserStruct_QuotaUsed
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_UserStruct_Quota, kn) {
-					currentKey = ffj_t_UserStruct_Quota
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_UserStruct_ProfilePic, kn) {
-					currentKey = ffj_t_UserStruct_ProfilePic
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_UserStruct_Web, kn) {
-					currentKey = ffj_t_UserStruct_Web
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_UserStruct_Ios, kn) {
-					currentKey = ffj_t_UserStruct_Ios
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.EqualFoldRight(ffj_key_UserStruct_Fireos, kn) {
-					currentKey = ffj_t_UserStruct_Fireos
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
-
-				if fflib.SimpleLetterEqualFold(ffj_key_UserStruct_Android, kn) {
-					currentKey = ffj_t_UserStruct_Android
-					state = fflib.FFParse_want_colon
-					goto mainparse
+					if bytes.Equal(ffj_key_UpdateEmailCmdStruct_Cmd, kn) {
+						currentKey = ffj_t_UpdateEmailCmdStruct_Cmd
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_UserStruct_EmailMtime, kn) {
-					currentKey = ffj_t_UserStruct_EmailMtime
+				if fflib.SimpleLetterEqualFold(ffj_key_UpdateEmailCmdStruct_Deleted, kn) {
+					currentKey = ffj_t_UpdateEmailCmdStruct_Deleted
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_UserStruct_ScheduledMessages, kn) {
-					currentKey = ffj_t_UserStruct_ScheduledMessages
+				if fflib.EqualFoldRight(ffj_key_UpdateEmailCmdStruct_Starred, kn) {
+					currentKey = ffj_t_UpdateEmailCmdStruct_Starred
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_UserStruct_CIDs, kn) {
-					currentKey = ffj_t_UserStruct_CIDs
+				if fflib.EqualFoldRight(ffj_key_UpdateEmailCmdStruct_Spam, kn) {
+					currentKey = ffj_t_UpdateEmailCmdStruct_Spam
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_UserStruct_OutgoingPendingFriends, kn) {
-					currentKey = ffj_t_UserStruct_OutgoingPendingFriends
+				if fflib.SimpleLetterEqualFold(ffj_key_UpdateEmailCmdStruct_Unread, kn) {
+					currentKey = ffj_t_UpdateEmailCmdStruct_Unread
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_UserStruct_IncomingPendingFriends, kn) {
-					currentKey = ffj_t_UserStruct_IncomingPendingFriends
+				if fflib.SimpleLetterEqualFold(ffj_key_UpdateEmailCmdStruct_EmailMtime, kn) {
+					currentKey = ffj_t_UpdateEmailCmdStruct_EmailMtime
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_UserStruct_Friends, kn) {
-					currentKey = ffj_t_UserStruct_Friends
+				if fflib.SimpleLetterEqualFold(ffj_key_UpdateEmailCmdStruct_RecvTime, kn) {
+					currentKey = ffj_t_UpdateEmailCmdStruct_RecvTime
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_UserStruct_AutoreplyMessage, kn) {
-					currentKey = ffj_t_UserStruct_AutoreplyMessage
+				if fflib.SimpleLetterEqualFold(ffj_key_UpdateEmailCmdStruct_Content, kn) {
+					currentKey = ffj_t_UpdateEmailCmdStruct_Content
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_UserStruct_PhoneGateway, kn) {
-					currentKey = ffj_t_UserStruct_PhoneGateway
+				if fflib.EqualFoldRight(ffj_key_UpdateEmailCmdStruct_Attachments, kn) {
+					currentKey = ffj_t_UpdateEmailCmdStruct_Attachments
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_UserStruct_Phone, kn) {
-					currentKey = ffj_t_UserStruct_Phone
+				if fflib.EqualFoldRight(ffj_key_UpdateEmailCmdStruct_Subject, kn) {
+					currentKey = ffj_t_UpdateEmailCmdStruct_Subject
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_UserStruct_Email, kn) {
-					currentKey = ffj_t_UserStruct_Email
+				if fflib.EqualFoldRight(ffj_key_UpdateEmailCmdStruct_ToEmails, kn) {
+					currentKey = ffj_t_UpdateEmailCmdStruct_ToEmails
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_UserStruct_Password, kn) {
-					currentKey = ffj_t_UserStruct_Password
+				if fflib.SimpleLetterEqualFold(ffj_key_UpdateEmailCmdStruct_FromEmail, kn) {
+					currentKey = ffj_t_UpdateEmailCmdStruct_FromEmail
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_UserStruct_UsernameUpper, kn) {
-					currentKey = ffj_t_UserStruct_UsernameUpper
+				if fflib.EqualFoldRight(ffj_key_UpdateEmailCmdStruct_Username, kn) {
+					currentKey = ffj_t_UpdateEmailCmdStruct_Username
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_UserStruct_Username, kn) {
-					currentKey = ffj_t_UserStruct_Username
+				if fflib.SimpleLetterEqualFold(ffj_key_UpdateEmailCmdStruct_Cmd, kn) {
+					currentKey = ffj_t_UpdateEmailCmdStruct_Cmd
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_UserStructno_such_key
+				currentKey = ffj_t_UpdateEmailCmdStructno_such_key
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -14709,73 +11195,46 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_UserStruct_Username:
-					goto handle_Username
-
-				case ffj_t_UserStruct_UsernameUpper:
-					goto handle_UsernameUpper
-
-				case ffj_t_UserStruct_Password:
-					goto handle_Password
-
-				case ffj_t_UserStruct_Email:
-					goto handle_Email
-
-				case ffj_t_UserStruct_Phone:
-					goto handle_Phone
+				case ffj_t_UpdateEmailCmdStruct_Cmd:
+					goto handle_Cmd
 
-				case ffj_t_UserStruct_PhoneGateway:
-					goto handle_PhoneGateway
+				case ffj_t_UpdateEmailCmdStruct_Username:
+					goto handle_Username
 
-				case ffj_t_UserStruct_AutoreplyMessage:
-					goto handle_AutoreplyMessage
+				case ffj_t_UpdateEmailCmdStruct_FromEmail:
+					goto handle_FromEmail
 
-				case ffj_t_UserStruct_Friends:
-					goto handle_Friends
+				case ffj_t_UpdateEmailCmdStruct_ToEmails:
+					goto handle_ToEmails
 
-				case ffj_t_UserStruct_IncomingPendingFriends:
-					goto handle_IncomingPendingFriends
+				case ffj_t_UpdateEmailCmdStruct_Subject:
+					goto handle_Subject
 
-				case ffj_t_UserStruct_OutgoingPendingFriends:
-					goto handle_OutgoingPendingFriends
+				case ffj_t_UpdateEmailCmdStruct_Attachments:
+					goto handle_Attachments
 
-				case ffj_t_UserStruct_CIDs:
-					goto handle_CIDs
+				case ffj_t_UpdateEmailCmdStruct_Content:
+					goto handle_Content
 
-				case ffj_t_UserStruct_ScheduledMessages:
-					goto handle_ScheduledMessages
+				case ffj_t_UpdateEmailCmdStruct_RecvTime:
+					goto handle_RecvTime
 
-				case ffj_t_UserStruct_EmailMtime:
+				case ffj_t_UpdateEmailCmdStruct_EmailMtime:
 					goto handle_EmailMtime
 
-				case ffj_t_UserStruct_Android:
-					goto handle_Android
-
-				case ffj_t_UserStruct_Fireos:
-					goto handle_Fireos
-
-				case ffj_t_UserStruct_Ios:
-					goto handle_Ios
-
-				case ffj_t_UserStruct_Web:
-					goto handle_Web
-
-				case ffj_t_UserStruct_ProfilePic:
-					goto handle_ProfilePic
-
-				case ffj_t_UserStruct_Quota:
-					goto handle_Quota
+				case ffj_t_UpdateEmailCmdStruct_Unread:
+					goto handle_Unread
 
-				case ffj_t_UserStruct_QuotaUsed:
-					goto handle_QuotaUsed
+				case ffj_t_UpdateEmailCmdStruct_Spam:
+					goto handle_Spam
 
-				case ffj_t_UserStruct_SecQuests:
-					goto handle_SecQuests
+				case ffj_t_UpdateEmailCmdStruct_Starred:
+					goto handle_Starred
 
-				case ffj_t_UserStruct_Cmd:
-					goto handle_Cmd
+				case ffj_t_UpdateEmailCmdStruct_Deleted:
+					goto handle_Deleted
 
-				case ffj_t_UserStructno_such_key:
+				case ffj_t_UpdateEmailCmdStructno_such_key:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -14789,35 +11248,9 @@ mainparse:
 		}
 	}
 
-handle_Username:
-
-	/* handler: uj.Username type=string kind=string quoted=false*/
-
-	{
-
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.Username = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_UsernameUpper:
+handle_Cmd:
 
-	/* handler: uj.UsernameUpper type=string kind=string quoted=false*/
+	/* handler: uj.Cmd type=string kind=string quoted=false*/
 
 	{
 
@@ -14833,7 +11266,7 @@ handle_UsernameUpper:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.UsernameUpper = string(string(outBuf))
+			uj.Cmd = string(string(outBuf))
 
 		}
 	}
@@ -14841,9 +11274,9 @@ handle_UsernameUpper:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Password:
+handle_Username:
 
-	/* handler: uj.Password type=string kind=string quoted=false*/
+	/* handler: uj.Username type=string kind=string quoted=false*/
 
 	{
 
@@ -14859,7 +11292,7 @@ handle_Password:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Password = string(string(outBuf))
+			uj.Username = string(string(outBuf))
 
 		}
 	}
@@ -14867,9 +11300,9 @@ handle_Password:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Email:
+handle_FromEmail:
 
-	/* handler: uj.Email type=string kind=string quoted=false*/
+	/* handler: uj.FromEmail type=string kind=string quoted=false*/
 
 	{
 
@@ -14885,7 +11318,7 @@ handle_Email:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Email = string(string(outBuf))
+			uj.FromEmail = string(string(outBuf))
 
 		}
 	}
@@ -14893,87 +11326,82 @@ handle_Email:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Phone:
+handle_ToEmails:
 
-	/* handler: uj.Phone type=string kind=string quoted=false*/
+	/* handler: uj.ToEmails type=[]string kind=slice quoted=false*/
 
 	{
 
 		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
 			}
 		}
 
 		if tok == fflib.FFTok_null {
-
+			uj.ToEmails = nil
 		} else {
 
-			outBuf := fs.Output.Bytes()
-
-			uj.Phone = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
+			uj.ToEmails = make([]string, 0)
 
-handle_PhoneGateway:
+			wantVal := true
 
-	/* handler: uj.PhoneGateway type=string kind=string quoted=false*/
+			for {
 
-	{
+				var v string
 
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
+				tok = fs.Scan()
+				if tok == fflib.FFTok_error {
+					goto tokerror
+				}
+				if tok == fflib.FFTok_right_brace {
+					break
+				}
 
-		if tok == fflib.FFTok_null {
+				if tok == fflib.FFTok_comma {
+					if wantVal == true {
+						// TODO(pquerna): this isn't an ideal error message, this handles
+						// things like [,,,] as an array value.
+						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+					}
+					continue
+				} else {
+					wantVal = true
+				}
 
-		} else {
+				/* handler: v type=string kind=string quoted=false*/
 
-			outBuf := fs.Output.Bytes()
+				{
 
-			uj.PhoneGateway = string(string(outBuf))
+					{
+						if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+							return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+						}
+					}
 
-		}
-	}
+					if tok == fflib.FFTok_null {
 
-	state = fflib.FFParse_after_value
-	goto mainparse
+					} else {
 
-handle_AutoreplyMessage:
+						outBuf := fs.Output.Bytes()
 
-	/* handler: uj.AutoreplyMessage type=string kind=string quoted=false*/
+						v = string(string(outBuf))
 
-	{
+					}
+				}
 
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+				uj.ToEmails = append(uj.ToEmails, v)
+				wantVal = false
 			}
 		}
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			outBuf := fs.Output.Bytes()
-
-			uj.AutoreplyMessage = string(string(outBuf))
-
-		}
 	}
 
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Friends:
+handle_Subject:
 
-	/* handler: uj.Friends type=string kind=string quoted=false*/
+	/* handler: uj.Subject type=string kind=string quoted=false*/
 
 	{
 
@@ -14989,7 +11417,7 @@ handle_Friends:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Friends = string(string(outBuf))
+			uj.Subject = string(string(outBuf))
 
 		}
 	}
@@ -14997,61 +11425,77 @@ handle_Friends:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_IncomingPendingFriends:
+handle_Attachments:
 
-	/* handler: uj.IncomingPendingFriends type=string kind=string quoted=false*/
+	/* handler: uj.Attachments type=[]pcDatabase.SendEmailAttachment kind=slice quoted=false*/
 
 	{
 
 		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
 			}
 		}
 
 		if tok == fflib.FFTok_null {
-
+			uj.Attachments = nil
 		} else {
 
-			outBuf := fs.Output.Bytes()
-
-			uj.IncomingPendingFriends = string(string(outBuf))
-
-		}
-	}
+			uj.Attachments = make([]SendEmailAttachment, 0)
 
-	state = fflib.FFParse_after_value
-	goto mainparse
+			wantVal := true
 
-handle_OutgoingPendingFriends:
+			for {
 
-	/* handler: uj.OutgoingPendingFriends type=string kind=string quoted=false*/
+				var v SendEmailAttachment
 
-	{
+				tok = fs.Scan()
+				if tok == fflib.FFTok_error {
+					goto tokerror
+				}
+				if tok == fflib.FFTok_right_brace {
+					break
+				}
 
-		{
-			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-			}
-		}
+				if tok == fflib.FFTok_comma {
+					if wantVal == true {
+						// TODO(pquerna): this isn't an ideal error message, this handles
+						// things like [,,,] as an array value.
+						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+					}
+					continue
+				} else {
+					wantVal = true
+				}
 
-		if tok == fflib.FFTok_null {
+				/* handler: v type=pcDatabase.SendEmailAttachment kind=struct quoted=false*/
 
-		} else {
+				{
+					if tok == fflib.FFTok_null {
 
-			outBuf := fs.Output.Bytes()
+						state = fflib.FFParse_after_value
+						goto mainparse
+					}
 
-			uj.OutgoingPendingFriends = string(string(outBuf))
+					err = v.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+					if err != nil {
+						return err
+					}
+					state = fflib.FFParse_after_value
+				}
 
+				uj.Attachments = append(uj.Attachments, v)
+				wantVal = false
+			}
 		}
 	}
 
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_CIDs:
+handle_Content:
 
-	/* handler: uj.CIDs type=string kind=string quoted=false*/
+	/* handler: uj.Content type=string kind=string quoted=false*/
 
 	{
 
@@ -15067,7 +11511,7 @@ handle_CIDs:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.CIDs = string(string(outBuf))
+			uj.Content = string(string(outBuf))
 
 		}
 	}
@@ -15075,9 +11519,9 @@ handle_CIDs:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_ScheduledMessages:
+handle_RecvTime:
 
-	/* handler: uj.ScheduledMessages type=string kind=string quoted=false*/
+	/* handler: uj.RecvTime type=string kind=string quoted=false*/
 
 	{
 
@@ -15093,7 +11537,7 @@ handle_ScheduledMessages:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.ScheduledMessages = string(string(outBuf))
+			uj.RecvTime = string(string(outBuf))
 
 		}
 	}
@@ -15127,301 +11571,357 @@ handle_EmailMtime:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Android:
+handle_Unread:
 
-	/* handler: uj.Android type=[]string kind=slice quoted=false*/
+	/* handler: uj.Unread type=bool kind=bool quoted=false*/
 
 	{
-
-		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
-			}
+		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
 		}
+	}
 
+	{
 		if tok == fflib.FFTok_null {
-			uj.Android = nil
-		} else {
-
-			uj.Android = make([]string, 0)
-
-			wantVal := true
-
-			for {
-
-				var v string
-
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
-				}
-				if tok == fflib.FFTok_right_brace {
-					break
-				}
-
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
-				}
-
-				/* handler: v type=string kind=string quoted=false*/
-
-				{
 
-					{
-						if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-							return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-						}
-					}
-
-					if tok == fflib.FFTok_null {
+		} else {
+			tmpb := fs.Output.Bytes()
 
-					} else {
+			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
 
-						outBuf := fs.Output.Bytes()
+				uj.Unread = true
 
-						v = string(string(outBuf))
+			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
 
-					}
-				}
+				uj.Unread = false
 
-				uj.Android = append(uj.Android, v)
-				wantVal = false
+			} else {
+				err = errors.New("unexpected bytes for true/false value")
+				return fs.WrapErr(err)
 			}
+
 		}
 	}
 
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Fireos:
+handle_Spam:
 
-	/* handler: uj.Fireos type=[]string kind=slice quoted=false*/
+	/* handler: uj.Spam type=bool kind=bool quoted=false*/
 
 	{
-
-		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
-			}
+		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
 		}
+	}
 
+	{
 		if tok == fflib.FFTok_null {
-			uj.Fireos = nil
+
 		} else {
+			tmpb := fs.Output.Bytes()
 
-			uj.Fireos = make([]string, 0)
+			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
 
-			wantVal := true
+				uj.Spam = true
 
-			for {
+			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
 
-				var v string
+				uj.Spam = false
 
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
-				}
-				if tok == fflib.FFTok_right_brace {
-					break
-				}
+			} else {
+				err = errors.New("unexpected bytes for true/false value")
+				return fs.WrapErr(err)
+			}
 
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
-				}
+		}
+	}
 
-				/* handler: v type=string kind=string quoted=false*/
+	state = fflib.FFParse_after_value
+	goto mainparse
 
-				{
+handle_Starred:
 
-					{
-						if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-							return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-						}
-					}
+	/* handler: uj.Starred type=bool kind=bool quoted=false*/
 
-					if tok == fflib.FFTok_null {
+	{
+		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
+		}
+	}
 
-					} else {
+	{
+		if tok == fflib.FFTok_null {
 
-						outBuf := fs.Output.Bytes()
+		} else {
+			tmpb := fs.Output.Bytes()
 
-						v = string(string(outBuf))
+			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
 
-					}
-				}
+				uj.Starred = true
 
-				uj.Fireos = append(uj.Fireos, v)
-				wantVal = false
+			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
+
+				uj.Starred = false
+
+			} else {
+				err = errors.New("unexpected bytes for true/false value")
+				return fs.WrapErr(err)
 			}
+
 		}
 	}
 
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Ios:
+handle_Deleted:
 
-	/* handler: uj.Ios type=[]string kind=slice quoted=false*/
+	/* handler: uj.Deleted type=bool kind=bool quoted=false*/
 
 	{
-
-		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
-			}
+		if tok != fflib.FFTok_bool && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for bool", tok))
 		}
+	}
 
+	{
 		if tok == fflib.FFTok_null {
-			uj.Ios = nil
+
 		} else {
+			tmpb := fs.Output.Bytes()
 
-			uj.Ios = make([]string, 0)
+			if bytes.Compare([]byte{'t', 'r', 'u', 'e'}, tmpb) == 0 {
 
-			wantVal := true
+				uj.Deleted = true
 
-			for {
+			} else if bytes.Compare([]byte{'f', 'a', 'l', 's', 'e'}, tmpb) == 0 {
 
-				var v string
+				uj.Deleted = false
 
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
-				}
-				if tok == fflib.FFTok_right_brace {
-					break
-				}
+			} else {
+				err = errors.New("unexpected bytes for true/false value")
+				return fs.WrapErr(err)
+			}
 
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
-				}
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+wantedvalue:
+	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+wrongtokenerror:
+	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
+tokerror:
+	if fs.BigError != nil {
+		return fs.WrapErr(fs.BigError)
+	}
+	err = fs.Error.ToError()
+	if err != nil {
+		return fs.WrapErr(err)
+	}
+	panic("ffjson-generated: unreachable, please report bug.")
+done:
+	return nil
+}
+
+func (mj *UserFriendStruct) MarshalJSON() ([]byte, error) {
+	var buf fflib.Buffer
+	if mj == nil {
+		buf.WriteString("null")
+		return buf.Bytes(), nil
+	}
+	err := mj.MarshalJSONBuf(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+func (mj *UserFriendStruct) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+	if mj == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	var err error
+	var obj []byte
+	_ = obj
+	_ = err
+	buf.WriteString(`{"Username":`)
+	fflib.WriteJsonString(buf, string(mj.Username))
+	buf.WriteString(`,"ProfilePic":`)
+	fflib.WriteJsonString(buf, string(mj.ProfilePic))
+	buf.WriteString(`,"Message":`)
+	fflib.WriteJsonString(buf, string(mj.Message))
+	buf.WriteByte('}')
+	return nil
+}
+
+const (
+	ffj_t_UserFriendStructbase = iota
+	ffj_t_UserFriendStructno_such_key
 
-				/* handler: v type=string kind=string quoted=false*/
+	ffj_t_UserFriendStruct_Username
 
-				{
+	ffj_t_UserFriendStruct_ProfilePic
 
-					{
-						if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-							return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-						}
-					}
+	ffj_t_UserFriendStruct_Message
+)
 
-					if tok == fflib.FFTok_null {
+var ffj_key_UserFriendStruct_Username = []byte("Username")
 
-					} else {
+var ffj_key_UserFriendStruct_ProfilePic = []byte("ProfilePic")
 
-						outBuf := fs.Output.Bytes()
+var ffj_key_UserFriendStruct_Message = []byte("Message")
 
-						v = string(string(outBuf))
+func (uj *UserFriendStruct) UnmarshalJSON(input []byte) error {
+	fs := fflib.NewFFLexer(input)
+	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
+}
 
-					}
-				}
+func (uj *UserFriendStruct) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+	var err error = nil
+	currentKey := ffj_t_UserFriendStructbase
+	_ = currentKey
+	tok := fflib.FFTok_init
+	wantedTok := fflib.FFTok_init
 
-				uj.Ios = append(uj.Ios, v)
-				wantVal = false
-			}
+mainparse:
+	for {
+		tok = fs.Scan()
+		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
+		if tok == fflib.FFTok_error {
+			goto tokerror
 		}
-	}
 
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Web:
+		switch state {
 
-	/* handler: uj.Web type=[]string kind=slice quoted=false*/
+		case fflib.FFParse_map_start:
+			if tok != fflib.FFTok_left_bracket {
+				wantedTok = fflib.FFTok_left_bracket
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_key
+			continue
 
-	{
+		case fflib.FFParse_after_value:
+			if tok == fflib.FFTok_comma {
+				state = fflib.FFParse_want_key
+			} else if tok == fflib.FFTok_right_bracket {
+				goto done
+			} else {
+				wantedTok = fflib.FFTok_comma
+				goto wrongtokenerror
+			}
 
-		{
-			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
-				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
+		case fflib.FFParse_want_key:
+			// json {} ended. goto exit. woo.
+			if tok == fflib.FFTok_right_bracket {
+				goto done
+			}
+			if tok != fflib.FFTok_string {
+				wantedTok = fflib.FFTok_string
+				goto wrongtokenerror
 			}
-		}
 
-		if tok == fflib.FFTok_null {
-			uj.Web = nil
-		} else {
+			kn := fs.Output.Bytes()
+			if len(kn) <= 0 {
+				// "" case. hrm.
+				currentKey = ffj_t_UserFriendStructno_such_key
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			} else {
+				switch kn[0] {
 
-			uj.Web = make([]string, 0)
+				case 'M':
 
-			wantVal := true
+					if bytes.Equal(ffj_key_UserFriendStruct_Message, kn) {
+						currentKey = ffj_t_UserFriendStruct_Message
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
 
-			for {
+				case 'P':
 
-				var v string
+					if bytes.Equal(ffj_key_UserFriendStruct_ProfilePic, kn) {
+						currentKey = ffj_t_UserFriendStruct_ProfilePic
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'U':
+
+					if bytes.Equal(ffj_key_UserFriendStruct_Username, kn) {
+						currentKey = ffj_t_UserFriendStruct_Username
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
 
-				tok = fs.Scan()
-				if tok == fflib.FFTok_error {
-					goto tokerror
 				}
-				if tok == fflib.FFTok_right_brace {
-					break
+
+				if fflib.EqualFoldRight(ffj_key_UserFriendStruct_Message, kn) {
+					currentKey = ffj_t_UserFriendStruct_Message
+					state = fflib.FFParse_want_colon
+					goto mainparse
 				}
 
-				if tok == fflib.FFTok_comma {
-					if wantVal == true {
-						// TODO(pquerna): this isn't an ideal error message, this handles
-						// things like [,,,] as an array value.
-						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
-					}
-					continue
-				} else {
-					wantVal = true
+				if fflib.SimpleLetterEqualFold(ffj_key_UserFriendStruct_ProfilePic, kn) {
+					currentKey = ffj_t_UserFriendStruct_ProfilePic
+					state = fflib.FFParse_want_colon
+					goto mainparse
 				}
 
-				/* handler: v type=string kind=string quoted=false*/
+				if fflib.EqualFoldRight(ffj_key_UserFriendStruct_Username, kn) {
+					currentKey = ffj_t_UserFriendStruct_Username
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
 
-				{
+				currentKey = ffj_t_UserFriendStructno_such_key
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			}
 
-					{
-						if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
-							return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
-						}
-					}
+		case fflib.FFParse_want_colon:
+			if tok != fflib.FFTok_colon {
+				wantedTok = fflib.FFTok_colon
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_value
+			continue
+		case fflib.FFParse_want_value:
 
-					if tok == fflib.FFTok_null {
+			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
+				switch currentKey {
 
-					} else {
+				case ffj_t_UserFriendStruct_Username:
+					goto handle_Username
 
-						outBuf := fs.Output.Bytes()
+				case ffj_t_UserFriendStruct_ProfilePic:
+					goto handle_ProfilePic
 
-						v = string(string(outBuf))
+				case ffj_t_UserFriendStruct_Message:
+					goto handle_Message
 
+				case ffj_t_UserFriendStructno_such_key:
+					err = fs.SkipField(tok)
+					if err != nil {
+						return fs.WrapErr(err)
 					}
+					state = fflib.FFParse_after_value
+					goto mainparse
 				}
-
-				uj.Web = append(uj.Web, v)
-				wantVal = false
+			} else {
+				goto wantedvalue
 			}
 		}
 	}
 
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_ProfilePic:
+handle_Username:
 
-	/* handler: uj.ProfilePic type=string kind=string quoted=false*/
+	/* handler: uj.Username type=string kind=string quoted=false*/
 
 	{
 
@@ -15437,67 +11937,7 @@ handle_ProfilePic:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.ProfilePic = string(string(outBuf))
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_Quota:
-
-	/* handler: uj.Quota type=uint32 kind=uint32 quoted=false*/
-
-	{
-		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for uint32", tok))
-		}
-	}
-
-	{
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			tval, err := fflib.ParseUint(fs.Output.Bytes(), 10, 32)
-
-			if err != nil {
-				return fs.WrapErr(err)
-			}
-
-			uj.Quota = uint32(tval)
-
-		}
-	}
-
-	state = fflib.FFParse_after_value
-	goto mainparse
-
-handle_QuotaUsed:
-
-	/* handler: uj.QuotaUsed type=uint32 kind=uint32 quoted=false*/
-
-	{
-		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for uint32", tok))
-		}
-	}
-
-	{
-
-		if tok == fflib.FFTok_null {
-
-		} else {
-
-			tval, err := fflib.ParseUint(fs.Output.Bytes(), 10, 32)
-
-			if err != nil {
-				return fs.WrapErr(err)
-			}
-
-			uj.QuotaUsed = uint32(tval)
+			uj.Username = string(string(outBuf))
 
 		}
 	}
@@ -15505,9 +11945,9 @@ handle_QuotaUsed:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_SecQuests:
+handle_ProfilePic:
 
-	/* handler: uj.SecQuests type=string kind=string quoted=false*/
+	/* handler: uj.ProfilePic type=string kind=string quoted=false*/
 
 	{
 
@@ -15523,7 +11963,7 @@ handle_SecQuests:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.SecQuests = string(string(outBuf))
+			uj.ProfilePic = string(string(outBuf))
 
 		}
 	}
@@ -15531,9 +11971,9 @@ handle_SecQuests:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
-handle_Cmd:
+handle_Message:
 
-	/* handler: uj.Cmd type=string kind=string quoted=false*/
+	/* handler: uj.Message type=string kind=string quoted=false*/
 
 	{
 
@@ -15549,7 +11989,7 @@ handle_Cmd:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Cmd = string(string(outBuf))
+			uj.Message = string(string(outBuf))
 
 		}
 	}
@@ -0,0 +1,89 @@
+package pcDatabase
+
+import (
+	"strings"
+)
+
+// THREADS
+// a message sent with p_MID is a reply in that message's thread.  Replies
+// stay out of the conversation's own messages (GetConvoData and the rest),
+// the message they reply to has reply_count and last_reply instead, which
+// leave out deleted replies, and GetThread pages through them.  A reply to a reply goes in the first
+// message's thread, threads only go one deep.  Everyone who sent the first
+// message or replied to it is pushed the replies even if they muted the
+// conversation (MuteConversation).
+
+// replies per GetThread
+const THREAD_PAGE_SIZE = 50
+
+// the MID of the thread MID is in, which is MID itself unless it's a reply.
+// Replies can't go to deleted messages but a deleted message's thread can
+// still be read
+func (db *Database) threadRoot(CID string, MID string, deletedOK bool) (string, error) {
+	if db.Messages == nil {
+		return "", ErrMessagesDown
+	}
+	row, err := db.Messages.GetMessage(CID, MID)
+	if err == ErrNoMessage || (err == nil && row.Deleted && !deletedOK) {
+		return "", ErrMessageNotFound
+	} else if err != nil {
+		logPqError("threadRoot", err)
+		return "", ErrMessagesDown
+	}
+	if row.P_MID != "" {
+		return row.P_MID, nil
+	}
+	return MID, nil
+}
+
+// upper case usernames of who's in the thread
+func (db *Database) threadParticipants(CID string, parentMID string) map[string]bool {
+	participants := make(map[string]bool)
+	usernames, err := db.Messages.GetThreadParticipants(CID, parentMID)
+	if err != nil {
+		logPqError("threadParticipants", err)
+	}
+	for _, username := range usernames {
+		participants[strings.ToUpper(username)] = true
+	}
+	return participants
+}
+
+// whether user muted CID
+func convoMuted(user UserStruct, CID string) bool {
+	for _, convo := range user.GetCIDStructs() {
+		if convo.CID == CID {
+			return convo.Muted == 1
+		}
+	}
+	return false
+}
+
+// GetThread is the message MID's thread, THREAD_PAGE_SIZE replies at a time
+// after M_time
+func (db *Database) GetThread(jsondata *ThreadCmdStruct) (*ThreadResponse, error) {
+	parentMID, err := db.threadRoot(jsondata.CID, jsondata.MID, true)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := db.Messages.GetMessage(jsondata.CID, parentMID)
+	if err != nil {
+		logPqError("GetThread", err)
+		return nil, ErrMessagesDown
+	}
+	mtime := jsondata.M_time
+	if mtime == "" {
+		mtime = "1970-01-01T00:00:00.000Z"
+	}
+	// one more than a page to know if there's another one
+	replies, err := db.Messages.GetReplies(jsondata.CID, parentMID, mtime, jsondata.After_MID, THREAD_PAGE_SIZE+1)
+	if err != nil {
+		logPqError("GetThread", err)
+		return nil, ErrMessagesDown
+	}
+	more := len(replies) > THREAD_PAGE_SIZE
+	if more {
+		replies = replies[:THREAD_PAGE_SIZE]
+	}
+	return &ThreadResponse{CID: jsondata.CID, Parent: parent, Replies: replies, More: more}, nil
+}
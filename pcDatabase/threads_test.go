@@ -0,0 +1,91 @@
+package pcDatabase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThreadReplies(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	CID, MID := sendTestMessage(t, db)
+
+	if env := dispatch(t, db, `{"cmd":"SendMessage","CID":"`+CID+`","t_UIDs":["alice","bob"],"m_time":"2015-06-12T19:21:00.000Z","content":"bob?","p_MID":"`+MID+`"}`, nil); !env.OK {
+		t.Fatalf("SendMessage reply returned %+v", env)
+	}
+	expectEvents(t, db, 2)
+	thread := ThreadResponse{}
+	dispatch(t, db, `{"cmd":"GetThread","CID":"`+CID+`","MID":"`+MID+`"}`, &thread)
+	if len(thread.Replies) != 1 || thread.Replies[0].P_MID != MID || thread.More {
+		t.Fatalf("GetThread returned %+v", thread)
+	}
+	// a reply to the reply goes in the same thread
+	reply := thread.Replies[0].MID
+	dispatch(t, db, `{"cmd":"SendMessage","CID":"`+CID+`","t_UIDs":["alice","bob"],"m_time":"2015-06-12T19:22:00.000Z","content":"bob!!","p_MID":"`+reply+`"}`, nil)
+	expectEvents(t, db, 2)
+
+	convoData, _ := db.GetConvoData(&CIDCommandStruct{Cmd: "GetConvoData", CID: CID})
+	if len(convoData.Messages) != 1 || convoData.Messages[0].ReplyCount != 2 || convoData.Messages[0].LastReply == nil {
+		t.Errorf("GetConvoData returned messages %+v", convoData.Messages)
+	}
+	thread = ThreadResponse{}
+	dispatch(t, db, `{"cmd":"GetThread","CID":"`+CID+`","MID":"`+reply+`","M_time":"2015-06-12T19:21:00.000Z"}`, &thread)
+	if thread.Parent.MID != MID || len(thread.Replies) != 1 || thread.Replies[0].Content != "bob!!" {
+		t.Errorf("GetThread after the first reply returned %+v", thread)
+	}
+	// deleting the last reply takes it out of the summary
+	dispatch(t, db, `{"cmd":"DeleteMessage","CID":"`+CID+`","MID":"`+thread.Replies[0].MID+`"}`, nil)
+	expectEvents(t, db, 2)
+	convoData, _ = db.GetConvoData(&CIDCommandStruct{Cmd: "GetConvoData", CID: CID})
+	if summary := convoData.Messages[0]; summary.ReplyCount != 1 || summary.LastReply == nil || summary.LastReply.Format(time.RFC3339) != "2015-06-12T19:21:00Z" {
+		t.Errorf("after deleting a reply GetConvoData returned messages %+v", convoData.Messages)
+	}
+
+	// replies at the same m_time page by MID
+	for _, sender := range []string{"alice", "bob"} {
+		db.Messages.AddMessage(MessageStruct{CID: CID, MID: newMessageID(), FromUsername: sender, M_time: "2015-06-12T19:23:00.000Z", Content: "same time", ParentMID: MID})
+	}
+	first, _ := db.Messages.GetReplies(CID, MID, "2015-06-12T19:22:30.000Z", "", 1)
+	second, _ := db.Messages.GetReplies(CID, MID, "2015-06-12T19:23:00.000Z", first[0].MID, 1)
+	if len(second) != 1 || second[0].MID <= first[0].MID || second[0].F_username == first[0].F_username {
+		t.Errorf("the second page after %+v was %+v", first, second)
+	}
+	expectError(t, db, `{"cmd":"SendMessage","CID":"`+CID+`","t_UIDs":["alice","bob"],"m_time":"2015-06-12T19:23:00.000Z","content":"hi","p_MID":"nope"}`, ERR_NOT_FOUND)
+	expectError(t, db, `{"cmd":"GetThread","CID":"`+CID+`","MID":"nope"}`, ERR_NOT_FOUND)
+}
+
+func TestMuteConversation(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	CID, MID := sendTestMessage(t, db)
+
+	if env := dispatch(t, db, `{"cmd":"MuteConversation","CID":"`+CID+`","muted":true}`, nil); !env.OK {
+		t.Fatalf("MuteConversation returned %+v", env)
+	}
+	alice := db.Users.GetUser("alice")
+	if !convoMuted(alice, CID) {
+		t.Fatalf("alice's conversations are %+v", alice.GetCIDStructs())
+	}
+	// alice sent MID so alice is in its thread, bob isn't yet
+	participants := db.threadParticipants(CID, MID)
+	if !participants["ALICE"] || participants["BOB"] {
+		t.Errorf("thread participants are %v", participants)
+	}
+	// bob replying puts bob in it, until the reply is deleted
+	bob := newTestConnection(db)
+	defer bob.Close()
+	dispatch(t, bob, `{"cmd":"ValidateUser","username":"bob","password":"secret","token":"bob-phone"}`, nil)
+	dispatch(t, bob, `{"cmd":"SendMessage","CID":"`+CID+`","t_UIDs":["alice","bob"],"m_time":"2015-06-12T19:21:00.000Z","content":"hi","p_MID":"`+MID+`"}`, nil)
+	replies, _ := db.Messages.GetReplies(CID, MID, "", "", 10)
+	if len(replies) != 1 || !db.threadParticipants(CID, MID)["BOB"] {
+		t.Fatalf("bob's reply %+v", replies)
+	}
+	db.Messages.DeleteMessage(CID, replies[0].MID, "2015-06-12T19:22:00.000Z")
+	if participants = db.threadParticipants(CID, MID); participants["BOB"] {
+		t.Errorf("bob's deleted reply kept bob in the thread %v", participants)
+	}
+	dispatch(t, db, `{"cmd":"MuteConversation","CID":"`+CID+`","muted":false}`, nil)
+	if alice = db.Users.GetUser("alice"); convoMuted(alice, CID) {
+		t.Errorf("alice's conversation is still muted: %+v", alice.GetCIDStructs())
+	}
+}
@@ -0,0 +1,120 @@
+package pcDatabase
+
+import (
+	"github.com/pquerna/ffjson/ffjson"
+	"strings"
+	"time"
+)
+
+// TYPING
+// whether someone is typing is never saved, it's only sent to the web
+// devices of the other members as a Typing event.  Clients send SetTyping
+// every few seconds while the user types, only a change goes out and the
+// rest just keep it from running out.  The session clears it (sending
+// typing false) once TYPING_TIMEOUT goes by without one, and clears every
+// conversation it's typing in when it ends.
+//
+// the typing map is only touched by the session's own goroutine, commands
+// and the expiry timer both run in Run.  The timer is only armed while the
+// user is typing somewhere, for whichever conversation runs out first, so
+// idle sessions never wake up for it
+
+// how long typing lasts after the last SetTyping
+const TYPING_TIMEOUT = 6 * time.Second
+
+type TypingEvent struct {
+	Cmd      string `json:"cmd"`
+	CID      string `json:"CID"`
+	Username string `json:"Username"`
+	Typing   bool   `json:"typing"`
+}
+
+// sends whether the caller is typing in CID to everyone else in it
+func (db *Database) publishTyping(CID string, typing bool) {
+	eventBytes, err := ffjson.Marshal(TypingEvent{Cmd: "Typing", CID: CID, Username: db.Username(), Typing: typing})
+	if err != nil {
+		ERROR.Println("error in ffjson.Marshal in publishTyping:", err)
+		return
+	}
+	for _, m := range ToConvoMemberArray(db.Convos.GetConvoMembers(CID)) {
+		if strings.EqualFold(m.Username, db.Username()) {
+			continue
+		}
+		user := db.Users.GetUser(m.Username)
+		db.SendStringToWebDevices(user.Web, string(eventBytes))
+	}
+}
+
+// starts, keeps up or stops the caller typing in CID
+func (db *Database) setTyping(CID string, typing bool) {
+	_, wasTyping := db.typing[CID]
+	defer db.armTypingTimer()
+	if !typing {
+		delete(db.typing, CID)
+		if wasTyping {
+			db.publishTyping(CID, false)
+		}
+		return
+	}
+	if db.typing == nil {
+		db.typing = make(map[string]time.Time)
+	}
+	db.typing[CID] = time.Now().Add(TYPING_TIMEOUT)
+	if !wasTyping {
+		db.publishTyping(CID, true)
+	}
+}
+
+// sets typingTimer to go off when the first typing runs out, or stops it
+// when there's none
+func (db *Database) armTypingTimer() {
+	if db.typingTimer != nil && !db.typingTimer.Stop() {
+		// went off without Run reading it
+		select {
+		case <-db.typingTimer.C:
+		default:
+		}
+	}
+	var first time.Time
+	for _, expires := range db.typing {
+		if first.IsZero() || expires.Before(first) {
+			first = expires
+		}
+	}
+	if first.IsZero() {
+		return
+	}
+	if db.typingTimer == nil {
+		db.typingTimer = time.NewTimer(time.Until(first))
+	} else {
+		db.typingTimer.Reset(time.Until(first))
+	}
+}
+
+// for Run's select, nil (never ready) while nobody's typing
+func (db *Database) typingExpired() <-chan time.Time {
+	if db.typingTimer == nil || len(db.typing) == 0 {
+		return nil
+	}
+	return db.typingTimer.C
+}
+
+// stops typing that's run out by now
+func (db *Database) expireTyping(now time.Time) {
+	for CID, expires := range db.typing {
+		if !now.Before(expires) {
+			db.setTyping(CID, false)
+		}
+	}
+}
+
+// stops typing everywhere, for when the session ends
+func (db *Database) clearTyping() {
+	for CID := range db.typing {
+		db.setTyping(CID, false)
+	}
+}
+
+func (db *Database) SetTyping(jsondata *TypingCmdStruct) {
+	db.setTyping(jsondata.CID, jsondata.Typing)
+}
@@ -0,0 +1,48 @@
+package pcDatabase
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTypingIsOnlySentOut(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "bob")
+	createTestUser(t, db, "alice")
+	CID := createTestConversation(t, db, "alice", "bob")
+	members := db.Convos.GetConvoMembers(CID)
+	mtime := db.Convos.GetConvoMtime(CID)
+
+	dispatch(t, db, `{"cmd":"SetTyping","CID":"`+CID+`","typing":true}`, nil)
+	if event := expectEvents(t, db, 1)[0]; !strings.Contains(event, `"typing":true`) {
+		t.Errorf("unexpected event %s", event)
+	}
+	// still typing, nothing new to send
+	dispatch(t, db, `{"cmd":"UpdateUserStatus","CID":"`+CID+`","Typing":true}`, nil)
+	db.expireTyping(time.Now())
+	select {
+	case event := <-db.nats_receive:
+		t.Errorf("typing again sent %s", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+	db.expireTyping(time.Now().Add(TYPING_TIMEOUT + time.Second))
+	if event := expectEvents(t, db, 1)[0]; !strings.Contains(event, `"typing":false`) {
+		t.Errorf("unexpected event after typing ran out %s", event)
+	}
+	if db.typingExpired() != nil {
+		t.Errorf("the typing timer is still armed with nobody typing")
+	}
+
+	// the session ending stops it too
+	dispatch(t, db, `{"cmd":"SetTyping","CID":"`+CID+`","typing":true}`, nil)
+	expectEvents(t, db, 1)
+	db.clearTyping()
+	if event := expectEvents(t, db, 1)[0]; !strings.Contains(event, `"typing":false`) {
+		t.Errorf("unexpected event when the session ended %s", event)
+	}
+	if got := db.Convos.GetConvoMembers(CID); strings.Join(got, "") != strings.Join(members, "") || db.Convos.GetConvoMtime(CID) != mtime {
+		t.Errorf("typing changed the conversation, members %v m_time %s", got, db.Convos.GetConvoMtime(CID))
+	}
+}
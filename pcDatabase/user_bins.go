@@ -0,0 +1,260 @@
+package pcDatabase
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// USER BINS
+// the lists in UserStruct (CIDs, Friends, ...) are JSON strings because
+// that's what clients are sent, but in aerospike they're maps keyed by CID
+// or username, so a single conversation can be changed on the server without
+// reading and rewriting the whole user (see UserStore.BumpUserConvo).
+//
+// a map op can't reach into another map's values, so a user's conversations
+// are spread over four maps, all keyed by CID:
+//   CIDs         CID -> M_time
+//   CIDUnread    CID -> UnreadCount
+//   CIDAutoreply CID -> AutoreplySent
+//   CIDMuted     CID -> Muted
+// Friends, InPendFriend and OutPendFriend map the friend's upper case
+// username to {Username, ProfilePic, Message}.  SchedMessages and SecQuests
+// aren't keyed by anything, they're lists of maps.
+//
+// users written by older servers still have JSON strings in these bins,
+// FillUserWithAerospikeBins reads either, see users_migration.go
+
+const (
+	AEROSPIKE_USERS_CIDS_BIN          = "CIDs"
+	AEROSPIKE_USERS_CID_UNREAD_BIN    = "CIDUnread"
+	AEROSPIKE_USERS_CID_AUTOREPLY_BIN = "CIDAutoreply"
+	AEROSPIKE_USERS_CID_MUTED_BIN     = "CIDMuted"
+)
+
+// the bins older servers wrote JSON strings to
+var legacyJSONUserBins = []string{AEROSPIKE_USERS_CIDS_BIN, "Friends", "InPendFriend", "OutPendFriend", "SchedMessages", "SecQuests"}
+
+// from the UserStore conversation updates, the user isn't in the conversation
+// or doesn't exist
+var ErrNotInConvo = errors.New("the user isn't in that conversation")
+
+func hasLegacyUserBins(bins map[string]interface{}) bool {
+	for _, name := range legacyJSONUserBins {
+		if _, ok := bins[name].(string); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// aerospike has no booleans, 1 is true
+func boolToBin(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// a map bin, nil if it's missing or isn't a map
+func binMap(value interface{}) map[interface{}]interface{} {
+	m, _ := value.(map[interface{}]interface{})
+	return m
+}
+
+func binString(m map[interface{}]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// copy of a map bin, so it can be changed without touching the original
+func copyBinMap(value interface{}) map[interface{}]interface{} {
+	copied := make(map[interface{}]interface{})
+	for k, v := range binMap(value) {
+		copied[k] = v
+	}
+	return copied
+}
+
+func cidsToBins(CIDs []UserCIDStruct) (mtimes, unread, autoreply, muted map[interface{}]interface{}) {
+	mtimes = make(map[interface{}]interface{}, len(CIDs))
+	unread = make(map[interface{}]interface{}, len(CIDs))
+	autoreply = make(map[interface{}]interface{}, len(CIDs))
+	muted = make(map[interface{}]interface{}, len(CIDs))
+	for _, convo := range CIDs {
+		mtimes[convo.CID] = convo.M_time
+		unread[convo.CID] = convo.UnreadCount
+		autoreply[convo.CID] = convo.AutoreplySent
+		muted[convo.CID] = convo.Muted
+	}
+	return mtimes, unread, autoreply, muted
+}
+
+// newest conversation first
+func cidsFromBins(mtimes, unread, autoreply, muted map[interface{}]interface{}) []UserCIDStruct {
+	CIDs := make([]UserCIDStruct, 0, len(mtimes))
+	for CID, mtime := range mtimes {
+		convo := UserCIDStruct{CID: CID.(string)}
+		convo.M_time, _ = mtime.(string)
+		convo.UnreadCount, _ = unread[CID].(int)
+		convo.AutoreplySent, _ = autoreply[CID].(int)
+		convo.Muted, _ = muted[CID].(int)
+		CIDs = append(CIDs, convo)
+	}
+	sort.Slice(CIDs, func(i, j int) bool {
+		if CIDs[i].M_time != CIDs[j].M_time {
+			return CIDs[i].M_time > CIDs[j].M_time
+		}
+		return CIDs[i].CID < CIDs[j].CID
+	})
+	return CIDs
+}
+
+func friendsToBin(friends []UserFriendStruct) map[interface{}]interface{} {
+	bin := make(map[interface{}]interface{}, len(friends))
+	for _, friend := range friends {
+		bin[strings.ToUpper(friend.Username)] = map[interface{}]interface{}{
+			"Username":   friend.Username,
+			"ProfilePic": friend.ProfilePic,
+			"Message":    friend.Message,
+		}
+	}
+	return bin
+}
+
+// sorted by username
+func friendsFromBin(bin map[interface{}]interface{}) []UserFriendStruct {
+	friends := make([]UserFriendStruct, 0, len(bin))
+	for _, value := range bin {
+		friend := binMap(value)
+		friends = append(friends, UserFriendStruct{
+			Username:   binString(friend, "Username"),
+			ProfilePic: binString(friend, "ProfilePic"),
+			Message:    binString(friend, "Message"),
+		})
+	}
+	sort.Slice(friends, func(i, j int) bool {
+		return strings.ToUpper(friends[i].Username) < strings.ToUpper(friends[j].Username)
+	})
+	return friends
+}
+
+func scheduledMessagesToBin(scheduled []ScheduledMessagesStruct) []interface{} {
+	bin := make([]interface{}, len(scheduled))
+	for i, msg := range scheduled {
+		bin[i] = map[interface{}]interface{}{"CID": msg.CID, "Time": msg.Time, "Content": msg.Content}
+	}
+	return bin
+}
+
+func scheduledMessagesFromBin(bin []interface{}) []ScheduledMessagesStruct {
+	scheduled := make([]ScheduledMessagesStruct, len(bin))
+	for i, value := range bin {
+		msg := binMap(value)
+		scheduled[i] = ScheduledMessagesStruct{CID: binString(msg, "CID"), Time: binString(msg, "Time"), Content: binString(msg, "Content")}
+	}
+	return scheduled
+}
+
+func securityQuestionsToBin(questions []SecurityQuestionStruct) []interface{} {
+	bin := make([]interface{}, len(questions))
+	for i, question := range questions {
+		bin[i] = map[interface{}]interface{}{"Question": question.Question, "Answer": question.Answer}
+	}
+	return bin
+}
+
+func securityQuestionsFromBin(bin []interface{}) []SecurityQuestionStruct {
+	questions := make([]SecurityQuestionStruct, len(bin))
+	for i, value := range bin {
+		question := binMap(value)
+		questions[i] = SecurityQuestionStruct{Question: binString(question, "Question"), Answer: binString(question, "Answer")}
+	}
+	return questions
+}
+
+// the JSON string fields of user as map and list bins, empty strings are
+// empty maps and lists
+func (user *UserStruct) jsonFieldsToBins() map[string]interface{} {
+	CIDs := []UserCIDStruct{}
+	if user.CIDs != "" {
+		CIDs = user.GetCIDStructs()
+	}
+	mtimes, unread, autoreply, muted := cidsToBins(CIDs)
+	bins := map[string]interface{}{
+		AEROSPIKE_USERS_CIDS_BIN:          mtimes,
+		AEROSPIKE_USERS_CID_UNREAD_BIN:    unread,
+		AEROSPIKE_USERS_CID_AUTOREPLY_BIN: autoreply,
+		AEROSPIKE_USERS_CID_MUTED_BIN:     muted,
+		"Friends":                         friendsToBin(nil),
+		"InPendFriend":                    friendsToBin(nil),
+		"OutPendFriend":                   friendsToBin(nil),
+		"SchedMessages":                   scheduledMessagesToBin(nil),
+		"SecQuests":                       securityQuestionsToBin(nil),
+	}
+	if user.Friends != "" {
+		bins["Friends"] = friendsToBin(user.GetFriendStructs())
+	}
+	if user.IncomingPendingFriends != "" {
+		bins["InPendFriend"] = friendsToBin(user.GetIncomingPendingFriendStructs())
+	}
+	if user.OutgoingPendingFriends != "" {
+		bins["OutPendFriend"] = friendsToBin(user.GetOutgoingPendingFriendStructs())
+	}
+	if user.ScheduledMessages != "" {
+		bins["SchedMessages"] = scheduledMessagesToBin(user.GetScheduledMessagesStructs())
+	}
+	if user.SecQuests != "" {
+		bins["SecQuests"] = securityQuestionsToBin(user.GetSecurityQuestionStructs())
+	}
+	return bins
+}
+
+// fills the JSON string fields of user from recbins, bins that are still
+// JSON strings are taken as they are
+func (user *UserStruct) fillJSONFieldsFromBins(recbins map[string]interface{}) {
+	if value, ok := recbins[AEROSPIKE_USERS_CIDS_BIN]; ok {
+		if legacy, ok := value.(string); ok {
+			user.CIDs = legacy
+		} else {
+			user.SaveCIDStructs(cidsFromBins(binMap(value), binMap(recbins[AEROSPIKE_USERS_CID_UNREAD_BIN]), binMap(recbins[AEROSPIKE_USERS_CID_AUTOREPLY_BIN]), binMap(recbins[AEROSPIKE_USERS_CID_MUTED_BIN])))
+		}
+	}
+	if value, ok := recbins["Friends"]; ok {
+		if legacy, ok := value.(string); ok {
+			user.Friends = legacy
+		} else {
+			user.SaveFriendStructs(friendsFromBin(binMap(value)))
+		}
+	}
+	if value, ok := recbins["InPendFriend"]; ok {
+		if legacy, ok := value.(string); ok {
+			user.IncomingPendingFriends = legacy
+		} else {
+			user.SaveIncomingPendingFriendStructs(friendsFromBin(binMap(value)))
+		}
+	}
+	if value, ok := recbins["OutPendFriend"]; ok {
+		if legacy, ok := value.(string); ok {
+			user.OutgoingPendingFriends = legacy
+		} else {
+			user.SaveOutgoingPendingFriendStructs(friendsFromBin(binMap(value)))
+		}
+	}
+	if value, ok := recbins["SchedMessages"]; ok {
+		if legacy, ok := value.(string); ok {
+			user.ScheduledMessages = legacy
+		} else {
+			list, _ := value.([]interface{})
+			user.SaveScheduledMessagesStructs(scheduledMessagesFromBin(list))
+		}
+	}
+	if value, ok := recbins["SecQuests"]; ok {
+		if legacy, ok := value.(string); ok {
+			user.SecQuests = legacy
+		} else {
+			list, _ := value.([]interface{})
+			user.SaveSecurityQuestionStructs(securityQuestionsFromBin(list))
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package pcDatabase
+
+import (
+	"testing"
+)
+
+func TestUserConvosAreMapsUpdatedInPlace(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "bob")
+	createTestUser(t, db, "alice")
+	CID := createTestConversation(t, db, "alice", "bob")
+
+	store := db.Users.(*MemoryStore)
+	if _, ok := store.users["BOB"][AEROSPIKE_USERS_CIDS_BIN].(map[interface{}]interface{}); !ok {
+		t.Fatalf("bob's CIDs bin is %#v", store.users["BOB"][AEROSPIKE_USERS_CIDS_BIN])
+	}
+	if err := store.BumpUserConvo("bob", CID, "2015-06-12T19:20:00.000Z"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.BumpUserConvo("bob", CID, "2015-06-12T19:21:00.000Z"); err != nil {
+		t.Fatal(err)
+	}
+	bob := store.GetUser("bob")
+	CIDs := bob.GetCIDStructs()
+	if len(CIDs) != 1 || CIDs[0].UnreadCount != 2 || CIDs[0].M_time != "2015-06-12T19:21:00.000Z" {
+		t.Errorf("bob's conversations are %+v", CIDs)
+	}
+	if err := store.SetUserConvoUnread("bob", "not-a-convo", 3); err != ErrNotInConvo {
+		t.Errorf("SetUserConvoUnread on another conversation returned %v", err)
+	}
+	if err := store.SetUserConvoMtime("nobody", CID, "2015-06-12T19:21:00.000Z"); err != ErrNotInConvo {
+		t.Errorf("SetUserConvoMtime on a missing user returned %v", err)
+	}
+}
+
+func TestLegacyJSONUserBinsAreConvertedOnWrite(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "bob")
+	// what older servers wrote
+	db.Users.UpdateUserFields("bob", UserFields{
+		"CIDs":         `[{"CID":"CID1","M_time":"2015-06-12T19:20:00.000Z","UnreadCount":4,"AutoreplySent":0}]`,
+		"Friends":      `[{"Username":"alice","ProfilePic":"","Message":""}]`,
+		"InPendFriend": `[]`,
+	})
+
+	bob := db.Users.GetUser("bob")
+	if CIDs := bob.GetCIDStructs(); len(CIDs) != 1 || CIDs[0].UnreadCount != 4 {
+		t.Errorf("bob's conversations are %+v", CIDs)
+	}
+	if _, err := db.updateUser("bob", func(user *UserStruct) error {
+		user.ProfilePic = "bob.png"
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if bins := db.Users.(*MemoryStore).users["BOB"]; hasLegacyUserBins(bins) {
+		t.Errorf("bob still has JSON bins after a write: %v", bins)
+	}
+	bob = db.Users.GetUser("bob")
+	if CIDs := bob.GetCIDStructs(); len(CIDs) != 1 || CIDs[0].UnreadCount != 4 {
+		t.Errorf("bob's conversations are %+v after the write", CIDs)
+	}
+	if friends := bob.GetFriendStructs(); len(friends) != 1 || friends[0].Username != "alice" {
+		t.Errorf("bob's friends are %+v after the write", friends)
+	}
+}
@@ -0,0 +1,98 @@
+package pcDatabase
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// USER UPDATES
+// a user is one record, and apart from the single conversation updates in
+// user_bins.go changing it means reading the whole record, editing it and
+// writing it back.  Two sessions doing that at once (two messages to the
+// same recipient) used to lose one of the writes, so every change goes
+// through updateUser, which only writes if nobody else wrote since the read
+// and starts over if somebody did.
+
+const (
+	// attempts before updateUser gives up on a busy user
+	USER_UPDATE_RETRIES = 20
+	// longest wait between attempts, the wait is random up to this
+	USER_UPDATE_BACKOFF = 10 * time.Millisecond
+)
+
+var (
+	// from UserStore.CheckAndSetUser, the record changed since it was read
+	ErrStaleUser = errors.New("the user was written since it was read")
+	ErrUserBusy  = cmdError(ERR_UNAVAILABLE, "too many changes to the user at once, try again")
+	// an edit returns errNoChange to skip the write, updateUser returns nil
+	errNoChange = errors.New("nothing to change")
+)
+
+// updateUser reads username, runs edit on it and writes it back if nobody
+// else wrote in between, otherwise it reads again and reruns edit.  edit can
+// run more than once so it should only change user, send events after
+// updateUser returns.  An error from edit is returned as is and nothing is
+// written
+func (db *Database) updateUser(username string, edit func(user *UserStruct) error) (UserStruct, error) {
+	for attempt := 0; attempt < USER_UPDATE_RETRIES; attempt++ {
+		user, generation := db.Users.GetUserGeneration(strings.ToUpper(username))
+		if generation == 0 {
+			return user, ErrUserNotFound
+		}
+		if err := edit(&user); err == errNoChange {
+			return user, nil
+		} else if err != nil {
+			return user, err
+		}
+		err := db.Users.CheckAndSetUser(user, generation)
+		if err == nil {
+			return user, nil
+		} else if err != ErrStaleUser {
+			ERROR.Println("error in updateUser for "+username+":", err)
+			return user, ErrNotSaved
+		}
+		time.Sleep(time.Duration(rand.Int63n(int64(USER_UPDATE_BACKOFF))))
+	}
+	ERROR.Println("updateUser gave up on " + username + " after too many conflicts")
+	return UserStruct{}, ErrUserBusy
+}
+
+// updateUserAndNotify is updateUser for edits that say whether they changed
+// anything, when one did the user is sent with cmd to their web devices and
+// logged as kind.  The error is updateUser's
+func (db *Database) updateUserAndNotify(username string, cmd string, kind string, edit func(user *UserStruct) bool) error {
+	changed := false
+	storeduser, err := db.updateUser(username, func(user *UserStruct) error {
+		if changed = edit(user); !changed {
+			return errNoChange
+		}
+		return nil
+	})
+	if err != nil || !changed {
+		return err
+	}
+	db.sendChangeString(storeduser, kind, storeduser.ToJSONStringWithCmd(cmd))
+	return nil
+}
+
+func hasFriend(friends []UserFriendStruct, username string) bool {
+	for _, friend := range friends {
+		if strings.ToUpper(friend.Username) == strings.ToUpper(username) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeFriend drops username from friends, removed says if it was there
+func removeFriend(friends []UserFriendStruct, username string) ([]UserFriendStruct, bool) {
+	kept := make([]UserFriendStruct, 0, len(friends))
+	for _, friend := range friends {
+		if strings.ToUpper(friend.Username) != strings.ToUpper(username) {
+			kept = append(kept, friend)
+		}
+	}
+	return kept, len(kept) != len(friends)
+}
@@ -0,0 +1,124 @@
+package pcDatabase
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// a write only fails when another one got in, so with this many writers
+// every one of them gets its turn within USER_UPDATE_RETRIES
+const CONCURRENT_WRITERS = USER_UPDATE_RETRIES
+
+// a store that's slow to read users, so the writers all read before any of
+// them writes, like aerospike over the network
+type slowUserStore struct {
+	*MemoryStore
+}
+
+func (s slowUserStore) GetUserGeneration(username string) (UserStruct, uint32) {
+	user, generation := s.MemoryStore.GetUserGeneration(username)
+	time.Sleep(time.Millisecond)
+	return user, generation
+}
+
+// runs f from CONCURRENT_WRITERS connections at once
+func concurrently(db *Database, f func(conn *Database, i int)) {
+	db.Users = slowUserStore{db.Users.(*MemoryStore)}
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < CONCURRENT_WRITERS; i++ {
+		conn := newTestConnection(db)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer conn.Close()
+			<-start
+			f(conn, i)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+}
+
+func TestConcurrentMessagesKeepEveryUnreadCount(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "bob")
+	createTestUser(t, db, "alice")
+	CID := createTestConversation(t, db, "alice", "bob")
+
+	// a sender can't have two messages at the same m_time, so each gets its own
+	concurrently(db, func(conn *Database, i int) {
+		conn.SendMessage(&MessageStruct{
+			Cmd:          "SendMessage",
+			CID:          CID,
+			FromUsername: "alice",
+			ToUIDs:       []string{"bob"},
+			M_time:       fmt.Sprintf("2015-06-12T19:20:%02d.000Z", i),
+			Content:      fmt.Sprint("message ", i),
+		})
+	})
+	bob := db.Users.GetUser("BOB")
+	if CIDs := bob.GetCIDStructs(); len(CIDs) != 1 || CIDs[0].UnreadCount != CONCURRENT_WRITERS {
+		t.Errorf("bob's conversations are %+v, expected an unread count of %d", CIDs, CONCURRENT_WRITERS)
+	}
+}
+
+func TestConcurrentConversationsAreAllAdded(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "bob")
+
+	concurrently(db, func(conn *Database, i int) {
+		conn.AddConversationToUser(fmt.Sprint("CID", i), "convo", "bob", "2015-06-12T19:20:00.000Z")
+	})
+	bob := db.Users.GetUser("BOB")
+	if CIDs := bob.GetCIDStructs(); len(CIDs) != CONCURRENT_WRITERS {
+		t.Errorf("bob is in %d conversations, expected %d", len(CIDs), CONCURRENT_WRITERS)
+	}
+}
+
+func TestConcurrentFriendRequestsAreAllKept(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "bob")
+	for i := 0; i < CONCURRENT_WRITERS; i++ {
+		createTestUser(t, db, fmt.Sprint("fan", i))
+	}
+
+	concurrently(db, func(conn *Database, i int) {
+		conn.AddFriend(&FriendCmdStruct{Cmd: "AddFriend", UID: fmt.Sprint("fan", i), FriendUID: "bob"})
+	})
+	bob := db.Users.GetUser("BOB")
+	if incoming := bob.GetIncomingPendingFriendStructs(); len(incoming) != CONCURRENT_WRITERS {
+		t.Errorf("bob has %d friend requests, expected %d", len(incoming), CONCURRENT_WRITERS)
+	}
+}
+
+// a store where somebody always writes the user first
+type contendedStore struct {
+	*MemoryStore
+}
+
+func (s contendedStore) CheckAndSetUser(user UserStruct, generation uint32) error {
+	return ErrStaleUser
+}
+
+func TestUpdateUserGivesUpOnABusyUser(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+	createTestUser(t, db, "bob")
+	db.Users = contendedStore{db.Users.(*MemoryStore)}
+
+	edits := 0
+	_, err := db.updateUser("bob", func(user *UserStruct) error {
+		edits++
+		user.ProfilePic = "bob.png"
+		return nil
+	})
+	if err != ErrUserBusy || edits != USER_UPDATE_RETRIES {
+		t.Errorf("updateUser returned %v after %d edits", err, edits)
+	}
+}
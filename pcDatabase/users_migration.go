@@ -0,0 +1,70 @@
+package pcDatabase
+
+import (
+	"errors"
+	aerospike "github.com/aerospike/aerospike-client-go"
+)
+
+// moving users from JSON strings in the CIDs, Friends, InPendFriend,
+// OutPendFriend, SchedMessages and SecQuests bins to maps and lists, see
+// user_bins.go.
+//
+// this server reads either, and every write of a user converts it, so
+// ConvertLegacyUserBins is only needed for users that haven't been written
+// since.  Older servers can't read the converted users, so they all have to
+// be gone before this version starts, there's no rolling deploy for this one.
+
+// rewrites the user at key with map and list bins, if it isn't already.
+// ErrStaleUser if somebody wrote it in between
+func (s *AerospikeStore) convertUser(key *aerospike.Key) error {
+	rec := s.ReadAerospike(key)
+	if rec == nil || !hasLegacyUserBins(rec.Bins) {
+		return nil
+	}
+	return s.convertUserRecord(rec)
+}
+
+func (s *AerospikeStore) convertUserRecord(rec *aerospike.Record) error {
+	user := FillUserWithAerospikeBins(rec.Bins)
+	if user.UsernameUpper == "" {
+		return errors.New("couldn't read the user")
+	}
+	return s.CheckAndSetUser(user, rec.Generation)
+}
+
+// ConvertLegacyUserBins rewrites every user that still has JSON strings in
+// bins that are maps and lists now.  It's safe to run while the server is up
+// and to run again if it gets interrupted.  Returns how many users were
+// converted.
+func (s *AerospikeStore) ConvertLegacyUserBins() (int, error) {
+	if s.conn == nil {
+		return 0, errors.New("s.conn == nil in ConvertLegacyUserBins")
+	}
+	rs, err := s.conn.ScanAll(nil, AEROSPIKE_USERS_NAMESPACE, AEROSPIKE_USERS_USERNAME_TABLE)
+	if err != nil {
+		return 0, err
+	}
+	defer rs.Close()
+	converted := 0
+	failed := 0
+	for res := range rs.Results() {
+		if res.Err != nil {
+			return converted, res.Err
+		}
+		if !hasLegacyUserBins(res.Record.Bins) {
+			continue
+		}
+		if err := s.convertUserRecord(res.Record); err != nil {
+			// ErrStaleUser too, it was written by something since the scan
+			// read it, which may have been an older server
+			ERROR.Println("error converting a user:", err)
+			failed++
+			continue
+		}
+		converted++
+	}
+	if failed > 0 {
+		return converted, errors.New("some users could not be converted, run again to retry them")
+	}
+	return converted, nil
+}
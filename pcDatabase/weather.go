@@ -2,14 +2,36 @@ package pcDatabase
 
 import (
 	"encoding/json"
-	"fmt"
 	forecast "github.com/mlbright/forecast/v2"
+	"regexp"
+	"strconv"
 )
 
-const (
-	forecastPrefix     = "@forecast "
-	FORECASTIO_API_KEY = "FILL_ME"
-)
+// @forecast {"latitude":"..","longitude":".."}, clients send it with the
+// phone's location and the message is replaced by the weather there
+type forecastBot struct{}
+
+var forecastTriggers = []*regexp.Regexp{regexp.MustCompile(`(?is)^\s*@forecast\s+(\{.*\})\s*$`)}
+
+func (forecastBot) Name() string {
+	return "forecast"
+}
+
+func (forecastBot) Triggers() []*regexp.Regexp {
+	return forecastTriggers
+}
+
+func (forecastBot) Help() string {
+	return "@forecast with your location sends the weather where you are"
+}
+
+func (forecastBot) Handle(msg BotMessage) BotResponse {
+	weather, ok := GetWeather(msg.Args)
+	if !ok {
+		return BotResponse{} // the message goes as it is
+	}
+	return BotResponse{Rewrite: true, Attachments: []MessageAttachment{weather}}
+}
 
 type WeatherCmdStruct struct {
 	Cmd       string `json:"cmd"`
@@ -20,83 +42,39 @@ type WeatherCmdStruct struct {
 }
 
 // EXAMPLE RETURNED JSON
-// https://api.forecast.io/forecast/FILL_ME/37.8267,-122.423
+// https://api.forecast.io/forecast/<API key>/37.8267,-122.423
 
-func GetWeather(message string) string {
+// GetWeather is the weather where message (a WeatherCmdStruct) says, false
+// when it can't be had
+func GetWeather(message string) (MessageAttachment, bool) {
 	// unmarshal message into latitude string, longitude string, unit string, language string
 	weatherdata := WeatherCmdStruct{}
 	err := json.Unmarshal([]byte(message), &weatherdata)
 	if err != nil {
 		ERROR.Println("error in GetWeather Unmarshalling into WeatherCmdStruct:", err)
-		return message // return original message back
+		return MessageAttachment{}, false // the message goes as it is
+	}
+	// they end up in a link
+	if _, err := strconv.ParseFloat(weatherdata.Latitude, 64); err != nil {
+		return MessageAttachment{}, false
+	}
+	if _, err := strconv.ParseFloat(weatherdata.Longitude, 64); err != nil {
+		return MessageAttachment{}, false
 	}
 	// now use API to get forecast
-	f, err := forecast.Get(FORECASTIO_API_KEY, weatherdata.Latitude, weatherdata.Longitude, "now", forecast.AUTO)
+	f, err := forecast.Get(conf.Forecast.APIKey, weatherdata.Latitude, weatherdata.Longitude, "now", forecast.AUTO)
 	if err != nil {
 		ERROR.Println(err)
+		return MessageAttachment{}, false
 	}
-	// forecastBytes, err := json.Marshal(f)
-	// if err != nil {
-	// 	ERROR.Println("error in json.Marshal in GetWeather:")
-	// 	ERROR.Println(err)
-	// } else {
-	//     TRACE.Println(string(forecastBytes))
-	// }
-	// create string to send
-	// first, determine which weather-icon to include
-	wIcon := "<i class=\"wi "
-	switch f.Hourly.Icon {
-	case "clear-day":
-		wIcon += "wi-day-sunny"
-	case "clear-night":
-		wIcon += "wi-night-clear"
-	case "rain":
-		wIcon += "wi-day-rain"
-	case "snow":
-		wIcon += "wi-day-snow"
-	case "sleet":
-		wIcon += "wi-day-sleet"
-	case "wind":
-		wIcon += "wi-day-windy"
-	case "fog":
-		wIcon += "wi-day-fog"
-	case "cloudy":
-		wIcon += "wi-day-cloudy"
-	case "partly-cloudy-day":
-		wIcon += "wi-day-cloudy"
-	case "partly-cloudy-night":
-		wIcon += "wi-night-partly-cloudy"
-	case "hail":
-		wIcon += "wi-day-hail"
-	case "thunderstorm":
-		wIcon += "wi-day-thunderstorm"
-	case "tornado":
-		wIcon += "wi-tornado"
-	default:
-		wIcon += "na"
-	}
-	wIcon += "\"></i>"
-	// now create HTML string to return
-	retstr := `<div id="forecast-embedded">` +
-		`<div id="forecast-header">` +
-		`<i>Forecast provided by <a target="_blank" href="http://forecast.io/#/f/33.7748323,-84.3804785">forecast.io</a></i>` +
-		`</div>` +
-		`<div id="forecast-main">` +
-		`<span id="forecast-icon">` +
-		wIcon +
-		`</span>` +
-		`<span id="forecast-temp">` +
-		fmt.Sprintf("%2.0f", f.Currently.Temperature) +
-		`<i class="wi wi-fahrenheit"></i>` +
-		`</span>` +
-		`</div>` +
-		`<div id="forecast-summary">` +
-		f.Hourly.Summary +
-		`</div>` +
-		`</div>`
-	TRACE.Println("weather string: " + retstr)
-	return retstr
-
-	// cheap solution
-	// return `<iframe id="forecast_embed" type="text/html" frameborder="0" height="245" width="100%" src="http://forecast.io/embed/#lat=` + weatherdata.Latitude + `&lon=` + weatherdata.Longitude + `"> </iframe>`;
+	// clients pick the weather-icon from Icon (clear-day, rain and so on)
+	temperature := f.Currently.Temperature
+	return MessageAttachment{
+		Type:        ATTACHMENT_WEATHER,
+		URL:         "https://forecast.io/#/f/" + weatherdata.Latitude + "," + weatherdata.Longitude,
+		Title:       "Forecast provided by forecast.io",
+		Icon:        f.Hourly.Icon,
+		Temperature: &temperature,
+		Summary:     f.Hourly.Summary,
+	}, true
 }